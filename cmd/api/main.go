@@ -10,13 +10,17 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Cancellation windows are counted in the destination's time zone and the image has no tzdata
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jimmmmisss/api-viagens/internal/config"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
 	"github.com/jimmmmisss/api-viagens/internal/handler"
 	"github.com/jimmmmisss/api-viagens/internal/repository"
 	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/jimmmmisss/api-viagens/internal/utils"
 )
 
 func main() {
@@ -34,10 +38,106 @@ func main() {
 	// Setup dependencies
 	userRepo := repository.NewPostgresUserRepository(dbpool)
 	tripRepo := repository.NewPostgresTripRepository(dbpool)
+	orgRepo := repository.NewPostgresOrganizationRepository(dbpool)
+	costCenterRepo := repository.NewPostgresCostCenterRepository(dbpool)
+	departmentRepo := repository.NewPostgresDepartmentRepository(dbpool)
+	policyRepo := repository.NewPostgresApprovalPolicyRepository(dbpool)
+	stepRepo := repository.NewPostgresApprovalStepRepository(dbpool)
+	travelPolicyRepo := repository.NewPostgresTravelPolicyRepository(dbpool)
+	tripEventRepo := repository.NewPostgresTripEventRepository(dbpool)
+	delegationRepo := repository.NewPostgresDelegationRepository(dbpool)
+	scheduledNotificationRepo := repository.NewPostgresScheduledNotificationRepository(dbpool)
+	blackoutCalendarRepo := repository.NewPostgresBlackoutCalendarRepository(dbpool)
+	exchangeRateRepo := repository.NewPostgresExchangeRateRepository(dbpool)
+	budgetRepo := repository.NewPostgresCostCenterBudgetRepository(dbpool)
+	perDiemRateRepo := repository.NewPostgresPerDiemRateRepository(dbpool)
+	expenseReportRepo := repository.NewPostgresExpenseReportRepository(dbpool)
+	attachmentRepo := repository.NewPostgresAttachmentRepository(dbpool)
+	cashAdvanceRepo := repository.NewPostgresCashAdvanceRepository(dbpool)
+	glAccountRepo := repository.NewPostgresGLAccountRepository(dbpool)
+	accountingExportRepo := repository.NewPostgresAccountingExportRepository(dbpool)
+	cardTransactionRepo := repository.NewPostgresCardTransactionRepository(dbpool)
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatalf("could not set up blob store: %v", err)
+	}
+	destinationRepo, err := repository.NewEmbeddedDestinationRepository()
+	if err != nil {
+		log.Fatalf("could not load destinations: %v", err)
+	}
 	notificationSvc := service.NewLogNotificationService()
-	userSvc := service.NewUserService(userRepo)
-	tripSvc := service.NewTripService(tripRepo, userRepo, notificationSvc)
-	h := handler.NewHandler(userSvc, tripSvc)
+	userSvc, err := newUserService(cfg, userRepo, orgRepo)
+	if err != nil {
+		log.Fatalf("could not set up user service: %v", err)
+	}
+	tripSvc := service.NewTripService(tripRepo, userRepo, notificationSvc,
+		service.WithCostCenters(costCenterRepo, departmentRepo),
+		service.WithApprovalChains(policyRepo, stepRepo),
+		service.WithTravelPolicies(travelPolicyRepo),
+		service.WithTripHistory(tripEventRepo),
+		service.WithDelegations(delegationRepo),
+		service.WithTripReminders(scheduledNotificationRepo, tripReminderDays(cfg.TripReminderDays)...),
+		service.WithBlackoutCalendars(blackoutCalendarRepo),
+		service.WithDestinationCatalog(destinationRepo),
+		service.WithExchangeRates(exchangeRateRepo, orgRepo),
+		service.WithCostCenterBudgets(budgetRepo, expenseReportRepo),
+		service.WithPerDiem(perDiemRateRepo),
+		service.WithTransactions(repository.NewPostgresTransactor(dbpool)),
+	)
+	costCenterSvc := service.NewCostCenterService(costCenterRepo, departmentRepo, userRepo)
+	approvalSvc := service.NewApprovalService(policyRepo, stepRepo, tripRepo, userRepo,
+		service.WithDelegatedApprovals(delegationRepo),
+	)
+	travelPolicySvc := service.NewTravelPolicyService(travelPolicyRepo, userRepo)
+	delegationSvc := service.NewDelegationService(delegationRepo, userRepo)
+	blackoutCalendarSvc := service.NewBlackoutCalendarService(blackoutCalendarRepo, departmentRepo, userRepo)
+	destinationSvc := service.NewDestinationService(destinationRepo)
+	exchangeRateSvc := service.NewExchangeRateService(exchangeRateRepo, orgRepo, userRepo)
+	budgetSvc := service.NewBudgetService(budgetRepo, tripRepo, expenseReportRepo, costCenterRepo, orgRepo, userRepo)
+	perDiemRateSvc := service.NewPerDiemRateService(perDiemRateRepo, userRepo)
+	expenseSvc := service.NewExpenseService(expenseReportRepo, tripRepo, cashAdvanceRepo, userRepo, orgRepo, exchangeRateRepo, notificationSvc)
+	cashAdvanceSvc := service.NewCashAdvanceService(cashAdvanceRepo, tripRepo, expenseReportRepo, userRepo, orgRepo, exchangeRateRepo, notificationSvc,
+		repository.NewPostgresTransactor(dbpool))
+	accountingExportSvc := service.NewAccountingExportService(accountingExportRepo, glAccountRepo, tripRepo, costCenterRepo, userRepo)
+	cardTransactionSvc := service.NewCardTransactionService(cardTransactionRepo, tripRepo, expenseReportRepo, userRepo, orgRepo, exchangeRateRepo, notificationSvc)
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, tripRepo, expenseReportRepo, userRepo, blobStore, service.AttachmentConfig{
+		MaxSize:         int64(cfg.AttachmentMaxSizeMB) << 20,
+		URLTTL:          time.Duration(cfg.AttachmentURLTTLMinutes) * time.Minute,
+		DownloadBaseURL: cfg.PublicBaseURL,
+		URLSecret:       cfg.AttachmentURLSecret,
+	})
+	h := handler.NewHandler(userSvc, tripSvc,
+		handler.WithCostCenterService(costCenterSvc),
+		handler.WithApprovalService(approvalSvc),
+		handler.WithTravelPolicyService(travelPolicySvc),
+		handler.WithDelegationService(delegationSvc),
+		handler.WithBlackoutCalendarService(blackoutCalendarSvc),
+		handler.WithDestinationService(destinationSvc),
+		handler.WithExchangeRateService(exchangeRateSvc),
+		handler.WithBudgetService(budgetSvc),
+		handler.WithPerDiemRateService(perDiemRateSvc),
+		handler.WithExpenseService(expenseSvc),
+		handler.WithAttachmentService(attachmentSvc),
+		handler.WithCashAdvanceService(cashAdvanceSvc),
+		handler.WithAccountingExportService(accountingExportSvc),
+		handler.WithCardTransactionService(cardTransactionSvc),
+	)
+
+	// Background jobs
+	scheduler := service.NewScheduler(repository.NewPostgresJobLocker(dbpool))
+	escalationSvc := service.NewApprovalEscalationService(stepRepo, tripRepo, userRepo, notificationSvc, service.ApprovalEscalationConfig{
+		RemindAfter:   time.Duration(cfg.ApprovalReminderHours) * time.Hour,
+		EscalateAfter: time.Duration(cfg.ApprovalEscalationHours) * time.Hour,
+	})
+	if cfg.ApprovalReminderHours > 0 {
+		scheduler.Register("approval-escalation", time.Duration(cfg.ApprovalEscalationIntervalMinutes)*time.Minute, escalationSvc.EscalateStaleApprovals)
+	}
+	notificationWorker := service.NewNotificationWorker(scheduledNotificationRepo, tripRepo, userRepo, notificationSvc)
+	scheduler.Register("scheduled-notifications", time.Duration(cfg.NotificationIntervalMinutes)*time.Minute, notificationWorker.SendDue)
+	if cfg.TripConclusionIntervalMinutes > 0 {
+		scheduler.Register("trip-conclusion", time.Duration(cfg.TripConclusionIntervalMinutes)*time.Minute, tripSvc.ConcludeEndedTrips)
+	}
+	scheduler.Start()
 
 	// Setup Gin router
 	router := setupRouter(h, cfg.JWTSecretKey)
@@ -65,6 +165,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	// Jobs still running must finish before the database pool closes
+	if err := scheduler.Shutdown(ctx); err != nil {
+		log.Println("Background jobs forced to stop:", err)
+	}
 
 	log.Println("Server exiting")
 }
@@ -87,6 +191,53 @@ func connectDB(cfg *config.Config) (*pgxpool.Pool, error) {
 	return dbpool, nil
 }
 
+func newUserService(cfg *config.Config, userRepo domain.UserRepository, orgRepo domain.OrganizationRepository) (*service.UserService, error) {
+	opts := []service.UserServiceOption{
+		service.WithPasswordPolicy(domain.PasswordPolicy{
+			MinLength:     cfg.PasswordMinLength,
+			RequireUpper:  cfg.PasswordRequireUpper,
+			RequireLower:  cfg.PasswordRequireLower,
+			RequireDigit:  cfg.PasswordRequireDigit,
+			RequireSymbol: cfg.PasswordRequireSymbol,
+			HistorySize:   cfg.PasswordHistorySize,
+		}),
+	}
+
+	if cfg.BreachedPasswordsFile != "" {
+		list, err := utils.LoadBreachedPasswordList(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, service.WithBreachedPasswords(list))
+	}
+
+	return service.NewUserService(userRepo, orgRepo, opts...), nil
+}
+
+// newBlobStore sets up the store attachment contents are kept in
+func newBlobStore(cfg *config.Config) (domain.BlobStore, error) {
+	if cfg.BlobStore == "s3" {
+		return repository.NewS3BlobStore(repository.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		})
+	}
+	return repository.NewLocalBlobStore(cfg.BlobLocalDir)
+}
+
+// tripReminderDays returns the days before a trip its traveler is reminded:
+// the configured notice and the day before
+func tripReminderDays(noticeDays int) []int {
+	if noticeDays > 1 {
+		return []int{noticeDays, 1}
+	}
+	return []int{1}
+}
+
 func setupRouter(h *handler.Handler, jwtSecret string) *gin.Engine {
 	r := gin.Default()
 
@@ -108,17 +259,92 @@ func setupRouter(h *handler.Handler, jwtSecret string) *gin.Engine {
 	// Public routes
 	r.POST("/register", h.RegisterUser)
 	r.POST("/login", h.LoginUser)
+	// Signed download URLs carry their own credential
+	r.GET("/attachments/:id/download", h.DownloadAttachment)
 
 	// Authenticated routes
 	authRoutes := r.Group("/")
 	authMiddleware := middleware.AuthMiddleware(jwtSecret)
 	authRoutes.Use(authMiddleware)
 	{
+		authRoutes.GET("/destinations", h.SearchDestinations)
 		authRoutes.POST("/trips", h.CreateTrip)
 		authRoutes.GET("/trips", h.ListTrips)
 		authRoutes.GET("/trips/:id", h.GetTripByID)
+		authRoutes.PUT("/trips/:id", h.UpdateTrip)
 		authRoutes.PATCH("/trips/:id/status", h.UpdateTripStatus)
 		authRoutes.POST("/trips/:id/cancel", h.CancelApprovedTrip)
+		authRoutes.POST("/trips/:id/withdraw", h.WithdrawTrip)
+		authRoutes.GET("/trips/:id/history", h.GetTripHistory)
+		authRoutes.GET("/trips/:id/approvals", h.GetTripApprovals)
+		authRoutes.GET("/trips/:id/expenses", h.GetExpenseReport)
+		authRoutes.POST("/trips/:id/expenses/items", h.AddExpenseItem)
+		authRoutes.PUT("/trips/:id/expenses/items/:item_id", h.UpdateExpenseItem)
+		authRoutes.DELETE("/trips/:id/expenses/items/:item_id", h.DeleteExpenseItem)
+		authRoutes.POST("/trips/:id/expenses/submit", h.SubmitExpenseReport)
+		authRoutes.POST("/trips/:id/expenses/approve", h.ApproveExpenseReport)
+		authRoutes.POST("/trips/:id/expenses/reject", h.RejectExpenseReport)
+		authRoutes.POST("/trips/:id/expenses/reimburse", h.ReimburseExpenseReport)
+		authRoutes.POST("/trips/:id/attachments", h.UploadAttachment)
+		authRoutes.GET("/trips/:id/attachments", h.ListAttachments)
+		authRoutes.GET("/trips/:id/attachments/:attachment_id/url", h.GetAttachmentURL)
+		authRoutes.POST("/trips/:id/advances", h.RequestCashAdvance)
+		authRoutes.GET("/trips/:id/advances", h.ListTripCashAdvances)
+		authRoutes.GET("/trips/:id/advances/reconciliation", h.GetCashAdvanceReconciliation)
+		authRoutes.POST("/trips/:id/advances/settle", h.SettleCashAdvances)
+		authRoutes.POST("/trips/:id/advances/:advance_id/approve", h.ApproveCashAdvance)
+		authRoutes.POST("/trips/:id/advances/:advance_id/reject", h.RejectCashAdvance)
+		authRoutes.POST("/trips/:id/advances/:advance_id/pay", h.PayCashAdvance)
+		authRoutes.GET("/trips/:id/card-spend", h.GetTripCardSpend)
+		authRoutes.POST("/users", h.CreateUser)
+		authRoutes.PUT("/users/me/password", h.ChangePassword)
+		authRoutes.PUT("/users/:id/department", h.AssignUserDepartment)
+		authRoutes.PUT("/users/:id/role", h.UpdateUserRole)
+
+		authRoutes.POST("/cost-centers", h.CreateCostCenter)
+		authRoutes.GET("/cost-centers", h.ListCostCenters)
+		authRoutes.PUT("/cost-centers/:id", h.UpdateCostCenter)
+		authRoutes.POST("/departments", h.CreateDepartment)
+		authRoutes.GET("/departments", h.ListDepartments)
+		authRoutes.PUT("/departments/:id", h.UpdateDepartment)
+
+		authRoutes.POST("/approval-policies", h.CreateApprovalPolicy)
+		authRoutes.GET("/approval-policies", h.ListApprovalPolicies)
+		authRoutes.DELETE("/approval-policies/:id", h.DeleteApprovalPolicy)
+		authRoutes.GET("/approvals/pending", h.ListPendingApprovals)
+		authRoutes.POST("/delegations", h.CreateDelegation)
+		authRoutes.GET("/delegations", h.ListDelegations)
+		authRoutes.DELETE("/delegations/:id", h.DeleteDelegation)
+
+		authRoutes.GET("/travel-policy", h.GetTravelPolicy)
+		authRoutes.PUT("/travel-policy", h.UpdateTravelPolicy)
+		authRoutes.POST("/blackout-calendars", h.CreateBlackoutCalendar)
+		authRoutes.GET("/blackout-calendars", h.ListBlackoutCalendars)
+		authRoutes.PUT("/blackout-calendars/:id/ical", h.ImportBlackoutCalendar)
+		authRoutes.DELETE("/blackout-calendars/:id", h.DeleteBlackoutCalendar)
+		authRoutes.POST("/exchange-rates", h.CreateExchangeRate)
+		authRoutes.POST("/exchange-rates/import", h.ImportExchangeRates)
+		authRoutes.GET("/exchange-rates", h.ListExchangeRates)
+		authRoutes.POST("/budgets", h.CreateBudget)
+		authRoutes.GET("/budgets", h.ListBudgets)
+		authRoutes.GET("/budgets/:id", h.GetBudget)
+		authRoutes.PUT("/budgets/:id", h.UpdateBudget)
+		authRoutes.POST("/per-diem-rates", h.SavePerDiemRate)
+		authRoutes.POST("/per-diem-rates/import", h.ImportPerDiemRates)
+		authRoutes.GET("/per-diem-rates", h.ListPerDiemRates)
+		authRoutes.DELETE("/per-diem-rates/:id", h.DeletePerDiemRate)
+		authRoutes.GET("/expense-reports", h.ListExpenseReports)
+		authRoutes.GET("/advances", h.ListCashAdvances)
+		authRoutes.GET("/accounting/gl-accounts", h.ListGLAccounts)
+		authRoutes.PUT("/accounting/gl-accounts/:category", h.SaveGLAccount)
+		authRoutes.GET("/exports/accounting", h.ExportAccounting)
+		authRoutes.GET("/exports/accounting/batches", h.ListAccountingExportBatches)
+		authRoutes.GET("/exports/accounting/batches/:id", h.DownloadAccountingExportBatch)
+		authRoutes.POST("/card-transactions/import", h.ImportCardStatement)
+		authRoutes.GET("/card-transactions", h.ListCardTransactions)
+		authRoutes.GET("/card-transactions/over-budget-trips", h.ListOverBudgetCardSpend)
+		authRoutes.POST("/card-transactions/:id/match", h.MatchCardTransaction)
+		authRoutes.POST("/card-transactions/:id/ignore", h.IgnoreCardTransaction)
 	}
 
 	return r
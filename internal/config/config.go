@@ -18,6 +18,34 @@ type Config struct {
 	DBSSLMode          string
 	JWTSecretKey       string
 	JWTExpirationHours int
+
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordHistorySize   int
+	BreachedPasswordsFile string
+
+	ApprovalReminderHours             int
+	ApprovalEscalationHours           int
+	ApprovalEscalationIntervalMinutes int
+	TripConclusionIntervalMinutes     int
+	TripReminderDays                  int
+	NotificationIntervalMinutes       int
+
+	BlobStore               string
+	BlobLocalDir            string
+	S3Endpoint              string
+	S3Region                string
+	S3Bucket                string
+	S3AccessKeyID           string
+	S3SecretAccessKey       string
+	S3UsePathStyle          bool
+	AttachmentMaxSizeMB     int
+	AttachmentURLTTLMinutes int
+	AttachmentURLSecret     string
+	PublicBaseURL           string
 }
 
 func Load() (*Config, error) {
@@ -31,17 +59,98 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_EXPIRATION_HOURS: %w", err)
 	}
 
-	return &Config{
-		APIPort:            getEnv("API_PORT", "8080"),
-		DBHost:             getEnv("DB_HOST", "localhost"),
-		DBPort:             getEnv("DB_PORT", "5432"),
-		DBUser:             getEnv("DB_USER", "user"),
-		DBPassword:         getEnv("DB_PASSWORD", "password"),
-		DBName:             getEnv("DB_NAME", "tripdb"),
-		DBSSLMode:          getEnv("DB_SSLMODE", "disable"),
-		JWTSecretKey:       getEnv("JWT_SECRET_KEY", "default-secret"),
-		JWTExpirationHours: jwtExp,
-	}, nil
+	cfg := &Config{
+		APIPort:               getEnv("API_PORT", "8080"),
+		DBHost:                getEnv("DB_HOST", "localhost"),
+		DBPort:                getEnv("DB_PORT", "5432"),
+		DBUser:                getEnv("DB_USER", "user"),
+		DBPassword:            getEnv("DB_PASSWORD", "password"),
+		DBName:                getEnv("DB_NAME", "tripdb"),
+		DBSSLMode:             getEnv("DB_SSLMODE", "disable"),
+		JWTSecretKey:          getEnv("JWT_SECRET_KEY", "default-secret"),
+		JWTExpirationHours:    jwtExp,
+		BreachedPasswordsFile: getEnv("BREACHED_PASSWORDS_FILE", ""),
+		BlobStore:             getEnv("BLOB_STORE", "local"),
+		BlobLocalDir:          getEnv("BLOB_LOCAL_DIR", "./data/blobs"),
+		S3Endpoint:            getEnv("S3_ENDPOINT", ""),
+		S3Region:              getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:              getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:         getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:     getEnv("S3_SECRET_ACCESS_KEY", ""),
+	}
+	cfg.PublicBaseURL = getEnv("PUBLIC_BASE_URL", "http://localhost:"+cfg.APIPort)
+	cfg.AttachmentURLSecret = getEnv("ATTACHMENT_URL_SECRET", "")
+
+	if cfg.PasswordMinLength, err = getEnvInt("PASSWORD_MIN_LENGTH", 8); err != nil {
+		return nil, err
+	}
+	if cfg.PasswordRequireUpper, err = getEnvBool("PASSWORD_REQUIRE_UPPER", false); err != nil {
+		return nil, err
+	}
+	if cfg.PasswordRequireLower, err = getEnvBool("PASSWORD_REQUIRE_LOWER", false); err != nil {
+		return nil, err
+	}
+	if cfg.PasswordRequireDigit, err = getEnvBool("PASSWORD_REQUIRE_DIGIT", false); err != nil {
+		return nil, err
+	}
+	if cfg.PasswordRequireSymbol, err = getEnvBool("PASSWORD_REQUIRE_SYMBOL", false); err != nil {
+		return nil, err
+	}
+	if cfg.PasswordHistorySize, err = getEnvInt("PASSWORD_HISTORY_SIZE", 5); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalReminderHours, err = getEnvInt("APPROVAL_REMINDER_HOURS", 48); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalEscalationHours, err = getEnvInt("APPROVAL_ESCALATION_HOURS", 96); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalEscalationIntervalMinutes, err = getEnvInt("APPROVAL_ESCALATION_INTERVAL_MINUTES", 15); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalEscalationHours > 0 && cfg.ApprovalEscalationHours <= cfg.ApprovalReminderHours {
+		return nil, fmt.Errorf("APPROVAL_ESCALATION_HOURS must be greater than APPROVAL_REMINDER_HOURS")
+	}
+	if cfg.ApprovalEscalationIntervalMinutes <= 0 {
+		return nil, fmt.Errorf("APPROVAL_ESCALATION_INTERVAL_MINUTES must be positive")
+	}
+	if cfg.TripConclusionIntervalMinutes, err = getEnvInt("TRIP_CONCLUSION_INTERVAL_MINUTES", 60); err != nil {
+		return nil, err
+	}
+	if cfg.TripReminderDays, err = getEnvInt("TRIP_REMINDER_DAYS", 7); err != nil {
+		return nil, err
+	}
+	if cfg.NotificationIntervalMinutes, err = getEnvInt("NOTIFICATION_INTERVAL_MINUTES", 1); err != nil {
+		return nil, err
+	}
+	if cfg.NotificationIntervalMinutes <= 0 {
+		return nil, fmt.Errorf("NOTIFICATION_INTERVAL_MINUTES must be positive")
+	}
+	if cfg.BlobStore != "local" && cfg.BlobStore != "s3" {
+		return nil, fmt.Errorf("BLOB_STORE must be local or s3")
+	}
+	if cfg.S3UsePathStyle, err = getEnvBool("S3_USE_PATH_STYLE", true); err != nil {
+		return nil, err
+	}
+	if cfg.AttachmentMaxSizeMB, err = getEnvInt("ATTACHMENT_MAX_SIZE_MB", 10); err != nil {
+		return nil, err
+	}
+	if cfg.AttachmentMaxSizeMB <= 0 {
+		return nil, fmt.Errorf("ATTACHMENT_MAX_SIZE_MB must be positive")
+	}
+	if cfg.AttachmentURLTTLMinutes, err = getEnvInt("ATTACHMENT_URL_TTL_MINUTES", 15); err != nil {
+		return nil, err
+	}
+	if cfg.AttachmentURLTTLMinutes <= 0 {
+		return nil, fmt.Errorf("ATTACHMENT_URL_TTL_MINUTES must be positive")
+	}
+	// Download URLs are signed with a key of their own, so rotating the JWT key
+	// doesn't break them and one leaked key can't forge the other's signatures
+	if cfg.AttachmentURLSecret == "" || cfg.AttachmentURLSecret == "default-secret" || cfg.AttachmentURLSecret == cfg.JWTSecretKey {
+		return nil, fmt.Errorf("ATTACHMENT_URL_SECRET must be set to a secret of its own, different from JWT_SECRET_KEY")
+	}
+
+	return cfg, nil
 }
 
 func getEnv(key, fallback string) string {
@@ -50,3 +159,19 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) (int, error) {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(fallback)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func getEnvBool(key string, fallback bool) (bool, error) {
+	value, err := strconv.ParseBool(getEnv(key, strconv.FormatBool(fallback)))
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
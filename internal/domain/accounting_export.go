@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ErrAlreadyExported is returned when a batch has an expense another batch exported
+var ErrAlreadyExported = errors.New("some expenses were already exported")
+
+type AccountingExportFormat string
+
+const (
+	// ExportCSV is a comma-separated file with a header line
+	ExportCSV AccountingExportFormat = "csv"
+	// ExportFixedWidth is a text file of fixed-width records, see WriteFixedWidth
+	ExportFixedWidth AccountingExportFormat = "fixed"
+)
+
+func (f AccountingExportFormat) IsValid() bool {
+	return f == ExportCSV || f == ExportFixedWidth
+}
+
+// GLAccount maps an expense category to the general ledger account of the
+// organization's ERP the expenses are booked to
+type GLAccount struct {
+	OrgID     uuid.UUID      `json:"org_id"`
+	Category  BudgetCategory `json:"category"`
+	Account   string         `json:"account"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Validate checks if the account data is valid according to business rules
+func (a *GLAccount) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(a.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(!a.Category.IsValid(), "category must be airfare, lodging, per_diem, ground_transport or other")
+	validationErrors.AddIf(a.Account == "", "account is required")
+	validationErrors.AddIf(utf8.RuneCountInString(a.Account) > fixedWidthGLAccount, fmt.Sprintf("account must have at most %d characters", fixedWidthGLAccount))
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// AccountingExportLine is an expense booked to the ERP
+type AccountingExportLine struct {
+	ExpenseItemID  uuid.UUID      `json:"expense_item_id"`
+	TripID         uuid.UUID      `json:"trip_id"`
+	TravelerID     uuid.UUID      `json:"traveler_id"`
+	Destination    string         `json:"destination"`
+	CostCenterCode string         `json:"cost_center_code"`
+	GLAccount      string         `json:"gl_account"`
+	Category       BudgetCategory `json:"category"`
+	Date           time.Time      `json:"date"`
+	Description    string         `json:"description"`
+	// Amount is in minor units of Currency, the organization's base currency
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// AccountingExportBatch records the expenses exported for a period. An
+// expense is exported in a single batch, so exporting a period again only
+// brings the expenses approved since the last export.
+type AccountingExportBatch struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+	// Period is the month the expense reports were approved in, YYYY-MM
+	Period    string                 `json:"period"`
+	CreatedBy uuid.UUID              `json:"created_by"`
+	CreatedAt time.Time              `json:"created_at"`
+	LineCount int                    `json:"line_count"`
+	Lines     []AccountingExportLine `json:"lines,omitempty"`
+}
+
+// ParseAccountingPeriod reads a YYYY-MM period and returns the instants it
+// starts and ends at, in UTC
+func ParseAccountingPeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		validationErrors := NewValidationErrors()
+		validationErrors.Add("period must be YYYY-MM")
+		return time.Time{}, time.Time{}, validationErrors
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+var accountingCSVHeader = []string{
+	"batch_id", "period", "trip_id", "traveler_id", "destination", "cost_center", "gl_account", "category",
+	"expense_date", "description", "amount", "currency",
+}
+
+// WriteCSV writes the lines of the batch as CSV with a header line. Amounts
+// have the decimal places of their currency, e.g. 1500.00.
+func (b *AccountingExportBatch) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(accountingCSVHeader); err != nil {
+		return err
+	}
+	for _, line := range b.Lines {
+		record := []string{
+			b.ID.String(), b.Period, line.TripID.String(), line.TravelerID.String(), line.Destination, line.CostCenterCode,
+			line.GLAccount, string(line.Category), line.Date.Format("2006-01-02"), line.Description,
+			Money{Amount: line.Amount, Currency: line.Currency}.Decimal(), line.Currency,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Widths of the fixed-width fields, in characters
+const (
+	fixedWidthCostCenter  = 20
+	fixedWidthGLAccount   = 20
+	fixedWidthCategory    = 16
+	fixedWidthAmount      = 15
+	fixedWidthDescription = 40
+	fixedWidthCount       = 6
+)
+
+// WriteFixedWidth writes the batch as fixed-width records, one per line:
+//
+//	header  H, period (YYYYMM), batch ID (36), created on (YYYYMMDD), line count (6)
+//	detail  D, trip ID (36), traveler ID (36), cost center (20), GL account (20),
+//	        category (16), expense date (YYYYMMDD), amount (15), currency (3), description (40)
+//
+// Text is left-aligned and padded with spaces, numbers are right-aligned and
+// padded with zeros. Amounts are in minor units, without a decimal separator.
+// Longer text is cut.
+func (b *AccountingExportBatch) WriteFixedWidth(w io.Writer) error {
+	header := "H" + strings.ReplaceAll(b.Period, "-", "") + b.ID.String() + b.CreatedAt.UTC().Format("20060102") +
+		fixedNumber(int64(len(b.Lines)), fixedWidthCount)
+	if _, err := io.WriteString(w, header+"\n"); err != nil {
+		return err
+	}
+	for _, line := range b.Lines {
+		record := "D" + line.TripID.String() + line.TravelerID.String() +
+			fixedText(line.CostCenterCode, fixedWidthCostCenter) +
+			fixedText(line.GLAccount, fixedWidthGLAccount) +
+			fixedText(string(line.Category), fixedWidthCategory) +
+			line.Date.Format("20060102") +
+			fixedNumber(line.Amount, fixedWidthAmount) +
+			fixedText(line.Currency, 3) +
+			fixedText(line.Description, fixedWidthDescription)
+		if _, err := io.WriteString(w, record+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixedText cuts or pads the text with spaces to width characters. Line
+// breaks would break the layout and become spaces.
+func fixedText(text string, width int) string {
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, text)
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+// fixedNumber pads the number with zeros to width characters, after the sign of a negative one
+func fixedNumber(n int64, width int) string {
+	return fmt.Sprintf("%0*d", width, n)
+}
+
+type GLAccountRepository interface {
+	// Save creates or replaces the account of the category
+	Save(ctx context.Context, account *GLAccount) error
+	List(ctx context.Context) ([]*GLAccount, error)
+}
+
+type AccountingExportRepository interface {
+	// ListExportableReports returns the expense reports approved in [from, to)
+	// with only their items that weren't exported yet
+	ListExportableReports(ctx context.Context, from, to time.Time) ([]*ExpenseReport, error)
+	// CreateBatch records the batch and its lines. It fails when a line's expense was already exported.
+	CreateBatch(ctx context.Context, batch *AccountingExportBatch) error
+	// ListBatches returns the batches without their lines, newest first
+	ListBatches(ctx context.Context) ([]*AccountingExportBatch, error)
+	FindBatch(ctx context.Context, id uuid.UUID) (*AccountingExportBatch, error)
+}
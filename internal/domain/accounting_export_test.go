@@ -0,0 +1,102 @@
+package domain_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGLAccount_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		account := &domain.GLAccount{OrgID: uuid.New(), Category: domain.BudgetLodging, Account: "4.1.02.003"}
+		assert.NoError(t, account.Validate())
+	})
+
+	t.Run("Invalid category and account", func(t *testing.T) {
+		account := &domain.GLAccount{OrgID: uuid.New(), Category: "meals", Account: strings.Repeat("9", 21)}
+
+		err := account.Validate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "category must be airfare")
+		assert.Contains(t, err.Error(), "account must have at most 20 characters")
+	})
+}
+
+func TestParseAccountingPeriod(t *testing.T) {
+	t.Run("Month", func(t *testing.T) {
+		from, to, err := domain.ParseAccountingPeriod("2024-12")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, period := range []string{"", "2024-13", "2024-05-01", "05/2024"} {
+			_, _, err := domain.ParseAccountingPeriod(period)
+
+			assert.Error(t, err, period)
+			assert.Contains(t, err.Error(), "period must be YYYY-MM")
+		}
+	})
+}
+
+func accountingExportBatch() *domain.AccountingExportBatch {
+	return &domain.AccountingExportBatch{
+		ID:        uuid.MustParse("00000000-0000-0000-0000-0000000000b1"),
+		Period:    "2024-05",
+		CreatedAt: time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+		LineCount: 1,
+		Lines: []domain.AccountingExportLine{{
+			TripID:         uuid.MustParse("00000000-0000-0000-0000-0000000000a1"),
+			TravelerID:     uuid.MustParse("00000000-0000-0000-0000-0000000000c1"),
+			Destination:    "São Paulo",
+			CostCenterCode: "CC-100",
+			GLAccount:      "4.1.02.003",
+			Category:       domain.BudgetLodging,
+			Date:           time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+			Description:    "Hotel, 2 nights\nbreakfast included",
+			Amount:         123456,
+			Currency:       "BRL",
+		}},
+	}
+}
+
+func TestAccountingExportBatch_WriteCSV(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := accountingExportBatch().WriteCSV(&buf)
+
+	// Assert
+	assert.NoError(t, err)
+	lines := strings.SplitN(buf.String(), "\n", 2)
+	assert.Equal(t, "batch_id,period,trip_id,traveler_id,destination,cost_center,gl_account,category,expense_date,description,amount,currency", lines[0])
+	assert.Equal(t, "00000000-0000-0000-0000-0000000000b1,2024-05,00000000-0000-0000-0000-0000000000a1,00000000-0000-0000-0000-0000000000c1,"+
+		"São Paulo,CC-100,4.1.02.003,lodging,2024-05-20,\"Hotel, 2 nights\nbreakfast included\",1234.56,BRL\n", lines[1])
+}
+
+func TestAccountingExportBatch_WriteFixedWidth(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := accountingExportBatch().WriteFixedWidth(&buf)
+
+	// Assert
+	assert.NoError(t, err)
+	records := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, records, 2)
+	assert.Equal(t, "H20240500000000-0000-0000-0000-0000000000b120240603000001", records[0])
+	assert.Equal(t, "D00000000-0000-0000-0000-0000000000a100000000-0000-0000-0000-0000000000c1"+
+		"CC-100              4.1.02.003          lodging         20240520000000000123456BRL"+
+		"Hotel, 2 nights breakfast included      ", records[1])
+	assert.Equal(t, 1+36+36+20+20+16+8+15+3+40, len(records[1]))
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPolicy adds approval steps to the trips it matches. A policy without
+// conditions matches every trip of its organization.
+type ApprovalPolicy struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+	Name  string    `json:"name"`
+	// Priority orders the steps of different policies, lowest first
+	Priority int `json:"priority"`
+	// Destinations matches trips whose destination contains any of the values, ignoring case
+	Destinations []string `json:"destinations"`
+	// MinDurationDays matches trips lasting at least this many days
+	MinDurationDays int `json:"min_duration_days"`
+	// TripType matches only domestic or only international trips; empty matches both
+	TripType  TripType   `json:"trip_type,omitempty"`
+	Steps     []UserRole `json:"steps"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// MinBudget matches trips whose budget totals at least this amount, in
+	// minor units of BudgetCurrency, converted to it when needed. Trips without
+	// a budget, or whose budget can't be compared, match too, so leaving the
+	// budget out doesn't skip approvals.
+	MinBudget      int64  `json:"min_budget,omitempty"`
+	BudgetCurrency string `json:"budget_currency,omitempty"`
+}
+
+// Validate checks if the approval policy data is valid according to business rules
+func (p *ApprovalPolicy) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(p.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(p.Name == "", "name is required")
+	validationErrors.AddIf(p.MinDurationDays < 0, "min_duration_days cannot be negative")
+	validationErrors.AddIf(p.TripType != "" && !p.TripType.IsValid(), "trip_type must be domestic or international")
+	validationErrors.AddIf(p.MinBudget < 0, "min_budget cannot be negative")
+	validationErrors.AddIf(p.MinBudget > 0 && !currencyCodePattern.MatchString(p.BudgetCurrency), "budget_currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(len(p.Steps) == 0, "steps are required")
+	for _, role := range p.Steps {
+		if !role.IsValid() || role == RoleEmployee {
+			validationErrors.Add("steps must be manager, director, finance or admin")
+			break
+		}
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// Matches reports whether the policy applies to the trip
+func (p *ApprovalPolicy) Matches(trip *Trip) bool {
+	if p.TripType != "" && trip.Type != p.TripType {
+		return false
+	}
+
+	if len(p.Destinations) > 0 {
+		destination := strings.ToLower(trip.Destination)
+		found := false
+		for _, d := range p.Destinations {
+			if d != "" && strings.Contains(destination, strings.ToLower(d)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p.MinDurationDays > 0 && trip.EndDate.Sub(trip.StartDate) < time.Duration(p.MinDurationDays)*24*time.Hour {
+		return false
+	}
+
+	if p.MinBudget > 0 && trip.Budget != nil {
+		if total, ok := trip.Budget.TotalIn(p.BudgetCurrency); ok && total < p.MinBudget {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BuildApprovalChain returns the roles that must approve the trip, in order.
+// Steps of the matching policies are concatenated by priority and each role
+// appears only once. Trips no policy matches need a single manager approval.
+func BuildApprovalChain(policies []*ApprovalPolicy, trip *Trip) []UserRole {
+	matching := make([]*ApprovalPolicy, 0, len(policies))
+	for _, p := range policies {
+		if p.Matches(trip) {
+			matching = append(matching, p)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Priority < matching[j].Priority
+	})
+
+	var chain []UserRole
+	seen := make(map[UserRole]bool)
+	for _, p := range matching {
+		for _, role := range p.Steps {
+			if !seen[role] {
+				seen[role] = true
+				chain = append(chain, role)
+			}
+		}
+	}
+
+	if len(chain) == 0 {
+		return []UserRole{RoleManager}
+	}
+	return chain
+}
+
+// ErrStepAlreadyDecided is returned when someone else decided the approval step first
+var ErrStepAlreadyDecided = errors.New("approval step was already decided")
+
+type ApprovalStepStatus string
+
+const (
+	StepPending  ApprovalStepStatus = "pending"
+	StepApproved ApprovalStepStatus = "approved"
+	StepRejected ApprovalStepStatus = "rejected"
+)
+
+// ApprovalStep is one decision a trip needs before it's approved
+type ApprovalStep struct {
+	ID       uuid.UUID `json:"id"`
+	OrgID    uuid.UUID `json:"org_id"`
+	TripID   uuid.UUID `json:"trip_id"`
+	Position int       `json:"position"`
+	Role     UserRole  `json:"role"`
+	// ApproverID restricts the step to a single user, e.g. the requester's manager
+	ApproverID *uuid.UUID         `json:"approver_id,omitempty"`
+	Status     ApprovalStepStatus `json:"status"`
+	DecidedBy  *uuid.UUID         `json:"decided_by,omitempty"`
+	DecidedAt  *time.Time         `json:"decided_at,omitempty"`
+	// RemindedAt is when the approver was reminded of the step running late
+	RemindedAt *time.Time `json:"reminded_at,omitempty"`
+	// EscalatedAt is when the step was last handed up to the approver's manager
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CanBeDecidedBy reports whether the user is allowed to approve or reject the step
+func (s *ApprovalStep) CanBeDecidedBy(user *User) bool {
+	if user.Role == RoleAdmin {
+		return true
+	}
+	if s.ApproverID != nil {
+		return *s.ApproverID == user.ID
+	}
+	return user.Role == s.Role
+}
+
+// CurrentApprovalStep returns the first pending step, or nil when every step was decided
+func CurrentApprovalStep(steps []*ApprovalStep) *ApprovalStep {
+	for _, step := range steps {
+		if step.Status == StepPending {
+			return step
+		}
+	}
+	return nil
+}
+
+type ApprovalPolicyRepository interface {
+	Create(ctx context.Context, policy *ApprovalPolicy) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]*ApprovalPolicy, error)
+}
+
+type ApprovalStepRepository interface {
+	CreateSteps(ctx context.Context, steps []*ApprovalStep) error
+	// FindByTripID returns the steps of a trip ordered by position
+	FindByTripID(ctx context.Context, tripID uuid.UUID) ([]*ApprovalStep, error)
+	// UpdateDecision saves the decision on a pending step, returning
+	// ErrStepAlreadyDecided when the step isn't pending anymore
+	UpdateDecision(ctx context.Context, step *ApprovalStep) error
+	DeleteByTripID(ctx context.Context, tripID uuid.UUID) error
+	// ListCurrent returns the current step of every trip waiting on the given
+	// approver, either directly or through their role
+	ListCurrent(ctx context.Context, approverID uuid.UUID, role UserRole) ([]*ApprovalStep, error)
+	// ListStale returns the current step of every requested trip that has been
+	// waiting on its approver since before the given moment. A step waits from
+	// the decision of the previous step, or from its last escalation.
+	ListStale(ctx context.Context, waitingSince time.Time) ([]*ApprovalStep, error)
+	// UpdateEscalation saves the approver and reminder timestamps of a pending step
+	UpdateEscalation(ctx context.Context, step *ApprovalStep) error
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalPolicy_Validate(t *testing.T) {
+	t.Run("Valid policy", func(t *testing.T) {
+		policy := &ApprovalPolicy{
+			OrgID: uuid.New(),
+			Name:  "International",
+			Steps: []UserRole{RoleManager, RoleDirector},
+		}
+
+		err := policy.Validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Employees cannot approve", func(t *testing.T) {
+		policy := &ApprovalPolicy{
+			OrgID: uuid.New(),
+			Name:  "Peer review",
+			Steps: []UserRole{RoleEmployee},
+		}
+
+		err := policy.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "steps must be manager, director, finance or admin")
+	})
+
+	t.Run("Multiple validation errors", func(t *testing.T) {
+		policy := &ApprovalPolicy{MinDurationDays: -1, TripType: "abroad"}
+
+		err := policy.Validate()
+		assert.Error(t, err)
+
+		errMsg := err.Error()
+		assert.Contains(t, errMsg, "org_id is required")
+		assert.Contains(t, errMsg, "name is required")
+		assert.Contains(t, errMsg, "min_duration_days cannot be negative")
+		assert.Contains(t, errMsg, "trip_type must be domestic or international")
+		assert.Contains(t, errMsg, "steps are required")
+	})
+}
+
+func TestBuildApprovalChain(t *testing.T) {
+	start := time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC)
+	trip := &Trip{Destination: "Lisboa, Portugal", StartDate: start, EndDate: start.AddDate(0, 0, 10)}
+
+	t.Run("Defaults to a single manager step", func(t *testing.T) {
+		chain := BuildApprovalChain(nil, trip)
+		assert.Equal(t, []UserRole{RoleManager}, chain)
+	})
+
+	t.Run("Concatenates matching policies by priority without repeating roles", func(t *testing.T) {
+		policies := []*ApprovalPolicy{
+			{Name: "Long trips", Priority: 20, MinDurationDays: 7, Steps: []UserRole{RoleManager, RoleFinance}},
+			{Name: "International", Priority: 10, Destinations: []string{"portugal"}, Steps: []UserRole{RoleManager, RoleDirector}},
+		}
+
+		chain := BuildApprovalChain(policies, trip)
+		assert.Equal(t, []UserRole{RoleManager, RoleDirector, RoleFinance}, chain)
+	})
+
+	t.Run("Ignores policies that don't match", func(t *testing.T) {
+		policies := []*ApprovalPolicy{
+			{Name: "International", Destinations: []string{"Berlin"}, Steps: []UserRole{RoleDirector}},
+			{Name: "Very long trips", MinDurationDays: 30, Steps: []UserRole{RoleFinance}},
+		}
+
+		chain := BuildApprovalChain(policies, trip)
+		assert.Equal(t, []UserRole{RoleManager}, chain)
+	})
+
+	t.Run("International trips", func(t *testing.T) {
+		policies := []*ApprovalPolicy{
+			{Name: "International", TripType: TripInternational, Steps: []UserRole{RoleManager, RoleDirector}},
+		}
+		international := &Trip{Type: TripInternational, Destination: "Buenos Aires", StartDate: start, EndDate: start.AddDate(0, 0, 3)}
+		domestic := &Trip{Type: TripDomestic, Destination: "Recife", StartDate: start, EndDate: start.AddDate(0, 0, 3)}
+
+		assert.Equal(t, []UserRole{RoleManager, RoleDirector}, BuildApprovalChain(policies, international))
+		assert.Equal(t, []UserRole{RoleManager}, BuildApprovalChain(policies, domestic))
+	})
+
+	t.Run("Budget threshold", func(t *testing.T) {
+		policies := []*ApprovalPolicy{
+			{Name: "Expensive trips", MinBudget: 1000000, BudgetCurrency: "BRL", Steps: []UserRole{RoleFinance}},
+		}
+		budgetOf := func(currency string, amount int64) *Trip {
+			return &Trip{Destination: "Recife", StartDate: start, EndDate: start.AddDate(0, 0, 2),
+				Budget: &TripBudget{Currency: currency, Items: []BudgetItem{{Category: BudgetAirfare, Amount: amount}}}}
+		}
+
+		assert.Equal(t, []UserRole{RoleManager}, BuildApprovalChain(policies, budgetOf("BRL", 999999)))
+		assert.Equal(t, []UserRole{RoleFinance}, BuildApprovalChain(policies, budgetOf("BRL", 1000000)))
+		converted := budgetOf("EUR", 100000)
+		converted.Budget.Converted = &BudgetConversion{Total: Money{Amount: 550000, Currency: "BRL"}, Rate: 5.5}
+		assert.Equal(t, []UserRole{RoleManager}, BuildApprovalChain(policies, converted))
+		// Trips whose cost is unknown don't skip the policy
+		assert.Equal(t, []UserRole{RoleFinance}, BuildApprovalChain(policies, budgetOf("EUR", 100)))
+		assert.Equal(t, []UserRole{RoleFinance}, BuildApprovalChain(policies, trip))
+	})
+}
+
+func TestApprovalStep_CanBeDecidedBy(t *testing.T) {
+	managerID := uuid.New()
+
+	t.Run("Role based step", func(t *testing.T) {
+		step := &ApprovalStep{Role: RoleDirector}
+		assert.True(t, step.CanBeDecidedBy(&User{ID: uuid.New(), Role: RoleDirector}))
+		assert.False(t, step.CanBeDecidedBy(&User{ID: uuid.New(), Role: RoleManager}))
+	})
+
+	t.Run("Assigned step", func(t *testing.T) {
+		step := &ApprovalStep{Role: RoleManager, ApproverID: &managerID}
+		assert.True(t, step.CanBeDecidedBy(&User{ID: managerID, Role: RoleManager}))
+		assert.False(t, step.CanBeDecidedBy(&User{ID: uuid.New(), Role: RoleManager}))
+	})
+
+	t.Run("Admins can decide any step", func(t *testing.T) {
+		step := &ApprovalStep{Role: RoleManager, ApproverID: &managerID}
+		assert.True(t, step.CanBeDecidedBy(&User{ID: uuid.New(), Role: RoleAdmin}))
+	})
+}
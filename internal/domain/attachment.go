@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ErrBlobNotFound is returned by blob stores for keys they don't have
+var ErrBlobNotFound = errors.New("blob not found")
+
+// attachmentTypes are the content types accepted for attachments: receipts are
+// scanned documents or photos
+var attachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+}
+
+var checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Attachment is a file uploaded to a trip, e.g. a boarding pass, or to one of
+// its expenses, e.g. the receipt
+type Attachment struct {
+	ID     uuid.UUID `json:"id"`
+	OrgID  uuid.UUID `json:"org_id"`
+	TripID uuid.UUID `json:"trip_id"`
+	// ExpenseItemID links the attachment to an expense of the trip's expense report
+	ExpenseItemID *uuid.UUID `json:"expense_item_id,omitempty"`
+	UploadedBy    uuid.UUID  `json:"uploaded_by"`
+	FileName      string     `json:"file_name"`
+	// ContentType is sniffed from the content, the one sent by the client is ignored
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Checksum is the hex SHA-256 of the content
+	Checksum string `json:"checksum"`
+	// StorageKey locates the content in the blob store. Attachments with the
+	// same content in an organization share it.
+	StorageKey string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Validate checks if the attachment data is valid according to business rules
+func (a *Attachment) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(a.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(a.TripID == uuid.Nil, "trip_id is required")
+	validationErrors.AddIf(a.UploadedBy == uuid.Nil, "uploaded_by is required")
+	validationErrors.AddIf(a.FileName == "", "file_name is required")
+	validationErrors.AddIf(!attachmentTypes[a.ContentType], "content_type must be application/pdf, image/jpeg, image/png or image/webp")
+	validationErrors.AddIf(a.Size <= 0, "the file is empty")
+	validationErrors.AddIf(!checksumPattern.MatchString(a.Checksum), "checksum must be a hex SHA-256")
+	validationErrors.AddIf(a.StorageKey == "", "storage_key is required")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// SameTarget reports whether the attachment belongs to the same trip and expense as other
+func (a *Attachment) SameTarget(other *Attachment) bool {
+	if a.TripID != other.TripID {
+		return false
+	}
+	if a.ExpenseItemID == nil || other.ExpenseItemID == nil {
+		return a.ExpenseItemID == nil && other.ExpenseItemID == nil
+	}
+	return *a.ExpenseItemID == *other.ExpenseItemID
+}
+
+// SniffAttachmentType detects the content type from the first bytes of the
+// content and reports whether it's accepted for attachments
+func SniffAttachmentType(content []byte) (string, bool) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(content))
+	if err != nil {
+		return "", false
+	}
+	return contentType, attachmentTypes[contentType]
+}
+
+// AttachmentStorageKey is where the content with the checksum is stored for
+// the organization, so the same file is stored once
+func AttachmentStorageKey(orgID uuid.UUID, checksum string) string {
+	return "attachments/" + orgID.String() + "/" + checksum
+}
+
+// CleanFileName keeps the base name of an uploaded file without control
+// characters or quotes, which would break the Content-Disposition header
+func CleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *Attachment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	// ListByTripID returns the attachments of the trip, oldest first
+	ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*Attachment, error)
+	// FindByChecksum returns the attachments of the organization with the content, oldest first
+	FindByChecksum(ctx context.Context, checksum string) ([]*Attachment, error)
+}
+
+// BlobStore keeps the content of attachments, e.g. on the local filesystem or
+// in an S3-compatible bucket
+type BlobStore interface {
+	// Put stores the content under the key, replacing what was there
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get returns the content of the key, or ErrBlobNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobURLSigner is implemented by the blob stores clients can download from
+// directly. Downloads from other stores go through the API.
+type BlobURLSigner interface {
+	// SignedURL returns a URL that downloads the blob as fileName without credentials until expiresAt
+	SignedURL(ctx context.Context, key, fileName string, expiresAt time.Time) (string, error)
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffAttachmentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     []byte
+		contentType string
+		accepted    bool
+	}{
+		{"PDF", []byte("%PDF-1.7\n"), "application/pdf", true},
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", true},
+		{"JPEG", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), "image/jpeg", true},
+		{"WebP", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), "image/webp", true},
+		{"HTML", []byte("<html><body>receipt</body></html>"), "text/html", false},
+		{"Text", []byte("just text"), "text/plain", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, accepted := domain.SniffAttachmentType(tt.content)
+			assert.Equal(t, tt.contentType, contentType)
+			assert.Equal(t, tt.accepted, accepted)
+		})
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	assert.Equal(t, "recibo.pdf", domain.CleanFileName("recibo.pdf"))
+	assert.Equal(t, "recibo.pdf", domain.CleanFileName(`C:\Users\ana\recibo.pdf`))
+	assert.Equal(t, "passwd", domain.CleanFileName("../../etc/passwd"))
+	assert.Equal(t, "recibo hotel.pdf", domain.CleanFileName("recibo \"hotel\"\r\n.pdf"))
+	assert.Equal(t, "attachment", domain.CleanFileName("  "))
+	assert.Len(t, []rune(domain.CleanFileName(strings.Repeat("á", 300)+".pdf")), 255)
+}
+
+func TestAttachment_SameTarget(t *testing.T) {
+	tripID := uuid.New()
+	itemID := uuid.New()
+	otherItemID := uuid.New()
+
+	trip := &domain.Attachment{TripID: tripID}
+	item := &domain.Attachment{TripID: tripID, ExpenseItemID: &itemID}
+
+	assert.True(t, trip.SameTarget(&domain.Attachment{TripID: tripID}))
+	assert.False(t, trip.SameTarget(&domain.Attachment{TripID: uuid.New()}))
+	assert.False(t, trip.SameTarget(item))
+	assert.True(t, item.SameTarget(&domain.Attachment{TripID: tripID, ExpenseItemID: &itemID}))
+	assert.False(t, item.SameTarget(&domain.Attachment{TripID: tripID, ExpenseItemID: &otherItemID}))
+}
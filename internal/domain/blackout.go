@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleBlackout marks the violations of blackout calendars. It isn't a rule of the travel policy.
+const RuleBlackout TravelRuleType = "blackout"
+
+// BlackoutPeriod is a range of dates nobody should travel in, e.g. a fiscal
+// close or a holiday. EndsAt is exclusive, like DTEND in iCalendar.
+type BlackoutPeriod struct {
+	Summary  string    `json:"summary"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Overlaps reports whether a trip between start and end, both days included, falls in the period
+func (p BlackoutPeriod) Overlaps(start, end time.Time) bool {
+	return !p.StartsAt.After(end) && start.Before(p.EndsAt)
+}
+
+// BlackoutCalendar groups the blackout periods of an organization, or of a
+// single department when DepartmentID is set. Its severity decides whether
+// trips in a period are rejected or only flagged to the approvers.
+type BlackoutCalendar struct {
+	ID           uuid.UUID        `json:"id"`
+	OrgID        uuid.UUID        `json:"org_id"`
+	DepartmentID *uuid.UUID       `json:"department_id,omitempty"`
+	Name         string           `json:"name"`
+	Severity     RuleSeverity     `json:"severity"`
+	Periods      []BlackoutPeriod `json:"periods"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// Validate checks if the calendar data is valid according to business rules
+func (c *BlackoutCalendar) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(c.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(c.Name == "", "name is required")
+	validationErrors.AddIf(c.Severity != SeverityBlock && c.Severity != SeverityWarn, "severity must be block or warn")
+	for i, period := range c.Periods {
+		prefix := fmt.Sprintf("periods[%d]: ", i)
+		validationErrors.AddIf(period.StartsAt.IsZero(), prefix+"starts_at is required")
+		validationErrors.AddIf(period.EndsAt.IsZero(), prefix+"ends_at is required")
+		if !period.StartsAt.IsZero() && !period.EndsAt.IsZero() {
+			validationErrors.AddIf(!period.EndsAt.After(period.StartsAt), prefix+"ends_at must be after starts_at")
+		}
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// AppliesTo reports whether the calendar covers travelers of the department.
+// Organization-wide calendars cover everyone.
+func (c *BlackoutCalendar) AppliesTo(departmentID *uuid.UUID) bool {
+	return c.DepartmentID == nil || (departmentID != nil && *c.DepartmentID == *departmentID)
+}
+
+// Evaluate returns a violation for each period of the calendar the trip falls in
+func (c *BlackoutCalendar) Evaluate(trip *Trip) []PolicyViolation {
+	var violations []PolicyViolation
+	for _, period := range c.Periods {
+		if !period.Overlaps(trip.StartDate, trip.EndDate) {
+			continue
+		}
+		// DTEND is exclusive, show the last day of the period instead
+		lastDay := period.EndsAt.Add(-time.Nanosecond)
+		violations = append(violations, PolicyViolation{
+			Rule:     RuleBlackout,
+			Severity: c.Severity,
+			Message: fmt.Sprintf("trip falls in the blackout period %q of the %s calendar (%s to %s)",
+				period.Summary, c.Name, period.StartsAt.Format("02 Jan 2006"), lastDay.Format("02 Jan 2006")),
+		})
+	}
+	return violations
+}
+
+type BlackoutCalendarRepository interface {
+	Create(ctx context.Context, calendar *BlackoutCalendar) error
+	Update(ctx context.Context, calendar *BlackoutCalendar) error
+	FindByID(ctx context.Context, id uuid.UUID) (*BlackoutCalendar, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]*BlackoutCalendar, error)
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseICalendar(t *testing.T) {
+	t.Run("All-day and timed events", func(t *testing.T) {
+		document := []byte("BEGIN:VCALENDAR\r\n" +
+			"VERSION:2.0\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Fechamento fiscal\\, Q1\r\n" +
+			"DTSTART;VALUE=DATE:20300328\r\n" +
+			"DTEND;VALUE=DATE:20300402\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Carnaval\r\n" +
+			"DTSTART;VALUE=DATE:20300305\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:All-hands meeting with the\r\n" +
+			"  whole company\r\n" +
+			"DTSTART;TZID=America/Sao_Paulo:20300410T090000\r\n" +
+			"DURATION:PT8H\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Moved\r\n" +
+			"STATUS:CANCELLED\r\n" +
+			"DTSTART:20300501T120000Z\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n")
+
+		periods, err := ParseICalendar(document)
+		require.NoError(t, err)
+		require.Len(t, periods, 3)
+
+		assert.Equal(t, "Carnaval", periods[0].Summary)
+		assert.Equal(t, time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC), periods[0].StartsAt)
+		assert.Equal(t, time.Date(2030, 3, 6, 0, 0, 0, 0, time.UTC), periods[0].EndsAt)
+
+		assert.Equal(t, "Fechamento fiscal, Q1", periods[1].Summary)
+		assert.Equal(t, time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC), periods[1].EndsAt)
+
+		assert.Equal(t, "All-hands meeting with the whole company", periods[2].Summary)
+		assert.Equal(t, time.Date(2030, 4, 10, 12, 0, 0, 0, time.UTC), periods[2].StartsAt.UTC())
+		assert.Equal(t, 8*time.Hour, periods[2].EndsAt.Sub(periods[2].StartsAt))
+	})
+
+	t.Run("Recurring and incomplete events", func(t *testing.T) {
+		document := []byte(`BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:Weekly sync
+DTSTART:20300101T100000Z
+DTEND:20300101T110000Z
+RRULE:FREQ=WEEKLY
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:No start
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=Mars/Olympus:20300101T100000
+DTEND:20300101T110000Z
+END:VEVENT
+END:VCALENDAR
+`)
+
+		_, err := ParseICalendar(document)
+		require.Error(t, err)
+
+		errMsg := err.Error()
+		assert.Contains(t, errMsg, "event 1: recurring events are not supported")
+		assert.Contains(t, errMsg, "event 2: DTSTART is required")
+		assert.Contains(t, errMsg, `event 3: invalid DTSTART: unknown time zone "Mars/Olympus"`)
+	})
+
+	t.Run("Not a calendar", func(t *testing.T) {
+		_, err := ParseICalendar([]byte(`{"periods": []}`))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing BEGIN:VCALENDAR")
+	})
+
+	t.Run("No events", func(t *testing.T) {
+		_, err := ParseICalendar([]byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "the calendar has no events")
+	})
+}
+
+func TestBlackoutCalendar_Validate(t *testing.T) {
+	calendar := &BlackoutCalendar{
+		Severity: "maybe",
+		Periods: []BlackoutPeriod{
+			{StartsAt: time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	err := calendar.Validate()
+	assert.Error(t, err)
+
+	errMsg := err.Error()
+	assert.Contains(t, errMsg, "org_id is required")
+	assert.Contains(t, errMsg, "name is required")
+	assert.Contains(t, errMsg, "severity must be block or warn")
+	assert.Contains(t, errMsg, "periods[0]: ends_at must be after starts_at")
+}
+
+func TestBlackoutCalendar_Evaluate(t *testing.T) {
+	departmentID := uuid.New()
+	calendar := &BlackoutCalendar{
+		Name:     "Finance",
+		Severity: SeverityWarn,
+		Periods: []BlackoutPeriod{
+			{Summary: "Fiscal close", StartsAt: time.Date(2030, 3, 28, 0, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		DepartmentID: &departmentID,
+	}
+
+	t.Run("Trip in a period", func(t *testing.T) {
+		trip := &Trip{StartDate: time.Date(2030, 3, 25, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 3, 28, 0, 0, 0, 0, time.UTC)}
+
+		violations := calendar.Evaluate(trip)
+		require.Len(t, violations, 1)
+		assert.Equal(t, RuleBlackout, violations[0].Rule)
+		assert.Equal(t, SeverityWarn, violations[0].Severity)
+		assert.Equal(t, `trip falls in the blackout period "Fiscal close" of the Finance calendar (28 Mar 2030 to 01 Apr 2030)`, violations[0].Message)
+	})
+
+	t.Run("Trip starting when a period ends", func(t *testing.T) {
+		trip := &Trip{StartDate: time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 4, 5, 0, 0, 0, 0, time.UTC)}
+		assert.Empty(t, calendar.Evaluate(trip))
+	})
+
+	t.Run("Department scope", func(t *testing.T) {
+		otherDepartmentID := uuid.New()
+		assert.True(t, calendar.AppliesTo(&departmentID))
+		assert.False(t, calendar.AppliesTo(&otherDepartmentID))
+		assert.False(t, calendar.AppliesTo(nil))
+		assert.True(t, (&BlackoutCalendar{}).AppliesTo(nil))
+	})
+}
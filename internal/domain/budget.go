@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+type BudgetCategory string
+
+const (
+	BudgetAirfare         BudgetCategory = "airfare"
+	BudgetLodging         BudgetCategory = "lodging"
+	BudgetPerDiem         BudgetCategory = "per_diem"
+	BudgetGroundTransport BudgetCategory = "ground_transport"
+	BudgetOther           BudgetCategory = "other"
+)
+
+func (c BudgetCategory) IsValid() bool {
+	switch c {
+	case BudgetAirfare, BudgetLodging, BudgetPerDiem, BudgetGroundTransport, BudgetOther:
+		return true
+	}
+	return false
+}
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// BudgetItem is the estimated cost of one category of a trip
+type BudgetItem struct {
+	Category BudgetCategory `json:"category"`
+	// Amount is in minor units of the budget currency, e.g. cents: 150000 is BRL 1,500.00
+	Amount      int64  `json:"amount"`
+	Description string `json:"description,omitempty"`
+}
+
+// TripBudget is the estimated cost of a trip, so approvers know what they approve
+type TripBudget struct {
+	// Currency is an ISO 4217 code, e.g. BRL
+	Currency string       `json:"currency"`
+	Items    []BudgetItem `json:"items"`
+	// Converted is the total in the organization's base currency, when the
+	// budget is in another one. It's taken when the trip is requested.
+	Converted *BudgetConversion `json:"converted,omitempty"`
+}
+
+// BudgetConversion keeps the rate a budget was converted with, for auditing
+type BudgetConversion struct {
+	Total       Money     `json:"total"`
+	Rate        float64   `json:"rate"`
+	RateDate    time.Time `json:"rate_date"`
+	ConvertedAt time.Time `json:"converted_at"`
+}
+
+// Total is the sum of the items, in minor units of the budget currency
+func (b *TripBudget) Total() int64 {
+	var total int64
+	for _, item := range b.Items {
+		total += item.Amount
+	}
+	return total
+}
+
+// TotalMoney is the total in the budget currency
+func (b *TripBudget) TotalMoney() Money {
+	return Money{Amount: b.Total(), Currency: b.Currency}
+}
+
+// TotalIn returns the total in the currency, when it's the budget currency or
+// the one the budget was converted to
+func (b *TripBudget) TotalIn(currency string) (int64, bool) {
+	if b.Currency == currency {
+		return b.Total(), true
+	}
+	if b.Converted != nil && b.Converted.Total.Currency == currency {
+		return b.Converted.Total.Amount, true
+	}
+	return 0, false
+}
+
+// ConvertWith records the total in the base currency of the rate
+func (b *TripBudget) ConvertWith(rate *ExchangeRate, at time.Time) {
+	b.Converted = &BudgetConversion{
+		Total:       rate.Convert(b.TotalMoney()),
+		Rate:        rate.Rate,
+		RateDate:    rate.EffectiveDate,
+		ConvertedAt: at,
+	}
+}
+
+// MarshalJSON adds the total to the items
+func (b TripBudget) MarshalJSON() ([]byte, error) {
+	type budget TripBudget
+	return json.Marshal(struct {
+		budget
+		Total int64 `json:"total"`
+	}{budget(b), b.Total()})
+}
+
+func (b *TripBudget) validate(validationErrors *ValidationErrors) {
+	validationErrors.AddIf(!currencyCodePattern.MatchString(b.Currency), "budget: currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(len(b.Items) == 0, "budget: items are required")
+	for i, item := range b.Items {
+		prefix := fmt.Sprintf("budget.items[%d]: ", i)
+		validationErrors.AddIf(!item.Category.IsValid(), prefix+"category must be airfare, lodging, per_diem, ground_transport or other")
+		validationErrors.AddIf(item.Amount <= 0, prefix+"amount must be positive")
+	}
+}
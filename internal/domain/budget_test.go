@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTripBudget(t *testing.T) {
+	budget := &TripBudget{Currency: "BRL", Items: []BudgetItem{
+		{Category: BudgetAirfare, Amount: 150000},
+		{Category: BudgetLodging, Amount: 80000},
+		{Category: BudgetPerDiem, Amount: 25050},
+	}}
+
+	t.Run("Total", func(t *testing.T) {
+		assert.Equal(t, int64(255050), budget.Total())
+
+		data, err := json.Marshal(budget)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"total":255050`)
+	})
+
+	t.Run("Valid budget", func(t *testing.T) {
+		validationErrors := NewValidationErrors()
+		budget.validate(validationErrors)
+		assert.False(t, validationErrors.HasErrors())
+	})
+
+	t.Run("Invalid budget", func(t *testing.T) {
+		invalid := &TripBudget{Currency: "real", Items: []BudgetItem{
+			{Category: BudgetLodging, Amount: 0},
+			{Category: "snacks", Amount: 1000},
+		}}
+
+		validationErrors := NewValidationErrors()
+		invalid.validate(validationErrors)
+		errMsg := validationErrors.Error()
+		assert.Contains(t, errMsg, "budget: currency must be an ISO 4217 code, e.g. BRL")
+		assert.Contains(t, errMsg, "budget.items[0]: amount must be positive")
+		assert.Contains(t, errMsg, "budget.items[1]: category must be airfare, lodging, per_diem, ground_transport or other")
+	})
+
+	t.Run("Empty budget", func(t *testing.T) {
+		validationErrors := NewValidationErrors()
+		(&TripBudget{Currency: "BRL"}).validate(validationErrors)
+		assert.Contains(t, validationErrors.Error(), "budget: items are required")
+	})
+}
+
+func TestTripBudget_ConvertWith(t *testing.T) {
+	budget := &TripBudget{Currency: "EUR", Items: []BudgetItem{{Category: BudgetLodging, Amount: 100000}}}
+	rateDate := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	budget.ConvertWith(&ExchangeRate{Currency: "EUR", BaseCurrency: "BRL", Rate: 5.5, EffectiveDate: rateDate}, time.Now())
+
+	require.NotNil(t, budget.Converted)
+	assert.Equal(t, Money{Amount: 550000, Currency: "BRL"}, budget.Converted.Total)
+	assert.Equal(t, rateDate, budget.Converted.RateDate)
+
+	total, ok := budget.TotalIn("BRL")
+	assert.True(t, ok)
+	assert.Equal(t, int64(550000), total)
+	total, ok = budget.TotalIn("EUR")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100000), total)
+	_, ok = budget.TotalIn("USD")
+	assert.False(t, ok)
+}
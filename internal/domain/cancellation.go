@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultCancellationNoticeDays is the notice required when the travel policy doesn't set one
+const DefaultCancellationNoticeDays = 7
+
+// CancellationPolicy sets how many days before the start of a trip it can
+// still be cancelled. Rules override the organization default for trips of
+// the given types or destinations; the first matching rule wins.
+type CancellationPolicy struct {
+	NoticeDays *int `json:"notice_days,omitempty" yaml:"notice_days,omitempty"`
+	// TimeZone is the IANA zone days are counted in when no rule sets one
+	TimeZone string             `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
+	Rules    []CancellationRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// CancellationRule applies to trips of any of TripTypes heading to a region:
+// any of Countries or of DestinationCodes. Regions are matched on the codes of
+// the destination catalog, so trips without a catalog destination only match
+// rules without a region. Empty conditions match every trip.
+type CancellationRule struct {
+	TripTypes []TripType `json:"trip_types,omitempty" yaml:"trip_types,omitempty"`
+	// Countries are ISO 3166-1 alpha-2 codes, e.g. PT
+	Countries []string `json:"countries,omitempty" yaml:"countries,omitempty"`
+	// DestinationCodes are IATA city codes, e.g. LIS
+	DestinationCodes []string `json:"destination_codes,omitempty" yaml:"destination_codes,omitempty"`
+	NoticeDays       *int     `json:"notice_days" yaml:"notice_days"`
+	// TimeZone is the IANA zone of the destinations, e.g. Europe/Lisbon
+	TimeZone string `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
+}
+
+func (p *CancellationPolicy) validate(validationErrors *ValidationErrors) {
+	validationErrors.AddIf(p.NoticeDays != nil && *p.NoticeDays < 0, "cancellation: notice_days cannot be negative")
+	if p.TimeZone != "" {
+		_, ok := loadTimeZone(p.TimeZone)
+		validationErrors.AddIf(!ok, "cancellation: unknown time_zone "+p.TimeZone)
+	}
+
+	for i, rule := range p.Rules {
+		prefix := fmt.Sprintf("cancellation.rules[%d]: ", i)
+		validationErrors.AddIf(rule.NoticeDays == nil, prefix+"notice_days is required")
+		validationErrors.AddIf(rule.NoticeDays != nil && *rule.NoticeDays < 0, prefix+"notice_days cannot be negative")
+		for _, tripType := range rule.TripTypes {
+			if !tripType.IsValid() {
+				validationErrors.Add(prefix + "trip_types must be domestic or international")
+				break
+			}
+		}
+		for _, country := range rule.Countries {
+			if !countryCodePattern.MatchString(country) {
+				validationErrors.Add(prefix + "countries must be ISO 3166-1 alpha-2 codes, e.g. PT")
+				break
+			}
+		}
+		for _, code := range rule.DestinationCodes {
+			if !cityCodePattern.MatchString(code) {
+				validationErrors.Add(prefix + "destination_codes must be IATA city codes, e.g. LIS")
+				break
+			}
+		}
+		if rule.TimeZone != "" {
+			_, ok := loadTimeZone(rule.TimeZone)
+			validationErrors.AddIf(!ok, prefix+"unknown time_zone "+rule.TimeZone)
+		}
+	}
+}
+
+// matches checks the rule against the trip and the catalog entry of its destination, nil when it has none
+func (r *CancellationRule) matches(trip *Trip, destination *Destination) bool {
+	if len(r.TripTypes) > 0 && !slices.Contains(r.TripTypes, trip.Type) {
+		return false
+	}
+	if len(r.Countries) == 0 && len(r.DestinationCodes) == 0 {
+		return true
+	}
+	if destination == nil {
+		return false
+	}
+	return slices.Contains(r.Countries, destination.CountryCode) || slices.Contains(r.DestinationCodes, destination.ReferenceCode())
+}
+
+// CancellationDeadline returns the moment from which the trip can no longer be
+// cancelled without an override. Days are counted in the destination's time
+// zone: with 7 days of notice, a trip starting on the 10th can be cancelled
+// until the end of the 2nd, local time. The trip's own time zone wins over the
+// policy's. destination is the catalog entry of the trip's destination, nil
+// when it has none. A nil policy requires the default notice in UTC.
+func (p *CancellationPolicy) CancellationDeadline(trip *Trip, destination *Destination) time.Time {
+	noticeDays := DefaultCancellationNoticeDays
+	timeZone := ""
+	if p != nil {
+		if p.NoticeDays != nil {
+			noticeDays = *p.NoticeDays
+		}
+		timeZone = p.TimeZone
+		for _, rule := range p.Rules {
+			if rule.NoticeDays != nil && rule.matches(trip, destination) {
+				noticeDays = *rule.NoticeDays
+				if rule.TimeZone != "" {
+					timeZone = rule.TimeZone
+				}
+				break
+			}
+		}
+	}
+
+	if trip.TimeZone != "" {
+		timeZone = trip.TimeZone
+	}
+
+	// Time zones are checked when the policy and the trip are saved
+	loc, ok := loadTimeZone(timeZone)
+	if !ok {
+		loc = time.UTC
+	}
+
+	start := trip.StartDate.In(loc)
+	startOfDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	return startOfDay.AddDate(0, 0, -noticeDays)
+}
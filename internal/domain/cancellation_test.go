@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellationPolicy_CancellationDeadline(t *testing.T) {
+	fourteen := 14
+	two := 2
+	policy := &CancellationPolicy{
+		NoticeDays: &two,
+		TimeZone:   "America/Sao_Paulo",
+		Rules: []CancellationRule{
+			{TripTypes: []TripType{TripInternational}, Countries: []string{"JP"}, NoticeDays: &fourteen, TimeZone: "Asia/Tokyo"},
+		},
+	}
+	tokyoCity := &Destination{Code: "TYO", Kind: DestinationCity, Name: "Tóquio", CountryCode: "JP"}
+	recifeCity := &Destination{Code: "REC", Kind: DestinationCity, Name: "Recife", CountryCode: "BR"}
+
+	t.Run("Default notice without a policy", func(t *testing.T) {
+		var none *CancellationPolicy
+		trip := &Trip{Type: TripDomestic, StartDate: time.Date(2030, 5, 10, 15, 0, 0, 0, time.UTC)}
+
+		deadline := none.CancellationDeadline(trip, nil)
+		assert.Equal(t, time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC), deadline)
+	})
+
+	t.Run("Organization default", func(t *testing.T) {
+		trip := &Trip{Type: TripDomestic, Destination: "Recife", StartDate: time.Date(2030, 5, 10, 15, 0, 0, 0, time.UTC)}
+
+		deadline := policy.CancellationDeadline(trip, recifeCity)
+		saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+		assert.True(t, deadline.Equal(time.Date(2030, 5, 8, 0, 0, 0, 0, saoPaulo)))
+	})
+
+	t.Run("Matching rule counts days in its time zone", func(t *testing.T) {
+		// 20:00 UTC on the 9th is already the 10th in Tokyo
+		trip := &Trip{Type: TripInternational, Destination: "Tóquio, Japão", StartDate: time.Date(2030, 5, 9, 20, 0, 0, 0, time.UTC)}
+
+		deadline := policy.CancellationDeadline(trip, tokyoCity)
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+		assert.True(t, deadline.Equal(time.Date(2030, 4, 26, 0, 0, 0, 0, tokyo)))
+	})
+
+	t.Run("Rule for another trip type", func(t *testing.T) {
+		trip := &Trip{Type: TripDomestic, Destination: "Tóquio", StartDate: time.Date(2030, 5, 10, 15, 0, 0, 0, time.UTC)}
+
+		saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+		assert.True(t, policy.CancellationDeadline(trip, tokyoCity).Equal(time.Date(2030, 5, 8, 0, 0, 0, 0, saoPaulo)))
+	})
+
+	t.Run("Regions match on codes, not on the destination name", func(t *testing.T) {
+		us := &CancellationPolicy{Rules: []CancellationRule{
+			{Countries: []string{"US"}, NoticeDays: &fourteen},
+			{DestinationCodes: []string{"LIS"}, NoticeDays: &two},
+		}}
+		start := time.Date(2030, 5, 10, 15, 0, 0, 0, time.UTC)
+
+		busan := &Trip{Type: TripInternational, Destination: "Busan", StartDate: start}
+		assert.Equal(t, time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC), us.CancellationDeadline(busan, &Destination{Code: "PUS", Kind: DestinationCity, CountryCode: "KR"}))
+
+		// An airport counts as its city
+		lisbon := &Trip{Type: TripInternational, Destination: "Lisboa", StartDate: start}
+		assert.Equal(t, time.Date(2030, 5, 8, 0, 0, 0, 0, time.UTC), us.CancellationDeadline(lisbon, &Destination{Code: "LIS", Kind: DestinationAirport, CityCode: "LIS", CountryCode: "PT"}))
+
+		// Without a catalog destination no region matches, whatever the name says
+		freeText := &Trip{Type: TripInternational, Destination: "Miami, US", StartDate: start}
+		assert.Equal(t, time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC), us.CancellationDeadline(freeText, nil))
+	})
+
+	t.Run("Trip time zone wins", func(t *testing.T) {
+		trip := &Trip{Type: TripDomestic, Destination: "Recife", StartDate: time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)}
+		trip.SetTimeZone("Europe/Lisbon")
+
+		lisbon, _ := time.LoadLocation("Europe/Lisbon")
+		assert.True(t, policy.CancellationDeadline(trip, recifeCity).Equal(time.Date(2030, 5, 8, 0, 0, 0, 0, lisbon)))
+	})
+}
+
+func TestCancellationPolicy_Validate(t *testing.T) {
+	negative := -1
+	policy := &TravelPolicy{Cancellation: &CancellationPolicy{
+		NoticeDays: &negative,
+		TimeZone:   "Mars/Olympus",
+		Rules: []CancellationRule{
+			{TripTypes: []TripType{"business"}},
+			{NoticeDays: &negative, TimeZone: "Local"},
+			{NoticeDays: &negative, Countries: []string{"Portugal"}, DestinationCodes: []string{"lis"}},
+		},
+	}}
+
+	err := policy.Validate()
+	assert.Error(t, err)
+
+	errMsg := err.Error()
+	assert.Contains(t, errMsg, "cancellation: notice_days cannot be negative")
+	assert.Contains(t, errMsg, "cancellation: unknown time_zone Mars/Olympus")
+	assert.Contains(t, errMsg, "cancellation.rules[0]: notice_days is required")
+	assert.Contains(t, errMsg, "cancellation.rules[0]: trip_types must be domestic or international")
+	assert.Contains(t, errMsg, "cancellation.rules[1]: unknown time_zone Local")
+	assert.Contains(t, errMsg, "cancellation.rules[2]: countries must be ISO 3166-1 alpha-2 codes, e.g. PT")
+	assert.Contains(t, errMsg, "cancellation.rules[2]: destination_codes must be IATA city codes, e.g. LIS")
+}
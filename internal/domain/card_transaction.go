@@ -0,0 +1,479 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ErrExpenseItemMatched is returned when an expense item already has a card transaction
+var ErrExpenseItemMatched = errors.New("the expense item is already matched to a card transaction")
+
+type CardTransactionStatus string
+
+const (
+	CardUnmatched CardTransactionStatus = "unmatched"
+	CardMatched   CardTransactionStatus = "matched"
+	// CardIgnored transactions aren't travel expenses, e.g. personal purchases
+	CardIgnored CardTransactionStatus = "ignored"
+)
+
+func (s CardTransactionStatus) IsValid() bool {
+	return s == CardUnmatched || s == CardMatched || s == CardIgnored
+}
+
+// CardMatchDays is how many days a transaction may be posted before or after
+// the date of the expense it pays for
+const CardMatchDays = 3
+
+// CardTransaction is a purchase on a traveler's corporate card, imported from
+// the card statement. It's matched to the expense item it pays for, and is
+// charged to the trip of the item or, while unmatched, to the trip of the
+// cardholder it was made during.
+type CardTransaction struct {
+	ID           uuid.UUID `json:"id"`
+	OrgID        uuid.UUID `json:"org_id"`
+	CardholderID uuid.UUID `json:"cardholder_id"`
+	// ExternalID identifies the transaction in the statement, so importing it again skips it
+	ExternalID string    `json:"external_id"`
+	PostedOn   time.Time `json:"posted_on"`
+	Merchant   string    `json:"merchant"`
+	// Amount is in minor units of Currency, e.g. cents
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// Converted is the amount in the organization's base currency, for
+	// transactions in another currency. It's taken with the rate in effect on the day it was posted.
+	Converted     *BudgetConversion     `json:"converted,omitempty"`
+	Status        CardTransactionStatus `json:"status"`
+	TripID        *uuid.UUID            `json:"trip_id,omitempty"`
+	ExpenseItemID *uuid.UUID            `json:"expense_item_id,omitempty"`
+	// Note explains why a reviewer ignored the transaction
+	Note       string     `json:"note,omitempty"`
+	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Validate checks if the transaction data is valid according to business rules
+func (t *CardTransaction) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(t.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(t.CardholderID == uuid.Nil, "cardholder_id is required")
+	validationErrors.AddIf(t.ExternalID == "", "external_id is required")
+	validationErrors.AddIf(len(t.ExternalID) > 255, "external_id must have at most 255 characters")
+	validationErrors.AddIf(t.PostedOn.IsZero(), "posted_on is required")
+	validationErrors.AddIf(t.Merchant == "", "merchant is required")
+	validationErrors.AddIf(len(t.Merchant) > 255, "merchant must have at most 255 characters")
+	validationErrors.AddIf(t.Amount <= 0, "amount must be positive")
+	validationErrors.AddIf(!IsCurrencyCode(t.Currency), "currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(!t.Status.IsValid(), "status must be unmatched, matched or ignored")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// ConvertWith records the amount in the base currency of the rate
+func (t *CardTransaction) ConvertWith(rate *ExchangeRate, at time.Time) {
+	t.Converted = &BudgetConversion{
+		Total:       rate.Convert(Money{Amount: t.Amount, Currency: t.Currency}),
+		Rate:        rate.Rate,
+		RateDate:    rate.EffectiveDate,
+		ConvertedAt: at,
+	}
+}
+
+// AmountIn returns the amount in the currency, when it's the transaction
+// currency or the one the transaction was converted to
+func (t *CardTransaction) AmountIn(currency string) (int64, bool) {
+	if t.Currency == currency {
+		return t.Amount, true
+	}
+	if t.Converted != nil && t.Converted.Total.Currency == currency {
+		return t.Converted.Total.Amount, true
+	}
+	return 0, false
+}
+
+// CardMatchCandidate is an expense item a transaction may pay for
+type CardMatchCandidate struct {
+	TripID uuid.UUID
+	Item   ExpenseItem
+}
+
+// MatchCardTransaction picks the expense item the transaction pays for: the
+// one with the same amount, a date up to CardMatchDays away and a description
+// or receipt reference sharing a word with the merchant. When several items
+// qualify the closest date wins; a tie is left for a reviewer. It returns nil
+// when no item qualifies.
+func MatchCardTransaction(t *CardTransaction, candidates []CardMatchCandidate) *CardMatchCandidate {
+	var best *CardMatchCandidate
+	bestDays, tied := 0, false
+	for i := range candidates {
+		candidate := &candidates[i]
+		amount, ok := candidate.Item.AmountIn(t.Currency)
+		if !ok || amount != t.Amount {
+			continue
+		}
+		days := daysApart(t.PostedOn, candidate.Item.Date)
+		if days > CardMatchDays {
+			continue
+		}
+		if !merchantMatches(t.Merchant, candidate.Item.Description+" "+candidate.Item.ReceiptRef) {
+			continue
+		}
+
+		switch {
+		case best == nil || days < bestDays:
+			best, bestDays, tied = candidate, days, false
+		case days == bestDays:
+			tied = true
+		}
+	}
+	if tied {
+		return nil
+	}
+	return best
+}
+
+// daysApart counts the calendar days between the dates
+func daysApart(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(math.Round(a.Sub(b).Hours() / 24))
+	if days < 0 {
+		return -days
+	}
+	return days
+}
+
+// merchantMatches reports whether the merchant and the text share a word of
+// three or more letters, regardless of case and accents. Statements shorten
+// merchant names, so "UBER *TRIP SAO PAULO" matches "Uber to the airport".
+func merchantMatches(merchant, text string) bool {
+	words := make(map[string]bool)
+	for _, word := range matchWords(merchant) {
+		words[word] = true
+	}
+	for _, word := range matchWords(text) {
+		if words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func matchWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(NormalizeSearchText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// CardSpend compares what was spent on the corporate card during a trip with
+// its approved budget. Amounts are in minor units of Currency, the organization's base currency.
+type CardSpend struct {
+	TripID   uuid.UUID `json:"trip_id"`
+	Currency string    `json:"currency"`
+	Spent    int64     `json:"spent"`
+	// Budget is the total of the trip budget, nil when the trip has none in the currency
+	Budget *int64 `json:"budget"`
+	// Remaining is what is left of the budget; it's negative when the card spend goes over it
+	Remaining    *int64             `json:"remaining"`
+	OverBudget   bool               `json:"over_budget"`
+	Transactions []*CardTransaction `json:"transactions"`
+}
+
+// SummarizeCardSpend adds up the transactions of the trip that weren't
+// ignored and compares them with the trip budget
+func SummarizeCardSpend(trip *Trip, currency string, transactions []*CardTransaction) *CardSpend {
+	spend := &CardSpend{TripID: trip.ID, Currency: currency, Transactions: transactions}
+	for _, transaction := range transactions {
+		if transaction.Status == CardIgnored {
+			continue
+		}
+		amount, _ := transaction.AmountIn(currency)
+		spend.Spent += amount
+	}
+
+	if trip.Budget == nil {
+		return spend
+	}
+	total, ok := trip.Budget.TotalIn(currency)
+	if !ok {
+		return spend
+	}
+	remaining := total - spend.Spent
+	spend.Budget = &total
+	spend.Remaining = &remaining
+	spend.OverBudget = remaining < 0
+	return spend
+}
+
+// CardStatementImport summarizes the import of a card statement
+type CardStatementImport struct {
+	// Imported is the number of new transactions; Duplicates were imported before
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	// Matched counts the cardholder's transactions matched by the import,
+	// including older ones whose expense was recorded since the last import.
+	// Unmatched counts the ones left for review.
+	Matched      int                `json:"matched"`
+	Unmatched    int                `json:"unmatched"`
+	Transactions []*CardTransaction `json:"transactions"`
+	// OverBudgetTrips are the trips of the statement whose card spend went over their budget
+	OverBudgetTrips []*CardSpend `json:"over_budget_trips"`
+}
+
+// ParseCardStatement reads the transactions of an OFX or CSV card statement,
+// telling the formats apart by their content. Credits, like the payment of
+// the card bill, are left out.
+func ParseCardStatement(data []byte) ([]*CardTransaction, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	upper := bytes.ToUpper(trimmed)
+	if bytes.HasPrefix(upper, []byte("OFXHEADER")) || bytes.HasPrefix(upper, []byte("<?XML")) || bytes.HasPrefix(upper, []byte("<OFX>")) {
+		return ParseCardStatementOFX(trimmed)
+	}
+	return ParseCardStatementCSV(trimmed)
+}
+
+// ParseCardStatementCSV reads transactions from a CSV file with the header
+// date,merchant,amount,currency and, optionally, transaction_id. Dates are
+// YYYY-MM-DD and amounts use a decimal point, e.g. "2024-05-03,UBER *TRIP,45.90,BRL".
+// Negative amounts are credits. Without a transaction_id, a line is told
+// apart by its fields and by how many equal lines precede it.
+func ParseCardStatementCSV(data []byte) ([]*CardTransaction, error) {
+	validationErrors := NewValidationErrors()
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		validationErrors.Add("invalid CSV file: " + err.Error())
+		return nil, validationErrors
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "merchant", "amount", "currency"} {
+		_, ok := columns[name]
+		validationErrors.AddIf(!ok, "invalid CSV file: missing the "+name+" column")
+	}
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+
+	var transactions []*CardTransaction
+	occurrences := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			validationErrors.Add("invalid CSV file: " + err.Error())
+			return nil, validationErrors
+		}
+
+		prefix := fmt.Sprintf("line %d: ", line)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		currency := strings.ToUpper(field("currency"))
+		postedOn, err := time.Parse("2006-01-02", field("date"))
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", field("date")))
+		amount, err := parseStatementAmount(field("amount"), currency)
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid amount %q", field("amount")))
+		validationErrors.AddIf(!IsCurrencyCode(currency), prefix+"currency must be an ISO 4217 code, e.g. BRL")
+		validationErrors.AddIf(field("merchant") == "", prefix+"merchant is required")
+		if amount <= 0 {
+			continue
+		}
+
+		externalID := field("transaction_id")
+		if externalID == "" {
+			key := strings.Join([]string{field("date"), field("merchant"), field("amount"), currency}, "|")
+			occurrences[key]++
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, occurrences[key])))
+			externalID = "csv:" + hex.EncodeToString(sum[:16])
+		}
+		transactions = append(transactions, &CardTransaction{
+			ExternalID: externalID,
+			PostedOn:   postedOn,
+			Merchant:   field("merchant"),
+			Amount:     amount,
+			Currency:   currency,
+		})
+	}
+	validationErrors.AddIf(len(transactions) == 0 && !validationErrors.HasErrors(), "the statement has no purchases")
+
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+	return transactions, nil
+}
+
+// ParseCardStatementOFX reads the transactions of an OFX credit card
+// statement, in the SGML (1.x) or XML (2.x) flavor. Transactions take the
+// statement currency (CURDEF) and are identified by their FITID. In OFX
+// purchases are negative amounts.
+func ParseCardStatementOFX(data []byte) ([]*CardTransaction, error) {
+	validationErrors := NewValidationErrors()
+
+	var transactions []*CardTransaction
+	var currency string
+	var fields map[string]string
+	inStatement, count := false, 0
+	finish := func() {
+		count++
+		prefix := fmt.Sprintf("transaction %d: ", count)
+		postedOn, err := parseOFXDate(fields["DTPOSTED"])
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid DTPOSTED %q", fields["DTPOSTED"]))
+		amount, err := parseStatementAmount(fields["TRNAMT"], currency)
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid TRNAMT %q", fields["TRNAMT"]))
+		validationErrors.AddIf(fields["FITID"] == "", prefix+"missing FITID")
+		if amount >= 0 {
+			return
+		}
+
+		merchant := fields["NAME"]
+		if merchant == "" {
+			merchant = fields["MEMO"]
+		}
+		transactions = append(transactions, &CardTransaction{
+			ExternalID: fields["FITID"],
+			PostedOn:   postedOn,
+			Merchant:   merchant,
+			Amount:     -amount,
+			Currency:   currency,
+		})
+	}
+
+	for _, element := range ofxElements(string(data)) {
+		switch {
+		case element.name == "OFX":
+			inStatement = true
+		case element.name == "CURDEF" && fields == nil:
+			currency = strings.ToUpper(element.value)
+		case element.name == "STMTTRN":
+			fields = map[string]string{}
+		case element.name == "/STMTTRN":
+			if fields != nil {
+				finish()
+			}
+			fields = nil
+		case fields != nil && !strings.HasPrefix(element.name, "/"):
+			if _, seen := fields[element.name]; !seen {
+				fields[element.name] = element.value
+			}
+		}
+	}
+
+	validationErrors.AddIf(!inStatement, "invalid OFX file: missing the OFX element")
+	validationErrors.AddIf(inStatement && !IsCurrencyCode(currency), "invalid OFX file: missing the statement currency (CURDEF)")
+	validationErrors.AddIf(inStatement && len(transactions) == 0 && !validationErrors.HasErrors(), "the statement has no purchases")
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+	return transactions, nil
+}
+
+type ofxElement struct {
+	name  string
+	value string
+}
+
+// ofxElements lists the tags of the document with the text that follows
+// them. SGML OFX leaves elements unclosed, so the value of <TRNAMT>-45.90 is
+// the text up to the next tag either way.
+func ofxElements(data string) []ofxElement {
+	var elements []ofxElement
+	for {
+		start := strings.IndexByte(data, '<')
+		if start < 0 {
+			return elements
+		}
+		end := strings.IndexByte(data[start:], '>')
+		if end < 0 {
+			return elements
+		}
+		name := strings.ToUpper(strings.TrimSpace(data[start+1 : start+end]))
+		data = data[start+end+1:]
+
+		value := data
+		if next := strings.IndexByte(data, '<'); next >= 0 {
+			value = data[:next]
+		}
+		elements = append(elements, ofxElement{name: name, value: strings.TrimSpace(value)})
+	}
+}
+
+// parseOFXDate reads the date of an OFX datetime, e.g. 20240503120000[-3:BRT]
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid OFX date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// parseStatementAmount reads a decimal amount, with a point or a comma, in
+// minor units of the currency: "45.90" is 4590 for BRL
+func parseStatementAmount(value, currency string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	whole, fraction, _ := strings.Cut(value, ".")
+	digits := CurrencyDigits(currency)
+	if len(fraction) > digits || strings.HasPrefix(fraction, "-") || strings.HasPrefix(fraction, "+") {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return minor, nil
+}
+
+// CardTransactionFilter selects transactions; nil fields match any value
+type CardTransactionFilter struct {
+	Status       *CardTransactionStatus
+	CardholderID *uuid.UUID
+	TripID       *uuid.UUID
+}
+
+type CardTransactionRepository interface {
+	// Import stores the transactions that weren't imported yet, in a single
+	// database transaction, and returns the ones stored
+	Import(ctx context.Context, transactions []*CardTransaction) ([]*CardTransaction, error)
+	// Update stores the status, trip, expense item and review data of the
+	// transaction. It returns ErrExpenseItemMatched when another transaction has the item.
+	Update(ctx context.Context, transaction *CardTransaction) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CardTransaction, error)
+	// List returns the transactions the filter selects, oldest first
+	List(ctx context.Context, filter CardTransactionFilter) ([]*CardTransaction, error)
+}
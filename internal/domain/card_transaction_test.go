@@ -0,0 +1,201 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCardStatementCSV(t *testing.T) {
+	t.Run("Purchases", func(t *testing.T) {
+		data := []byte("date,merchant,amount,currency\n" +
+			"2024-05-03,UBER *TRIP,45.90,brl\n" +
+			"2024-05-03,UBER *TRIP,45.90,BRL\n" +
+			"2024-05-10,PAGAMENTO FATURA,-1200.00,BRL\n")
+
+		transactions, err := domain.ParseCardStatement(data)
+
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 2)
+		assert.Equal(t, int64(4590), transactions[0].Amount)
+		assert.Equal(t, "BRL", transactions[0].Currency)
+		assert.Equal(t, "UBER *TRIP", transactions[0].Merchant)
+		assert.Equal(t, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), transactions[0].PostedOn)
+		// Equal purchases on the same day are told apart
+		assert.NotEqual(t, transactions[0].ExternalID, transactions[1].ExternalID)
+	})
+
+	t.Run("Same lines, same IDs", func(t *testing.T) {
+		data := []byte("date,merchant,amount,currency,transaction_id\n2024-05-03,HOTEL IBIS,800.00,BRL,\n2024-05-04,LATAM,1500,BRL,T-2\n")
+
+		first, _ := domain.ParseCardStatementCSV(data)
+		again, err := domain.ParseCardStatementCSV(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, first[0].ExternalID, again[0].ExternalID)
+		assert.Equal(t, "T-2", again[1].ExternalID)
+		assert.Equal(t, int64(150000), again[1].Amount)
+	})
+
+	t.Run("Invalid lines", func(t *testing.T) {
+		data := []byte("date,merchant,amount,currency\n03/05/2024,UBER,45.9O,BRL\n2024-05-04,,10.00,real\n")
+
+		_, err := domain.ParseCardStatement(data)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `line 2: invalid date "03/05/2024"`)
+		assert.Contains(t, err.Error(), `line 2: invalid amount "45.9O"`)
+		assert.Contains(t, err.Error(), "line 3: currency must be an ISO 4217 code")
+		assert.Contains(t, err.Error(), "line 3: merchant is required")
+	})
+
+	t.Run("Missing columns", func(t *testing.T) {
+		_, err := domain.ParseCardStatement([]byte("date,description,value\n"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing the merchant column")
+	})
+}
+
+func TestParseCardStatementOFX(t *testing.T) {
+	t.Run("SGML", func(t *testing.T) {
+		data := []byte(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+<CURDEF>BRL
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240503120000[-3:BRT]
+<TRNAMT>-45,90
+<FITID>202405030001
+<NAME>UBER *TRIP
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240510
+<TRNAMT>1200.00
+<FITID>202405100001
+<MEMO>PAGAMENTO FATURA
+</STMTTRN>
+</BANKTRANLIST>
+</CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>`)
+
+		transactions, err := domain.ParseCardStatement(data)
+
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 1)
+		assert.Equal(t, "202405030001", transactions[0].ExternalID)
+		assert.Equal(t, "UBER *TRIP", transactions[0].Merchant)
+		assert.Equal(t, int64(4590), transactions[0].Amount)
+		assert.Equal(t, "BRL", transactions[0].Currency)
+		assert.Equal(t, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), transactions[0].PostedOn)
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><CURDEF>USD</CURDEF><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20240601</DTPOSTED><TRNAMT>-120.50</TRNAMT><FITID>A1</FITID><MEMO>HILTON NEW YORK</MEMO></STMTTRN>
+</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>`)
+
+		transactions, err := domain.ParseCardStatement(data)
+
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 1)
+		assert.Equal(t, "HILTON NEW YORK", transactions[0].Merchant)
+		assert.Equal(t, int64(12050), transactions[0].Amount)
+		assert.Equal(t, "USD", transactions[0].Currency)
+	})
+
+	t.Run("Missing currency", func(t *testing.T) {
+		_, err := domain.ParseCardStatement([]byte("<OFX><STMTTRN><DTPOSTED>20240601<TRNAMT>-1.00<FITID>A1<NAME>X</STMTTRN></OFX>"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing the statement currency")
+	})
+}
+
+func TestMatchCardTransaction(t *testing.T) {
+	day := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	transaction := &domain.CardTransaction{PostedOn: day.AddDate(0, 0, 1), Merchant: "UBER *TRIP SAO PAULO", Amount: 4590, Currency: "BRL"}
+	uber := domain.CardMatchCandidate{TripID: uuid.New(), Item: domain.ExpenseItem{
+		ID: uuid.New(), Date: day, Amount: 4590, Currency: "BRL", Description: "Uber to the airport",
+	}}
+
+	t.Run("Amount, date and merchant", func(t *testing.T) {
+		match := domain.MatchCardTransaction(transaction, []domain.CardMatchCandidate{uber})
+
+		assert.NotNil(t, match)
+		assert.Equal(t, uber.Item.ID, match.Item.ID)
+	})
+
+	t.Run("Converted amount", func(t *testing.T) {
+		inDollars := uber
+		inDollars.Item.Amount, inDollars.Item.Currency = 900, "USD"
+		inDollars.Item.Converted = &domain.BudgetConversion{Total: domain.Money{Amount: 4590, Currency: "BRL"}}
+
+		assert.NotNil(t, domain.MatchCardTransaction(transaction, []domain.CardMatchCandidate{inDollars}))
+	})
+
+	t.Run("Different amount, far date or other merchant", func(t *testing.T) {
+		otherAmount, farDate, otherMerchant := uber, uber, uber
+		otherAmount.Item.Amount = 4500
+		farDate.Item.Date = day.AddDate(0, 0, -5)
+		otherMerchant.Item.Description = "Taxi to the hotel"
+
+		assert.Nil(t, domain.MatchCardTransaction(transaction, []domain.CardMatchCandidate{otherAmount, farDate, otherMerchant}))
+	})
+
+	t.Run("Closest date wins", func(t *testing.T) {
+		earlier := uber
+		earlier.Item.ID = uuid.New()
+		earlier.Item.Date = day.AddDate(0, 0, -1)
+
+		match := domain.MatchCardTransaction(transaction, []domain.CardMatchCandidate{earlier, uber})
+
+		assert.Equal(t, uber.Item.ID, match.Item.ID)
+	})
+
+	t.Run("Ties are left for review", func(t *testing.T) {
+		twin := uber
+		twin.Item.ID = uuid.New()
+
+		assert.Nil(t, domain.MatchCardTransaction(transaction, []domain.CardMatchCandidate{uber, twin}))
+	})
+}
+
+func TestSummarizeCardSpend(t *testing.T) {
+	trip := &domain.Trip{ID: uuid.New(), Budget: &domain.TripBudget{
+		Currency: "BRL",
+		Items:    []domain.BudgetItem{{Category: domain.BudgetLodging, Amount: 100000}},
+	}}
+	transactions := []*domain.CardTransaction{
+		{Status: domain.CardMatched, Amount: 80000, Currency: "BRL"},
+		{Status: domain.CardUnmatched, Amount: 5000, Currency: "USD", Converted: &domain.BudgetConversion{Total: domain.Money{Amount: 25000, Currency: "BRL"}}},
+		{Status: domain.CardIgnored, Amount: 90000, Currency: "BRL"},
+	}
+
+	t.Run("Over budget", func(t *testing.T) {
+		spend := domain.SummarizeCardSpend(trip, "BRL", transactions)
+
+		assert.Equal(t, int64(105000), spend.Spent)
+		assert.Equal(t, int64(100000), *spend.Budget)
+		assert.Equal(t, int64(-5000), *spend.Remaining)
+		assert.True(t, spend.OverBudget)
+	})
+
+	t.Run("Without a budget", func(t *testing.T) {
+		spend := domain.SummarizeCardSpend(&domain.Trip{ID: uuid.New()}, "BRL", transactions)
+
+		assert.Nil(t, spend.Budget)
+		assert.False(t, spend.OverBudget)
+	})
+}
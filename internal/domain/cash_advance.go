@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CashAdvanceStatus string
+
+const (
+	AdvanceRequested CashAdvanceStatus = "requested"
+	AdvanceApproved  CashAdvanceStatus = "approved"
+	AdvanceRejected  CashAdvanceStatus = "rejected"
+	AdvancePaid      CashAdvanceStatus = "paid"
+	AdvanceSettled   CashAdvanceStatus = "settled"
+)
+
+// ErrCashAdvanceStatusChanged is returned when an advance is saved on the
+// assumption of a status it no longer has, e.g. an advance paid twice at once
+var ErrCashAdvanceStatusChanged = errors.New("cash advance status changed meanwhile")
+
+func (s CashAdvanceStatus) IsValid() bool {
+	switch s {
+	case AdvanceRequested, AdvanceApproved, AdvanceRejected, AdvancePaid, AdvanceSettled:
+		return true
+	}
+	return false
+}
+
+// CashAdvance is money paid to the traveler before a trip, e.g. for expenses
+// that can't go on a corporate card. Finance approves and pays it, and once
+// the trip's expense report is approved it's settled against the expenses.
+type CashAdvance struct {
+	ID         uuid.UUID         `json:"id"`
+	OrgID      uuid.UUID         `json:"org_id"`
+	TripID     uuid.UUID         `json:"trip_id"`
+	TravelerID uuid.UUID         `json:"traveler_id"`
+	Status     CashAdvanceStatus `json:"status"`
+	// Amount is in minor units of Currency, e.g. cents
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Purpose  string `json:"purpose"`
+	// Converted is the amount in the organization's base currency, for advances
+	// in another currency. It's taken with the rate in effect on the day it's paid.
+	Converted       *BudgetConversion `json:"converted,omitempty"`
+	RejectionReason string            `json:"rejection_reason,omitempty"`
+	ReviewedBy      *uuid.UUID        `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	PaidBy          *uuid.UUID        `json:"paid_by,omitempty"`
+	PaidAt          *time.Time        `json:"paid_at,omitempty"`
+	SettledBy       *uuid.UUID        `json:"settled_by,omitempty"`
+	SettledAt       *time.Time        `json:"settled_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// Validate checks if the advance data is valid according to business rules
+func (a *CashAdvance) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(a.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(a.TripID == uuid.Nil, "trip_id is required")
+	validationErrors.AddIf(a.TravelerID == uuid.Nil, "traveler_id is required")
+	validationErrors.AddIf(!a.Status.IsValid(), "status must be requested, approved, rejected, paid or settled")
+	validationErrors.AddIf(a.Amount <= 0, "amount must be positive")
+	validationErrors.AddIf(!IsCurrencyCode(a.Currency), "currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(a.Purpose == "", "purpose is required")
+	validationErrors.AddIf(len(a.Purpose) > 255, "purpose must have at most 255 characters")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// Disbursed reports whether the traveler has the money, which then has to be
+// accounted for with expenses or returned
+func (a *CashAdvance) Disbursed() bool {
+	return a.Status == AdvancePaid || a.Status == AdvanceSettled
+}
+
+// ConvertWith records the amount in the base currency of the rate
+func (a *CashAdvance) ConvertWith(rate *ExchangeRate, at time.Time) {
+	a.Converted = &BudgetConversion{
+		Total:       rate.Convert(Money{Amount: a.Amount, Currency: a.Currency}),
+		Rate:        rate.Rate,
+		RateDate:    rate.EffectiveDate,
+		ConvertedAt: at,
+	}
+}
+
+// AmountIn returns the amount in the currency, when it's the advance currency
+// or the one the advance was converted to
+func (a *CashAdvance) AmountIn(currency string) (int64, bool) {
+	if a.Currency == currency {
+		return a.Amount, true
+	}
+	if a.Converted != nil && a.Converted.Total.Currency == currency {
+		return a.Converted.Total.Amount, true
+	}
+	return 0, false
+}
+
+// AdvanceReconciliation compares the advances paid for a trip with its
+// expenses. Amounts are in minor units of Currency, the organization's base currency.
+type AdvanceReconciliation struct {
+	Currency string `json:"currency"`
+	// Expenses is the total of the trip's expense report, zero while it has none
+	Expenses int64 `json:"expenses"`
+	// Advanced is the total of the advances paid to the traveler
+	Advanced int64 `json:"advanced"`
+	// ToReimburse is what the organization still owes the traveler. It's paid
+	// with the reimbursement of the expense report, so it's zero once the report is reimbursed.
+	ToReimburse int64 `json:"to_reimburse"`
+	// ToReturn is what the traveler has to give back
+	ToReturn int64 `json:"to_return"`
+	// Final is set once the expense report is approved, when the amounts no longer change
+	Final    bool           `json:"final"`
+	Advances []*CashAdvance `json:"advances"`
+}
+
+// ReconcileAdvances balances the advances paid to the traveler against the
+// expenses of the report, which may be nil when no expense was recorded yet
+func ReconcileAdvances(currency string, report *ExpenseReport, advances []*CashAdvance) *AdvanceReconciliation {
+	reconciliation := &AdvanceReconciliation{Currency: currency, Advances: advances}
+	if report != nil {
+		reconciliation.Expenses = report.Total()
+		reconciliation.Final = report.Status == ExpenseApproved || report.Status == ExpenseReimbursed
+	}
+	for _, advance := range advances {
+		if !advance.Disbursed() {
+			continue
+		}
+		amount, _ := advance.AmountIn(currency)
+		reconciliation.Advanced += amount
+	}
+
+	if balance := reconciliation.Expenses - reconciliation.Advanced; balance > 0 {
+		if report.Status != ExpenseReimbursed {
+			reconciliation.ToReimburse = balance
+		}
+	} else {
+		reconciliation.ToReturn = -balance
+	}
+	return reconciliation
+}
+
+type CashAdvanceRepository interface {
+	Create(ctx context.Context, advance *CashAdvance) error
+	// Update stores the status and review data of the advance. It returns
+	// ErrCashAdvanceStatusChanged unless the stored advance is still in the from status.
+	Update(ctx context.Context, advance *CashAdvance, from CashAdvanceStatus) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CashAdvance, error)
+	// ListByTripID returns the advances of the trip, oldest first
+	ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*CashAdvance, error)
+	// List returns the advances in the status, or all of them when status is nil, oldest first
+	List(ctx context.Context, status *CashAdvanceStatus) ([]*CashAdvance, error)
+}
@@ -0,0 +1,110 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCashAdvance_Validate(t *testing.T) {
+	valid := func() *domain.CashAdvance {
+		return &domain.CashAdvance{
+			OrgID:      uuid.New(),
+			TripID:     uuid.New(),
+			TravelerID: uuid.New(),
+			Status:     domain.AdvanceRequested,
+			Amount:     50000,
+			Currency:   "USD",
+			Purpose:    "Taxis and meals",
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, valid().Validate())
+	})
+
+	t.Run("Invalid amount, currency and purpose", func(t *testing.T) {
+		advance := valid()
+		advance.Amount = 0
+		advance.Currency = "dollars"
+		advance.Purpose = ""
+
+		err := advance.Validate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "amount must be positive")
+		assert.Contains(t, err.Error(), "currency must be an ISO 4217 code")
+		assert.Contains(t, err.Error(), "purpose is required")
+	})
+}
+
+func TestReconcileAdvances(t *testing.T) {
+	report := &domain.ExpenseReport{
+		Status:   domain.ExpenseApproved,
+		Currency: "BRL",
+		Items: []domain.ExpenseItem{
+			{Amount: 30000, Currency: "BRL"},
+			{Amount: 10000, Currency: "USD", Converted: &domain.BudgetConversion{Total: domain.Money{Amount: 50000, Currency: "BRL"}}},
+		},
+	}
+	paidInDollars := &domain.CashAdvance{
+		Status:    domain.AdvancePaid,
+		Amount:    20000,
+		Currency:  "USD",
+		Converted: &domain.BudgetConversion{Total: domain.Money{Amount: 100000, Currency: "BRL"}, RateDate: time.Now()},
+	}
+
+	t.Run("Traveler returns what wasn't spent", func(t *testing.T) {
+		reconciliation := domain.ReconcileAdvances("BRL", report, []*domain.CashAdvance{
+			paidInDollars,
+			{Status: domain.AdvanceRequested, Amount: 90000, Currency: "BRL"},
+			{Status: domain.AdvanceRejected, Amount: 90000, Currency: "BRL"},
+		})
+
+		assert.Equal(t, int64(80000), reconciliation.Expenses)
+		assert.Equal(t, int64(100000), reconciliation.Advanced)
+		assert.Equal(t, int64(20000), reconciliation.ToReturn)
+		assert.Zero(t, reconciliation.ToReimburse)
+		assert.True(t, reconciliation.Final)
+	})
+
+	t.Run("Traveler is reimbursed what the advances didn't cover", func(t *testing.T) {
+		reconciliation := domain.ReconcileAdvances("BRL", report, []*domain.CashAdvance{
+			{Status: domain.AdvanceSettled, Amount: 50000, Currency: "BRL"},
+		})
+
+		assert.Equal(t, int64(30000), reconciliation.ToReimburse)
+		assert.Zero(t, reconciliation.ToReturn)
+	})
+
+	t.Run("Reimbursed report already paid the rest", func(t *testing.T) {
+		reimbursed := *report
+		reimbursed.Status = domain.ExpenseReimbursed
+
+		reconciliation := domain.ReconcileAdvances("BRL", &reimbursed, []*domain.CashAdvance{
+			{Status: domain.AdvancePaid, Amount: 50000, Currency: "BRL"},
+		})
+
+		assert.Zero(t, reconciliation.ToReimburse)
+		assert.Zero(t, reconciliation.ToReturn)
+		assert.True(t, reconciliation.Final)
+	})
+
+	t.Run("Without expenses everything is returned and nothing is final", func(t *testing.T) {
+		reconciliation := domain.ReconcileAdvances("BRL", nil, []*domain.CashAdvance{paidInDollars})
+
+		assert.Zero(t, reconciliation.Expenses)
+		assert.Equal(t, int64(100000), reconciliation.ToReturn)
+		assert.False(t, reconciliation.Final)
+	})
+
+	t.Run("Submitted report isn't final", func(t *testing.T) {
+		submitted := *report
+		submitted.Status = domain.ExpenseSubmitted
+
+		assert.False(t, domain.ReconcileAdvances("BRL", &submitted, nil).Final)
+	})
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CostCenter is what finance charges a trip to
+type CostCenter struct {
+	ID        uuid.UUID `json:"id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the cost center data is valid according to business rules
+func (c *CostCenter) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(c.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(c.Code == "", "code is required")
+	validationErrors.AddIf(c.Name == "", "name is required")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// Department groups users. Trips requested by its members are charged to
+// the department's cost center unless another one is chosen.
+type Department struct {
+	ID           uuid.UUID `json:"id"`
+	OrgID        uuid.UUID `json:"org_id"`
+	Name         string    `json:"name"`
+	CostCenterID uuid.UUID `json:"cost_center_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks if the department data is valid according to business rules
+func (d *Department) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(d.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(d.Name == "", "name is required")
+	validationErrors.AddIf(d.CostCenterID == uuid.Nil, "cost_center_id is required")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+type CostCenterRepository interface {
+	Create(ctx context.Context, costCenter *CostCenter) error
+	Update(ctx context.Context, costCenter *CostCenter) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CostCenter, error)
+	List(ctx context.Context) ([]*CostCenter, error)
+}
+
+type DepartmentRepository interface {
+	Create(ctx context.Context, department *Department) error
+	Update(ctx context.Context, department *Department) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Department, error)
+	List(ctx context.Context) ([]*Department, error)
+}
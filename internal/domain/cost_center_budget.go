@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleCostCenterBudget marks the violations of cost center budgets. It isn't a rule of the travel policy.
+const RuleCostCenterBudget TravelRuleType = "cost_center_budget"
+
+// ErrBudgetExceeded is returned when approving a trip would spend more than what is left of its cost center budget
+var ErrBudgetExceeded = errors.New("trip exceeds the remaining budget of its cost center")
+
+// BudgetExceededError tells how much was left of the budget. It matches ErrBudgetExceeded with errors.Is.
+type BudgetExceededError struct {
+	BudgetID  uuid.UUID
+	Remaining Money
+	TripCost  Money
+}
+
+func (e *BudgetExceededError) Error() string {
+	return ErrBudgetExceeded.Error()
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// CostCenterBudget is what a cost center can spend on travel in a period,
+// e.g. a quarter. Trips are charged to the period their first day falls in.
+type CostCenterBudget struct {
+	ID           uuid.UUID `json:"id"`
+	OrgID        uuid.UUID `json:"org_id"`
+	CostCenterID uuid.UUID `json:"cost_center_id"`
+	// PeriodStart and PeriodEnd are dates, both days included
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// Amount is in minor units of Currency, the organization's base currency
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// Severity decides whether approvals over the budget are rejected or only flagged
+	Severity  RuleSeverity `json:"severity"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Validate checks if the budget data is valid according to business rules
+func (b *CostCenterBudget) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(b.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(b.CostCenterID == uuid.Nil, "cost_center_id is required")
+	validationErrors.AddIf(b.PeriodStart.IsZero(), "period_start is required")
+	validationErrors.AddIf(b.PeriodEnd.IsZero(), "period_end is required")
+	if !b.PeriodStart.IsZero() && !b.PeriodEnd.IsZero() {
+		validationErrors.AddIf(b.PeriodEnd.Before(b.PeriodStart), "period_end must not be before period_start")
+	}
+	validationErrors.AddIf(b.Amount <= 0, "amount must be positive")
+	validationErrors.AddIf(!IsCurrencyCode(b.Currency), "currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(b.Severity != SeverityBlock && b.Severity != SeverityWarn, "severity must be block or warn")
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// Overlaps reports whether the periods of both budgets share a day
+func (b *CostCenterBudget) Overlaps(other *CostCenterBudget) bool {
+	return !b.PeriodStart.After(other.PeriodEnd) && !other.PeriodStart.After(b.PeriodEnd)
+}
+
+// Covers reports whether the trip is charged to the budget: same cost center
+// and a first day, in the trip's own zone, inside the period
+func (b *CostCenterBudget) Covers(trip *Trip) bool {
+	if trip.CostCenterID != b.CostCenterID {
+		return false
+	}
+	day := civilDate(trip.StartDate)
+	return !day.Before(civilDate(b.PeriodStart)) && !day.After(civilDate(b.PeriodEnd))
+}
+
+// civilDate drops the time and zone of t, keeping the date as it reads in its zone
+func civilDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// BudgetTrip is a trip charged to a budget
+type BudgetTrip struct {
+	TripID      uuid.UUID  `json:"trip_id"`
+	Destination string     `json:"destination"`
+	StartDate   time.Time  `json:"start_date"`
+	Status      TripStatus `json:"status"`
+	Amount      int64      `json:"amount"`
+	// Actual tells the amount is what the trip's expense report says was
+	// spent, not its estimated cost
+	Actual bool `json:"actual"`
+}
+
+// BudgetConsumption is how much of a budget its trips take. Amounts are in
+// minor units of the budget currency.
+type BudgetConsumption struct {
+	// Committed is the estimated cost of the approved trips, and of the
+	// concluded ones whose expenses finance hasn't approved yet
+	Committed int64 `json:"committed"`
+	// Actual is what the concluded trips spent, from their approved or
+	// reimbursed expense reports
+	Actual    int64        `json:"actual"`
+	Remaining int64        `json:"remaining"`
+	Trips     []BudgetTrip `json:"trips"`
+	// UnpricedTripIDs are trips charged to the budget without an estimated cost in its currency
+	UnpricedTripIDs []uuid.UUID `json:"unpriced_trip_ids"`
+}
+
+// Consumption adds up the approved and concluded trips the budget covers.
+// A concluded trip counts what its approved or reimbursed expense report
+// spent in place of its estimate, when the report can be told in the budget
+// currency. Other trips and reports are ignored.
+func (b *CostCenterBudget) Consumption(trips []*Trip, reports []*ExpenseReport) BudgetConsumption {
+	spent := make(map[uuid.UUID]int64)
+	for _, report := range reports {
+		if !report.Spent() {
+			continue
+		}
+		if amount, ok := report.TotalIn(b.Currency); ok {
+			spent[report.TripID] = amount
+		}
+	}
+
+	consumption := BudgetConsumption{Trips: []BudgetTrip{}, UnpricedTripIDs: []uuid.UUID{}}
+	for _, trip := range trips {
+		if !b.Covers(trip) || (trip.Status != StatusApproved && trip.Status != StatusConcluded) {
+			continue
+		}
+		amount, actual := spent[trip.ID]
+		actual = actual && trip.Status == StatusConcluded
+		if actual {
+			consumption.Actual += amount
+		} else {
+			var ok bool
+			if amount, ok = b.tripCost(trip); !ok {
+				consumption.UnpricedTripIDs = append(consumption.UnpricedTripIDs, trip.ID)
+				continue
+			}
+			consumption.Committed += amount
+		}
+		consumption.Trips = append(consumption.Trips, BudgetTrip{
+			TripID:      trip.ID,
+			Destination: trip.Destination,
+			StartDate:   trip.StartDate,
+			Status:      trip.Status,
+			Amount:      amount,
+			Actual:      actual,
+		})
+	}
+	consumption.Remaining = b.Amount - consumption.Committed - consumption.Actual
+	return consumption
+}
+
+// Check returns a violation when the trip costs more than what remains of the
+// budget. Trips without an estimated cost in the budget currency pass.
+func (b *CostCenterBudget) Check(trip *Trip, consumption BudgetConsumption) *PolicyViolation {
+	amount, ok := b.tripCost(trip)
+	if !ok || amount <= consumption.Remaining {
+		return nil
+	}
+	return &PolicyViolation{
+		Rule:     RuleCostCenterBudget,
+		Severity: b.Severity,
+		Message: fmt.Sprintf("trip costs %s, more than what is left of the cost center budget for %s to %s",
+			Money{Amount: amount, Currency: b.Currency}, b.PeriodStart.Format("2006-01-02"), b.PeriodEnd.Format("2006-01-02")),
+	}
+}
+
+// tripCost is the estimated cost of the trip in the budget currency
+func (b *CostCenterBudget) tripCost(trip *Trip) (int64, bool) {
+	if trip.Budget == nil {
+		return 0, false
+	}
+	return trip.Budget.TotalIn(b.Currency)
+}
+
+// BudgetReport is a budget with what its trips have taken of it
+type BudgetReport struct {
+	*CostCenterBudget
+	Consumption BudgetConsumption `json:"consumption"`
+}
+
+type CostCenterBudgetRepository interface {
+	Create(ctx context.Context, budget *CostCenterBudget) error
+	Update(ctx context.Context, budget *CostCenterBudget) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CostCenterBudget, error)
+	// ListByCostCenter returns the budgets of a cost center, or of all of them
+	// when costCenterID is nil, latest period first
+	ListByCostCenter(ctx context.Context, costCenterID *uuid.UUID) ([]*CostCenterBudget, error)
+	// Lock holds the budget until the transaction ends, so the approvals
+	// charged to it are checked one at a time. It's meant to run inside
+	// Transactor.WithinTx.
+	Lock(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostCenterBudget_Validate(t *testing.T) {
+	budget := CostCenterBudget{
+		OrgID:        uuid.New(),
+		CostCenterID: uuid.New(),
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       1000000,
+		Currency:     "BRL",
+		Severity:     SeverityBlock,
+	}
+	assert.NoError(t, budget.Validate())
+
+	budget.PeriodEnd = time.Date(2030, 3, 31, 0, 0, 0, 0, time.UTC)
+	budget.Amount = 0
+	budget.Severity = "deny"
+	err := budget.Validate()
+	assert.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"period_end must not be before period_start",
+		"amount must be positive",
+		"severity must be block or warn",
+	}, err.(*ValidationErrors).GetErrors())
+}
+
+func TestCostCenterBudget_Consumption(t *testing.T) {
+	costCenterID := uuid.New()
+	budget := &CostCenterBudget{
+		CostCenterID: costCenterID,
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       1000000,
+		Currency:     "BRL",
+		Severity:     SeverityWarn,
+	}
+	brl := func(amount int64) *TripBudget {
+		return &TripBudget{Currency: "BRL", Items: []BudgetItem{{Category: BudgetLodging, Amount: amount}}}
+	}
+	lisbon, _ := time.LoadLocation("Europe/Lisbon")
+
+	approved := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusApproved,
+		StartDate: time.Date(2030, 4, 10, 9, 0, 0, 0, time.UTC), Budget: brl(200000)}
+	concluded := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusConcluded,
+		StartDate: time.Date(2030, 6, 30, 23, 0, 0, 0, time.UTC), Budget: brl(150000)}
+	converted := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusApproved,
+		// March 31st in UTC, but already April 1st in Lisbon
+		StartDate: time.Date(2030, 3, 31, 23, 30, 0, 0, time.UTC).In(lisbon),
+		Budget: &TripBudget{Currency: "EUR", Items: []BudgetItem{{Category: BudgetAirfare, Amount: 10000}},
+			Converted: &BudgetConversion{Total: Money{Amount: 55000, Currency: "BRL"}}}}
+	unpriced := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusApproved,
+		StartDate: time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)}
+	ignored := []*Trip{
+		{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusRequested, StartDate: time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC), Budget: brl(1)},
+		{ID: uuid.New(), CostCenterID: uuid.New(), Status: StatusApproved, StartDate: time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC), Budget: brl(1)},
+		{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusApproved, StartDate: time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC), Budget: brl(1)},
+	}
+
+	// Concluded trips count what finance approved of their expenses, when it can be told in BRL
+	reviewed := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusConcluded,
+		StartDate: time.Date(2030, 5, 20, 9, 0, 0, 0, time.UTC), Budget: brl(30000)}
+	reimbursedWithoutEstimate := &Trip{ID: uuid.New(), CostCenterID: costCenterID, Status: StatusConcluded,
+		StartDate: time.Date(2030, 5, 25, 9, 0, 0, 0, time.UTC)}
+	reports := []*ExpenseReport{
+		{TripID: concluded.ID, Status: ExpenseApproved, Currency: "BRL", Items: []ExpenseItem{
+			{Amount: 100000, Currency: "BRL"},
+			{Amount: 4000, Currency: "USD", Converted: &BudgetConversion{Total: Money{Amount: 20000, Currency: "BRL"}}},
+		}},
+		{TripID: reviewed.ID, Status: ExpenseSubmitted, Currency: "BRL", Items: []ExpenseItem{{Amount: 99999, Currency: "BRL"}}},
+		{TripID: reimbursedWithoutEstimate.ID, Status: ExpenseReimbursed, Currency: "BRL", Items: []ExpenseItem{{Amount: 40000, Currency: "BRL"}}},
+		{TripID: approved.ID, Status: ExpenseApproved, Currency: "BRL", Items: []ExpenseItem{{Amount: 1, Currency: "BRL"}}},
+	}
+
+	trips := append([]*Trip{approved, concluded, converted, unpriced, reviewed, reimbursedWithoutEstimate}, ignored...)
+	consumption := budget.Consumption(trips, reports)
+
+	assert.Equal(t, int64(285000), consumption.Committed)
+	assert.Equal(t, int64(160000), consumption.Actual)
+	assert.Equal(t, int64(555000), consumption.Remaining)
+	assert.Len(t, consumption.Trips, 5)
+	assert.Equal(t, []uuid.UUID{unpriced.ID}, consumption.UnpricedTripIDs)
+	assert.Equal(t, BudgetTrip{TripID: concluded.ID, StartDate: concluded.StartDate, Status: StatusConcluded, Amount: 120000, Actual: true}, consumption.Trips[1])
+
+	t.Run("Report in another currency counts the estimate", func(t *testing.T) {
+		unconverted := []*ExpenseReport{{TripID: concluded.ID, Status: ExpenseApproved, Currency: "BRL",
+			Items: []ExpenseItem{{Amount: 4000, Currency: "USD"}}}}
+
+		consumption := budget.Consumption([]*Trip{concluded}, unconverted)
+
+		assert.Equal(t, int64(150000), consumption.Committed)
+		assert.Zero(t, consumption.Actual)
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		assert.Nil(t, budget.Check(&Trip{Budget: brl(555000)}, consumption))
+		assert.Nil(t, budget.Check(&Trip{}, consumption), "trips without a budget can't be checked")
+
+		violation := budget.Check(&Trip{Budget: brl(555001)}, consumption)
+		if assert.NotNil(t, violation) {
+			assert.Equal(t, RuleCostCenterBudget, violation.Rule)
+			assert.Equal(t, SeverityWarn, violation.Severity)
+			assert.Equal(t, "trip costs BRL 5550.01, more than what is left of the cost center budget for 2030-04-01 to 2030-06-30", violation.Message)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostCenter_Validate(t *testing.T) {
+	validOrgID := uuid.New()
+
+	t.Run("Valid cost center", func(t *testing.T) {
+		costCenter := &CostCenter{
+			ID:     uuid.New(),
+			OrgID:  validOrgID,
+			Code:   "CC-100",
+			Name:   "Sales",
+			Active: true,
+		}
+
+		err := costCenter.Validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Multiple validation errors", func(t *testing.T) {
+		costCenter := &CostCenter{ID: uuid.New()}
+
+		err := costCenter.Validate()
+		assert.Error(t, err)
+
+		errMsg := err.Error()
+		assert.Contains(t, errMsg, "org_id is required")
+		assert.Contains(t, errMsg, "code is required")
+		assert.Contains(t, errMsg, "name is required")
+	})
+}
+
+func TestDepartment_Validate(t *testing.T) {
+	validOrgID := uuid.New()
+	validCostCenterID := uuid.New()
+
+	t.Run("Valid department", func(t *testing.T) {
+		department := &Department{
+			ID:           uuid.New(),
+			OrgID:        validOrgID,
+			Name:         "Engineering",
+			CostCenterID: validCostCenterID,
+		}
+
+		err := department.Validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Missing cost center", func(t *testing.T) {
+		department := &Department{
+			ID:           uuid.New(),
+			OrgID:        validOrgID,
+			Name:         "Engineering",
+			CostCenterID: uuid.Nil, // Invalid: zero value
+		}
+
+		err := department.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cost_center_id is required")
+	})
+
+	t.Run("Missing name", func(t *testing.T) {
+		department := &Department{
+			ID:           uuid.New(),
+			OrgID:        validOrgID,
+			Name:         "", // Invalid: empty name
+			CostCenterID: validCostCenterID,
+		}
+
+		err := department.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name is required")
+	})
+}
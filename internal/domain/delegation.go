@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delegation lets the delegate decide the approval steps the delegator could
+// decide, while it is active
+type Delegation struct {
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	DelegatorID uuid.UUID `json:"delegator_id"`
+	DelegateID  uuid.UUID `json:"delegate_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	// CostCenterID limits the delegation to trips charged to a cost center
+	CostCenterID *uuid.UUID `json:"cost_center_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Validate checks if the delegation data is valid according to business rules
+func (d *Delegation) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(d.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(d.DelegatorID == uuid.Nil, "delegator_id is required")
+	validationErrors.AddIf(d.DelegateID == uuid.Nil, "delegate_id is required")
+	validationErrors.AddIf(d.DelegatorID != uuid.Nil && d.DelegatorID == d.DelegateID, "cannot delegate to yourself")
+	validationErrors.AddIf(d.StartsAt.IsZero(), "starts_at is required")
+	validationErrors.AddIf(d.EndsAt.IsZero(), "ends_at is required")
+	if !d.StartsAt.IsZero() && !d.EndsAt.IsZero() {
+		validationErrors.AddIf(!d.EndsAt.After(d.StartsAt), "ends_at must be after starts_at")
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// IsActive reports whether the delegation is in effect at the given moment
+func (d *Delegation) IsActive(at time.Time) bool {
+	return !at.Before(d.StartsAt) && at.Before(d.EndsAt)
+}
+
+// Covers reports whether the delegation's scope includes the trip
+func (d *Delegation) Covers(trip *Trip) bool {
+	return d.CostCenterID == nil || *d.CostCenterID == trip.CostCenterID
+}
+
+type DelegationRepository interface {
+	Create(ctx context.Context, delegation *Delegation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Delegation, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns the delegations the user gave or received
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*Delegation, error)
+	// ListActive returns the delegations the delegate holds at the given moment
+	ListActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]*Delegation, error)
+}
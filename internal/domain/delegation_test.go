@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelegation_Validate(t *testing.T) {
+	startsAt := time.Now()
+
+	t.Run("Valid delegation", func(t *testing.T) {
+		delegation := &Delegation{
+			OrgID:       uuid.New(),
+			DelegatorID: uuid.New(),
+			DelegateID:  uuid.New(),
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.AddDate(0, 0, 14),
+		}
+
+		assert.NoError(t, delegation.Validate())
+	})
+
+	t.Run("Delegating to yourself", func(t *testing.T) {
+		userID := uuid.New()
+		delegation := &Delegation{
+			OrgID:       uuid.New(),
+			DelegatorID: userID,
+			DelegateID:  userID,
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.AddDate(0, 0, 14),
+		}
+
+		err := delegation.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot delegate to yourself")
+	})
+
+	t.Run("Ends before it starts", func(t *testing.T) {
+		delegation := &Delegation{
+			OrgID:       uuid.New(),
+			DelegatorID: uuid.New(),
+			DelegateID:  uuid.New(),
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.Add(-time.Hour),
+		}
+
+		err := delegation.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ends_at must be after starts_at")
+	})
+}
+
+func TestDelegation_IsActiveAndCovers(t *testing.T) {
+	startsAt := time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)
+	costCenterID := uuid.New()
+	delegation := &Delegation{StartsAt: startsAt, EndsAt: startsAt.AddDate(0, 0, 10), CostCenterID: &costCenterID}
+
+	assert.False(t, delegation.IsActive(startsAt.Add(-time.Second)))
+	assert.True(t, delegation.IsActive(startsAt))
+	assert.False(t, delegation.IsActive(startsAt.AddDate(0, 0, 10)))
+
+	assert.True(t, delegation.Covers(&Trip{CostCenterID: costCenterID}))
+	assert.False(t, delegation.Covers(&Trip{CostCenterID: uuid.New()}))
+	assert.True(t, (&Delegation{}).Covers(&Trip{CostCenterID: uuid.New()}))
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+type DestinationKind string
+
+const (
+	DestinationCity    DestinationKind = "city"
+	DestinationAirport DestinationKind = "airport"
+)
+
+// Destination is an entry of the destination catalog: a city, identified by
+// its IATA city code, or one of its airports
+type Destination struct {
+	Code string          `json:"code"`
+	Kind DestinationKind `json:"kind"`
+	Name string          `json:"name"`
+	// CityCode is the city an airport serves
+	CityCode    string  `json:"city_code,omitempty"`
+	CountryCode string  `json:"country_code"`
+	TimeZone    string  `json:"time_zone"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	// Aliases are other names the destination is searched by, e.g. SP for São Paulo
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ReferenceCode is the code trips store for the destination: airports are
+// normalized to their city, so reports group all trips to a city together
+func (d *Destination) ReferenceCode() string {
+	if d.Kind == DestinationAirport && d.CityCode != "" {
+		return d.CityCode
+	}
+	return d.Code
+}
+
+// MatchesName reports whether the normalized name, see NormalizeSearchText,
+// is the name or one of the aliases of the destination
+func (d *Destination) MatchesName(name string) bool {
+	if NormalizeSearchText(d.Name) == name {
+		return true
+	}
+	for _, alias := range d.Aliases {
+		if NormalizeSearchText(alias) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeSearchText lower-cases text and strips its accents, so "São Paulo",
+// "sao paulo" and "SAO PAULO" compare equal
+func NormalizeSearchText(text string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(stripAccents, text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(strings.TrimSpace(folded))
+}
+
+type DestinationRepository interface {
+	// Search returns up to limit destinations whose code, name or aliases match
+	// the query, best matches first
+	Search(ctx context.Context, query string, limit int) ([]*Destination, error)
+	// FindByCode returns the destination with the IATA code, or nil
+	FindByCode(ctx context.Context, code string) (*Destination, error)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchText(t *testing.T) {
+	assert.Equal(t, "sao paulo", NormalizeSearchText(" São Paulo "))
+	assert.Equal(t, "zurich", NormalizeSearchText("ZÜRICH"))
+	assert.Equal(t, "bogota", NormalizeSearchText("Bogotá"))
+}
+
+func TestDestination_ReferenceCode(t *testing.T) {
+	airport := &Destination{Code: "GRU", Kind: DestinationAirport, CityCode: "SAO"}
+	city := &Destination{Code: "LIS", Kind: DestinationCity}
+
+	assert.Equal(t, "SAO", airport.ReferenceCode())
+	assert.Equal(t, "LIS", city.ReferenceCode())
+}
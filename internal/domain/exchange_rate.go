@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExchangeRate converts Currency to the organization's base currency from
+// EffectiveDate on: 1 Currency is worth Rate BaseCurrency
+type ExchangeRate struct {
+	ID            uuid.UUID `json:"id"`
+	OrgID         uuid.UUID `json:"org_id"`
+	Currency      string    `json:"currency"`
+	BaseCurrency  string    `json:"base_currency"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Validate checks if the exchange rate data is valid according to business rules
+func (r *ExchangeRate) Validate() error {
+	validationErrors := NewValidationErrors()
+	r.validate(validationErrors, "")
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+func (r *ExchangeRate) validate(validationErrors *ValidationErrors, prefix string) {
+	validationErrors.AddIf(r.OrgID == uuid.Nil, prefix+"org_id is required")
+	validationErrors.AddIf(!IsCurrencyCode(r.Currency), prefix+"currency must be an ISO 4217 code, e.g. USD")
+	validationErrors.AddIf(!IsCurrencyCode(r.BaseCurrency), prefix+"base_currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(r.Currency == r.BaseCurrency, prefix+"currency must differ from the base currency")
+	validationErrors.AddIf(r.Rate <= 0, prefix+"rate must be positive")
+	validationErrors.AddIf(r.EffectiveDate.IsZero(), prefix+"effective_date is required")
+}
+
+// Convert returns the amount in the base currency
+func (r *ExchangeRate) Convert(m Money) Money {
+	return m.convert(r.Rate, r.BaseCurrency)
+}
+
+// ValidateExchangeRates checks a batch of rates, e.g. an imported file,
+// prefixing the errors with the line of each rate
+func ValidateExchangeRates(rates []*ExchangeRate) error {
+	validationErrors := NewValidationErrors()
+	for i, rate := range rates {
+		rate.validate(validationErrors, fmt.Sprintf("line %d: ", i+2))
+	}
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// ParseExchangeRatesCSV reads rates from a CSV file with the header
+// currency,rate,effective_date, e.g. "USD,5.4321,2030-05-01". Rates use a
+// decimal point and dates are YYYY-MM-DD.
+func ParseExchangeRatesCSV(data []byte) ([]*ExchangeRate, error) {
+	validationErrors := NewValidationErrors()
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		validationErrors.Add("invalid CSV file: " + err.Error())
+		return nil, validationErrors
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"currency", "rate", "effective_date"} {
+		_, ok := columns[name]
+		validationErrors.AddIf(!ok, "invalid CSV file: missing the "+name+" column")
+	}
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+
+	var rates []*ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			validationErrors.Add("invalid CSV file: " + err.Error())
+			return nil, validationErrors
+		}
+
+		prefix := fmt.Sprintf("line %d: ", line)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[columns["rate"]]), 64)
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid rate %q", record[columns["rate"]]))
+		effectiveDate, err := time.Parse("2006-01-02", strings.TrimSpace(record[columns["effective_date"]]))
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid effective_date %q, expected YYYY-MM-DD", record[columns["effective_date"]]))
+
+		rates = append(rates, &ExchangeRate{
+			Currency:      strings.ToUpper(strings.TrimSpace(record[columns["currency"]])),
+			Rate:          rate,
+			EffectiveDate: effectiveDate,
+		})
+	}
+	validationErrors.AddIf(len(rates) == 0 && !validationErrors.HasErrors(), "the file has no exchange rates")
+
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+	return rates, nil
+}
+
+type ExchangeRateRepository interface {
+	// Save stores the rates, replacing the ones of the same currencies and dates
+	Save(ctx context.Context, rates []*ExchangeRate) error
+	// FindEffective returns the latest rate from currency to baseCurrency in effect at the moment, or nil
+	FindEffective(ctx context.Context, currency, baseCurrency string, at time.Time) (*ExchangeRate, error)
+	List(ctx context.Context) ([]*ExchangeRate, error)
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExpenseReportStatus string
+
+const (
+	ExpenseDraft      ExpenseReportStatus = "draft"
+	ExpenseSubmitted  ExpenseReportStatus = "submitted"
+	ExpenseApproved   ExpenseReportStatus = "approved"
+	ExpenseRejected   ExpenseReportStatus = "rejected"
+	ExpenseReimbursed ExpenseReportStatus = "reimbursed"
+)
+
+// ErrExpenseReportStatusChanged is returned when a report is saved on the
+// assumption of a status it no longer has, e.g. a report approved twice at once
+var ErrExpenseReportStatusChanged = errors.New("expense report status changed meanwhile")
+
+func (s ExpenseReportStatus) IsValid() bool {
+	switch s {
+	case ExpenseDraft, ExpenseSubmitted, ExpenseApproved, ExpenseRejected, ExpenseReimbursed:
+		return true
+	}
+	return false
+}
+
+// ExpenseItem is an expense the traveler had on the trip
+type ExpenseItem struct {
+	ID uuid.UUID `json:"id"`
+	// Date is the day the expense was made
+	Date     time.Time      `json:"date"`
+	Category BudgetCategory `json:"category"`
+	// Amount is in minor units of Currency, e.g. cents
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	// ReceiptRef identifies the receipt of the expense, e.g. an invoice number or a file name
+	ReceiptRef string `json:"receipt_ref,omitempty"`
+	// Converted is the amount in the report currency, for items in another
+	// currency. It's taken with the rate in effect on the day of the expense.
+	Converted *BudgetConversion `json:"converted,omitempty"`
+}
+
+// AmountIn returns the amount in the currency, when it's the item currency or
+// the one the item was converted to
+func (i *ExpenseItem) AmountIn(currency string) (int64, bool) {
+	if i.Currency == currency {
+		return i.Amount, true
+	}
+	if i.Converted != nil && i.Converted.Total.Currency == currency {
+		return i.Converted.Total.Amount, true
+	}
+	return 0, false
+}
+
+// ConvertWith records the amount in the base currency of the rate
+func (i *ExpenseItem) ConvertWith(rate *ExchangeRate, at time.Time) {
+	i.Converted = &BudgetConversion{
+		Total:       rate.Convert(Money{Amount: i.Amount, Currency: i.Currency}),
+		Rate:        rate.Rate,
+		RateDate:    rate.EffectiveDate,
+		ConvertedAt: at,
+	}
+}
+
+// AfterTrip reports whether the expense was made after the last day of the
+// trip, in the trip's zone. Tickets are often bought before the trip, but
+// nothing is spent on it after it ends.
+func (i *ExpenseItem) AfterTrip(trip *Trip) bool {
+	return civilDate(i.Date).After(civilDate(trip.EndDate.In(trip.Location())))
+}
+
+func (i *ExpenseItem) validate(validationErrors *ValidationErrors, prefix string) {
+	validationErrors.AddIf(i.Date.IsZero(), prefix+"date is required")
+	validationErrors.AddIf(!i.Category.IsValid(), prefix+"category must be airfare, lodging, per_diem, ground_transport or other")
+	validationErrors.AddIf(i.Amount <= 0, prefix+"amount must be positive")
+	validationErrors.AddIf(!IsCurrencyCode(i.Currency), prefix+"currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(i.Description == "", prefix+"description is required")
+	validationErrors.AddIf(len(i.Description) > 255, prefix+"description must have at most 255 characters")
+	validationErrors.AddIf(len(i.ReceiptRef) > 255, prefix+"receipt_ref must have at most 255 characters")
+}
+
+// ExpenseReport gathers the expenses of a trip for reimbursement. It has its
+// own workflow, apart from the trip approval: the traveler submits it, finance
+// approves or rejects it and, once approved, reimburses it. A rejected report
+// can be corrected and submitted again.
+type ExpenseReport struct {
+	ID         uuid.UUID           `json:"id"`
+	OrgID      uuid.UUID           `json:"org_id"`
+	TripID     uuid.UUID           `json:"trip_id"`
+	TravelerID uuid.UUID           `json:"traveler_id"`
+	Status     ExpenseReportStatus `json:"status"`
+	// Currency is the organization's base currency, the one the report is totaled in
+	Currency string        `json:"currency"`
+	Items    []ExpenseItem `json:"items"`
+	// RejectionReason is kept until the report is submitted again
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+	ReviewedBy      *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	ReimbursedBy    *uuid.UUID `json:"reimbursed_by,omitempty"`
+	ReimbursedAt    *time.Time `json:"reimbursed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Validate checks if the report data is valid according to business rules
+func (r *ExpenseReport) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(r.OrgID == uuid.Nil, "org_id is required")
+	validationErrors.AddIf(r.TripID == uuid.Nil, "trip_id is required")
+	validationErrors.AddIf(r.TravelerID == uuid.Nil, "traveler_id is required")
+	validationErrors.AddIf(!r.Status.IsValid(), "status must be draft, submitted, approved, rejected or reimbursed")
+	validationErrors.AddIf(!IsCurrencyCode(r.Currency), "currency must be an ISO 4217 code, e.g. BRL")
+	for i := range r.Items {
+		r.Items[i].validate(validationErrors, fmt.Sprintf("items[%d]: ", i))
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// Editable reports whether the traveler can still change the items
+func (r *ExpenseReport) Editable() bool {
+	return r.Status == ExpenseDraft || r.Status == ExpenseRejected
+}
+
+// FindItem returns the item with the ID, or nil
+func (r *ExpenseReport) FindItem(id uuid.UUID) *ExpenseItem {
+	for i := range r.Items {
+		if r.Items[i].ID == id {
+			return &r.Items[i]
+		}
+	}
+	return nil
+}
+
+// Total adds up the items in the report currency
+func (r *ExpenseReport) Total() int64 {
+	var total int64
+	for _, item := range r.Items {
+		amount, _ := item.AmountIn(r.Currency)
+		total += amount
+	}
+	return total
+}
+
+// TotalIn adds up the items in the currency. It's false when an item is in
+// another currency and wasn't converted to it.
+func (r *ExpenseReport) TotalIn(currency string) (int64, bool) {
+	var total int64
+	for _, item := range r.Items {
+		amount, ok := item.AmountIn(currency)
+		if !ok {
+			return 0, false
+		}
+		total += amount
+	}
+	return total, true
+}
+
+// Spent reports whether finance accepted the expenses, making them the
+// actual cost of the trip
+func (r *ExpenseReport) Spent() bool {
+	return r.Status == ExpenseApproved || r.Status == ExpenseReimbursed
+}
+
+// ExpenseCategoryTotal is what was spent and budgeted in a category
+type ExpenseCategoryTotal struct {
+	Category BudgetCategory `json:"category"`
+	Spent    int64          `json:"spent"`
+	// Budgeted is zero when the trip budget can't be told in the report currency
+	Budgeted int64 `json:"budgeted"`
+}
+
+// ExpenseComparison compares the expenses of a trip with its approved budget.
+// Amounts are in minor units of the report currency.
+type ExpenseComparison struct {
+	Total int64 `json:"total"`
+	// Budget is the total of the trip budget, nil when the trip has none in the report currency
+	Budget *int64 `json:"budget"`
+	// Remaining is what is left of the budget; it's negative when the expenses go over it
+	Remaining  *int64                 `json:"remaining"`
+	OverBudget bool                   `json:"over_budget"`
+	Categories []ExpenseCategoryTotal `json:"categories"`
+}
+
+// Compare adds up the items by category and compares them with the trip budget.
+// A budget in another currency is compared with the rate it was converted with.
+func (r *ExpenseReport) Compare(trip *Trip) ExpenseComparison {
+	comparison := ExpenseComparison{Categories: []ExpenseCategoryTotal{}}
+	index := make(map[BudgetCategory]int)
+	category := func(c BudgetCategory) *ExpenseCategoryTotal {
+		i, ok := index[c]
+		if !ok {
+			i = len(comparison.Categories)
+			index[c] = i
+			comparison.Categories = append(comparison.Categories, ExpenseCategoryTotal{Category: c})
+		}
+		return &comparison.Categories[i]
+	}
+
+	for _, item := range r.Items {
+		amount, _ := item.AmountIn(r.Currency)
+		category(item.Category).Spent += amount
+		comparison.Total += amount
+	}
+
+	budget := trip.Budget
+	if budget == nil {
+		return comparison
+	}
+	total, ok := budget.TotalIn(r.Currency)
+	if !ok {
+		return comparison
+	}
+	for _, item := range budget.Items {
+		amount := item.Amount
+		if budget.Currency != r.Currency {
+			amount = Money{Amount: item.Amount, Currency: budget.Currency}.convert(budget.Converted.Rate, r.Currency).Amount
+		}
+		category(item.Category).Budgeted += amount
+	}
+	remaining := total - comparison.Total
+	comparison.Budget = &total
+	comparison.Remaining = &remaining
+	comparison.OverBudget = remaining < 0
+	return comparison
+}
+
+// ExpenseReportDetails is a report with its comparison to the trip budget
+type ExpenseReportDetails struct {
+	*ExpenseReport
+	Comparison ExpenseComparison `json:"budget_comparison"`
+}
+
+type ExpenseReportRepository interface {
+	// Create stores the report with its items
+	Create(ctx context.Context, report *ExpenseReport) error
+	// Update stores the status and review data of the report and replaces its
+	// items. Card transactions matched to items it drops go back to unmatched.
+	// It returns ErrExpenseReportStatusChanged unless the stored report is still in the from status.
+	Update(ctx context.Context, report *ExpenseReport, from ExpenseReportStatus) error
+	FindByTripID(ctx context.Context, tripID uuid.UUID) (*ExpenseReport, error)
+	// ListByTripIDs returns the reports of the trips, in no particular order
+	ListByTripIDs(ctx context.Context, tripIDs []uuid.UUID) ([]*ExpenseReport, error)
+	// List returns the reports in the status, or all of them when status is nil, oldest first
+	List(ctx context.Context, status *ExpenseReportStatus) ([]*ExpenseReport, error)
+}
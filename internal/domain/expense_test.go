@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpenseReport_Validate(t *testing.T) {
+	report := ExpenseReport{
+		OrgID:      uuid.New(),
+		TripID:     uuid.New(),
+		TravelerID: uuid.New(),
+		Status:     ExpenseDraft,
+		Currency:   "BRL",
+		Items: []ExpenseItem{{
+			Date:        time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC),
+			Category:    BudgetLodging,
+			Amount:      45000,
+			Currency:    "BRL",
+			Description: "Hotel",
+		}},
+	}
+	assert.NoError(t, report.Validate())
+
+	report.Items = append(report.Items, ExpenseItem{Category: "meals", Currency: "real"})
+	err := report.Validate()
+	assert.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"items[1]: date is required",
+		"items[1]: category must be airfare, lodging, per_diem, ground_transport or other",
+		"items[1]: amount must be positive",
+		"items[1]: currency must be an ISO 4217 code, e.g. BRL",
+		"items[1]: description is required",
+	}, err.(*ValidationErrors).GetErrors())
+}
+
+func TestExpenseItem_AfterTrip(t *testing.T) {
+	// The trip ends late on May 12 in São Paulo, already May 13 in UTC
+	trip := &Trip{
+		StartDate: time.Date(2030, 5, 10, 3, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2030, 5, 13, 1, 0, 0, 0, time.UTC),
+		TimeZone:  "America/Sao_Paulo",
+	}
+
+	item := ExpenseItem{Date: time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)}
+	assert.False(t, item.AfterTrip(trip))
+	item.Date = time.Date(2030, 5, 12, 0, 0, 0, 0, time.UTC)
+	assert.False(t, item.AfterTrip(trip))
+	item.Date = time.Date(2030, 5, 13, 0, 0, 0, 0, time.UTC)
+	assert.True(t, item.AfterTrip(trip))
+}
+
+func TestExpenseReport_Compare(t *testing.T) {
+	report := &ExpenseReport{
+		Currency: "BRL",
+		Items: []ExpenseItem{
+			{Category: BudgetLodging, Amount: 90000, Currency: "BRL"},
+			{Category: BudgetGroundTransport, Amount: 5000, Currency: "BRL"},
+			{Category: BudgetLodging, Amount: 10000, Currency: "EUR", Converted: &BudgetConversion{
+				Total: Money{Amount: 60000, Currency: "BRL"},
+				Rate:  6,
+			}},
+		},
+	}
+
+	t.Run("Budget in the report currency", func(t *testing.T) {
+		trip := &Trip{Budget: &TripBudget{Currency: "BRL", Items: []BudgetItem{
+			{Category: BudgetAirfare, Amount: 100000},
+			{Category: BudgetLodging, Amount: 120000},
+		}}}
+
+		comparison := report.Compare(trip)
+
+		assert.Equal(t, int64(155000), comparison.Total)
+		assert.Equal(t, int64(220000), *comparison.Budget)
+		assert.Equal(t, int64(65000), *comparison.Remaining)
+		assert.False(t, comparison.OverBudget)
+		assert.Equal(t, []ExpenseCategoryTotal{
+			{Category: BudgetLodging, Spent: 150000, Budgeted: 120000},
+			{Category: BudgetGroundTransport, Spent: 5000},
+			{Category: BudgetAirfare, Budgeted: 100000},
+		}, comparison.Categories)
+	})
+
+	t.Run("Budget converted from another currency", func(t *testing.T) {
+		trip := &Trip{Budget: &TripBudget{
+			Currency:  "USD",
+			Items:     []BudgetItem{{Category: BudgetLodging, Amount: 25000}},
+			Converted: &BudgetConversion{Total: Money{Amount: 125000, Currency: "BRL"}, Rate: 5},
+		}}
+
+		comparison := report.Compare(trip)
+
+		assert.Equal(t, int64(125000), *comparison.Budget)
+		assert.Equal(t, int64(-30000), *comparison.Remaining)
+		assert.True(t, comparison.OverBudget)
+		assert.Equal(t, int64(125000), comparison.Categories[0].Budgeted)
+	})
+
+	t.Run("Trip without budget", func(t *testing.T) {
+		comparison := report.Compare(&Trip{})
+
+		assert.Equal(t, int64(155000), comparison.Total)
+		assert.Nil(t, comparison.Budget)
+		assert.Nil(t, comparison.Remaining)
+		assert.False(t, comparison.OverBudget)
+	})
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseICalendar reads the events of an iCalendar (RFC 5545) file as blackout
+// periods. All-day events and events without a time zone are read in UTC.
+// Recurring events aren't supported, export them with their occurrences expanded.
+func ParseICalendar(data []byte) ([]BlackoutPeriod, error) {
+	validationErrors := NewValidationErrors()
+
+	var periods []BlackoutPeriod
+	var event map[string]icalProperty
+	inCalendar, events := false, 0
+	for _, line := range unfoldICalendarLines(string(data)) {
+		prop, ok := parseICalendarLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			inCalendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event = map[string]icalProperty{}
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if event == nil {
+				continue
+			}
+			events++
+			prefix := fmt.Sprintf("event %d: ", events)
+			period, skip, err := icalEventPeriod(event)
+			if err != nil {
+				validationErrors.Add(prefix + err.Error())
+			} else if !skip {
+				periods = append(periods, period)
+			}
+			event = nil
+		case event != nil:
+			// Only the first occurrence of a property counts
+			if _, seen := event[prop.name]; !seen {
+				event[prop.name] = prop
+			}
+		}
+	}
+
+	validationErrors.AddIf(!inCalendar, "invalid iCalendar file: missing BEGIN:VCALENDAR")
+	validationErrors.AddIf(inCalendar && len(periods) == 0 && !validationErrors.HasErrors(), "the calendar has no events")
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].StartsAt.Before(periods[j].StartsAt)
+	})
+	return periods, nil
+}
+
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICalendarLines joins the lines folded by a leading space or tab
+func unfoldICalendarLines(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// parseICalendarLine splits a content line like DTSTART;TZID=America/Sao_Paulo:20240101T090000
+func parseICalendarLine(line string) (icalProperty, bool) {
+	// The value starts at the first colon outside a quoted parameter
+	colon, quoted := -1, false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icalProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// icalEventPeriod turns an event into a period. Canceled events are skipped.
+func icalEventPeriod(event map[string]icalProperty) (period BlackoutPeriod, skip bool, err error) {
+	if status, ok := event["STATUS"]; ok && strings.EqualFold(status.value, "CANCELLED") {
+		return period, true, nil
+	}
+	if _, ok := event["RRULE"]; ok {
+		return period, false, fmt.Errorf("recurring events are not supported")
+	}
+
+	start, ok := event["DTSTART"]
+	if !ok {
+		return period, false, fmt.Errorf("DTSTART is required")
+	}
+	startsAt, allDay, err := parseICalendarTime(start)
+	if err != nil {
+		return period, false, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+
+	var endsAt time.Time
+	if end, ok := event["DTEND"]; ok {
+		if endsAt, _, err = parseICalendarTime(end); err != nil {
+			return period, false, fmt.Errorf("invalid DTEND: %w", err)
+		}
+	} else if duration, ok := event["DURATION"]; ok {
+		d, err := parseICalendarDuration(duration.value)
+		if err != nil {
+			return period, false, fmt.Errorf("invalid DURATION: %w", err)
+		}
+		endsAt = startsAt.Add(d)
+	} else if allDay {
+		endsAt = startsAt.AddDate(0, 0, 1)
+	} else {
+		return period, false, fmt.Errorf("DTEND is required")
+	}
+	if !endsAt.After(startsAt) {
+		return period, false, fmt.Errorf("DTEND must be after DTSTART")
+	}
+
+	period = BlackoutPeriod{
+		Summary:  unescapeICalendarText(event["SUMMARY"].value),
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	}
+	return period, false, nil
+}
+
+// parseICalendarTime reads a DATE or DATE-TIME value and reports whether it was a DATE
+func parseICalendarTime(prop icalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	location := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	return t, false, err
+}
+
+var icalDurationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICalendarDuration reads a positive duration like P1D or PT2H30M
+func parseICalendarDuration(value string) (time.Duration, error) {
+	match := icalDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("%q is not a duration", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, err
+		}
+		duration += time.Duration(n) * unit
+	}
+	return duration, nil
+}
+
+var icalTextEscapes = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+
+func unescapeICalendarText(value string) string {
+	return icalTextEscapes.Replace(strings.TrimSpace(value))
+}
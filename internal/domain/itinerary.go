@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransportMode string
+
+const (
+	TransportFlight TransportMode = "flight"
+	TransportTrain  TransportMode = "train"
+	TransportBus    TransportMode = "bus"
+	TransportCar    TransportMode = "car"
+	TransportShip   TransportMode = "ship"
+)
+
+func (m TransportMode) IsValid() bool {
+	switch m {
+	case TransportFlight, TransportTrain, TransportBus, TransportCar, TransportShip:
+		return true
+	}
+	return false
+}
+
+// ItineraryLeg is one stretch of a trip, e.g. São Paulo to Lisbon by plane.
+// Legs are kept in travel order by Position.
+type ItineraryLeg struct {
+	ID          uuid.UUID     `json:"id"`
+	Position    int           `json:"position"`
+	Origin      string        `json:"origin"`
+	Destination string        `json:"destination"`
+	DepartAt    time.Time     `json:"depart_at"`
+	ArriveAt    time.Time     `json:"arrive_at"`
+	Mode        TransportMode `json:"mode"`
+}
+
+// ItineraryDestination describes where an itinerary goes: the destinations of
+// its legs in order, leaving out the way back to where it started.
+// São Paulo → Lisbon → Berlin → São Paulo goes to "Lisbon, Berlin".
+func ItineraryDestination(legs []ItineraryLeg) string {
+	if len(legs) == 0 {
+		return ""
+	}
+
+	stops := legs
+	home := strings.TrimSpace(legs[0].Origin)
+	if len(legs) > 1 && strings.EqualFold(strings.TrimSpace(legs[len(legs)-1].Destination), home) {
+		stops = legs[:len(legs)-1]
+	}
+
+	var destinations []string
+	for _, leg := range stops {
+		destination := strings.TrimSpace(leg.Destination)
+		if n := len(destinations); n > 0 && strings.EqualFold(destinations[n-1], destination) {
+			continue
+		}
+		destinations = append(destinations, destination)
+	}
+	return strings.Join(destinations, ", ")
+}
+
+// validateItinerary checks that each leg starts where the previous one ended,
+// after it arrived, and that the whole itinerary fits in the trip's days
+func (t *Trip) validateItinerary(validationErrors *ValidationErrors) {
+	// Legs may leave any time on the first day and arrive any time on the last
+	windowStart := startOfDay(t.StartDate)
+	windowEnd := startOfDay(t.EndDate).AddDate(0, 0, 1)
+
+	for i, leg := range t.Legs {
+		prefix := fmt.Sprintf("legs[%d]: ", i)
+		validationErrors.AddIf(strings.TrimSpace(leg.Origin) == "", prefix+"origin is required")
+		validationErrors.AddIf(strings.TrimSpace(leg.Destination) == "", prefix+"destination is required")
+		validationErrors.AddIf(!leg.Mode.IsValid(), prefix+"mode must be flight, train, bus, car or ship")
+		validationErrors.AddIf(leg.DepartAt.IsZero(), prefix+"depart_at is required")
+		validationErrors.AddIf(leg.ArriveAt.IsZero(), prefix+"arrive_at is required")
+		if leg.DepartAt.IsZero() || leg.ArriveAt.IsZero() {
+			continue
+		}
+		validationErrors.AddIf(!leg.ArriveAt.After(leg.DepartAt), prefix+"arrive_at must be after depart_at")
+
+		if !t.StartDate.IsZero() && !t.EndDate.IsZero() {
+			validationErrors.AddIf(leg.DepartAt.Before(windowStart) || !leg.ArriveAt.Before(windowEnd),
+				prefix+"leg must be within the trip dates")
+		}
+
+		if i == 0 {
+			continue
+		}
+		previous := t.Legs[i-1]
+		validationErrors.AddIf(!strings.EqualFold(strings.TrimSpace(leg.Origin), strings.TrimSpace(previous.Destination)),
+			fmt.Sprintf("%smust depart from %s, where the previous leg arrives", prefix, previous.Destination))
+		validationErrors.AddIf(!previous.ArriveAt.IsZero() && leg.DepartAt.Before(previous.ArriveAt),
+			prefix+"must depart after the previous leg arrives")
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItineraryDestination(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		legs := []ItineraryLeg{
+			{Origin: "São Paulo", Destination: "Lisbon"},
+			{Origin: "Lisbon", Destination: "Berlin"},
+			{Origin: "Berlin", Destination: "São Paulo"},
+		}
+		assert.Equal(t, "Lisbon, Berlin", ItineraryDestination(legs))
+	})
+
+	t.Run("One way", func(t *testing.T) {
+		legs := []ItineraryLeg{{Origin: "São Paulo", Destination: "Lisbon"}}
+		assert.Equal(t, "Lisbon", ItineraryDestination(legs))
+	})
+
+	t.Run("No legs", func(t *testing.T) {
+		assert.Empty(t, ItineraryDestination(nil))
+	})
+}
+
+func TestTrip_SetLegs(t *testing.T) {
+	trip := &Trip{Destination: "Lisboa", Type: TripInternational,
+		StartDate: time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC)}
+
+	trip.SetLegs([]ItineraryLeg{
+		{Origin: "São Paulo", Destination: "Lisbon"},
+		{Origin: "Lisbon", Destination: "São Paulo"},
+	})
+
+	require.Len(t, trip.Legs, 2)
+	assert.Equal(t, 1, trip.Legs[0].Position)
+	assert.Equal(t, 2, trip.Legs[1].Position)
+	assert.NotEqual(t, uuid.Nil, trip.Legs[0].ID)
+	assert.Equal(t, "Lisbon", trip.Destination)
+	assert.Equal(t, "São Paulo → Lisbon → São Paulo (international), 10 May 2030 to 20 May 2030", trip.ItinerarySummary())
+
+	trip.SetLegs(nil)
+	assert.Empty(t, trip.Legs)
+	assert.Equal(t, "Lisbon", trip.Destination)
+}
+
+func TestTrip_Validate_Itinerary(t *testing.T) {
+	start := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC)
+	newTrip := func(legs ...ItineraryLeg) *Trip {
+		trip := &Trip{RequesterID: uuid.New(), CostCenterID: uuid.New(), Type: TripInternational,
+			StartDate: start, EndDate: end, Status: StatusRequested}
+		trip.SetLegs(legs)
+		return trip
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2030, 5, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("Valid itinerary", func(t *testing.T) {
+		trip := newTrip(
+			ItineraryLeg{Origin: "São Paulo", Destination: "Lisbon", DepartAt: at(10, 22), ArriveAt: at(11, 11), Mode: TransportFlight},
+			ItineraryLeg{Origin: "Lisbon", Destination: "Berlin", DepartAt: at(15, 8), ArriveAt: at(15, 13), Mode: TransportFlight},
+			// Arrives late on the last day of the trip
+			ItineraryLeg{Origin: "Berlin", Destination: "São Paulo", DepartAt: at(20, 9), ArriveAt: at(20, 23), Mode: TransportFlight},
+		)
+
+		assert.NoError(t, trip.Validate())
+	})
+
+	t.Run("Broken continuity", func(t *testing.T) {
+		trip := newTrip(
+			ItineraryLeg{Origin: "São Paulo", Destination: "Lisbon", DepartAt: at(10, 22), ArriveAt: at(11, 11), Mode: TransportFlight},
+			ItineraryLeg{Origin: "Porto", Destination: "Berlin", DepartAt: at(11, 8), ArriveAt: at(11, 13), Mode: TransportTrain},
+		)
+
+		err := trip.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "legs[1]: must depart from Lisbon, where the previous leg arrives")
+		assert.Contains(t, err.Error(), "legs[1]: must depart after the previous leg arrives")
+	})
+
+	t.Run("Leg outside the trip dates", func(t *testing.T) {
+		trip := newTrip(
+			ItineraryLeg{Origin: "São Paulo", Destination: "Lisbon", DepartAt: at(9, 22), ArriveAt: at(10, 11), Mode: TransportFlight},
+			ItineraryLeg{Origin: "Lisbon", Destination: "São Paulo", DepartAt: at(21, 9), ArriveAt: at(21, 20), Mode: TransportFlight},
+		)
+
+		err := trip.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "legs[0]: leg must be within the trip dates")
+		assert.Contains(t, err.Error(), "legs[1]: leg must be within the trip dates")
+	})
+
+	t.Run("Incomplete leg", func(t *testing.T) {
+		trip := newTrip(ItineraryLeg{Origin: "São Paulo", Destination: "Lisbon", DepartAt: at(11, 10), ArriveAt: at(11, 9), Mode: "rocket"})
+
+		err := trip.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "legs[0]: mode must be flight, train, bus, car or ship")
+		assert.Contains(t, err.Error(), "legs[0]: arrive_at must be after depart_at")
+	})
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// JobLocker makes sure a background job runs on a single replica at a time
+type JobLocker interface {
+	// TryLock takes the named lock without waiting for it. When the lock is
+	// acquired, release must be called once the job is done.
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money is an amount in minor units of an ISO 4217 currency: {150000, "BRL"} is R$ 1.500,00
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// currencyDigits lists the currencies whose minor unit isn't a hundredth
+var currencyDigits = map[string]int{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 code, e.g. BRL
+func IsCurrencyCode(code string) bool {
+	return currencyCodePattern.MatchString(code)
+}
+
+// CurrencyDigits is the number of decimal places of the currency's minor unit
+func CurrencyDigits(currency string) int {
+	if digits, ok := currencyDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+// String formats the amount with the decimal places of its currency, e.g. "BRL 1500.00"
+func (m Money) String() string {
+	return m.Currency + " " + m.Decimal()
+}
+
+// Decimal formats the amount alone with the decimal places of its currency, e.g. "1500.00"
+func (m Money) Decimal() string {
+	digits := CurrencyDigits(m.Currency)
+	if digits == 0 {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := int64(math.Pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, digits, amount%unit)
+}
+
+// convert multiplies the amount by rate, going from the minor unit of its
+// currency to the one of currency, and rounds to the nearest minor unit
+func (m Money) convert(rate float64, currency string) Money {
+	scale := math.Pow10(CurrencyDigits(currency) - CurrencyDigits(m.Currency))
+	return Money{Amount: int64(math.Round(float64(m.Amount) * rate * scale)), Currency: strings.ToUpper(currency)}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "BRL 1500.00", Money{Amount: 150000, Currency: "BRL"}.String())
+	assert.Equal(t, "USD -0.05", Money{Amount: -5, Currency: "USD"}.String())
+	assert.Equal(t, "JPY 12000", Money{Amount: 12000, Currency: "JPY"}.String())
+	assert.Equal(t, "KWD 1.250", Money{Amount: 1250, Currency: "KWD"}.String())
+	assert.Equal(t, "1500.00", Money{Amount: 150000, Currency: "BRL"}.Decimal())
+}
+
+func TestExchangeRate_Convert(t *testing.T) {
+	t.Run("Rounds to the minor unit", func(t *testing.T) {
+		rate := &ExchangeRate{Currency: "EUR", BaseCurrency: "BRL", Rate: 5.8765}
+		// EUR 100.01 is BRL 587.708765
+		assert.Equal(t, Money{Amount: 58771, Currency: "BRL"}, rate.Convert(Money{Amount: 10001, Currency: "EUR"}))
+	})
+
+	t.Run("Currencies without cents", func(t *testing.T) {
+		rate := &ExchangeRate{Currency: "JPY", BaseCurrency: "BRL", Rate: 0.0362}
+		// JPY 50,000 is BRL 1,810.00
+		assert.Equal(t, Money{Amount: 181000, Currency: "BRL"}, rate.Convert(Money{Amount: 50000, Currency: "JPY"}))
+	})
+}
+
+func TestParseExchangeRatesCSV(t *testing.T) {
+	t.Run("Valid file", func(t *testing.T) {
+		data := "currency,rate,effective_date\nusd, 5.4321, 2030-05-01\nEUR,5.8765,2030-05-01\n"
+
+		rates, err := ParseExchangeRatesCSV([]byte(data))
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "USD", rates[0].Currency)
+		assert.Equal(t, 5.4321, rates[0].Rate)
+		assert.Equal(t, time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC), rates[1].EffectiveDate)
+	})
+
+	t.Run("Invalid lines", func(t *testing.T) {
+		data := "currency,rate,effective_date\nUSD,5,43,2030-05-01\n"
+
+		_, err := ParseExchangeRatesCSV([]byte(data))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid CSV file")
+
+		data = "currency,rate,effective_date\nUSD,abc,01/05/2030\n"
+		_, err = ParseExchangeRatesCSV([]byte(data))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `line 2: invalid rate "abc"`)
+		assert.Contains(t, err.Error(), `line 2: invalid effective_date "01/05/2030", expected YYYY-MM-DD`)
+	})
+
+	t.Run("Missing columns", func(t *testing.T) {
+		_, err := ParseExchangeRatesCSV([]byte("currency,value\nUSD,5.43\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid CSV file: missing the rate column")
+		assert.Contains(t, err.Error(), "invalid CSV file: missing the effective_date column")
+	})
+
+	t.Run("No rates", func(t *testing.T) {
+		_, err := ParseExchangeRatesCSV([]byte("currency,rate,effective_date\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the file has no exchange rates")
+	})
+}
+
+func TestValidateExchangeRates(t *testing.T) {
+	orgID := uuid.New()
+	rates := []*ExchangeRate{
+		{OrgID: orgID, Currency: "USD", BaseCurrency: "BRL", Rate: 5.43, EffectiveDate: time.Now()},
+		{OrgID: orgID, Currency: "BRL", BaseCurrency: "BRL", Rate: 0, EffectiveDate: time.Now()},
+	}
+
+	err := ValidateExchangeRates(rates)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3: currency must differ from the base currency")
+	assert.Contains(t, err.Error(), "line 3: rate must be positive")
+	assert.NotContains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrMissingTenant is returned when an operation needs an organization but the context has none
+var ErrMissingTenant = errors.New("organization is required for this operation")
+
+// Organization is a client company. Users and trips always belong to exactly one organization.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// BaseCurrency is the ISO 4217 currency budgets are reported in, BRL by default
+	BaseCurrency string `json:"base_currency"`
+	// AllowedEmailDomains are the email domains that can register themselves in
+	// the organization, e.g. "acme.com". Without any, only admins add users.
+	AllowedEmailDomains []string `json:"allowed_email_domains"`
+}
+
+// AllowsSelfRegistration reports whether the owner of the email can register in
+// the organization without an admin. Subdomains must be listed on their own.
+func (o *Organization) AllowsSelfRegistration(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	emailDomain := email[at+1:]
+	for _, allowed := range o.AllowedEmailDomains {
+		if strings.EqualFold(allowed, emailDomain) {
+			return true
+		}
+	}
+	return false
+}
+
+type OrganizationRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*Organization, error)
+}
+
+type tenantContextKey struct{}
+
+// tenant is what gets stored in the context. allTenants is only used for
+// lookups that must happen before the organization is known (e.g. login) and
+// for background jobs.
+type tenant struct {
+	orgID      uuid.UUID
+	allTenants bool
+}
+
+// ContextWithOrgID scopes every repository call made with the returned context to the organization
+func ContextWithOrgID(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant{orgID: orgID})
+}
+
+// ContextWithAllTenants returns a context whose repository calls are not scoped to an organization.
+// Use it only where the organization can't be known yet.
+func ContextWithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant{allTenants: true})
+}
+
+// OrgIDFromContext returns the organization carried by the context, if any
+func OrgIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	t, ok := ctx.Value(tenantContextKey{}).(tenant)
+	if !ok || t.allTenants || t.orgID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return t.orgID, true
+}
+
+// IsAllTenantsContext reports whether the context was created with ContextWithAllTenants
+func IsAllTenantsContext(ctx context.Context) bool {
+	t, ok := ctx.Value(tenantContextKey{}).(tenant)
+	return ok && t.allTenants
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganization_AllowsSelfRegistration(t *testing.T) {
+	org := &Organization{AllowedEmailDomains: []string{"acme.com", "Acme.com.br"}}
+
+	assert.True(t, org.AllowsSelfRegistration("ana@acme.com"))
+	assert.True(t, org.AllowsSelfRegistration("ana@ACME.COM.BR"), "domains are case-insensitive")
+	assert.False(t, org.AllowsSelfRegistration("ana@sales.acme.com"), "subdomains must be listed on their own")
+	assert.False(t, org.AllowsSelfRegistration("ana@notacme.com"))
+	assert.False(t, org.AllowsSelfRegistration("acme.com"))
+	assert.False(t, (&Organization{}).AllowsSelfRegistration("ana@acme.com"), "no domains means no self-registration")
+}
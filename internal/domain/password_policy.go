@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicy describes the rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many previous passwords cannot be reused (0 disables the check)
+	HistorySize int
+}
+
+// DefaultPasswordPolicy returns the policy used when none is configured
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:   8,
+		HistorySize: 5,
+	}
+}
+
+// Validate checks the password against the rules that don't need stored state.
+// Reuse and breached-password checks are done by the user service.
+func (p PasswordPolicy) Validate(password string, user *User) error {
+	validationErrors := NewValidationErrors()
+
+	validationErrors.AddIf(len([]rune(password)) < p.MinLength, "password must be at least "+strconv.Itoa(p.MinLength)+" characters")
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	validationErrors.AddIf(p.RequireUpper && !hasUpper, "password must contain an uppercase letter")
+	validationErrors.AddIf(p.RequireLower && !hasLower, "password must contain a lowercase letter")
+	validationErrors.AddIf(p.RequireDigit && !hasDigit, "password must contain a digit")
+	validationErrors.AddIf(p.RequireSymbol && !hasSymbol, "password must contain a symbol")
+
+	if user != nil {
+		validationErrors.AddIf(containsPersonalInfo(password, user), "password must not contain your name or email")
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// containsPersonalInfo reports whether the password contains the user's email
+// local part or any part of their name. Very short fragments are ignored to
+// avoid rejecting passwords because of initials.
+func containsPersonalInfo(password string, user *User) bool {
+	lowered := strings.ToLower(password)
+
+	fragments := strings.Fields(strings.ToLower(user.Name))
+	if local, _, found := strings.Cut(strings.ToLower(user.Email), "@"); found {
+		fragments = append(fragments, local)
+	}
+
+	for _, fragment := range fragments {
+		if len([]rune(fragment)) >= 3 && strings.Contains(lowered, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	user := &User{
+		Name:  "Maria Silva",
+		Email: "maria.silva@example.com",
+	}
+
+	t.Run("Valid password with default policy", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("correct-horse-battery", user)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Too short", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("short", user)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must be at least 8 characters")
+	})
+
+	t.Run("Missing character classes", func(t *testing.T) {
+		policy := PasswordPolicy{
+			MinLength:     8,
+			RequireUpper:  true,
+			RequireLower:  true,
+			RequireDigit:  true,
+			RequireSymbol: true,
+		}
+
+		err := policy.Validate("alllowercase", user)
+		assert.Error(t, err)
+
+		validationErrs, ok := err.(*ValidationErrors)
+		assert.True(t, ok, "Error should be of type *ValidationErrors")
+		assert.ElementsMatch(t, []string{
+			"password must contain an uppercase letter",
+			"password must contain a digit",
+			"password must contain a symbol",
+		}, validationErrs.GetErrors())
+
+		assert.NoError(t, policy.Validate("Str0ng!Pass", user))
+	})
+
+	t.Run("Contains name", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("SilvaRocks2024", user)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must not contain your name or email")
+	})
+
+	t.Run("Contains email local part", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("my-maria.silva-pass", user)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must not contain your name or email")
+	})
+
+	t.Run("Short name fragments are ignored", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("jo-banana-split", &User{Name: "Jo Li", Email: "jl@example.com"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Nil user skips personal info check", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		err := policy.Validate("maria.silva", nil)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,316 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	cityCodePattern    = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// PerDiemRate is the daily allowance of travelers in a city, or in a whole
+// country when CityCode is empty. City rates take precedence over country ones.
+type PerDiemRate struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+	// CountryCode is an ISO 3166-1 alpha-2 code, e.g. PT
+	CountryCode string `json:"country_code"`
+	// CityCode is the IATA code of the city in the destination catalog, e.g. LIS
+	CityCode string `json:"city_code,omitempty"`
+	Currency string `json:"currency"`
+	// DailyAmount is in minor units of Currency
+	DailyAmount int64 `json:"daily_amount"`
+	// PartialDayPercent is the share of the daily amount paid on the first and last days
+	PartialDayPercent int `json:"partial_day_percent"`
+	// The meal percents are deducted from the daily amount of the days the meal is provided
+	BreakfastPercent int       `json:"breakfast_percent"`
+	LunchPercent     int       `json:"lunch_percent"`
+	DinnerPercent    int       `json:"dinner_percent"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Validate checks if the rate data is valid according to business rules
+func (r *PerDiemRate) Validate() error {
+	validationErrors := NewValidationErrors()
+	r.validate(validationErrors, "")
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+func (r *PerDiemRate) validate(validationErrors *ValidationErrors, prefix string) {
+	validationErrors.AddIf(r.OrgID == uuid.Nil, prefix+"org_id is required")
+	validationErrors.AddIf(!countryCodePattern.MatchString(r.CountryCode), prefix+"country_code must be an ISO 3166-1 alpha-2 code, e.g. PT")
+	validationErrors.AddIf(r.CityCode != "" && !cityCodePattern.MatchString(r.CityCode), prefix+"city_code must be an IATA city code, e.g. LIS")
+	validationErrors.AddIf(!IsCurrencyCode(r.Currency), prefix+"currency must be an ISO 4217 code, e.g. BRL")
+	validationErrors.AddIf(r.DailyAmount <= 0, prefix+"daily_amount must be positive")
+	for name, percent := range map[string]int{
+		"partial_day_percent": r.PartialDayPercent,
+		"breakfast_percent":   r.BreakfastPercent,
+		"lunch_percent":       r.LunchPercent,
+		"dinner_percent":      r.DinnerPercent,
+	} {
+		validationErrors.AddIf(percent < 0 || percent > 100, prefix+name+" must be between 0 and 100")
+	}
+}
+
+// ValidatePerDiemRates validates rates read from a file, prefixing the errors
+// with the line of the rate (the header is line 1)
+func ValidatePerDiemRates(rates []*PerDiemRate) error {
+	validationErrors := NewValidationErrors()
+	for i, rate := range rates {
+		rate.validate(validationErrors, fmt.Sprintf("line %d: ", i+2))
+	}
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// ParsePerDiemRatesCSV reads rates from a CSV file with the header
+// country_code,city_code,currency,daily_amount and, optionally,
+// partial_day_percent,breakfast_percent,lunch_percent,dinner_percent.
+// An empty city_code makes the rate valid for the whole country. Without a
+// partial_day_percent the first and last days are paid in full.
+func ParsePerDiemRatesCSV(data []byte) ([]*PerDiemRate, error) {
+	validationErrors := NewValidationErrors()
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		validationErrors.Add("invalid CSV file: " + err.Error())
+		return nil, validationErrors
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"country_code", "city_code", "currency", "daily_amount"} {
+		_, ok := columns[name]
+		validationErrors.AddIf(!ok, "invalid CSV file: missing the "+name+" column")
+	}
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+
+	var rates []*PerDiemRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			validationErrors.Add("invalid CSV file: " + err.Error())
+			return nil, validationErrors
+		}
+
+		prefix := fmt.Sprintf("line %d: ", line)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		percent := func(name string, empty int) int {
+			value := field(name)
+			if value == "" {
+				return empty
+			}
+			n, err := strconv.Atoi(value)
+			validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid %s %q", name, value))
+			return n
+		}
+
+		dailyAmount, err := strconv.ParseInt(field("daily_amount"), 10, 64)
+		validationErrors.AddIf(err != nil, prefix+fmt.Sprintf("invalid daily_amount %q", field("daily_amount")))
+
+		rates = append(rates, &PerDiemRate{
+			CountryCode:       strings.ToUpper(field("country_code")),
+			CityCode:          strings.ToUpper(field("city_code")),
+			Currency:          strings.ToUpper(field("currency")),
+			DailyAmount:       dailyAmount,
+			PartialDayPercent: percent("partial_day_percent", 100),
+			BreakfastPercent:  percent("breakfast_percent", 0),
+			LunchPercent:      percent("lunch_percent", 0),
+			DinnerPercent:     percent("dinner_percent", 0),
+		})
+	}
+	validationErrors.AddIf(len(rates) == 0 && !validationErrors.HasErrors(), "the file has no per diem rates")
+
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+	return rates, nil
+}
+
+// ProvidedMeals are the meals of a day of the trip that the traveler doesn't
+// pay for, e.g. breakfast included with the hotel
+type ProvidedMeals struct {
+	// Date is YYYY-MM-DD, in the trip's time zone
+	Date      string `json:"date"`
+	Breakfast bool   `json:"breakfast,omitempty"`
+	Lunch     bool   `json:"lunch,omitempty"`
+	Dinner    bool   `json:"dinner,omitempty"`
+}
+
+// PerDiemDay is the allowance of a day of the trip
+type PerDiemDay struct {
+	Date            string `json:"date"`
+	DestinationCode string `json:"destination_code"`
+	Currency        string `json:"currency"`
+	// DailyAmount is the full allowance of the destination
+	DailyAmount int64 `json:"daily_amount"`
+	// Percent is the share of DailyAmount paid, less than 100 on partial days
+	Percent    int   `json:"percent"`
+	Deductions int64 `json:"deductions"`
+	Amount     int64 `json:"amount"`
+}
+
+// TripPerDiem is the daily allowance of a trip, computed from the per diem
+// rates of its destinations when the trip is requested or edited
+type TripPerDiem struct {
+	ProvidedMeals []ProvidedMeals `json:"provided_meals,omitempty"`
+	Days          []PerDiemDay    `json:"days"`
+	// Totals has one amount per currency of the rates used
+	Totals       []Money   `json:"totals"`
+	CalculatedAt time.Time `json:"calculated_at"`
+}
+
+// PerDiemStop is a destination of the trip, where the traveler stays from the day they arrive on
+type PerDiemStop struct {
+	Arrival     time.Time
+	Destination *Destination
+}
+
+// CalculatePerDiem computes the allowance of each day of the trip, from its
+// first to its last day in the trip's time zone. Each day is paid at the rate
+// of the last stop reached by then, or of the first stop before it's reached.
+// The first and last days get the partial day share of the rate and the
+// provided meals are deducted, never going below zero. A trip without stops,
+// or with a stop that has no rate, gets no per diem.
+func CalculatePerDiem(trip *Trip, stops []PerDiemStop, rates []*PerDiemRate, meals []ProvidedMeals, now time.Time) (*TripPerDiem, error) {
+	validationErrors := NewValidationErrors()
+	loc := trip.Location()
+	first := civilDate(trip.StartDate.In(loc))
+	last := civilDate(trip.EndDate.In(loc))
+
+	mealsByDate := make(map[string]ProvidedMeals)
+	for i, provided := range meals {
+		prefix := fmt.Sprintf("provided_meals[%d]: ", i)
+		date, err := time.Parse("2006-01-02", provided.Date)
+		if err != nil {
+			validationErrors.Add(prefix + "date must be YYYY-MM-DD")
+			continue
+		}
+		if date.Before(first) || date.After(last) {
+			validationErrors.Add(prefix + fmt.Sprintf("%s is not a day of the trip", provided.Date))
+			continue
+		}
+		mealsByDate[provided.Date] = provided
+	}
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+	if len(stops) == 0 {
+		return nil, nil
+	}
+
+	perDiem := &TripPerDiem{ProvidedMeals: meals, CalculatedAt: now}
+	totals := make(map[string]int64)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		stop := stops[0]
+		for _, candidate := range stops {
+			if !civilDate(candidate.Arrival.In(loc)).After(day) {
+				stop = candidate
+			}
+		}
+		rate := findPerDiemRate(rates, stop.Destination)
+		if rate == nil {
+			return nil, nil
+		}
+
+		date := day.Format("2006-01-02")
+		percent := 100
+		if day.Equal(first) || day.Equal(last) {
+			percent = rate.PartialDayPercent
+		}
+		deductions := rate.mealDeductions(mealsByDate[date])
+		amount := max(percentOf(rate.DailyAmount, percent)-deductions, 0)
+		perDiem.Days = append(perDiem.Days, PerDiemDay{
+			Date:            date,
+			DestinationCode: stop.Destination.Code,
+			Currency:        rate.Currency,
+			DailyAmount:     rate.DailyAmount,
+			Percent:         percent,
+			Deductions:      deductions,
+			Amount:          amount,
+		})
+		totals[rate.Currency] += amount
+	}
+
+	for currency, amount := range totals {
+		perDiem.Totals = append(perDiem.Totals, Money{Amount: amount, Currency: currency})
+	}
+	sort.Slice(perDiem.Totals, func(i, j int) bool { return perDiem.Totals[i].Currency < perDiem.Totals[j].Currency })
+	return perDiem, nil
+}
+
+// findPerDiemRate returns the rate of the destination's city, else the one of its country
+func findPerDiemRate(rates []*PerDiemRate, destination *Destination) *PerDiemRate {
+	var countryRate *PerDiemRate
+	for _, rate := range rates {
+		if rate.CountryCode != destination.CountryCode {
+			continue
+		}
+		if rate.CityCode == destination.ReferenceCode() {
+			return rate
+		}
+		if rate.CityCode == "" {
+			countryRate = rate
+		}
+	}
+	return countryRate
+}
+
+// mealDeductions is what the provided meals take off the daily amount
+func (r *PerDiemRate) mealDeductions(meals ProvidedMeals) int64 {
+	var deductions int64
+	if meals.Breakfast {
+		deductions += percentOf(r.DailyAmount, r.BreakfastPercent)
+	}
+	if meals.Lunch {
+		deductions += percentOf(r.DailyAmount, r.LunchPercent)
+	}
+	if meals.Dinner {
+		deductions += percentOf(r.DailyAmount, r.DinnerPercent)
+	}
+	return deductions
+}
+
+// percentOf rounds half up to the minor unit
+func percentOf(amount int64, percent int) int64 {
+	return (amount*int64(percent) + 50) / 100
+}
+
+type PerDiemRateRepository interface {
+	// Save stores the rates, replacing the ones of the same country and city
+	Save(ctx context.Context, rates []*PerDiemRate) error
+	FindByID(ctx context.Context, id uuid.UUID) (*PerDiemRate, error)
+	List(ctx context.Context) ([]*PerDiemRate, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatePerDiem(t *testing.T) {
+	lisbon := &Destination{Code: "LIS", Kind: DestinationCity, Name: "Lisboa", CountryCode: "PT"}
+	porto := &Destination{Code: "OPO", Kind: DestinationCity, Name: "Porto", CountryCode: "PT"}
+	berlin := &Destination{Code: "BER", Kind: DestinationCity, Name: "Berlim", CountryCode: "DE"}
+	rates := []*PerDiemRate{
+		{CountryCode: "PT", CityCode: "LIS", Currency: "EUR", DailyAmount: 10000, PartialDayPercent: 75,
+			BreakfastPercent: 20, LunchPercent: 40, DinnerPercent: 40},
+		{CountryCode: "PT", Currency: "EUR", DailyAmount: 8000, PartialDayPercent: 75},
+	}
+	now := time.Date(2030, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Partial first and last days and provided meals", func(t *testing.T) {
+		trip := &Trip{TimeZone: "Europe/Lisbon"}
+		loc := trip.Location()
+		trip.StartDate = time.Date(2030, 5, 10, 8, 0, 0, 0, loc)
+		trip.EndDate = time.Date(2030, 5, 13, 20, 0, 0, 0, loc)
+		meals := []ProvidedMeals{{Date: "2030-05-11", Breakfast: true}, {Date: "2030-05-12", Breakfast: true, Lunch: true, Dinner: true}}
+
+		perDiem, err := CalculatePerDiem(trip, []PerDiemStop{{Arrival: trip.StartDate, Destination: lisbon}}, rates, meals, now)
+
+		require.NoError(t, err)
+		require.Len(t, perDiem.Days, 4)
+		assert.Equal(t, PerDiemDay{Date: "2030-05-10", DestinationCode: "LIS", Currency: "EUR", DailyAmount: 10000, Percent: 75, Amount: 7500}, perDiem.Days[0])
+		assert.Equal(t, int64(2000), perDiem.Days[1].Deductions)
+		assert.Equal(t, int64(8000), perDiem.Days[1].Amount)
+		assert.Equal(t, int64(0), perDiem.Days[2].Amount, "deductions never take a day below zero")
+		assert.Equal(t, 75, perDiem.Days[3].Percent)
+		assert.Equal(t, []Money{{Amount: 23000, Currency: "EUR"}}, perDiem.Totals)
+		assert.Equal(t, meals, perDiem.ProvidedMeals)
+		assert.Equal(t, now, perDiem.CalculatedAt)
+	})
+
+	t.Run("Each day is paid at the rate of where the traveler is", func(t *testing.T) {
+		trip := &Trip{
+			StartDate: time.Date(2030, 5, 10, 8, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2030, 5, 12, 20, 0, 0, 0, time.UTC),
+		}
+		stops := []PerDiemStop{
+			{Arrival: time.Date(2030, 5, 10, 10, 0, 0, 0, time.UTC), Destination: lisbon},
+			{Arrival: time.Date(2030, 5, 11, 15, 0, 0, 0, time.UTC), Destination: porto},
+		}
+
+		perDiem, err := CalculatePerDiem(trip, stops, rates, nil, now)
+
+		require.NoError(t, err)
+		require.Len(t, perDiem.Days, 3)
+		assert.Equal(t, "LIS", perDiem.Days[0].DestinationCode)
+		// Porto has no city rate, the one of Portugal applies
+		assert.Equal(t, "OPO", perDiem.Days[1].DestinationCode)
+		assert.Equal(t, int64(8000), perDiem.Days[1].Amount)
+		assert.Equal(t, int64(6000), perDiem.Days[2].Amount)
+	})
+
+	t.Run("Destination without a rate", func(t *testing.T) {
+		trip := &Trip{
+			StartDate: time.Date(2030, 5, 10, 8, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2030, 5, 12, 20, 0, 0, 0, time.UTC),
+		}
+
+		perDiem, err := CalculatePerDiem(trip, []PerDiemStop{{Arrival: trip.StartDate, Destination: berlin}}, rates, nil, now)
+
+		assert.NoError(t, err)
+		assert.Nil(t, perDiem)
+	})
+
+	t.Run("Trip without stops", func(t *testing.T) {
+		trip := &Trip{
+			StartDate: time.Date(2030, 5, 10, 8, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2030, 5, 12, 20, 0, 0, 0, time.UTC),
+		}
+
+		perDiem, err := CalculatePerDiem(trip, nil, rates, nil, now)
+
+		assert.NoError(t, err)
+		assert.Nil(t, perDiem)
+	})
+
+	t.Run("Meals outside the trip", func(t *testing.T) {
+		trip := &Trip{
+			StartDate: time.Date(2030, 5, 10, 8, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2030, 5, 12, 20, 0, 0, 0, time.UTC),
+		}
+		meals := []ProvidedMeals{{Date: "2030-05-13", Dinner: true}, {Date: "13/05/2030"}}
+
+		_, err := CalculatePerDiem(trip, []PerDiemStop{{Arrival: trip.StartDate, Destination: lisbon}}, rates, meals, now)
+
+		assert.Equal(t, []string{
+			"provided_meals[0]: 2030-05-13 is not a day of the trip",
+			"provided_meals[1]: date must be YYYY-MM-DD",
+		}, err.(*ValidationErrors).GetErrors())
+	})
+}
+
+func TestParsePerDiemRatesCSV(t *testing.T) {
+	t.Run("Valid file", func(t *testing.T) {
+		document := []byte("country_code,city_code,currency,daily_amount,partial_day_percent,breakfast_percent\n" +
+			"pt,lis,eur,10000,75,20\n" +
+			"DE,,EUR,12000,,\n")
+
+		rates, err := ParsePerDiemRatesCSV(document)
+
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, &PerDiemRate{CountryCode: "PT", CityCode: "LIS", Currency: "EUR", DailyAmount: 10000,
+			PartialDayPercent: 75, BreakfastPercent: 20}, rates[0])
+		assert.Equal(t, 100, rates[1].PartialDayPercent)
+		assert.Equal(t, "", rates[1].CityCode)
+	})
+
+	t.Run("Invalid lines", func(t *testing.T) {
+		document := []byte("country_code,city_code,currency,daily_amount,lunch_percent\n" +
+			"PT,LIS,EUR,100.50,\n" +
+			"PT,OPO,EUR,8000,half\n")
+
+		rates, err := ParsePerDiemRatesCSV(document)
+
+		assert.Nil(t, rates)
+		assert.Equal(t, []string{`line 2: invalid daily_amount "100.50"`, `line 3: invalid lunch_percent "half"`},
+			err.(*ValidationErrors).GetErrors())
+	})
+
+	t.Run("Missing column", func(t *testing.T) {
+		_, err := ParsePerDiemRatesCSV([]byte("country_code,currency,daily_amount\nPT,EUR,8000\n"))
+
+		assert.Equal(t, []string{"invalid CSV file: missing the city_code column"}, err.(*ValidationErrors).GetErrors())
+	})
+}
+
+func TestValidatePerDiemRates(t *testing.T) {
+	rates := []*PerDiemRate{{CountryCode: "PRT", Currency: "EUR", DailyAmount: 0, PartialDayPercent: 120}}
+
+	err := ValidatePerDiemRates(rates)
+
+	assert.ElementsMatch(t, []string{
+		"line 2: org_id is required",
+		"line 2: country_code must be an ISO 3166-1 alpha-2 code, e.g. PT",
+		"line 2: daily_amount must be positive",
+		"line 2: partial_day_percent must be between 0 and 100",
+	}, err.(*ValidationErrors).GetErrors())
+}
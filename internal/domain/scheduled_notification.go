@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationTemplate string
+
+const (
+	// TemplateTripReminder reminds the traveler of an upcoming trip with its itinerary
+	TemplateTripReminder NotificationTemplate = "trip_reminder"
+)
+
+// ScheduledNotification is a notification to be sent once DueAt is reached.
+// The message is rendered from the template when it's sent, so it reflects
+// the trip as it is then.
+type ScheduledNotification struct {
+	ID          uuid.UUID            `json:"id"`
+	OrgID       uuid.UUID            `json:"org_id"`
+	RecipientID uuid.UUID            `json:"recipient_id"`
+	TripID      *uuid.UUID           `json:"trip_id,omitempty"`
+	Template    NotificationTemplate `json:"template"`
+	// DedupeKey identifies the notification within the organization: scheduling
+	// a key again moves the pending notification instead of adding another one,
+	// and a key that was already sent is not sent again
+	DedupeKey string     `json:"dedupe_key"`
+	DueAt     time.Time  `json:"due_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ScheduledNotificationRepository interface {
+	// Schedule creates the notification or reschedules the pending one with the same dedupe key
+	Schedule(ctx context.Context, notification *ScheduledNotification) error
+	// DeletePendingByTripID drops the notifications of a trip that were not sent yet
+	DeletePendingByTripID(ctx context.Context, tripID uuid.UUID) error
+	// ListDue returns up to limit pending notifications due at the given moment, oldest first
+	ListDue(ctx context.Context, at time.Time, limit int) ([]*ScheduledNotification, error)
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
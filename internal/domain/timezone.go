@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SetTimeZone sets the IANA time zone of the trip and shows its dates, and
+// the times of its legs, in that zone. The instants sent are kept: a trip
+// starting on 2030-05-10T00:00:00Z in Asia/Tokyo starts at 09:00 in Tokyo.
+// Trips without a time zone keep their dates as sent. Unknown zones are
+// reported by Validate.
+func (t *Trip) SetTimeZone(name string) {
+	t.TimeZone = name
+	t.LocalizeDates()
+}
+
+// LocalizeDates shows the dates of the trip and the times of its legs in the
+// trip's time zone, without changing the instants. Trips without a time zone,
+// or with an unknown one, are left as they are.
+func (t *Trip) LocalizeDates() {
+	loc, ok := loadTimeZone(t.TimeZone)
+	if !ok {
+		return
+	}
+	t.StartDate, t.EndDate = t.StartDate.In(loc), t.EndDate.In(loc)
+	for i := range t.Legs {
+		t.Legs[i].DepartAt, t.Legs[i].ArriveAt = t.Legs[i].DepartAt.In(loc), t.Legs[i].ArriveAt.In(loc)
+	}
+}
+
+// Location is the time zone the rules of the trip are computed in, UTC when it has none
+func (t *Trip) Location() *time.Location {
+	if loc, ok := loadTimeZone(t.TimeZone); ok {
+		return loc
+	}
+	return time.UTC
+}
+
+// MarshalJSON adds the UTC instants of the trip dates next to the local ones
+func (t Trip) MarshalJSON() ([]byte, error) {
+	type trip Trip
+	return json.Marshal(struct {
+		trip
+		StartDateUTC time.Time `json:"start_date_utc"`
+		EndDateUTC   time.Time `json:"end_date_utc"`
+	}{trip(t), t.StartDate.UTC(), t.EndDate.UTC()})
+}
+
+// loadTimeZone loads an IANA time zone. "Local" is refused, as it depends on the server.
+func loadTimeZone(name string) (*time.Location, bool) {
+	if name == "" || name == "Local" {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(name)
+	return loc, err == nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrip_SetTimeZone(t *testing.T) {
+	newTrip := func() *Trip {
+		return &Trip{RequesterID: uuid.New(), CostCenterID: uuid.New(), Type: TripInternational, Destination: "Tóquio",
+			StartDate: time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC), Status: StatusRequested}
+	}
+
+	t.Run("Dates are shown in the zone", func(t *testing.T) {
+		trip := newTrip()
+		trip.SetTimeZone("Asia/Tokyo")
+
+		assert.NoError(t, trip.Validate())
+		assert.Equal(t, "2030-05-10T09:00:00+09:00", trip.StartDate.Format(time.RFC3339))
+		assert.True(t, trip.StartDate.Equal(time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, "Asia/Tokyo", trip.Location().String())
+	})
+
+	t.Run("Legs are checked against the local days of the trip", func(t *testing.T) {
+		// The trip starts on 10 May in Tokyo, which began at 15:00 UTC on the 9th
+		withLeg := func(departAt time.Time) *Trip {
+			trip := newTrip()
+			trip.Legs = []ItineraryLeg{{Origin: "São Paulo", Destination: "Tóquio", Mode: TransportFlight,
+				DepartAt: departAt, ArriveAt: time.Date(2030, 5, 11, 3, 0, 0, 0, time.UTC)}}
+			trip.SetTimeZone("Asia/Tokyo")
+			return trip
+		}
+
+		trip := withLeg(time.Date(2030, 5, 9, 16, 0, 0, 0, time.UTC))
+		assert.NoError(t, trip.Validate())
+		assert.Equal(t, "2030-05-10T01:00:00+09:00", trip.Legs[0].DepartAt.Format(time.RFC3339))
+		assert.Equal(t, "2030-05-11T12:00:00+09:00", trip.Legs[0].ArriveAt.Format(time.RFC3339))
+
+		err := withLeg(time.Date(2030, 5, 9, 14, 0, 0, 0, time.UTC)).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "legs[0]: leg must be within the trip dates")
+	})
+
+	t.Run("No zone keeps the dates", func(t *testing.T) {
+		trip := newTrip()
+		trip.SetTimeZone("")
+
+		assert.Equal(t, time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC), trip.StartDate)
+		assert.Equal(t, time.UTC, trip.Location())
+	})
+
+	t.Run("Unknown zone", func(t *testing.T) {
+		for _, zone := range []string{"Mars/Olympus", "Local"} {
+			trip := newTrip()
+			trip.SetTimeZone(zone)
+
+			err := trip.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "time_zone must be an IANA time zone")
+		}
+	})
+}
+
+func TestTrip_MarshalJSON(t *testing.T) {
+	trip := &Trip{Destination: "Lisboa", StartDate: time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC)}
+	trip.SetTimeZone("Europe/Lisbon")
+
+	data, err := json.Marshal(trip)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "Lisboa", fields["destination"])
+	assert.Equal(t, "Europe/Lisbon", fields["time_zone"])
+	assert.Equal(t, "2030-05-10T01:00:00+01:00", fields["start_date"])
+	assert.Equal(t, "2030-05-10T00:00:00Z", fields["start_date_utc"])
+	assert.Equal(t, "2030-05-20T00:00:00Z", fields["end_date_utc"])
+}
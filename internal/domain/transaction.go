@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// Transactor runs several repository calls as a single database transaction
+type Transactor interface {
+	// WithinTx calls fn with a context whose repository calls share one
+	// transaction, committed when fn returns nil and rolled back otherwise.
+	// Calls made inside a transaction join it instead of starting another one.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+type TravelRuleType string
+
+const (
+	RuleMaxTripDays    TravelRuleType = "max_trip_days"
+	RuleMinAdvanceDays TravelRuleType = "min_advance_days"
+	RuleDestinations   TravelRuleType = "destinations"
+	RuleWeekendTravel  TravelRuleType = "weekend_travel"
+)
+
+// RuleSeverity decides what happens when a trip breaks a rule: block rejects
+// the trip, warn stores the violation on the trip for the approvers
+type RuleSeverity string
+
+const (
+	SeverityBlock RuleSeverity = "block"
+	SeverityWarn  RuleSeverity = "warn"
+)
+
+// TravelRule is a single rule of a travel policy. Days is used by the
+// max_trip_days and min_advance_days rules, Destinations by the destinations rule.
+type TravelRule struct {
+	Type         TravelRuleType `json:"type" yaml:"type"`
+	Severity     RuleSeverity   `json:"severity" yaml:"severity"`
+	Days         int            `json:"days,omitempty" yaml:"days,omitempty"`
+	Destinations []string       `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+	// Message replaces the default violation message
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// TravelPolicy holds the travel rules of an organization
+type TravelPolicy struct {
+	OrgID        uuid.UUID           `json:"org_id" yaml:"-"`
+	Rules        []TravelRule        `json:"rules" yaml:"rules"`
+	Cancellation *CancellationPolicy `json:"cancellation,omitempty" yaml:"cancellation,omitempty"`
+	UpdatedAt    time.Time           `json:"updated_at" yaml:"-"`
+}
+
+// ParseTravelPolicy reads a policy document. JSON is valid YAML, so both formats are accepted.
+func ParseTravelPolicy(data []byte) (*TravelPolicy, error) {
+	var policy TravelPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		validationErrors := NewValidationErrors()
+		validationErrors.Add("invalid travel policy document: " + err.Error())
+		return nil, validationErrors
+	}
+	return &policy, nil
+}
+
+// Validate checks if the travel policy is valid according to business rules
+func (p *TravelPolicy) Validate() error {
+	validationErrors := NewValidationErrors()
+
+	for i, rule := range p.Rules {
+		prefix := fmt.Sprintf("rules[%d]: ", i)
+		validationErrors.AddIf(rule.Severity != SeverityBlock && rule.Severity != SeverityWarn, prefix+"severity must be block or warn")
+
+		switch rule.Type {
+		case RuleMaxTripDays, RuleMinAdvanceDays:
+			validationErrors.AddIf(rule.Days <= 0, prefix+"days must be positive")
+		case RuleDestinations:
+			validationErrors.AddIf(len(rule.Destinations) == 0, prefix+"destinations are required")
+		case RuleWeekendTravel:
+		default:
+			validationErrors.Add(prefix + "unknown rule type")
+		}
+	}
+
+	if p.Cancellation != nil {
+		p.Cancellation.validate(validationErrors)
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// PolicyViolation is a rule a trip breaks
+type PolicyViolation struct {
+	Rule     TravelRuleType `json:"rule"`
+	Severity RuleSeverity   `json:"severity"`
+	Message  string         `json:"message"`
+}
+
+// Evaluate returns the rules the trip breaks. now is the moment the trip is
+// requested or edited, used by the minimum advance rule.
+func (p *TravelPolicy) Evaluate(trip *Trip, now time.Time) []PolicyViolation {
+	var violations []PolicyViolation
+	for _, rule := range p.Rules {
+		message, broken := rule.check(trip, now)
+		if !broken {
+			continue
+		}
+		if rule.Message != "" {
+			message = rule.Message
+		}
+		violations = append(violations, PolicyViolation{Rule: rule.Type, Severity: rule.Severity, Message: message})
+	}
+	return violations
+}
+
+func (r TravelRule) check(trip *Trip, now time.Time) (string, bool) {
+	day := 24 * time.Hour
+
+	switch r.Type {
+	case RuleMaxTripDays:
+		if trip.EndDate.Sub(trip.StartDate) > time.Duration(r.Days)*day {
+			return fmt.Sprintf("trips cannot last more than %d days", r.Days), true
+		}
+	case RuleMinAdvanceDays:
+		if trip.StartDate.Sub(now) < time.Duration(r.Days)*day {
+			return fmt.Sprintf("trips must be requested at least %d days in advance", r.Days), true
+		}
+	case RuleDestinations:
+		destination := strings.ToLower(trip.Destination)
+		for _, d := range r.Destinations {
+			if d != "" && strings.Contains(destination, strings.ToLower(d)) {
+				return fmt.Sprintf("travel to %s is restricted", d), true
+			}
+		}
+	case RuleWeekendTravel:
+		if isWeekend(trip.StartDate) || isWeekend(trip.EndDate) {
+			return "trips should not start or end on a weekend", true
+		}
+	}
+	return "", false
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+type TravelPolicyRepository interface {
+	// Find returns the policy of the organization in ctx, or nil when it has none
+	Find(ctx context.Context) (*TravelPolicy, error)
+	Save(ctx context.Context, policy *TravelPolicy) error
+}
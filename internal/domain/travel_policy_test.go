@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTravelPolicy(t *testing.T) {
+	t.Run("YAML document", func(t *testing.T) {
+		document := []byte(`
+rules:
+  - type: max_trip_days
+    severity: block
+    days: 15
+  - type: destinations
+    severity: warn
+    destinations: [Moscou, Caracas]
+    message: destino exige aprovação da segurança
+`)
+
+		policy, err := ParseTravelPolicy(document)
+		require.NoError(t, err)
+		require.Len(t, policy.Rules, 2)
+		assert.Equal(t, RuleMaxTripDays, policy.Rules[0].Type)
+		assert.Equal(t, 15, policy.Rules[0].Days)
+		assert.Equal(t, []string{"Moscou", "Caracas"}, policy.Rules[1].Destinations)
+		assert.Equal(t, "destino exige aprovação da segurança", policy.Rules[1].Message)
+		assert.NoError(t, policy.Validate())
+	})
+
+	t.Run("JSON document", func(t *testing.T) {
+		document := []byte(`{"rules": [{"type": "weekend_travel", "severity": "warn"}]}`)
+
+		policy, err := ParseTravelPolicy(document)
+		require.NoError(t, err)
+		require.Len(t, policy.Rules, 1)
+		assert.Equal(t, RuleWeekendTravel, policy.Rules[0].Type)
+		assert.Equal(t, SeverityWarn, policy.Rules[0].Severity)
+	})
+
+	t.Run("Malformed document", func(t *testing.T) {
+		_, err := ParseTravelPolicy([]byte(`rules: [`))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid travel policy document")
+	})
+}
+
+func TestTravelPolicy_Validate(t *testing.T) {
+	policy := &TravelPolicy{Rules: []TravelRule{
+		{Type: RuleMaxTripDays, Severity: SeverityBlock},
+		{Type: RuleDestinations, Severity: "maybe"},
+		{Type: "max_budget", Severity: SeverityWarn},
+	}}
+
+	err := policy.Validate()
+	assert.Error(t, err)
+
+	errMsg := err.Error()
+	assert.Contains(t, errMsg, "rules[0]: days must be positive")
+	assert.Contains(t, errMsg, "rules[1]: severity must be block or warn")
+	assert.Contains(t, errMsg, "rules[1]: destinations are required")
+	assert.Contains(t, errMsg, "rules[2]: unknown rule type")
+}
+
+func TestTravelPolicy_Evaluate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2030, 3, 6, 10, 0, 0, 0, time.UTC)
+
+	policy := &TravelPolicy{Rules: []TravelRule{
+		{Type: RuleMaxTripDays, Severity: SeverityBlock, Days: 10},
+		{Type: RuleMinAdvanceDays, Severity: SeverityWarn, Days: 14},
+		{Type: RuleDestinations, Severity: SeverityBlock, Destinations: []string{"caracas"}},
+		{Type: RuleWeekendTravel, Severity: SeverityWarn},
+	}}
+
+	t.Run("Compliant trip", func(t *testing.T) {
+		// Monday to Friday, a month ahead
+		start := time.Date(2030, 4, 8, 9, 0, 0, 0, time.UTC)
+		trip := &Trip{Destination: "Lisboa", StartDate: start, EndDate: start.AddDate(0, 0, 4)}
+
+		assert.Empty(t, policy.Evaluate(trip, now))
+	})
+
+	t.Run("Every rule broken", func(t *testing.T) {
+		// Saturday, three days ahead, lasting 12 days
+		start := time.Date(2030, 3, 9, 9, 0, 0, 0, time.UTC)
+		trip := &Trip{Destination: "Caracas, Venezuela", StartDate: start, EndDate: start.AddDate(0, 0, 12)}
+
+		violations := policy.Evaluate(trip, now)
+		assert.Equal(t, []PolicyViolation{
+			{Rule: RuleMaxTripDays, Severity: SeverityBlock, Message: "trips cannot last more than 10 days"},
+			{Rule: RuleMinAdvanceDays, Severity: SeverityWarn, Message: "trips must be requested at least 14 days in advance"},
+			{Rule: RuleDestinations, Severity: SeverityBlock, Message: "travel to caracas is restricted"},
+			{Rule: RuleWeekendTravel, Severity: SeverityWarn, Message: "trips should not start or end on a weekend"},
+		}, violations)
+	})
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,25 +16,105 @@ const (
 	StatusRequested TripStatus = "solicitado"
 	StatusApproved  TripStatus = "aprovado"
 	StatusCanceled  TripStatus = "cancelado"
+	// StatusWithdrawn is a pending trip the requester gave up on
+	StatusWithdrawn TripStatus = "retirado"
+	// StatusConcluded is an approved trip whose end date has passed
+	StatusConcluded TripStatus = "concluido"
 )
 
 func (s TripStatus) IsValid() bool {
 	switch s {
-	case StatusRequested, StatusApproved, StatusCanceled:
+	case StatusRequested, StatusApproved, StatusCanceled, StatusWithdrawn, StatusConcluded:
 		return true
 	}
 	return false
 }
 
+// ErrTripStatusChanged is returned when a trip is saved on the assumption of
+// a status it no longer has, e.g. a trip approved while its requester edited it
+var ErrTripStatusChanged = errors.New("trip status changed meanwhile")
+
+// ErrTripOverlap is returned when a trip's dates overlap another active trip of the same requester
+var ErrTripOverlap = errors.New("trip dates overlap other trips of the requester")
+
+// TripConflictError lists the requester's trips a trip overlaps. It matches ErrTripOverlap with errors.Is.
+type TripConflictError struct {
+	TripIDs []uuid.UUID
+}
+
+func (e *TripConflictError) Error() string {
+	return ErrTripOverlap.Error()
+}
+
+func (e *TripConflictError) Unwrap() error {
+	return ErrTripOverlap
+}
+
+type TripType string
+
+const (
+	TripDomestic      TripType = "domestic"
+	TripInternational TripType = "international"
+)
+
+func (t TripType) IsValid() bool {
+	return t == TripDomestic || t == TripInternational
+}
+
 type Trip struct {
-	ID          uuid.UUID  `json:"id"`
-	RequesterID uuid.UUID  `json:"requester_id"`
-	Destination string     `json:"destination"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     time.Time  `json:"end_date"`
-	Status      TripStatus `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	OrgID        uuid.UUID  `json:"org_id"`
+	RequesterID  uuid.UUID  `json:"requester_id"`
+	CostCenterID uuid.UUID  `json:"cost_center_id"`
+	Type         TripType   `json:"type"`
+	Destination  string     `json:"destination"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	Status       TripStatus `json:"status"`
+	// PolicyWarnings lists the travel policy rules with warn severity the trip breaks
+	PolicyWarnings []string `json:"policy_warnings,omitempty"`
+	// Legs is the itinerary of the trip, in travel order. Destination is derived from them.
+	Legs []ItineraryLeg `json:"legs,omitempty"`
+	// DestinationCode references the destination catalog, so trips to the same
+	// city can be grouped however their destination is spelled
+	DestinationCode *string   `json:"destination_code,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	// TimeZone is the IANA zone of the trip; its dates are shown in it
+	TimeZone string `json:"time_zone,omitempty"`
+	// Budget is the estimated cost of the trip
+	Budget *TripBudget `json:"budget,omitempty"`
+	// PerDiem is the daily allowance of the traveler, from the per diem rates
+	PerDiem *TripPerDiem `json:"per_diem,omitempty"`
+}
+
+// ItinerarySummary describes the trip in a line, for notifications. Trips
+// with legs show their route, e.g. São Paulo → Lisbon → São Paulo.
+func (t *Trip) ItinerarySummary() string {
+	route := t.Destination
+	if len(t.Legs) > 0 {
+		stops := []string{t.Legs[0].Origin}
+		for _, leg := range t.Legs {
+			stops = append(stops, leg.Destination)
+		}
+		route = strings.Join(stops, " → ")
+	}
+	return fmt.Sprintf("%s (%s), %s to %s", route, t.Type,
+		t.StartDate.Format("02 Jan 2006"), t.EndDate.Format("02 Jan 2006"))
+}
+
+// SetLegs replaces the itinerary of the trip, numbering the legs and deriving
+// the destination from them. Trips without legs keep their destination.
+func (t *Trip) SetLegs(legs []ItineraryLeg) {
+	t.Legs = nil
+	for i, leg := range legs {
+		leg.ID = uuid.New()
+		leg.Position = i + 1
+		t.Legs = append(t.Legs, leg)
+	}
+	if len(t.Legs) > 0 {
+		t.Destination = ItineraryDestination(t.Legs)
+	}
 }
 
 // Validate checks if the trip data is valid according to business rules
@@ -40,6 +123,8 @@ func (t *Trip) Validate() error {
 
 	// Check required fields
 	validationErrors.AddIf(t.RequesterID == uuid.Nil, "requester_id is required")
+	validationErrors.AddIf(t.CostCenterID == uuid.Nil, "cost_center_id is required")
+	validationErrors.AddIf(!t.Type.IsValid(), "type must be domestic or international")
 	validationErrors.AddIf(t.Destination == "", "destination is required")
 
 	// Check if StartDate is zero
@@ -56,6 +141,17 @@ func (t *Trip) Validate() error {
 	// Check if Status is valid
 	validationErrors.AddIf(!t.Status.IsValid(), "invalid status")
 
+	if t.TimeZone != "" {
+		_, ok := loadTimeZone(t.TimeZone)
+		validationErrors.AddIf(!ok, "time_zone must be an IANA time zone, e.g. America/Sao_Paulo")
+	}
+
+	t.validateItinerary(validationErrors)
+
+	if t.Budget != nil {
+		t.Budget.validate(validationErrors)
+	}
+
 	if validationErrors.HasErrors() {
 		return validationErrors
 	}
@@ -63,16 +159,33 @@ func (t *Trip) Validate() error {
 }
 
 type ListTripsParams struct {
-	RequesterID *uuid.UUID
-	Status      *TripStatus
-	Destination *string
-	StartDate   *time.Time
-	EndDate     *time.Time
+	RequesterID  *uuid.UUID
+	CostCenterID *uuid.UUID
+	Status       *TripStatus
+	Destination  *string
+	StartDate    *time.Time
+	EndDate      *time.Time
+	// DestinationCode matches the catalog code of the trip's destination
+	DestinationCode *string
 }
 
 type TripRepository interface {
 	Create(ctx context.Context, trip *Trip) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Trip, error)
+	// Update saves the fields a requester can edit. It returns
+	// ErrTripStatusChanged unless the stored trip still has trip.Status.
+	Update(ctx context.Context, trip *Trip) error
 	List(ctx context.Context, params ListTripsParams) ([]*Trip, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status TripStatus) error
+	// UpdateStatus moves the trip from one status to another. It returns
+	// ErrTripStatusChanged when the stored trip no longer has the from status,
+	// and ErrTripOverlap when the status brings back a cancelled trip whose
+	// dates were taken meanwhile.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to TripStatus) error
+	// FindOverlapping returns the requester's trips that are not canceled or
+	// withdrawn and share any moment with [start, end], leaving out the trip with excludeID.
+	// Create and Update return ErrTripOverlap when a concurrent save got there first.
+	FindOverlapping(ctx context.Context, requesterID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*Trip, error)
+	// ConcludeEnded moves the approved trips that ended before the given moment
+	// to StatusConcluded and returns them
+	ConcludeEnded(ctx context.Context, endedBefore time.Time) ([]*Trip, error)
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TripEventAction string
+
+const (
+	EventCreated  TripEventAction = "created"
+	EventUpdated  TripEventAction = "updated"
+	EventApproved TripEventAction = "approved"
+	EventRejected TripEventAction = "rejected"
+	EventCanceled TripEventAction = "canceled"
+	// EventWithdrawn is the requester giving up on a pending trip
+	EventWithdrawn TripEventAction = "withdrawn"
+	// EventCancellationOverride is a cancellation inside the cancellation
+	// window or on the requester's behalf, always with a justification
+	EventCancellationOverride TripEventAction = "cancellation_override"
+)
+
+// TripEvent is an entry of the trip history
+type TripEvent struct {
+	ID      uuid.UUID       `json:"id"`
+	OrgID   uuid.UUID       `json:"org_id"`
+	TripID  uuid.UUID       `json:"trip_id"`
+	ActorID uuid.UUID       `json:"actor_id"`
+	Action  TripEventAction `json:"action"`
+	// OnBehalfOfID is the approver whose delegation the actor used
+	OnBehalfOfID  *uuid.UUID `json:"on_behalf_of_id,omitempty"`
+	Justification string     `json:"justification,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type TripEventRepository interface {
+	Create(ctx context.Context, event *TripEvent) error
+	// ListByTripID returns the history of a trip, oldest first
+	ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*TripEvent, error)
+}
@@ -11,19 +11,22 @@ import (
 func TestTrip_Validate(t *testing.T) {
 	// Setup valid trip for reuse
 	validRequesterID := uuid.New()
+	validCostCenterID := uuid.New()
 	validStartDate := time.Now().Add(24 * time.Hour)   // tomorrow
 	validEndDate := validStartDate.Add(48 * time.Hour) // 2 days after start
 
 	t.Run("Valid trip", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "Paris",
-			StartDate:   validStartDate,
-			EndDate:     validEndDate,
-			Status:      StatusRequested,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
 		}
 
 		err := trip.Validate()
@@ -32,12 +35,14 @@ func TestTrip_Validate(t *testing.T) {
 
 	t.Run("Missing requester ID", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: uuid.Nil, // Invalid: zero value
-			Destination: "Paris",
-			StartDate:   validStartDate,
-			EndDate:     validEndDate,
-			Status:      StatusRequested,
+			ID:           uuid.New(),
+			RequesterID:  uuid.Nil, // Invalid: zero value
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
 		}
 
 		err := trip.Validate()
@@ -45,14 +50,32 @@ func TestTrip_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "requester_id is required")
 	})
 
+	t.Run("Missing cost center", func(t *testing.T) {
+		trip := &Trip{
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: uuid.Nil, // Invalid: zero value
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
+		}
+
+		err := trip.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cost_center_id is required")
+	})
+
 	t.Run("Missing destination", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "", // Invalid: empty string
-			StartDate:   validStartDate,
-			EndDate:     validEndDate,
-			Status:      StatusRequested,
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "", // Invalid: empty string
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
 		}
 
 		err := trip.Validate()
@@ -62,12 +85,14 @@ func TestTrip_Validate(t *testing.T) {
 
 	t.Run("Missing start date", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "Paris",
-			StartDate:   time.Time{}, // Invalid: zero value
-			EndDate:     validEndDate,
-			Status:      StatusRequested,
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    time.Time{}, // Invalid: zero value
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
 		}
 
 		err := trip.Validate()
@@ -77,12 +102,14 @@ func TestTrip_Validate(t *testing.T) {
 
 	t.Run("Missing end date", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "Paris",
-			StartDate:   validStartDate,
-			EndDate:     time.Time{}, // Invalid: zero value
-			Status:      StatusRequested,
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      time.Time{}, // Invalid: zero value
+			Status:       StatusRequested,
 		}
 
 		err := trip.Validate()
@@ -93,12 +120,14 @@ func TestTrip_Validate(t *testing.T) {
 	t.Run("End date not after start date", func(t *testing.T) {
 		// Test with end date equal to start date
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "Paris",
-			StartDate:   validStartDate,
-			EndDate:     validStartDate, // Invalid: same as start date
-			Status:      StatusRequested,
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validStartDate, // Invalid: same as start date
+			Status:       StatusRequested,
 		}
 
 		err := trip.Validate()
@@ -114,12 +143,14 @@ func TestTrip_Validate(t *testing.T) {
 
 	t.Run("Invalid status", func(t *testing.T) {
 		trip := &Trip{
-			ID:          uuid.New(),
-			RequesterID: validRequesterID,
-			Destination: "Paris",
-			StartDate:   validStartDate,
-			EndDate:     validEndDate,
-			Status:      "invalid_status", // Invalid status
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         TripDomestic,
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       "invalid_status", // Invalid status
 		}
 
 		err := trip.Validate()
@@ -127,6 +158,23 @@ func TestTrip_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid status")
 	})
 
+	t.Run("Invalid type", func(t *testing.T) {
+		trip := &Trip{
+			ID:           uuid.New(),
+			RequesterID:  validRequesterID,
+			CostCenterID: validCostCenterID,
+			Type:         "business", // Invalid type
+			Destination:  "Paris",
+			StartDate:    validStartDate,
+			EndDate:      validEndDate,
+			Status:       StatusRequested,
+		}
+
+		err := trip.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "type must be domestic or international")
+	})
+
 	t.Run("Multiple validation errors", func(t *testing.T) {
 		trip := &Trip{
 			ID:          uuid.New(),
@@ -143,6 +191,8 @@ func TestTrip_Validate(t *testing.T) {
 		// Check that all errors are reported
 		errMsg := err.Error()
 		assert.Contains(t, errMsg, "requester_id is required")
+		assert.Contains(t, errMsg, "cost_center_id is required")
+		assert.Contains(t, errMsg, "type must be domestic or international")
 		assert.Contains(t, errMsg, "destination is required")
 		assert.Contains(t, errMsg, "start_date is required")
 		assert.Contains(t, errMsg, "end_date is required")
@@ -151,6 +201,13 @@ func TestTrip_Validate(t *testing.T) {
 		// Check that it's a ValidationErrors type
 		validationErrs, ok := err.(*ValidationErrors)
 		assert.True(t, ok, "Error should be of type *ValidationErrors")
-		assert.Equal(t, 5, len(validationErrs.GetErrors()))
+		assert.Equal(t, 7, len(validationErrs.GetErrors()))
 	})
 }
+
+func TestTripConflictError(t *testing.T) {
+	var err error = &TripConflictError{TripIDs: []uuid.UUID{uuid.New()}}
+
+	assert.ErrorIs(t, err, ErrTripOverlap)
+	assert.Equal(t, ErrTripOverlap.Error(), err.Error())
+}
@@ -9,13 +9,38 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserRole decides which approval steps a user can decide and whether they
+// can manage the organization's settings
+type UserRole string
+
+const (
+	RoleEmployee UserRole = "employee"
+	RoleManager  UserRole = "manager"
+	RoleDirector UserRole = "director"
+	RoleFinance  UserRole = "finance"
+	RoleAdmin    UserRole = "admin"
+)
+
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleEmployee, RoleManager, RoleDirector, RoleFinance, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Don't expose password hash
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	OrgID        uuid.UUID  `json:"org_id"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Role         UserRole   `json:"role"`
+	// ManagerID is the user's direct manager, who decides the manager step of their trips
+	ManagerID    *uuid.UUID `json:"manager_id,omitempty"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"` // Don't expose password hash
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
 // Validate checks if the user data is valid according to business rules
@@ -37,6 +62,7 @@ func (u *User) Validate() error {
 	}
 
 	validationErrors.AddIf(u.PasswordHash == "", "password_hash is required")
+	validationErrors.AddIf(!u.Role.IsValid(), "invalid role")
 
 	if validationErrors.HasErrors() {
 		return validationErrors
@@ -48,4 +74,10 @@ type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	UpdateDepartment(ctx context.Context, id uuid.UUID, departmentID *uuid.UUID) error
+	UpdateRole(ctx context.Context, id uuid.UUID, role UserRole, managerID *uuid.UUID) error
+	ListByRole(ctx context.Context, role UserRole) ([]*User, error)
+	// FindPasswordHistory returns the most recent password hashes, newest first
+	FindPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([]string, error)
 }
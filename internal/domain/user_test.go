@@ -22,6 +22,7 @@ func TestUser_Validate(t *testing.T) {
 			Name:         validName,
 			Email:        validEmail,
 			PasswordHash: validPasswordHash,
+			Role:         RoleEmployee,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -30,6 +31,22 @@ func TestUser_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Invalid role", func(t *testing.T) {
+		user := &User{
+			ID:           validID,
+			Name:         validName,
+			Email:        validEmail,
+			PasswordHash: validPasswordHash,
+			Role:         "owner", // Invalid: unknown role
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		err := user.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid role")
+	})
+
 	t.Run("Missing name", func(t *testing.T) {
 		user := &User{
 			ID:           validID,
@@ -111,6 +128,7 @@ func TestUser_Validate(t *testing.T) {
 			Name:         "",              // Invalid: empty name
 			Email:        "invalid-email", // Invalid: not a valid email format
 			PasswordHash: "",              // Invalid: empty password hash
+			Role:         RoleEmployee,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type glAccountRequest struct {
+	Account string `json:"account" binding:"required"`
+}
+
+func (h *Handler) ListGLAccounts(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	accounts, err := h.accountingExportService.ListGLAccounts(c.Request.Context(), userID)
+	if err != nil {
+		respondAccountingExportError(c, err, "Failed to list GL accounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// SaveGLAccount maps the expense category of the path to a GL account
+func (h *Handler) SaveGLAccount(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req glAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	account, err := h.accountingExportService.SaveGLAccount(c.Request.Context(), userID, domain.BudgetCategory(c.Param("category")), req.Account)
+	if err != nil {
+		respondAccountingExportError(c, err, "Failed to save GL account")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// ExportAccounting exports the expenses approved in the period (?period=2024-05)
+// that weren't exported yet, as CSV or fixed-width text (?format=fixed). It
+// responds with no content when there's nothing left to export.
+func (h *Handler) ExportAccounting(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+	format, ok := accountingExportFormat(c)
+	if !ok {
+		return
+	}
+
+	batch, err := h.accountingExportService.Export(c.Request.Context(), userID, c.Query("period"))
+	if errors.Is(err, service.ErrNothingToExport) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		respondAccountingExportError(c, err, "Failed to export expenses")
+		return
+	}
+
+	respondAccountingExportBatch(c, batch, format)
+}
+
+func (h *Handler) ListAccountingExportBatches(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	batches, err := h.accountingExportService.ListBatches(c.Request.Context(), userID)
+	if err != nil {
+		respondAccountingExportError(c, err, "Failed to list export batches")
+		return
+	}
+
+	c.JSON(http.StatusOK, batches)
+}
+
+// DownloadAccountingExportBatch downloads a batch again, with the lines it was exported with
+func (h *Handler) DownloadAccountingExportBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export batch ID format"})
+		return
+	}
+	format, ok := accountingExportFormat(c)
+	if !ok {
+		return
+	}
+
+	batch, err := h.accountingExportService.GetBatch(c.Request.Context(), userID, batchID)
+	if err != nil {
+		respondAccountingExportError(c, err, "Failed to download export batch")
+		return
+	}
+
+	respondAccountingExportBatch(c, batch, format)
+}
+
+// accountingExportFormat reads the ?format= of the request, CSV by default,
+// responding with the error when it's invalid
+func accountingExportFormat(c *gin.Context) (domain.AccountingExportFormat, bool) {
+	format := domain.AccountingExportFormat(c.DefaultQuery("format", string(domain.ExportCSV)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format: must be csv or fixed"})
+		return "", false
+	}
+	return format, true
+}
+
+func respondAccountingExportBatch(c *gin.Context, batch *domain.AccountingExportBatch, format domain.AccountingExportFormat) {
+	var buf bytes.Buffer
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	write := batch.WriteCSV
+	if format == domain.ExportFixedWidth {
+		contentType, extension = "text/plain; charset=utf-8", "txt"
+		write = batch.WriteFixedWidth
+	}
+	if err := write(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write export batch"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="accounting-%s-%s.%s"`, batch.Period, batch.ID, extension))
+	c.Header("X-Export-Batch-ID", batch.ID.String())
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+func respondAccountingExportError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrExportBatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrAlreadyExported):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with mock accounting export collaborators and a fixed user
+func setupAccountingExportTestRouter() (*gin.Engine, *mocks.MockAccountingExportRepository, *mocks.MockGLAccountRepository, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockExportRepo := new(mocks.MockAccountingExportRepository)
+	mockGLAccountRepo := new(mocks.MockGLAccountRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService))
+	accountingExportService := service.NewAccountingExportService(mockExportRepo, mockGLAccountRepo, mockTripRepo,
+		new(mocks.MockCostCenterRepository), mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithAccountingExportService(accountingExportService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.GET("/accounting/gl-accounts", h.ListGLAccounts)
+	router.PUT("/accounting/gl-accounts/:category", h.SaveGLAccount)
+	router.GET("/exports/accounting", h.ExportAccounting)
+	router.GET("/exports/accounting/batches", h.ListAccountingExportBatches)
+	router.GET("/exports/accounting/batches/:id", h.DownloadAccountingExportBatch)
+
+	return router, mockExportRepo, mockGLAccountRepo, mockUserRepo, userID
+}
+
+func TestExportAccounting(t *testing.T) {
+	t.Run("Nothing to export", func(t *testing.T) {
+		// Arrange
+		router, mockExportRepo, mockGLAccountRepo, mockUserRepo, userID := setupAccountingExportTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockExportRepo.On("ListExportableReports", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.ExpenseReport{}, nil)
+		mockGLAccountRepo.On("List", mock.Anything).Return([]*domain.GLAccount{}, nil)
+
+		req, _ := http.NewRequest("GET", "/exports/accounting?period=2024-05", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupAccountingExportTestRouter()
+		req, _ := http.NewRequest("GET", "/exports/accounting?period=2024-05&format=xlsx", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo, userID := setupAccountingExportTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+
+		req, _ := http.NewRequest("GET", "/exports/accounting", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "period must be YYYY-MM")
+	})
+
+	t.Run("Employees can't export", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo, userID := setupAccountingExportTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleEmployee}, nil)
+
+		req, _ := http.NewRequest("GET", "/exports/accounting?period=2024-05", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestDownloadAccountingExportBatch(t *testing.T) {
+	t.Run("Fixed width", func(t *testing.T) {
+		// Arrange
+		router, mockExportRepo, _, mockUserRepo, userID := setupAccountingExportTestRouter()
+		batch := &domain.AccountingExportBatch{
+			ID:        uuid.New(),
+			Period:    "2024-05",
+			CreatedAt: time.Now(),
+			LineCount: 1,
+			Lines: []domain.AccountingExportLine{{
+				TripID: uuid.New(), TravelerID: uuid.New(), CostCenterCode: "CC-100", GLAccount: "4.1.02.003",
+				Category: domain.BudgetLodging, Date: time.Now(), Description: "Hotel", Amount: 80000, Currency: "BRL",
+			}},
+		}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockExportRepo.On("FindBatch", mock.Anything, batch.ID).Return(batch, nil)
+
+		req, _ := http.NewRequest("GET", "/exports/accounting/batches/"+batch.ID.String()+"?format=fixed", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, batch.ID.String(), w.Header().Get("X-Export-Batch-ID"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), ".txt")
+		records := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, records, 2)
+		assert.True(t, strings.HasPrefix(records[0], "H202405"+batch.ID.String()))
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		// Arrange
+		router, mockExportRepo, _, mockUserRepo, userID := setupAccountingExportTestRouter()
+		batchID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockExportRepo.On("FindBatch", mock.Anything, batchID).Return(nil, nil)
+
+		req, _ := http.NewRequest("GET", "/exports/accounting/batches/"+batchID.String(), nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSaveGLAccount(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, _, mockGLAccountRepo, mockUserRepo, userID := setupAccountingExportTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockGLAccountRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.GLAccount")).Return(nil)
+
+		req, _ := http.NewRequest("PUT", "/accounting/gl-accounts/lodging", bytes.NewBufferString(`{"account": "4.1.02.003"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "lodging", response["category"])
+		assert.Equal(t, "4.1.02.003", response["account"])
+	})
+
+	t.Run("Missing account", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupAccountingExportTestRouter()
+		req, _ := http.NewRequest("PUT", "/accounting/gl-accounts/lodging", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type approvalPolicyRequest struct {
+	Name            string            `json:"name" binding:"required"`
+	Priority        int               `json:"priority"`
+	Destinations    []string          `json:"destinations"`
+	MinDurationDays int               `json:"min_duration_days"`
+	TripType        domain.TripType   `json:"trip_type"`
+	Steps           []domain.UserRole `json:"steps" binding:"required"`
+	// MinBudget limits the policy to trips whose budget reaches it, in minor units of BudgetCurrency
+	MinBudget      int64  `json:"min_budget"`
+	BudgetCurrency string `json:"budget_currency"`
+}
+
+func (h *Handler) CreateApprovalPolicy(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req approvalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	policy, err := h.approvalService.CreatePolicy(c.Request.Context(), userID, service.ApprovalPolicyInput{
+		Name:            req.Name,
+		Priority:        req.Priority,
+		Destinations:    req.Destinations,
+		MinDurationDays: req.MinDurationDays,
+		TripType:        req.TripType,
+		Steps:           req.Steps,
+		MinBudget:       req.MinBudget,
+		BudgetCurrency:  req.BudgetCurrency,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create approval policy"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+func (h *Handler) ListApprovalPolicies(c *gin.Context) {
+	policies, err := h.approvalService.ListPolicies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list approval policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *Handler) DeleteApprovalPolicy(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid approval policy ID format"})
+		return
+	}
+
+	if err := h.approvalService.DeletePolicy(c.Request.Context(), userID, policyID); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete approval policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Approval policy deleted successfully"})
+}
+
+func (h *Handler) ListPendingApprovals(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	pending, err := h.approvalService.ListPendingApprovals(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pending approvals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pending)
+}
+
+func (h *Handler) GetTripApprovals(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return
+	}
+
+	steps, err := h.approvalService.GetTripApprovals(c.Request.Context(), tripID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTripNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trip approvals"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, steps)
+}
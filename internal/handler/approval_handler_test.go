@@ -0,0 +1,200 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type approvalTestMocks struct {
+	tripRepo   *mocks.MockTripRepository
+	userRepo   *mocks.MockUserRepository
+	notifier   *mocks.MockNotificationService
+	policyRepo *mocks.MockApprovalPolicyRepository
+	stepRepo   *mocks.MockApprovalStepRepository
+}
+
+// Setup test router with approval chains enabled and a fixed userID
+func setupApprovalTestRouter() (*gin.Engine, *approvalTestMocks, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	m := &approvalTestMocks{
+		tripRepo:   new(mocks.MockTripRepository),
+		userRepo:   new(mocks.MockUserRepository),
+		notifier:   new(mocks.MockNotificationService),
+		policyRepo: new(mocks.MockApprovalPolicyRepository),
+		stepRepo:   new(mocks.MockApprovalStepRepository),
+	}
+
+	userService := service.NewUserService(m.userRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(m.tripRepo, m.userRepo, m.notifier,
+		service.WithApprovalChains(m.policyRepo, m.stepRepo))
+	approvalService := service.NewApprovalService(m.policyRepo, m.stepRepo, m.tripRepo, m.userRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithApprovalService(approvalService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.PATCH("/trips/:id/status", h.UpdateTripStatus)
+	router.GET("/trips/:id/approvals", h.GetTripApprovals)
+	router.POST("/approval-policies", h.CreateApprovalPolicy)
+	router.GET("/approvals/pending", h.ListPendingApprovals)
+	router.PUT("/users/:id/role", h.UpdateUserRole)
+
+	return router, m, userID
+}
+
+func TestCreateApprovalPolicy(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, m, userID := setupApprovalTestRouter()
+
+		// Mock behavior
+		m.userRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		m.policyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ApprovalPolicy")).Return(nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{
+			"name":         "International",
+			"destinations": []string{"Paris", "Lisboa"},
+			"steps":        []string{"manager", "director"},
+		})
+		req, _ := http.NewRequest("POST", "/approval-policies", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.ApprovalPolicy
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.UserRole{domain.RoleManager, domain.RoleDirector}, response.Steps)
+	})
+
+	t.Run("Forbidden for non admins", func(t *testing.T) {
+		// Arrange
+		router, m, userID := setupApprovalTestRouter()
+
+		// Mock behavior
+		m.userRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"name": "International", "steps": []string{"director"}})
+		req, _ := http.NewRequest("POST", "/approval-policies", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		m.policyRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestListPendingApprovals(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, m, userID := setupApprovalTestRouter()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Destination: "Paris", Status: domain.StatusRequested}
+		step := &domain.ApprovalStep{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, Status: domain.StepPending}
+
+		// Mock behavior
+		m.userRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+		m.stepRepo.On("ListCurrent", mock.Anything, userID, domain.RoleManager).Return([]*domain.ApprovalStep{step}, nil)
+		m.tripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+
+		// Act
+		req, _ := http.NewRequest("GET", "/approvals/pending", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []service.PendingApproval
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, trip.ID, response[0].Trip.ID)
+		assert.Equal(t, domain.RoleManager, response[0].Step.Role)
+	})
+}
+
+func TestUpdateTripStatus_ApprovalChain(t *testing.T) {
+	t.Run("User without the step role", func(t *testing.T) {
+		// Arrange
+		router, m, userID := setupApprovalTestRouter()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleDirector, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		m.tripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		m.stepRepo.On("FindByTripID", mock.Anything, trip.ID).Return(steps, nil)
+		m.userRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"status": domain.StatusApproved})
+		req, _ := http.NewRequest("PATCH", "/trips/"+trip.ID.String()+"/status", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		m.tripRepo.AssertNotCalled(t, "UpdateStatus")
+	})
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, m, userID := setupApprovalTestRouter()
+		user := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		m.userRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		m.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		m.userRepo.On("UpdateRole", mock.Anything, user.ID, domain.RoleDirector, (*uuid.UUID)(nil)).Return(nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"role": "director"})
+		req, _ := http.NewRequest("PUT", "/users/"+user.ID.String()+"/role", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		m.userRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and the other
+// fields of an upload of the largest file accepted
+const multipartOverhead = 1 << 20
+
+// UploadAttachment attaches a file to a trip (multipart/form-data with the file
+// in "file" and, optionally, the expense it belongs to in "expense_item_id").
+// Uploading a file the trip already has returns the existing attachment with 200.
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	maxSize := h.attachmentService.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []string{"file is required"}})
+		return
+	}
+
+	input := service.AttachmentInput{FileName: fileHeader.Filename}
+	if value := c.PostForm("expense_item_id"); value != "" {
+		itemID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense item ID format"})
+			return
+		}
+		input.ExpenseItemID = &itemID
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+	// One byte past the limit is enough to tell the file is too large
+	if input.Content, err = io.ReadAll(io.LimitReader(file, maxSize+1)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+
+	attachment, created, err := h.attachmentService.Upload(c.Request.Context(), userID, tripID, input)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, attachment)
+}
+
+func (h *Handler) ListAttachments(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to list attachments")
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// GetAttachmentURL returns a signed, time-limited URL that downloads the attachment
+func (h *Handler) GetAttachmentURL(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID format"})
+		return
+	}
+
+	signed, err := h.attachmentService.DownloadURL(c.Request.Context(), userID, tripID, attachmentID)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to sign attachment URL")
+		return
+	}
+
+	c.JSON(http.StatusOK, signed)
+}
+
+// DownloadAttachment serves the content of an attachment to the holder of a
+// signed URL. It isn't authenticated: the signature is the credential.
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID format"})
+		return
+	}
+	orgID, orgErr := uuid.Parse(c.Query("org"))
+	expires, expiresErr := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if orgErr != nil || expiresErr != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": service.ErrInvalidDownloadURL.Error()})
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenDownload(c.Request.Context(), attachmentID, orgID, expires, c.Query("signature"))
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to download attachment")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, attachment.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, attachment.ContentType, content)
+}
+
+func respondAttachmentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrInvalidDownloadURL):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTripNotFound), errors.Is(err, service.ErrAttachmentNotFound),
+		errors.Is(err, service.ErrExpenseItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedAttachment):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
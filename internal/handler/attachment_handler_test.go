@@ -0,0 +1,202 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var handlerTestPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+// Setup test router with mock attachment collaborators and a fixed traveler
+func setupAttachmentTestRouter() (*gin.Engine, *mocks.MockAttachmentRepository, *mocks.MockTripRepository, *mocks.MockBlobStore, uuid.UUID, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockAttachmentRepo := new(mocks.MockAttachmentRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockStore := new(mocks.MockBlobStore)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService))
+	attachmentService := service.NewAttachmentService(mockAttachmentRepo, mockTripRepo, new(mocks.MockExpenseReportRepository),
+		mockUserRepo, mockStore, service.AttachmentConfig{
+			MaxSize:         64,
+			URLTTL:          15 * time.Minute,
+			DownloadBaseURL: "http://api.test",
+			URLSecret:       "test-secret",
+		})
+
+	h := handler.NewHandler(userService, tripService, handler.WithAttachmentService(attachmentService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.GET("/attachments/:id/download", h.DownloadAttachment)
+	authRoutes := router.Group("/")
+	authRoutes.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	authRoutes.POST("/trips/:id/attachments", h.UploadAttachment)
+	authRoutes.GET("/trips/:id/attachments/:attachment_id/url", h.GetAttachmentURL)
+
+	return router, mockAttachmentRepo, mockTripRepo, mockStore, userID, orgID
+}
+
+func multipartUpload(t *testing.T, path, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	part.Write(content)
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadAttachment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockAttachmentRepo, mockTripRepo, mockStore, userID, orgID := setupAttachmentTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, OrgID: orgID, RequesterID: userID}, nil)
+		mockAttachmentRepo.On("FindByChecksum", mock.Anything, mock.AnythingOfType("string")).Return([]*domain.Attachment{}, nil)
+		mockStore.On("Put", mock.Anything, mock.AnythingOfType("string"), handlerTestPNG, "image/png").Return(nil)
+		mockAttachmentRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		req := multipartUpload(t, "/trips/"+tripID.String()+"/attachments", "boarding-pass.png", handlerTestPNG)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "image/png", response["content_type"])
+		assert.Equal(t, "boarding-pass.png", response["file_name"])
+		assert.NotContains(t, response, "storage_key")
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		// Arrange
+		router, _, mockTripRepo, _, userID, orgID := setupAttachmentTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, OrgID: orgID, RequesterID: userID}, nil)
+
+		req := multipartUpload(t, "/trips/"+tripID.String()+"/attachments", "scan.png", append(handlerTestPNG, make([]byte, 64)...))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		// Arrange
+		router, _, mockTripRepo, _, userID, orgID := setupAttachmentTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, OrgID: orgID, RequesterID: userID}, nil)
+
+		req := multipartUpload(t, "/trips/"+tripID.String()+"/attachments", "receipt.png", []byte("plain text pretending"))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _, _ := setupAttachmentTestRouter()
+		req, _ := http.NewRequest("POST", "/trips/"+uuid.New().String()+"/attachments", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	// Arrange
+	router, mockAttachmentRepo, mockTripRepo, mockStore, userID, orgID := setupAttachmentTestRouter()
+	tripID := uuid.New()
+	attachment := &domain.Attachment{
+		ID: uuid.New(), OrgID: orgID, TripID: tripID, FileName: "boarding-pass.png", ContentType: "image/png", StorageKey: "attachments/key",
+	}
+
+	// Mock behavior
+	mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, OrgID: orgID, RequesterID: userID}, nil)
+	mockAttachmentRepo.On("FindByID", mock.Anything, attachment.ID).Return(attachment, nil)
+	mockStore.On("Get", mock.Anything, "attachments/key").Return(handlerTestPNG, nil)
+
+	req, _ := http.NewRequest("GET", "/trips/"+tripID.String()+"/attachments/"+attachment.ID.String()+"/url", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var signed service.AttachmentURL
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &signed))
+	u, err := url.Parse(signed.URL)
+	require.NoError(t, err)
+
+	t.Run("Signed URL downloads the file", func(t *testing.T) {
+		// Act
+		req, _ := http.NewRequest("GET", u.RequestURI(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="boarding-pass.png"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, handlerTestPNG, w.Body.Bytes())
+	})
+
+	t.Run("Tampered URL", func(t *testing.T) {
+		// Arrange
+		query := u.Query()
+		query.Set("expires", "9999999999")
+
+		// Act
+		req, _ := http.NewRequest("GET", u.Path+"?"+query.Encode(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type blackoutCalendarRequest struct {
+	// DepartmentID limits the calendar to a department
+	DepartmentID *uuid.UUID              `json:"department_id"`
+	Name         string                  `json:"name" binding:"required"`
+	Severity     domain.RuleSeverity     `json:"severity" binding:"required"`
+	Periods      []domain.BlackoutPeriod `json:"periods"`
+}
+
+func (h *Handler) CreateBlackoutCalendar(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req blackoutCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	calendar, err := h.blackoutCalendarService.CreateCalendar(c.Request.Context(), userID, service.BlackoutCalendarInput{
+		DepartmentID: req.DepartmentID,
+		Name:         req.Name,
+		Severity:     req.Severity,
+		Periods:      req.Periods,
+	})
+	if err != nil {
+		respondBlackoutCalendarError(c, err, "Failed to create blackout calendar")
+		return
+	}
+
+	c.JSON(http.StatusCreated, calendar)
+}
+
+func (h *Handler) ListBlackoutCalendars(c *gin.Context) {
+	calendars, err := h.blackoutCalendarService.ListCalendars(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list blackout calendars"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendars)
+}
+
+// ImportBlackoutCalendar replaces the periods of a calendar with the events of the iCalendar file in the body
+func (h *Handler) ImportBlackoutCalendar(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	calendarID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blackout calendar ID format"})
+		return
+	}
+
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	calendar, err := h.blackoutCalendarService.ImportCalendar(c.Request.Context(), userID, calendarID, document)
+	if err != nil {
+		respondBlackoutCalendarError(c, err, "Failed to import blackout calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+func (h *Handler) DeleteBlackoutCalendar(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	calendarID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blackout calendar ID format"})
+		return
+	}
+
+	if err := h.blackoutCalendarService.DeleteCalendar(c.Request.Context(), userID, calendarID); err != nil {
+		respondBlackoutCalendarError(c, err, "Failed to delete blackout calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blackout calendar deleted successfully"})
+}
+
+func respondBlackoutCalendarError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBlackoutCalendarNotFound), errors.Is(err, service.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
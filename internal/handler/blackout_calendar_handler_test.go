@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock blackout calendar repository and a fixed userID
+func setupBlackoutCalendarTestRouter() (*gin.Engine, *mocks.MockBlackoutCalendarRepository, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockCalendarRepo := new(mocks.MockBlackoutCalendarRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	blackoutCalendarService := service.NewBlackoutCalendarService(mockCalendarRepo, new(mocks.MockDepartmentRepository), mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithBlackoutCalendarService(blackoutCalendarService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/blackout-calendars", h.CreateBlackoutCalendar)
+	router.PUT("/blackout-calendars/:id/ical", h.ImportBlackoutCalendar)
+
+	return router, mockCalendarRepo, mockUserRepo, userID
+}
+
+func TestCreateBlackoutCalendar(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockCalendarRepo, mockUserRepo, userID := setupBlackoutCalendarTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockCalendarRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.BlackoutCalendar")).Return(nil)
+
+		// Create request
+		body := `{"name": "Fiscal close", "severity": "block", "periods": [{"summary": "Q1", "starts_at": "2030-03-28T00:00:00Z", "ends_at": "2030-04-02T00:00:00Z"}]}`
+		req, _ := http.NewRequest("POST", "/blackout-calendars", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.BlackoutCalendar
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SeverityBlock, response.Severity)
+		assert.Len(t, response.Periods, 1)
+		mockCalendarRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid severity", func(t *testing.T) {
+		// Arrange
+		router, mockCalendarRepo, mockUserRepo, userID := setupBlackoutCalendarTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+
+		// Create request
+		req, _ := http.NewRequest("POST", "/blackout-calendars", bytes.NewBufferString(`{"name": "Holidays", "severity": "maybe"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "severity must be block or warn")
+		mockCalendarRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestImportBlackoutCalendar(t *testing.T) {
+	document := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Tiradentes\r\nDTSTART;VALUE=DATE:20300421\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockCalendarRepo, mockUserRepo, userID := setupBlackoutCalendarTestRouter()
+		calendar := &domain.BlackoutCalendar{ID: uuid.New(), OrgID: uuid.New(), Name: "Holidays", Severity: domain.SeverityWarn}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockCalendarRepo.On("FindByID", mock.Anything, calendar.ID).Return(calendar, nil)
+		mockCalendarRepo.On("Update", mock.Anything, calendar).Return(nil)
+
+		// Create request
+		req, _ := http.NewRequest("PUT", "/blackout-calendars/"+calendar.ID.String()+"/ical", bytes.NewBufferString(document))
+		req.Header.Set("Content-Type", "text/calendar")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response domain.BlackoutCalendar
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Periods, 1)
+		assert.Equal(t, "Tiradentes", response.Periods[0].Summary)
+	})
+
+	t.Run("Calendar not found", func(t *testing.T) {
+		// Arrange
+		router, mockCalendarRepo, mockUserRepo, userID := setupBlackoutCalendarTestRouter()
+		calendarID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockCalendarRepo.On("FindByID", mock.Anything, calendarID).Return(nil, nil)
+
+		// Create request
+		req, _ := http.NewRequest("PUT", "/blackout-calendars/"+calendarID.String()+"/ical", bytes.NewBufferString(document))
+		req.Header.Set("Content-Type", "text/calendar")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
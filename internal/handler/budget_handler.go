@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type budgetRequest struct {
+	// CostCenterID is required to create a budget and can't be changed afterwards
+	CostCenterID uuid.UUID `json:"cost_center_id"`
+	// PeriodStart and PeriodEnd are YYYY-MM-DD, both days included
+	PeriodStart string              `json:"period_start" binding:"required"`
+	PeriodEnd   string              `json:"period_end" binding:"required"`
+	Amount      int64               `json:"amount" binding:"required"`
+	Severity    domain.RuleSeverity `json:"severity" binding:"required"`
+}
+
+// input parses the period of the request
+func (r budgetRequest) input() (service.BudgetInput, error) {
+	validationErrors := domain.NewValidationErrors()
+	periodStart, err := time.Parse("2006-01-02", r.PeriodStart)
+	validationErrors.AddIf(err != nil, "period_start must be YYYY-MM-DD")
+	periodEnd, err := time.Parse("2006-01-02", r.PeriodEnd)
+	validationErrors.AddIf(err != nil, "period_end must be YYYY-MM-DD")
+	if validationErrors.HasErrors() {
+		return service.BudgetInput{}, validationErrors
+	}
+
+	return service.BudgetInput{
+		CostCenterID: r.CostCenterID,
+		PeriodStart:  periodStart,
+		PeriodEnd:    periodEnd,
+		Amount:       r.Amount,
+		Severity:     r.Severity,
+	}, nil
+}
+
+func (h *Handler) CreateBudget(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req budgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+	input, err := req.input()
+	if err != nil {
+		respondBudgetError(c, err, "Failed to create budget")
+		return
+	}
+
+	budget, err := h.budgetService.CreateBudget(c.Request.Context(), userID, input)
+	if err != nil {
+		respondBudgetError(c, err, "Failed to create budget")
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	budgetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget ID format"})
+		return
+	}
+
+	var req budgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+	input, err := req.input()
+	if err != nil {
+		respondBudgetError(c, err, "Failed to update budget")
+		return
+	}
+
+	budget, err := h.budgetService.UpdateBudget(c.Request.Context(), userID, budgetID, input)
+	if err != nil {
+		respondBudgetError(c, err, "Failed to update budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// ListBudgets returns the budgets, optionally of a single cost center (?cost_center_id=)
+func (h *Handler) ListBudgets(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var costCenterID *uuid.UUID
+	if value := c.Query("cost_center_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cost center ID format"})
+			return
+		}
+		costCenterID = &id
+	}
+
+	budgets, err := h.budgetService.ListBudgets(c.Request.Context(), userID, costCenterID)
+	if err != nil {
+		respondBudgetError(c, err, "Failed to list budgets")
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// GetBudget returns a budget with the committed and concluded estimated amounts of its trips
+func (h *Handler) GetBudget(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	budgetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget ID format"})
+		return
+	}
+
+	report, err := h.budgetService.GetBudget(c.Request.Context(), userID, budgetID)
+	if err != nil {
+		respondBudgetError(c, err, "Failed to retrieve budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func respondBudgetError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBudgetNotFound), errors.Is(err, service.ErrCostCenterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with budgets enforced on approvals and a fixed finance user
+func setupBudgetTestRouter(budget *domain.CostCenterBudget, committed []*domain.Trip) (*gin.Engine, *mocks.MockTripRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockExpenseRepo := new(mocks.MockExpenseReportRepository)
+
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+		service.WithCostCenterBudgets(mockBudgetRepo, mockExpenseRepo))
+	budgetService := service.NewBudgetService(mockBudgetRepo, mockTripRepo, mockExpenseRepo, new(mocks.MockCostCenterRepository), mockOrgRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithBudgetService(budgetService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budget.ID).Return(budget, nil)
+	mockBudgetRepo.On("ListByCostCenter", mock.Anything, &budget.CostCenterID).Return([]*domain.CostCenterBudget{budget}, nil)
+	mockBudgetRepo.On("Lock", mock.Anything, budget.ID).Return(nil)
+	mockTripRepo.On("List", mock.Anything, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+		return *params.Status == domain.StatusApproved
+	})).Return(committed, nil)
+	mockTripRepo.On("List", mock.Anything, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+		return *params.Status == domain.StatusConcluded
+	})).Return([]*domain.Trip{}, nil)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.GET("/budgets/:id", h.GetBudget)
+	router.PATCH("/trips/:id/status", h.UpdateTripStatus)
+
+	return router, mockTripRepo, userID
+}
+
+func TestGetBudget(t *testing.T) {
+	// Arrange
+	budget := &domain.CostCenterBudget{
+		ID:           uuid.New(),
+		CostCenterID: uuid.New(),
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       1000000,
+		Currency:     "BRL",
+		Severity:     domain.SeverityBlock,
+	}
+	trip := &domain.Trip{ID: uuid.New(), CostCenterID: budget.CostCenterID, Status: domain.StatusApproved, Destination: "Recife",
+		StartDate: time.Date(2030, 5, 2, 9, 0, 0, 0, time.UTC),
+		Budget:    &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetAirfare, Amount: 400000}}}}
+	router, _, _ := setupBudgetTestRouter(budget, []*domain.Trip{trip})
+
+	req, _ := http.NewRequest("GET", "/budgets/"+budget.ID.String(), nil)
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		ID          uuid.UUID                `json:"id"`
+		Amount      int64                    `json:"amount"`
+		Consumption domain.BudgetConsumption `json:"consumption"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, budget.ID, response.ID)
+	assert.Equal(t, int64(1000000), response.Amount)
+	assert.Equal(t, int64(400000), response.Consumption.Committed)
+	assert.Equal(t, int64(600000), response.Consumption.Remaining)
+	if assert.Len(t, response.Consumption.Trips, 1) {
+		assert.Equal(t, trip.ID, response.Consumption.Trips[0].TripID)
+	}
+}
+
+func TestUpdateTripStatus_BudgetExceeded(t *testing.T) {
+	// Arrange
+	budget := &domain.CostCenterBudget{
+		ID:           uuid.New(),
+		CostCenterID: uuid.New(),
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       100000,
+		Currency:     "BRL",
+		Severity:     domain.SeverityBlock,
+	}
+	router, mockTripRepo, _ := setupBudgetTestRouter(budget, []*domain.Trip{})
+	trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), CostCenterID: budget.CostCenterID, Status: domain.StatusRequested,
+		StartDate: time.Date(2030, 5, 2, 9, 0, 0, 0, time.UTC),
+		Budget:    &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetAirfare, Amount: 150000}}}}
+
+	// Mock behavior
+	mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+
+	body, _ := json.Marshal(map[string]string{"status": "aprovado"})
+	req, _ := http.NewRequest("PATCH", "/trips/"+trip.ID.String()+"/status", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		BudgetID  uuid.UUID    `json:"budget_id"`
+		Remaining domain.Money `json:"remaining"`
+		TripCost  domain.Money `json:"trip_cost"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, budget.ID, response.BudgetID)
+	assert.Equal(t, domain.Money{Amount: 100000, Currency: "BRL"}, response.Remaining)
+	assert.Equal(t, domain.Money{Amount: 150000, Currency: "BRL"}, response.TripCost)
+	mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTripStatus_BudgetHiddenFromNonApprovers(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockStepRepo := new(mocks.MockApprovalStepRepository)
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+		service.WithApprovalChains(new(mocks.MockApprovalPolicyRepository), mockStepRepo),
+		service.WithCostCenterBudgets(mockBudgetRepo, new(mocks.MockExpenseReportRepository)))
+	h := handler.NewHandler(userService, tripService)
+
+	employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	managerID := uuid.New()
+	orgID := uuid.New()
+	trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), CostCenterID: uuid.New(), Status: domain.StatusRequested,
+		StartDate: time.Date(2030, 5, 2, 9, 0, 0, 0, time.UTC),
+		Budget:    &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetAirfare, Amount: 5000000}}}}
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", employee.ID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.PATCH("/trips/:id/status", h.UpdateTripStatus)
+
+	// Mock behavior
+	mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+	mockUserRepo.On("FindByID", mock.Anything, employee.ID).Return(employee, nil)
+	mockStepRepo.On("FindByTripID", mock.Anything, trip.ID).Return([]*domain.ApprovalStep{
+		{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &managerID, Status: domain.StepPending},
+	}, nil)
+
+	body, _ := json.Marshal(map[string]string{"status": "aprovado"})
+	req, _ := http.NewRequest("PATCH", "/trips/"+trip.ID.String()+"/status", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "budget_id")
+	assert.NotContains(t, w.Body.String(), "remaining")
+	mockBudgetRepo.AssertNotCalled(t, "ListByCostCenter", mock.Anything, mock.Anything)
+	mockTripRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
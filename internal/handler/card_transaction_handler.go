@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type matchCardTransactionRequest struct {
+	TripID        string `json:"trip_id" binding:"required"`
+	ExpenseItemID string `json:"expense_item_id" binding:"required"`
+}
+
+type ignoreCardTransactionRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// ImportCardStatement imports an OFX or CSV statement, sent as the request
+// body, of the corporate card of the cardholder (?cardholder_id=)
+func (h *Handler) ImportCardStatement(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+	cardholderID, err := uuid.Parse(c.Query("cardholder_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cardholder ID format"})
+		return
+	}
+
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	result, err := h.cardTransactionService.ImportStatement(c.Request.Context(), userID, cardholderID, document)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to import card statement")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListCardTransactions returns the card transactions, optionally in a status
+// (?status=unmatched), of a cardholder (?cardholder_id=) or of a trip (?trip_id=)
+func (h *Handler) ListCardTransactions(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var filter domain.CardTransactionFilter
+	if value := c.Query("status"); value != "" {
+		status := domain.CardTransactionStatus(value)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: must be unmatched, matched or ignored"})
+			return
+		}
+		filter.Status = &status
+	}
+	if value := c.Query("cardholder_id"); value != "" {
+		cardholderID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cardholder ID format"})
+			return
+		}
+		filter.CardholderID = &cardholderID
+	}
+	if value := c.Query("trip_id"); value != "" {
+		tripID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+			return
+		}
+		filter.TripID = &tripID
+	}
+
+	transactions, err := h.cardTransactionService.ListTransactions(c.Request.Context(), userID, filter)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to list card transactions")
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// MatchCardTransaction matches a transaction to an expense of a trip of the cardholder
+func (h *Handler) MatchCardTransaction(c *gin.Context) {
+	userID, transactionID, ok := cardTransactionRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req matchCardTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+	tripID, err := uuid.Parse(req.TripID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return
+	}
+	itemID, err := uuid.Parse(req.ExpenseItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense item ID format"})
+		return
+	}
+
+	transaction, err := h.cardTransactionService.MatchTransaction(c.Request.Context(), userID, transactionID, tripID, itemID)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to match card transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// IgnoreCardTransaction takes an unmatched transaction out of the review with a note
+func (h *Handler) IgnoreCardTransaction(c *gin.Context) {
+	userID, transactionID, ok := cardTransactionRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req ignoreCardTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	transaction, err := h.cardTransactionService.IgnoreTransaction(c.Request.Context(), userID, transactionID, req.Note)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to ignore card transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// GetTripCardSpend compares the corporate card spend of the trip with its budget
+func (h *Handler) GetTripCardSpend(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	spend, err := h.cardTransactionService.GetTripCardSpend(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to get card spend")
+		return
+	}
+
+	c.JSON(http.StatusOK, spend)
+}
+
+// ListOverBudgetCardSpend returns the trips whose card spend went over their budget
+func (h *Handler) ListOverBudgetCardSpend(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	spends, err := h.cardTransactionService.ListOverBudgetTrips(c.Request.Context(), userID)
+	if err != nil {
+		respondCardTransactionError(c, err, "Failed to list over budget trips")
+		return
+	}
+
+	c.JSON(http.StatusOK, spends)
+}
+
+// cardTransactionRequestIDs reads the user and the card transaction of a
+// request, responding with the error when one of them is missing or invalid
+func cardTransactionRequestIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid card transaction ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, transactionID, true
+}
+
+func respondCardTransactionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCardTransactionNotFound), errors.Is(err, service.ErrTripNotFound),
+		errors.Is(err, service.ErrExpenseItemNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrExpenseItemMatched):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with mock card transaction collaborators and a fixed user
+func setupCardTransactionTestRouter() (*gin.Engine, *mocks.MockCardTransactionRepository, *mocks.MockTripRepository, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockTransactionRepo := new(mocks.MockCardTransactionRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	mockNotifier.On("Send", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
+	cardTransactionService := service.NewCardTransactionService(mockTransactionRepo, mockTripRepo, new(mocks.MockExpenseReportRepository),
+		mockUserRepo, new(mocks.MockOrganizationRepository), new(mocks.MockExchangeRateRepository), mockNotifier)
+
+	h := handler.NewHandler(userService, tripService, handler.WithCardTransactionService(cardTransactionService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.GET("/trips/:id/card-spend", h.GetTripCardSpend)
+	router.POST("/card-transactions/import", h.ImportCardStatement)
+	router.GET("/card-transactions", h.ListCardTransactions)
+	router.GET("/card-transactions/over-budget-trips", h.ListOverBudgetCardSpend)
+	router.POST("/card-transactions/:id/match", h.MatchCardTransaction)
+	router.POST("/card-transactions/:id/ignore", h.IgnoreCardTransaction)
+
+	return router, mockTransactionRepo, mockTripRepo, mockUserRepo, userID
+}
+
+func TestImportCardStatement(t *testing.T) {
+	t.Run("Invalid cardholder", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupCardTransactionTestRouter()
+		req, _ := http.NewRequest("POST", "/card-transactions/import?cardholder_id=someone", bytes.NewBufferString("date,merchant,amount,currency\n"))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid statement", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo, userID := setupCardTransactionTestRouter()
+		cardholderID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, cardholderID).Return(&domain.User{ID: cardholderID, Role: domain.RoleEmployee}, nil)
+
+		req, _ := http.NewRequest("POST", "/card-transactions/import?cardholder_id="+cardholderID.String(),
+			bytes.NewBufferString("date,merchant,amount,currency\n2024-05-03,UBER,abc,BRL\n"))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `line 2: invalid amount \"abc\"`)
+	})
+
+	t.Run("Employees can't import", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo, userID := setupCardTransactionTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleEmployee}, nil)
+
+		req, _ := http.NewRequest("POST", "/card-transactions/import?cardholder_id="+userID.String(), bytes.NewBufferString(""))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestListCardTransactions(t *testing.T) {
+	t.Run("Unmatched for review", func(t *testing.T) {
+		// Arrange
+		router, mockTransactionRepo, _, mockUserRepo, userID := setupCardTransactionTestRouter()
+		unmatched := domain.CardUnmatched
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockTransactionRepo.On("List", mock.Anything, domain.CardTransactionFilter{Status: &unmatched}).Return([]*domain.CardTransaction{
+			{ID: uuid.New(), Merchant: "RESTAURANTE FASANO", Amount: 15000, Currency: "BRL", Status: domain.CardUnmatched},
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/card-transactions?status=unmatched", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "RESTAURANTE FASANO", response[0]["merchant"])
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupCardTransactionTestRouter()
+		req, _ := http.NewRequest("GET", "/card-transactions?status=pending", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMatchCardTransaction(t *testing.T) {
+	t.Run("Missing expense item", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupCardTransactionTestRouter()
+		body := `{"trip_id": "` + uuid.New().String() + `"}`
+		req, _ := http.NewRequest("POST", "/card-transactions/"+uuid.New().String()+"/match", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Transaction not found", func(t *testing.T) {
+		// Arrange
+		router, mockTransactionRepo, _, _, _ := setupCardTransactionTestRouter()
+		transactionID := uuid.New()
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", mock.Anything, transactionID).Return(nil, nil)
+
+		body := `{"trip_id": "` + uuid.New().String() + `", "expense_item_id": "` + uuid.New().String() + `"}`
+		req, _ := http.NewRequest("POST", "/card-transactions/"+transactionID.String()+"/match", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestIgnoreCardTransaction(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockTransactionRepo, _, mockUserRepo, userID := setupCardTransactionTestRouter()
+		transaction := &domain.CardTransaction{ID: uuid.New(), Status: domain.CardUnmatched}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockTransactionRepo.On("FindByID", mock.Anything, transaction.ID).Return(transaction, nil)
+		mockTransactionRepo.On("Update", mock.Anything, transaction).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/card-transactions/"+transaction.ID.String()+"/ignore", bytes.NewBufferString(`{"note": "Personal purchase"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "ignored", response["status"])
+	})
+}
+
+func TestGetTripCardSpend(t *testing.T) {
+	t.Run("Other travelers can't see it", func(t *testing.T) {
+		// Arrange
+		router, _, mockTripRepo, mockUserRepo, userID := setupCardTransactionTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, RequesterID: uuid.New()}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleEmployee}, nil)
+
+		req, _ := http.NewRequest("GET", "/trips/"+tripID.String()+"/card-spend", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
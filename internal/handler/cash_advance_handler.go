@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type cashAdvanceRequest struct {
+	// Amount is in minor units of Currency, e.g. cents
+	Amount   int64  `json:"amount" binding:"required"`
+	Currency string `json:"currency" binding:"required"`
+	Purpose  string `json:"purpose" binding:"required"`
+}
+
+type rejectCashAdvanceRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// RequestCashAdvance asks finance for an advance for the trip
+func (h *Handler) RequestCashAdvance(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req cashAdvanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	advance, err := h.cashAdvanceService.RequestAdvance(c.Request.Context(), userID, tripID, service.CashAdvanceInput{
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Purpose:  req.Purpose,
+	})
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to request cash advance")
+		return
+	}
+
+	c.JSON(http.StatusCreated, advance)
+}
+
+func (h *Handler) ListTripCashAdvances(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	advances, err := h.cashAdvanceService.ListTripAdvances(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to list cash advances")
+		return
+	}
+
+	c.JSON(http.StatusOK, advances)
+}
+
+// ListCashAdvances returns the advances, optionally in a status (?status=requested)
+func (h *Handler) ListCashAdvances(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var status *domain.CashAdvanceStatus
+	if value := c.Query("status"); value != "" {
+		s := domain.CashAdvanceStatus(value)
+		if !s.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: must be requested, approved, rejected, paid or settled"})
+			return
+		}
+		status = &s
+	}
+
+	advances, err := h.cashAdvanceService.ListAdvances(c.Request.Context(), userID, status)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to list cash advances")
+		return
+	}
+
+	c.JSON(http.StatusOK, advances)
+}
+
+func (h *Handler) ApproveCashAdvance(c *gin.Context) {
+	userID, tripID, advanceID, ok := cashAdvanceRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	advance, err := h.cashAdvanceService.ApproveAdvance(c.Request.Context(), userID, tripID, advanceID)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to approve cash advance")
+		return
+	}
+
+	c.JSON(http.StatusOK, advance)
+}
+
+func (h *Handler) RejectCashAdvance(c *gin.Context) {
+	userID, tripID, advanceID, ok := cashAdvanceRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req rejectCashAdvanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	advance, err := h.cashAdvanceService.RejectAdvance(c.Request.Context(), userID, tripID, advanceID, req.Reason)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to reject cash advance")
+		return
+	}
+
+	c.JSON(http.StatusOK, advance)
+}
+
+func (h *Handler) PayCashAdvance(c *gin.Context) {
+	userID, tripID, advanceID, ok := cashAdvanceRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	advance, err := h.cashAdvanceService.PayAdvance(c.Request.Context(), userID, tripID, advanceID)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to pay cash advance")
+		return
+	}
+
+	c.JSON(http.StatusOK, advance)
+}
+
+// GetCashAdvanceReconciliation balances the advances of the trip against its expenses
+func (h *Handler) GetCashAdvanceReconciliation(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.cashAdvanceService.GetReconciliation(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to reconcile cash advances")
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+func (h *Handler) SettleCashAdvances(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.cashAdvanceService.SettleAdvances(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondCashAdvanceError(c, err, "Failed to settle cash advances")
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// cashAdvanceRequestIDs reads the user, the trip and the advance of a request,
+// responding with the error when one of them is missing or invalid
+func cashAdvanceRequestIDs(c *gin.Context) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	advanceID, err := uuid.Parse(c.Param("advance_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cash advance ID format"})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return userID, tripID, advanceID, true
+}
+
+func respondCashAdvanceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTripNotFound), errors.Is(err, service.ErrCashAdvanceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
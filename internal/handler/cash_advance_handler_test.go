@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with mock cash advance collaborators and a fixed user
+func setupCashAdvanceTestRouter() (*gin.Engine, *mocks.MockCashAdvanceRepository, *mocks.MockTripRepository, *mocks.MockUserRepository, uuid.UUID, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockAdvanceRepo := new(mocks.MockCashAdvanceRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	mockNotifier.On("Send", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockTransactor := new(mocks.MockTransactor)
+	mockTransactor.On("WithinTx", mock.Anything).Return(nil)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
+	cashAdvanceService := service.NewCashAdvanceService(mockAdvanceRepo, mockTripRepo, new(mocks.MockExpenseReportRepository),
+		mockUserRepo, new(mocks.MockOrganizationRepository), new(mocks.MockExchangeRateRepository), mockNotifier, mockTransactor)
+
+	h := handler.NewHandler(userService, tripService, handler.WithCashAdvanceService(cashAdvanceService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/trips/:id/advances", h.RequestCashAdvance)
+	router.GET("/trips/:id/advances", h.ListTripCashAdvances)
+	router.GET("/trips/:id/advances/reconciliation", h.GetCashAdvanceReconciliation)
+	router.POST("/trips/:id/advances/settle", h.SettleCashAdvances)
+	router.POST("/trips/:id/advances/:advance_id/approve", h.ApproveCashAdvance)
+	router.POST("/trips/:id/advances/:advance_id/reject", h.RejectCashAdvance)
+	router.POST("/trips/:id/advances/:advance_id/pay", h.PayCashAdvance)
+	router.GET("/advances", h.ListCashAdvances)
+
+	return router, mockAdvanceRepo, mockTripRepo, mockUserRepo, userID, orgID
+}
+
+func TestRequestCashAdvance(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockAdvanceRepo, mockTripRepo, mockUserRepo, userID, orgID := setupCashAdvanceTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{
+			ID: tripID, OrgID: orgID, RequesterID: userID, Destination: "Nova York",
+			EndDate: time.Now().AddDate(0, 0, 10), Status: domain.StatusApproved,
+		}, nil)
+		mockAdvanceRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.CashAdvance")).Return(nil)
+		mockUserRepo.On("ListByRole", mock.Anything, domain.RoleFinance).Return([]*domain.User{}, nil)
+
+		body := `{"amount": 50000, "currency": "USD", "purpose": "Taxis and meals"}`
+		req, _ := http.NewRequest("POST", "/trips/"+tripID.String()+"/advances", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "requested", response["status"])
+		assert.Equal(t, "USD", response["currency"])
+	})
+
+	t.Run("Missing purpose", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _, _ := setupCashAdvanceTestRouter()
+		body := `{"amount": 50000, "currency": "USD"}`
+		req, _ := http.NewRequest("POST", "/trips/"+uuid.New().String()+"/advances", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestApproveCashAdvance(t *testing.T) {
+	t.Run("Employees can't approve", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo, userID, _ := setupCashAdvanceTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleEmployee}, nil)
+
+		req, _ := http.NewRequest("POST", "/trips/"+uuid.New().String()+"/advances/"+uuid.New().String()+"/approve", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown advance", func(t *testing.T) {
+		// Arrange
+		router, mockAdvanceRepo, mockTripRepo, mockUserRepo, userID, orgID := setupCashAdvanceTestRouter()
+		tripID := uuid.New()
+		advanceID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{ID: tripID, OrgID: orgID, RequesterID: uuid.New()}, nil)
+		mockAdvanceRepo.On("FindByID", mock.Anything, advanceID).Return(nil, nil)
+
+		req, _ := http.NewRequest("POST", "/trips/"+tripID.String()+"/advances/"+advanceID.String()+"/approve", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestListCashAdvances(t *testing.T) {
+	t.Run("Invalid status", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _, _ := setupCashAdvanceTestRouter()
+		req, _ := http.NewRequest("GET", "/advances?status=pending", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Requested advances", func(t *testing.T) {
+		// Arrange
+		router, mockAdvanceRepo, _, mockUserRepo, userID, _ := setupCashAdvanceTestRouter()
+		status := domain.AdvanceRequested
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleFinance}, nil)
+		mockAdvanceRepo.On("List", mock.Anything, &status).Return(nil, nil)
+
+		req, _ := http.NewRequest("GET", "/advances?status=requested", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+}
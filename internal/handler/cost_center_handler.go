@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type costCenterRequest struct {
+	Code   string `json:"code" binding:"required"`
+	Name   string `json:"name" binding:"required"`
+	Active *bool  `json:"active"`
+}
+
+func (h *Handler) CreateCostCenter(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req costCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	costCenter, err := h.costCenterService.CreateCostCenter(c.Request.Context(), adminID, req.Code, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create cost center"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, costCenter)
+}
+
+func (h *Handler) UpdateCostCenter(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	costCenterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cost center ID format"})
+		return
+	}
+
+	var req costCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	costCenter, err := h.costCenterService.UpdateCostCenter(c.Request.Context(), adminID, costCenterID, req.Code, req.Name, active)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors.Is(err, service.ErrCostCenterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cost center"})
+		return
+	}
+
+	c.JSON(http.StatusOK, costCenter)
+}
+
+func (h *Handler) ListCostCenters(c *gin.Context) {
+	costCenters, err := h.costCenterService.ListCostCenters(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list cost centers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, costCenters)
+}
+
+type departmentRequest struct {
+	Name         string    `json:"name" binding:"required"`
+	CostCenterID uuid.UUID `json:"cost_center_id" binding:"required"`
+}
+
+func (h *Handler) CreateDepartment(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req departmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	department, err := h.costCenterService.CreateDepartment(c.Request.Context(), adminID, req.Name, req.CostCenterID)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create department"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, department)
+}
+
+func (h *Handler) UpdateDepartment(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	departmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department ID format"})
+		return
+	}
+
+	var req departmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	department, err := h.costCenterService.UpdateDepartment(c.Request.Context(), adminID, departmentID, req.Name, req.CostCenterID)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors.Is(err, service.ErrDepartmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update department"})
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+func (h *Handler) ListDepartments(c *gin.Context) {
+	departments, err := h.costCenterService.ListDepartments(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list departments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, departments)
+}
+
+type assignDepartmentRequest struct {
+	// DepartmentID removes the user from any department when null
+	DepartmentID *uuid.UUID `json:"department_id"`
+}
+
+func (h *Handler) AssignUserDepartment(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	var req assignDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	err = h.costCenterService.AssignUserDepartment(c.Request.Context(), adminID, userID, req.DepartmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrDepartmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign department"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Department assigned successfully"})
+}
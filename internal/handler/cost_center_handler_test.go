@@ -0,0 +1,249 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with mock cost center and department repositories and an admin user
+func setupCostCenterTestRouter() (*gin.Engine, *mocks.MockCostCenterRepository, *mocks.MockDepartmentRepository, *mocks.MockUserRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+	mockDepartmentRepo := new(mocks.MockDepartmentRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	mockUserRepo.On("FindByID", mock.Anything, admin.ID).Return(admin, nil)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	costCenterService := service.NewCostCenterService(mockCostCenterRepo, mockDepartmentRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithCostCenterService(costCenterService))
+
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", admin.ID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/cost-centers", h.CreateCostCenter)
+	router.PUT("/cost-centers/:id", h.UpdateCostCenter)
+	router.POST("/departments", h.CreateDepartment)
+	router.PUT("/users/:id/department", h.AssignUserDepartment)
+
+	return router, mockCostCenterRepo, mockDepartmentRepo, mockUserRepo
+}
+
+func TestCreateCostCenter(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockCostCenterRepo, _, _ := setupCostCenterTestRouter()
+
+		// Mock behavior
+		mockCostCenterRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.CostCenter")).Return(nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"code": "CC-100", "name": "Sales"})
+		req, _ := http.NewRequest("POST", "/cost-centers", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.CostCenter
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "CC-100", response.Code)
+		assert.True(t, response.Active)
+		mockCostCenterRepo.AssertExpectations(t)
+	})
+
+	t.Run("Missing code", func(t *testing.T) {
+		// Arrange
+		router, mockCostCenterRepo, _, _ := setupCostCenterTestRouter()
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"name": "Sales"})
+		req, _ := http.NewRequest("POST", "/cost-centers", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "code is required")
+		mockCostCenterRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestUpdateCostCenter(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		// Arrange
+		router, mockCostCenterRepo, _, _ := setupCostCenterTestRouter()
+		id := uuid.New()
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", mock.Anything, id).Return(nil, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"code": "CC-100", "name": "Sales", "active": false})
+		req, _ := http.NewRequest("PUT", "/cost-centers/"+id.String(), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCreateDepartment(t *testing.T) {
+	t.Run("Unknown cost center", func(t *testing.T) {
+		// Arrange
+		router, mockCostCenterRepo, mockDepartmentRepo, _ := setupCostCenterTestRouter()
+		costCenterID := uuid.New()
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", mock.Anything, costCenterID).Return(nil, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"name": "Engineering", "cost_center_id": costCenterID})
+		req, _ := http.NewRequest("POST", "/departments", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "cost_center_id must reference an active cost center")
+		mockDepartmentRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestAssignUserDepartment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, _, mockDepartmentRepo, mockUserRepo := setupCostCenterTestRouter()
+		user := &domain.User{ID: uuid.New()}
+		department := &domain.Department{ID: uuid.New()}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		mockDepartmentRepo.On("FindByID", mock.Anything, department.ID).Return(department, nil)
+		mockUserRepo.On("UpdateDepartment", mock.Anything, user.ID, &department.ID).Return(nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"department_id": department.ID})
+		req, _ := http.NewRequest("PUT", "/users/"+user.ID.String()+"/department", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		// Arrange
+		router, _, _, mockUserRepo := setupCostCenterTestRouter()
+		userID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"department_id": nil})
+		req, _ := http.NewRequest("PUT", "/users/"+userID.String()+"/department", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCostCenterEndpointsRequireAdmin(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+	employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	costCenterService := service.NewCostCenterService(mockCostCenterRepo, new(mocks.MockDepartmentRepository), mockUserRepo)
+	h := handler.NewHandler(userService, tripService, handler.WithCostCenterService(costCenterService))
+
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", employee.ID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/cost-centers", h.CreateCostCenter)
+	router.PUT("/users/:id/department", h.AssignUserDepartment)
+
+	// Mock behavior
+	mockUserRepo.On("FindByID", mock.Anything, employee.ID).Return(employee, nil)
+
+	t.Run("Create cost center", func(t *testing.T) {
+		jsonBody, _ := json.Marshal(map[string]interface{}{"code": "CC-100", "name": "Sales"})
+		req, _ := http.NewRequest("POST", "/cost-centers", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockCostCenterRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Move themselves to another department", func(t *testing.T) {
+		jsonBody, _ := json.Marshal(map[string]interface{}{"department_id": uuid.New()})
+		req, _ := http.NewRequest("PUT", "/users/"+employee.ID.String()+"/department", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockUserRepo.AssertNotCalled(t, "UpdateDepartment", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
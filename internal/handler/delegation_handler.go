@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type delegationRequest struct {
+	// DelegatorID defaults to the authenticated user
+	DelegatorID  *uuid.UUID `json:"delegator_id"`
+	DelegateID   uuid.UUID  `json:"delegate_id" binding:"required"`
+	StartsAt     time.Time  `json:"starts_at" binding:"required"`
+	EndsAt       time.Time  `json:"ends_at" binding:"required"`
+	CostCenterID *uuid.UUID `json:"cost_center_id"`
+}
+
+func (h *Handler) CreateDelegation(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req delegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	delegation, err := h.delegationService.CreateDelegation(c.Request.Context(), userID, service.DelegationInput{
+		DelegatorID:  req.DelegatorID,
+		DelegateID:   req.DelegateID,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		CostCenterID: req.CostCenterID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			// Check if it's a validation error
+			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create delegation"})
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, delegation)
+}
+
+func (h *Handler) ListDelegations(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	delegations, err := h.delegationService.ListDelegations(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list delegations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, delegations)
+}
+
+func (h *Handler) DeleteDelegation(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	delegationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delegation ID format"})
+		return
+	}
+
+	if err := h.delegationService.DeleteDelegation(c.Request.Context(), userID, delegationID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrDelegationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete delegation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Delegation deleted successfully"})
+}
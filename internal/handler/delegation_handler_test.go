@@ -0,0 +1,159 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock delegation repository and a fixed userID
+func setupDelegationTestRouter() (*gin.Engine, *mocks.MockDelegationRepository, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockDelegationRepo := new(mocks.MockDelegationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	delegationService := service.NewDelegationService(mockDelegationRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithDelegationService(delegationService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/delegations", h.CreateDelegation)
+	router.GET("/delegations", h.ListDelegations)
+	router.DELETE("/delegations/:id", h.DeleteDelegation)
+
+	return router, mockDelegationRepo, mockUserRepo, userID
+}
+
+func TestCreateDelegation(t *testing.T) {
+	startsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	endsAt := startsAt.Add(7 * 24 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockDelegationRepo, mockUserRepo, userID := setupDelegationTestRouter()
+		delegateID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, delegateID).Return(&domain.User{ID: delegateID, Role: domain.RoleEmployee}, nil)
+		mockDelegationRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Delegation")).Return(nil)
+
+		// Create request
+		body, _ := json.Marshal(map[string]interface{}{
+			"delegate_id": delegateID,
+			"starts_at":   startsAt,
+			"ends_at":     endsAt,
+		})
+		req, _ := http.NewRequest("POST", "/delegations", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.Delegation
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, response.DelegatorID)
+		assert.Equal(t, delegateID, response.DelegateID)
+		mockDelegationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delegating to yourself", func(t *testing.T) {
+		// Arrange
+		router, mockDelegationRepo, _, userID := setupDelegationTestRouter()
+
+		// Create request
+		body, _ := json.Marshal(map[string]interface{}{
+			"delegate_id": userID,
+			"starts_at":   startsAt,
+			"ends_at":     endsAt,
+		})
+		req, _ := http.NewRequest("POST", "/delegations", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDelegationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown delegate", func(t *testing.T) {
+		// Arrange
+		router, mockDelegationRepo, mockUserRepo, userID := setupDelegationTestRouter()
+		delegateID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, delegateID).Return(nil, nil)
+
+		// Create request
+		body, _ := json.Marshal(map[string]interface{}{
+			"delegate_id": delegateID,
+			"starts_at":   startsAt,
+			"ends_at":     endsAt,
+		})
+		req, _ := http.NewRequest("POST", "/delegations", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDelegationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteDelegation(t *testing.T) {
+	t.Run("Someone else's delegation", func(t *testing.T) {
+		// Arrange
+		router, mockDelegationRepo, mockUserRepo, userID := setupDelegationTestRouter()
+		delegation := &domain.Delegation{ID: uuid.New(), DelegatorID: uuid.New(), DelegateID: uuid.New()}
+
+		// Mock behavior
+		mockDelegationRepo.On("FindByID", mock.Anything, delegation.ID).Return(delegation, nil)
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleEmployee}, nil)
+
+		// Create request
+		req, _ := http.NewRequest("DELETE", "/delegations/"+delegation.ID.String(), nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockDelegationRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
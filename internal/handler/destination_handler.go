@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchDestinations autocompletes destinations of the catalog: GET /destinations?q=lisb&limit=5
+func (h *Handler) SearchDestinations(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	destinations, err := h.destinationService.SearchDestinations(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search destinations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, destinations)
+}
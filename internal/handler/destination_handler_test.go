@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock destination repository
+func setupDestinationTestRouter() (*gin.Engine, *mocks.MockDestinationRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockDestinationRepo := new(mocks.MockDestinationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	destinationService := service.NewDestinationService(mockDestinationRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithDestinationService(destinationService))
+	router.GET("/destinations", h.SearchDestinations)
+
+	return router, mockDestinationRepo
+}
+
+func TestSearchDestinations(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockDestinationRepo := setupDestinationTestRouter()
+		lisbon := &domain.Destination{Code: "LIS", Kind: domain.DestinationCity, Name: "Lisboa", CountryCode: "PT", TimeZone: "Europe/Lisbon"}
+
+		// Mock behavior
+		mockDestinationRepo.On("Search", mock.Anything, "lisb", 5).Return([]*domain.Destination{lisbon}, nil)
+
+		req, _ := http.NewRequest("GET", "/destinations?q=lisb&limit=5", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []domain.Destination
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "LIS", response[0].Code)
+		mockDestinationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Default limit", func(t *testing.T) {
+		// Arrange
+		router, mockDestinationRepo := setupDestinationTestRouter()
+
+		// Mock behavior
+		mockDestinationRepo.On("Search", mock.Anything, "paris", 10).Return([]*domain.Destination{}, nil)
+
+		req, _ := http.NewRequest("GET", "/destinations?q=paris", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockDestinationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		// Arrange
+		router, mockDestinationRepo := setupDestinationTestRouter()
+
+		req, _ := http.NewRequest("GET", "/destinations?q=paris&limit=many", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDestinationRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
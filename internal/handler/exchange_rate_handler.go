@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type exchangeRateRequest struct {
+	Currency string  `json:"currency" binding:"required"`
+	Rate     float64 `json:"rate" binding:"required"`
+	// EffectiveDate is YYYY-MM-DD
+	EffectiveDate string `json:"effective_date" binding:"required"`
+}
+
+func (h *Handler) CreateExchangeRate(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req exchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []string{"effective_date must be YYYY-MM-DD"}})
+		return
+	}
+
+	rate, err := h.exchangeRateService.CreateRate(c.Request.Context(), userID, service.ExchangeRateInput{
+		Currency:      strings.ToUpper(req.Currency),
+		Rate:          req.Rate,
+		EffectiveDate: effectiveDate,
+	})
+	if err != nil {
+		respondExchangeRateError(c, err, "Failed to save exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// ImportExchangeRates saves the rates of a CSV file sent as the request body
+func (h *Handler) ImportExchangeRates(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	rates, err := h.exchangeRateService.ImportRates(c.Request.Context(), userID, document)
+	if err != nil {
+		respondExchangeRateError(c, err, "Failed to import exchange rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *Handler) ListExchangeRates(c *gin.Context) {
+	rates, err := h.exchangeRateService.ListRates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list exchange rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+func respondExchangeRateError(c *gin.Context, err error, message string) {
+	if errors.Is(err, service.ErrPermissionDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if it's a validation error
+	if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
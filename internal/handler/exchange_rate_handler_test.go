@@ -0,0 +1,139 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock exchange rate repository and a fixed admin
+func setupExchangeRateTestRouter() (*gin.Engine, *mocks.MockExchangeRateRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	exchangeRateService := service.NewExchangeRateService(mockRateRepo, mockOrgRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithExchangeRateService(exchangeRateService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+	mockOrgRepo.On("FindByID", mock.Anything, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/exchange-rates", h.CreateExchangeRate)
+	router.POST("/exchange-rates/import", h.ImportExchangeRates)
+
+	return router, mockRateRepo, userID
+}
+
+func TestCreateExchangeRate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo, _ := setupExchangeRateTestRouter()
+
+		// Mock behavior
+		mockRateRepo.On("Save", mock.Anything, mock.AnythingOfType("[]*domain.ExchangeRate")).Return(nil)
+
+		body := `{"currency": "usd", "rate": 5.4321, "effective_date": "2030-05-01"}`
+		req, _ := http.NewRequest("POST", "/exchange-rates", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.ExchangeRate
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", response.Currency)
+		assert.Equal(t, "BRL", response.BaseCurrency)
+		mockRateRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo, _ := setupExchangeRateTestRouter()
+
+		body := `{"currency": "USD", "rate": 5.4321, "effective_date": "01/05/2030"}`
+		req, _ := http.NewRequest("POST", "/exchange-rates", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "effective_date must be YYYY-MM-DD")
+		mockRateRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestImportExchangeRates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo, _ := setupExchangeRateTestRouter()
+
+		// Mock behavior
+		mockRateRepo.On("Save", mock.Anything, mock.AnythingOfType("[]*domain.ExchangeRate")).Return(nil)
+
+		body := "currency,rate,effective_date\nUSD,5.4321,2030-05-01\nEUR,5.8765,2030-05-01\n"
+		req, _ := http.NewRequest("POST", "/exchange-rates/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []domain.ExchangeRate
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo, _ := setupExchangeRateTestRouter()
+
+		body := "currency,rate,effective_date\nUSD,abc,2030-05-01\n"
+		req, _ := http.NewRequest("POST", "/exchange-rates/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `line 2: invalid rate \"abc\"`)
+		mockRateRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type expenseItemRequest struct {
+	// Date is YYYY-MM-DD
+	Date     string                `json:"date" binding:"required"`
+	Category domain.BudgetCategory `json:"category" binding:"required"`
+	// Amount is in minor units of Currency, e.g. cents
+	Amount      int64  `json:"amount" binding:"required"`
+	Currency    string `json:"currency" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	ReceiptRef  string `json:"receipt_ref"`
+}
+
+// input parses the date of the request
+func (r expenseItemRequest) input() (service.ExpenseItemInput, error) {
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("date must be YYYY-MM-DD")
+		return service.ExpenseItemInput{}, validationErrors
+	}
+
+	return service.ExpenseItemInput{
+		Date:        date,
+		Category:    r.Category,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Description: r.Description,
+		ReceiptRef:  r.ReceiptRef,
+	}, nil
+}
+
+type rejectExpenseReportRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GetExpenseReport returns the expense report of a trip with its comparison to the trip budget
+func (h *Handler) GetExpenseReport(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.GetReport(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to retrieve expense report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListExpenseReports returns the expense reports, optionally in a status (?status=submitted)
+func (h *Handler) ListExpenseReports(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var status *domain.ExpenseReportStatus
+	if value := c.Query("status"); value != "" {
+		s := domain.ExpenseReportStatus(value)
+		if !s.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: must be draft, submitted, approved, rejected or reimbursed"})
+			return
+		}
+		status = &s
+	}
+
+	reports, err := h.expenseService.ListReports(c.Request.Context(), userID, status)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to list expense reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+func (h *Handler) AddExpenseItem(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindExpenseItem(c, "Failed to add expense")
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.AddItem(c.Request.Context(), userID, tripID, input)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to add expense")
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+func (h *Handler) UpdateExpenseItem(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense item ID format"})
+		return
+	}
+
+	input, ok := bindExpenseItem(c, "Failed to update expense")
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.UpdateItem(c.Request.Context(), userID, tripID, itemID, input)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to update expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) DeleteExpenseItem(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense item ID format"})
+		return
+	}
+
+	report, err := h.expenseService.DeleteItem(c.Request.Context(), userID, tripID, itemID)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to delete expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) SubmitExpenseReport(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.SubmitReport(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to submit expense report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) ApproveExpenseReport(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.ApproveReport(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to approve expense report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) RejectExpenseReport(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req rejectExpenseReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	report, err := h.expenseService.RejectReport(c.Request.Context(), userID, tripID, req.Reason)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to reject expense report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) ReimburseExpenseReport(c *gin.Context) {
+	userID, tripID, ok := tripRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.ReimburseReport(c.Request.Context(), userID, tripID)
+	if err != nil {
+		respondExpenseError(c, err, "Failed to reimburse expense report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// tripRequestIDs reads the user and the trip of a request on a trip resource,
+// responding with the error when one of them is missing or invalid
+func tripRequestIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, tripID, true
+}
+
+func bindExpenseItem(c *gin.Context, message string) (service.ExpenseItemInput, bool) {
+	var req expenseItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return service.ExpenseItemInput{}, false
+	}
+	input, err := req.input()
+	if err != nil {
+		respondExpenseError(c, err, message)
+		return service.ExpenseItemInput{}, false
+	}
+	return input, true
+}
+
+func respondExpenseError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTripNotFound), errors.Is(err, service.ErrExpenseReportNotFound),
+		errors.Is(err, service.ErrExpenseItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with mock expense collaborators and a fixed traveler
+func setupExpenseTestRouter() (*gin.Engine, *mocks.MockExpenseReportRepository, *mocks.MockTripRepository, uuid.UUID, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockReportRepo := new(mocks.MockExpenseReportRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
+	tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService))
+	expenseService := service.NewExpenseService(mockReportRepo, mockTripRepo, new(mocks.MockCashAdvanceRepository), mockUserRepo, mockOrgRepo,
+		new(mocks.MockExchangeRateRepository), new(mocks.MockNotificationService))
+
+	h := handler.NewHandler(userService, tripService, handler.WithExpenseService(expenseService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	mockOrgRepo.On("FindByID", mock.Anything, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/trips/:id/expenses/items", h.AddExpenseItem)
+	router.GET("/expense-reports", h.ListExpenseReports)
+
+	return router, mockReportRepo, mockTripRepo, userID, orgID
+}
+
+func TestAddExpenseItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockReportRepo, mockTripRepo, userID, orgID := setupExpenseTestRouter()
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{
+			ID:          tripID,
+			OrgID:       orgID,
+			RequesterID: userID,
+			StartDate:   time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(2030, 5, 14, 18, 0, 0, 0, time.UTC),
+			Status:      domain.StatusApproved,
+		}, nil)
+		mockReportRepo.On("FindByTripID", mock.Anything, tripID).Return(nil, nil)
+		mockReportRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ExpenseReport")).Return(nil)
+
+		body := `{"date": "2030-05-11", "category": "ground_transport", "amount": 8500, "currency": "BRL", "description": "Taxi", "receipt_ref": "NF-77"}`
+		req, _ := http.NewRequest("POST", "/trips/"+tripID.String()+"/expenses/items", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.ExpenseReportDetails
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseDraft, response.Status)
+		assert.Len(t, response.Items, 1)
+		assert.Equal(t, "NF-77", response.Items[0].ReceiptRef)
+		assert.Equal(t, int64(8500), response.Comparison.Total)
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		// Arrange
+		router, _, _, _, _ := setupExpenseTestRouter()
+
+		body := `{"date": "11/05/2030", "category": "ground_transport", "amount": 8500, "currency": "BRL", "description": "Taxi"}`
+		req, _ := http.NewRequest("POST", "/trips/"+uuid.New().String()+"/expenses/items", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "date must be YYYY-MM-DD")
+	})
+}
+
+func TestListExpenseReports_InvalidStatus(t *testing.T) {
+	// Arrange
+	router, _, _, _, _ := setupExpenseTestRouter()
+	req, _ := http.NewRequest("GET", "/expense-reports?status=paid", nil)
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -11,17 +11,136 @@ import (
 
 // Handler holds all services that the handlers will need.
 type Handler struct {
-	userService *service.UserService
-	tripService *service.TripService
-	validate    *validator.Validate
+	userService             *service.UserService
+	tripService             *service.TripService
+	costCenterService       *service.CostCenterService
+	approvalService         *service.ApprovalService
+	travelPolicyService     *service.TravelPolicyService
+	delegationService       *service.DelegationService
+	blackoutCalendarService *service.BlackoutCalendarService
+	destinationService      *service.DestinationService
+	exchangeRateService     *service.ExchangeRateService
+	budgetService           *service.BudgetService
+	perDiemRateService      *service.PerDiemRateService
+	expenseService          *service.ExpenseService
+	attachmentService       *service.AttachmentService
+	cashAdvanceService      *service.CashAdvanceService
+	accountingExportService *service.AccountingExportService
+	cardTransactionService  *service.CardTransactionService
+	validate                *validator.Validate
 }
 
-func NewHandler(userSvc *service.UserService, tripSvc *service.TripService) *Handler {
-	return &Handler{
+// HandlerOption registers the services of optional modules
+type HandlerOption func(*Handler)
+
+// WithCostCenterService enables the cost center and department handlers
+func WithCostCenterService(svc *service.CostCenterService) HandlerOption {
+	return func(h *Handler) {
+		h.costCenterService = svc
+	}
+}
+
+// WithApprovalService enables the approval policy and approval queue handlers
+func WithApprovalService(svc *service.ApprovalService) HandlerOption {
+	return func(h *Handler) {
+		h.approvalService = svc
+	}
+}
+
+// WithTravelPolicyService enables the travel policy handlers
+func WithTravelPolicyService(svc *service.TravelPolicyService) HandlerOption {
+	return func(h *Handler) {
+		h.travelPolicyService = svc
+	}
+}
+
+// WithDelegationService enables the approval delegation handlers
+func WithDelegationService(svc *service.DelegationService) HandlerOption {
+	return func(h *Handler) {
+		h.delegationService = svc
+	}
+}
+
+// WithBlackoutCalendarService enables the blackout calendar handlers
+func WithBlackoutCalendarService(svc *service.BlackoutCalendarService) HandlerOption {
+	return func(h *Handler) {
+		h.blackoutCalendarService = svc
+	}
+}
+
+// WithDestinationService enables the destination catalog handlers
+func WithDestinationService(svc *service.DestinationService) HandlerOption {
+	return func(h *Handler) {
+		h.destinationService = svc
+	}
+}
+
+// WithExchangeRateService enables the exchange rate handlers
+func WithExchangeRateService(svc *service.ExchangeRateService) HandlerOption {
+	return func(h *Handler) {
+		h.exchangeRateService = svc
+	}
+}
+
+// WithBudgetService enables the cost center budget handlers
+func WithBudgetService(svc *service.BudgetService) HandlerOption {
+	return func(h *Handler) {
+		h.budgetService = svc
+	}
+}
+
+// WithPerDiemRateService enables the per diem rate handlers
+func WithPerDiemRateService(svc *service.PerDiemRateService) HandlerOption {
+	return func(h *Handler) {
+		h.perDiemRateService = svc
+	}
+}
+
+// WithExpenseService enables the expense report handlers
+func WithExpenseService(svc *service.ExpenseService) HandlerOption {
+	return func(h *Handler) {
+		h.expenseService = svc
+	}
+}
+
+// WithAttachmentService enables the attachment handlers
+func WithAttachmentService(svc *service.AttachmentService) HandlerOption {
+	return func(h *Handler) {
+		h.attachmentService = svc
+	}
+}
+
+// WithCashAdvanceService enables the cash advance handlers
+func WithCashAdvanceService(svc *service.CashAdvanceService) HandlerOption {
+	return func(h *Handler) {
+		h.cashAdvanceService = svc
+	}
+}
+
+// WithAccountingExportService enables the accounting export and GL account handlers
+func WithAccountingExportService(svc *service.AccountingExportService) HandlerOption {
+	return func(h *Handler) {
+		h.accountingExportService = svc
+	}
+}
+
+// WithCardTransactionService enables the corporate card handlers
+func WithCardTransactionService(svc *service.CardTransactionService) HandlerOption {
+	return func(h *Handler) {
+		h.cardTransactionService = svc
+	}
+}
+
+func NewHandler(userSvc *service.UserService, tripSvc *service.TripService, opts ...HandlerOption) *Handler {
+	h := &Handler{
 		userService: userSvc,
 		tripService: tripSvc,
 		validate:    validator.New(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Helper to get userID from context
@@ -45,7 +164,7 @@ func parseValidationErrors(err error) *domain.ValidationErrors {
 
 			// Map validation tags to user-friendly error messages
 			switch fieldErr.Tag() {
-			case "required":
+			case "required", "required_without", "required_without_all":
 				validationErrors.Add(fieldName + " is required")
 			case "email":
 				validationErrors.Add("invalid email format")
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+)
+
+type perDiemRateRequest struct {
+	CountryCode string `json:"country_code" binding:"required"`
+	// CityCode limits the rate to a city of the destination catalog
+	CityCode    string `json:"city_code"`
+	Currency    string `json:"currency" binding:"required"`
+	DailyAmount int64  `json:"daily_amount" binding:"required"`
+	// PartialDayPercent defaults to 100, paying the first and last days in full
+	PartialDayPercent *int `json:"partial_day_percent"`
+	BreakfastPercent  int  `json:"breakfast_percent"`
+	LunchPercent      int  `json:"lunch_percent"`
+	DinnerPercent     int  `json:"dinner_percent"`
+}
+
+func (h *Handler) SavePerDiemRate(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req perDiemRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+	partialDayPercent := 100
+	if req.PartialDayPercent != nil {
+		partialDayPercent = *req.PartialDayPercent
+	}
+
+	rate, err := h.perDiemRateService.SaveRate(c.Request.Context(), userID, &domain.PerDiemRate{
+		CountryCode:       strings.ToUpper(req.CountryCode),
+		CityCode:          strings.ToUpper(req.CityCode),
+		Currency:          strings.ToUpper(req.Currency),
+		DailyAmount:       req.DailyAmount,
+		PartialDayPercent: partialDayPercent,
+		BreakfastPercent:  req.BreakfastPercent,
+		LunchPercent:      req.LunchPercent,
+		DinnerPercent:     req.DinnerPercent,
+	})
+	if err != nil {
+		respondPerDiemRateError(c, err, "Failed to save per diem rate")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// ImportPerDiemRates saves the rates of a CSV file sent as the request body
+func (h *Handler) ImportPerDiemRates(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	rates, err := h.perDiemRateService.ImportRates(c.Request.Context(), userID, document)
+	if err != nil {
+		respondPerDiemRateError(c, err, "Failed to import per diem rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *Handler) ListPerDiemRates(c *gin.Context) {
+	rates, err := h.perDiemRateService.ListRates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list per diem rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *Handler) DeletePerDiemRate(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	rateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid per diem rate ID format"})
+		return
+	}
+
+	if err := h.perDiemRateService.DeleteRate(c.Request.Context(), userID, rateID); err != nil {
+		respondPerDiemRateError(c, err, "Failed to delete per diem rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Per diem rate deleted successfully"})
+}
+
+func respondPerDiemRateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPerDiemRateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Check if it's a validation error
+		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock per diem rate repository and a fixed admin
+func setupPerDiemRateTestRouter() (*gin.Engine, *mocks.MockPerDiemRateRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockRateRepo := new(mocks.MockPerDiemRateRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	perDiemRateService := service.NewPerDiemRateService(mockRateRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithPerDiemRateService(perDiemRateService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.POST("/per-diem-rates", h.SavePerDiemRate)
+	router.POST("/per-diem-rates/import", h.ImportPerDiemRates)
+
+	return router, mockRateRepo
+}
+
+func TestSavePerDiemRate(t *testing.T) {
+	// Arrange
+	router, mockRateRepo := setupPerDiemRateTestRouter()
+
+	// Mock behavior
+	mockRateRepo.On("Save", mock.Anything, mock.AnythingOfType("[]*domain.PerDiemRate")).Return(nil)
+
+	body := `{"country_code": "pt", "city_code": "lis", "currency": "EUR", "daily_amount": 10000, "breakfast_percent": 20}`
+	req, _ := http.NewRequest("POST", "/per-diem-rates", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.PerDiemRate
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "PT", response.CountryCode)
+	assert.Equal(t, "LIS", response.CityCode)
+	assert.Equal(t, 100, response.PartialDayPercent)
+	assert.Equal(t, 20, response.BreakfastPercent)
+}
+
+func TestImportPerDiemRates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo := setupPerDiemRateTestRouter()
+
+		// Mock behavior
+		mockRateRepo.On("Save", mock.Anything, mock.AnythingOfType("[]*domain.PerDiemRate")).Return(nil)
+
+		body := "country_code,city_code,currency,daily_amount,partial_day_percent\nPT,LIS,EUR,10000,75\nPT,,EUR,8000,75\n"
+		req, _ := http.NewRequest("POST", "/per-diem-rates/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []domain.PerDiemRate
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+	})
+
+	t.Run("Invalid rate", func(t *testing.T) {
+		// Arrange
+		router, mockRateRepo := setupPerDiemRateTestRouter()
+
+		body := "country_code,city_code,currency,daily_amount\nPT,LIS,EUR,0\n"
+		req, _ := http.NewRequest("POST", "/per-diem-rates/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "line 2: daily_amount must be positive")
+		mockRateRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
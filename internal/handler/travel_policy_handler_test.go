@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/handler"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup test router with a mock travel policy repository and a fixed userID
+func setupTravelPolicyTestRouter() (*gin.Engine, *mocks.MockTravelPolicyRepository, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockPolicyRepo := new(mocks.MockTravelPolicyRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, new(mocks.MockOrganizationRepository))
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	travelPolicyService := service.NewTravelPolicyService(mockPolicyRepo, mockUserRepo)
+
+	h := handler.NewHandler(userService, tripService, handler.WithTravelPolicyService(travelPolicyService))
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	})
+	router.GET("/travel-policy", h.GetTravelPolicy)
+	router.PUT("/travel-policy", h.UpdateTravelPolicy)
+
+	return router, mockPolicyRepo, mockUserRepo, userID
+}
+
+func TestUpdateTravelPolicy(t *testing.T) {
+	document := "rules:\n  - type: max_trip_days\n    severity: block\n    days: 10\n"
+
+	t.Run("Success with YAML", func(t *testing.T) {
+		// Arrange
+		router, mockPolicyRepo, mockUserRepo, userID := setupTravelPolicyTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+		mockPolicyRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.TravelPolicy")).Return(nil)
+
+		// Create request
+		req, _ := http.NewRequest("PUT", "/travel-policy", bytes.NewBufferString(document))
+		req.Header.Set("Content-Type", "application/yaml")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response domain.TravelPolicy
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Rules, 1)
+		assert.Equal(t, domain.RuleMaxTripDays, response.Rules[0].Type)
+		mockPolicyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not an admin", func(t *testing.T) {
+		// Arrange
+		router, mockPolicyRepo, mockUserRepo, userID := setupTravelPolicyTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleManager}, nil)
+
+		// Create request
+		req, _ := http.NewRequest("PUT", "/travel-policy", bytes.NewBufferString(document))
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockPolicyRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid document", func(t *testing.T) {
+		// Arrange
+		router, _, mockUserRepo, userID := setupTravelPolicyTestRouter()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+
+		// Create request
+		req, _ := http.NewRequest("PUT", "/travel-policy", bytes.NewBufferString(`{"rules": [{"type": "visa_required", "severity": "warn"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown rule type")
+	})
+}
@@ -12,9 +12,46 @@ import (
 )
 
 type createTripRequest struct {
-	Destination string    `json:"destination" binding:"required"`
-	StartDate   time.Time `json:"start_date" binding:"required"`
-	EndDate     time.Time `json:"end_date" binding:"required"`
+	// Type is domestic or international, domestic when omitted
+	Type        domain.TripType `json:"type"`
+	Destination string          `json:"destination" binding:"required_without_all=Legs DestinationCode"`
+	StartDate   time.Time       `json:"start_date" binding:"required"`
+	EndDate     time.Time       `json:"end_date" binding:"required"`
+	// Legs is the itinerary of the trip; when given, the destination is derived from it
+	Legs []tripLegRequest `json:"legs"`
+	// DestinationCode is the catalog code of the destination, see GET /destinations
+	DestinationCode string `json:"destination_code"`
+	// CostCenterID defaults to the requester's department cost center when omitted
+	CostCenterID *uuid.UUID `json:"cost_center_id"`
+	// TimeZone is the IANA zone the dates are shown in, e.g. Europe/Lisbon
+	TimeZone string `json:"time_zone"`
+	// Budget is the estimated cost of the trip
+	Budget *domain.TripBudget `json:"budget"`
+	// ProvidedMeals are deducted from the per diem of their days
+	ProvidedMeals []domain.ProvidedMeals `json:"provided_meals"`
+}
+
+type tripLegRequest struct {
+	Origin      string               `json:"origin"`
+	Destination string               `json:"destination"`
+	DepartAt    time.Time            `json:"depart_at"`
+	ArriveAt    time.Time            `json:"arrive_at"`
+	Mode        domain.TransportMode `json:"mode"`
+}
+
+// itineraryLegs converts the legs of the request; they are validated with the trip
+func (r createTripRequest) itineraryLegs() []domain.ItineraryLeg {
+	var legs []domain.ItineraryLeg
+	for _, leg := range r.Legs {
+		legs = append(legs, domain.ItineraryLeg{
+			Origin:      leg.Origin,
+			Destination: leg.Destination,
+			DepartAt:    leg.DepartAt,
+			ArriveAt:    leg.ArriveAt,
+			Mode:        leg.Mode,
+		})
+	}
+	return legs
 }
 
 func (h *Handler) CreateTrip(c *gin.Context) {
@@ -36,8 +73,22 @@ func (h *Handler) CreateTrip(c *gin.Context) {
 		return
 	}
 
-	trip, err := h.tripService.CreateTrip(c.Request.Context(), userID, req.Destination, req.StartDate, req.EndDate)
+	trip, err := h.tripService.CreateTrip(c.Request.Context(), userID, service.TripInput{
+		Type:            req.Type,
+		Destination:     req.Destination,
+		Legs:            req.itineraryLegs(),
+		DestinationCode: req.DestinationCode,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		CostCenterID:    req.CostCenterID,
+		TimeZone:        req.TimeZone,
+		Budget:          req.Budget,
+		ProvidedMeals:   req.ProvidedMeals,
+	})
 	if err != nil {
+		if respondTripConflict(c, err) {
+			return
+		}
 		// Check if it's a validation error
 		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
 			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
@@ -51,6 +102,67 @@ func (h *Handler) CreateTrip(c *gin.Context) {
 	c.JSON(http.StatusCreated, trip)
 }
 
+// UpdateTrip lets the requester edit a trip that is still waiting for approval
+func (h *Handler) UpdateTrip(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return
+	}
+
+	var req createTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	if req.EndDate.Before(req.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be after start_date"})
+		return
+	}
+
+	trip, err := h.tripService.UpdateTrip(c.Request.Context(), tripID, userID, service.TripInput{
+		Type:            req.Type,
+		Destination:     req.Destination,
+		Legs:            req.itineraryLegs(),
+		DestinationCode: req.DestinationCode,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		CostCenterID:    req.CostCenterID,
+		TimeZone:        req.TimeZone,
+		Budget:          req.Budget,
+		ProvidedMeals:   req.ProvidedMeals,
+	})
+	if err != nil {
+		if respondTripConflict(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrTripNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			// Check if it's a validation error
+			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update trip"})
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
 func (h *Handler) GetTripByID(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
@@ -105,9 +217,17 @@ func (h *Handler) ListTrips(c *gin.Context) {
 			params.Status = &s
 		}
 	}
+	if costCenter := c.Query("cost_center_id"); costCenter != "" {
+		if id, err := uuid.Parse(costCenter); err == nil {
+			params.CostCenterID = &id
+		}
+	}
 	if dest := c.Query("destination"); dest != "" {
 		params.Destination = &dest
 	}
+	if code := c.Query("destination_code"); code != "" {
+		params.DestinationCode = &code
+	}
 	if startDate := c.Query("start_date"); startDate != "" {
 		if t, err := time.Parse("2006-01-02", startDate); err == nil {
 			params.StartDate = &t
@@ -157,18 +277,25 @@ func (h *Handler) UpdateTripStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
 		return
 	}
-	if !req.Status.IsValid() || req.Status == domain.StatusRequested {
+	if req.Status != domain.StatusApproved && req.Status != domain.StatusCanceled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
 		return
 	}
 
 	err = h.tripService.UpdateTripStatus(c.Request.Context(), tripID, updaterID, req.Status)
 	if err != nil {
+		if respondTripConflict(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, service.ErrTripNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case errors.Is(err, service.ErrSelfApproval):
+		case errors.Is(err, service.ErrSelfApproval), errors.Is(err, service.ErrNotApprover), errors.Is(err, service.ErrInvalidStatus):
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrBudgetExceeded):
+			respondBudgetExceeded(c, err)
+		case errors.Is(err, domain.ErrStepAlreadyDecided), errors.Is(err, service.ErrMissingApprovalChain):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			// Check if it's a validation error
 			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
@@ -183,6 +310,10 @@ func (h *Handler) UpdateTripStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Trip status updated successfully"})
 }
 
+type cancelTripRequest struct {
+	Justification string `json:"justification"`
+}
+
 func (h *Handler) CancelApprovedTrip(c *gin.Context) {
 	cancelingUserID, ok := getUserID(c)
 	if !ok {
@@ -196,7 +327,17 @@ func (h *Handler) CancelApprovedTrip(c *gin.Context) {
 		return
 	}
 
-	err = h.tripService.CancelApprovedTrip(c.Request.Context(), tripID, cancelingUserID)
+	// The body is optional; a justification is only needed to override the cancellation window
+	var req cancelTripRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := parseValidationErrors(err)
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+			return
+		}
+	}
+
+	err = h.tripService.CancelApprovedTrip(c.Request.Context(), tripID, cancelingUserID, req.Justification)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrTripNotFound):
@@ -216,3 +357,87 @@ func (h *Handler) CancelApprovedTrip(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Trip cancellation successful"})
 }
+
+func (h *Handler) WithdrawTrip(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return
+	}
+
+	if err := h.tripService.WithdrawTrip(c.Request.Context(), tripID, userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrTripNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrInvalidStatus):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw trip"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trip withdrawn successfully"})
+}
+
+func (h *Handler) GetTripHistory(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID format"})
+		return
+	}
+
+	events, err := h.tripService.GetTripHistory(c.Request.Context(), tripID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTripNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trip history"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// respondTripConflict answers with 409 and the clashing trips when err is an overlap
+func respondTripConflict(c *gin.Context, err error) bool {
+	if !errors.Is(err, domain.ErrTripOverlap) {
+		return false
+	}
+
+	conflictingIDs := []uuid.UUID{}
+	var conflict *domain.TripConflictError
+	if errors.As(err, &conflict) {
+		conflictingIDs = conflict.TripIDs
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicting_trip_ids": conflictingIDs})
+	return true
+}
+
+// respondBudgetExceeded answers with 409 and what was left of the budget
+func respondBudgetExceeded(c *gin.Context, err error) {
+	body := gin.H{"error": err.Error()}
+	var exceeded *domain.BudgetExceededError
+	if errors.As(err, &exceeded) {
+		body["budget_id"] = exceeded.BudgetID
+		body["remaining"] = exceeded.Remaining
+		body["trip_cost"] = exceeded.TripCost
+	}
+	c.JSON(http.StatusConflict, body)
+}
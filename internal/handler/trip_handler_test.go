@@ -27,19 +27,23 @@ func setupTripTestRouter() (*gin.Engine, *mocks.MockTripRepository, *mocks.MockU
 
 	mockTripRepo := new(mocks.MockTripRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
 	mockNotifier := new(mocks.MockNotificationService)
 
-	userService := service.NewUserService(mockUserRepo)
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
 	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
 
 	h := handler.NewHandler(userService, tripService)
 
-	// Create a fixed userID for testing
+	// Create a fixed userID and organization for testing
 	userID := uuid.New()
+	orgID := uuid.New()
 
-	// Add middleware to set userID in context for authenticated routes
+	// Add middleware to set userID and tenant in context for authenticated routes
 	authMiddleware := func(c *gin.Context) {
 		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
 		c.Next()
 	}
 
@@ -48,9 +52,12 @@ func setupTripTestRouter() (*gin.Engine, *mocks.MockTripRepository, *mocks.MockU
 	tripRoutes.Use(authMiddleware)
 	{
 		tripRoutes.POST("/trips", h.CreateTrip)
+		tripRoutes.GET("/trips", h.ListTrips)
 		tripRoutes.GET("/trips/:id", h.GetTripByID)
+		tripRoutes.PUT("/trips/:id", h.UpdateTrip)
 		tripRoutes.PATCH("/trips/:id/status", h.UpdateTripStatus)
 		tripRoutes.POST("/trips/:id/cancel", h.CancelApprovedTrip)
+		tripRoutes.POST("/trips/:id/withdraw", h.WithdrawTrip)
 	}
 
 	return router, mockTripRepo, mockUserRepo, mockNotifier, userID
@@ -65,13 +72,15 @@ func TestCreateTrip(t *testing.T) {
 		endDate := startDate.AddDate(0, 0, 7)    // 7 days after start
 
 		// Mock behavior
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
 		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"destination": "Paris",
-			"start_date":  startDate.Format(time.RFC3339),
-			"end_date":    endDate.Format(time.RFC3339),
+			"destination":    "Paris",
+			"start_date":     startDate.Format(time.RFC3339),
+			"end_date":       endDate.Format(time.RFC3339),
+			"cost_center_id": uuid.New().String(),
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
@@ -93,6 +102,172 @@ func TestCreateTrip(t *testing.T) {
 		mockTripRepo.AssertExpectations(t)
 	})
 
+	t.Run("Success with an itinerary", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		startDate := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+		endDate := startDate.AddDate(0, 0, 10)
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Create request
+		reqBody := map[string]interface{}{
+			"type":           "international",
+			"start_date":     startDate.Format(time.RFC3339),
+			"end_date":       endDate.Format(time.RFC3339),
+			"cost_center_id": uuid.New().String(),
+			"legs": []map[string]interface{}{
+				{"origin": "São Paulo", "destination": "Lisbon", "depart_at": "2030-05-10T22:00:00Z", "arrive_at": "2030-05-11T11:00:00Z", "mode": "flight"},
+				{"origin": "Lisbon", "destination": "Berlin", "depart_at": "2030-05-15T08:00:00Z", "arrive_at": "2030-05-15T13:00:00Z", "mode": "flight"},
+				{"origin": "Berlin", "destination": "São Paulo", "depart_at": "2030-05-20T09:00:00Z", "arrive_at": "2030-05-20T23:00:00Z", "mode": "flight"},
+			},
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.Trip
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Lisbon, Berlin", response.Destination)
+		assert.Len(t, response.Legs, 3)
+		assert.Equal(t, 3, response.Legs[2].Position)
+	})
+
+	t.Run("Success with a time zone", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Create request
+		reqBody := map[string]interface{}{
+			"destination":    "Tóquio",
+			"start_date":     "2030-05-10T00:00:00+09:00",
+			"end_date":       "2030-05-20T00:00:00+09:00",
+			"time_zone":      "Asia/Tokyo",
+			"cost_center_id": uuid.New().String(),
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", response["time_zone"])
+		assert.Equal(t, "2030-05-10T00:00:00+09:00", response["start_date"])
+		assert.Equal(t, "2030-05-09T15:00:00Z", response["start_date_utc"])
+	})
+
+	t.Run("Success with a budget", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Create request
+		reqBody := map[string]interface{}{
+			"destination":    "Recife",
+			"start_date":     "2030-05-10T00:00:00Z",
+			"end_date":       "2030-05-13T00:00:00Z",
+			"cost_center_id": uuid.New().String(),
+			"budget": map[string]interface{}{
+				"currency": "BRL",
+				"items": []map[string]interface{}{
+					{"category": "airfare", "amount": 120000},
+					{"category": "lodging", "amount": 90000, "description": "3 nights"},
+				},
+			},
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":210000`)
+	})
+
+	t.Run("Invalid budget", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		// Create request
+		reqBody := map[string]interface{}{
+			"destination":    "Recife",
+			"start_date":     "2030-05-10T00:00:00Z",
+			"end_date":       "2030-05-13T00:00:00Z",
+			"cost_center_id": uuid.New().String(),
+			"budget":         map[string]interface{}{"currency": "BRL", "items": []map[string]interface{}{{"category": "airfare", "amount": -5}}},
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "budget.items[0]: amount must be positive")
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Broken itinerary", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		// Create request
+		reqBody := map[string]interface{}{
+			"start_date":     "2030-05-10T00:00:00Z",
+			"end_date":       "2030-05-20T00:00:00Z",
+			"cost_center_id": uuid.New().String(),
+			"legs": []map[string]interface{}{
+				{"origin": "São Paulo", "destination": "Lisbon", "depart_at": "2030-05-10T22:00:00Z", "arrive_at": "2030-05-11T11:00:00Z", "mode": "flight"},
+				{"origin": "Madrid", "destination": "São Paulo", "depart_at": "2030-05-20T09:00:00Z", "arrive_at": "2030-05-20T23:00:00Z", "mode": "flight"},
+			},
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "legs[1]: must depart from Lisbon, where the previous leg arrives")
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
 	t.Run("Invalid request body", func(t *testing.T) {
 		// Arrange
 		router, _, _, _, _ := setupTripTestRouter()
@@ -146,9 +321,10 @@ func TestCreateTrip(t *testing.T) {
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"destination": "Paris",
-			"start_date":  startDate.Format(time.RFC3339),
-			"end_date":    endDate.Format(time.RFC3339),
+			"destination":    "Paris",
+			"start_date":     startDate.Format(time.RFC3339),
+			"end_date":       endDate.Format(time.RFC3339),
+			"cost_center_id": uuid.New().String(),
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
@@ -171,13 +347,15 @@ func TestCreateTrip(t *testing.T) {
 		dbError := errors.New("database error")
 
 		// Mock behavior
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
 		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(dbError)
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"destination": "Paris",
-			"start_date":  startDate.Format(time.RFC3339),
-			"end_date":    endDate.Format(time.RFC3339),
+			"destination":    "Paris",
+			"start_date":     startDate.Format(time.RFC3339),
+			"end_date":       endDate.Format(time.RFC3339),
+			"cost_center_id": uuid.New().String(),
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
@@ -216,7 +394,7 @@ func TestUpdateTripStatus(t *testing.T) {
 
 		// Mock behavior
 		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", mock.Anything, tripID, domain.StatusApproved).Return(nil)
+		mockTripRepo.On("UpdateStatus", mock.Anything, tripID, mock.Anything, domain.StatusApproved).Return(nil)
 		mockUserRepo.On("FindByID", mock.Anything, requesterID).Return(user, nil)
 		mockNotifier.On("Send", user, trip, mock.AnythingOfType("string")).Return()
 
@@ -362,7 +540,7 @@ func TestCancelApprovedTrip(t *testing.T) {
 
 		// Mock behavior - we'll set up the trip to have the same requesterID as the userID in the context
 		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", mock.Anything, tripID, domain.StatusCanceled).Return(nil)
+		mockTripRepo.On("UpdateStatus", mock.Anything, tripID, domain.StatusApproved, domain.StatusCanceled).Return(nil)
 		mockUserRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
 		mockNotifier.On("Send", user, trip, mock.AnythingOfType("string")).Return()
 
@@ -494,3 +672,281 @@ func TestCancelApprovedTrip(t *testing.T) {
 		mockTripRepo.AssertExpectations(t)
 	})
 }
+
+func TestUpdateTrip(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, userID := setupTripTestRouter()
+
+		tripID := uuid.New()
+		costCenterID := uuid.New()
+		startDate := time.Now().AddDate(0, 1, 0)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{
+			ID:           tripID,
+			RequesterID:  userID,
+			CostCenterID: costCenterID,
+			Type:         domain.TripDomestic,
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 7),
+			Status:       domain.StatusRequested,
+		}, nil)
+		mockTripRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{
+			"destination": "Lisboa",
+			"start_date":  startDate.Format(time.RFC3339),
+			"end_date":    startDate.AddDate(0, 0, 3).Format(time.RFC3339),
+		})
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("/trips/%s", tripID), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response domain.Trip
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Lisboa", response.Destination)
+		assert.Equal(t, costCenterID, response.CostCenterID)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Trip already approved", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, userID := setupTripTestRouter()
+
+		tripID := uuid.New()
+		startDate := time.Now().AddDate(0, 1, 0)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{
+			ID:          tripID,
+			RequesterID: userID,
+			Status:      domain.StatusApproved,
+		}, nil)
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{
+			"destination": "Lisboa",
+			"start_date":  startDate.Format(time.RFC3339),
+			"end_date":    startDate.AddDate(0, 0, 3).Format(time.RFC3339),
+		})
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("/trips/%s", tripID), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockTripRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelApprovedTrip_Override(t *testing.T) {
+	// Arrange
+	router, mockTripRepo, mockUserRepo, mockNotifier, userID := setupTripTestRouter()
+
+	tripID := uuid.New()
+	requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	trip := &domain.Trip{
+		ID:          tripID,
+		RequesterID: requester.ID,
+		Status:      domain.StatusApproved,
+		StartDate:   time.Now().AddDate(0, 0, 3), // Inside the default window
+		Destination: "Paris",
+	}
+
+	// Mock behavior - the user in the context is an admin
+	mockTripRepo.On("FindByID", mock.Anything, tripID).Return(trip, nil)
+	mockUserRepo.On("FindByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin}, nil)
+	mockUserRepo.On("FindByID", mock.Anything, requester.ID).Return(requester, nil)
+	mockTripRepo.On("UpdateStatus", mock.Anything, tripID, domain.StatusApproved, domain.StatusCanceled).Return(nil)
+	mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+
+	// Create request
+	jsonBody, _ := json.Marshal(map[string]interface{}{"justification": "family emergency"})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/trips/%s/cancel", tripID), bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockTripRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestWithdrawTrip(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, userID := setupTripTestRouter()
+
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", mock.Anything, tripID).Return(&domain.Trip{
+			ID:          tripID,
+			RequesterID: userID,
+			Status:      domain.StatusRequested,
+		}, nil)
+		mockTripRepo.On("UpdateStatus", mock.Anything, tripID, domain.StatusRequested, domain.StatusWithdrawn).Return(nil)
+
+		// Create request
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/trips/%s/withdraw", tripID), nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Approvers cannot set the withdrawn status", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		tripID := uuid.New()
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"status": domain.StatusWithdrawn})
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/trips/%s/status", tripID), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTripRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListTrips(t *testing.T) {
+	t.Run("Filters concluded trips", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, userID := setupTripTestRouter()
+		trips := []*domain.Trip{{ID: uuid.New(), RequesterID: userID, Status: domain.StatusConcluded}}
+
+		// Mock behavior
+		mockTripRepo.On("List", mock.Anything, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+			return *params.RequesterID == userID && params.Status != nil && *params.Status == domain.StatusConcluded
+		})).Return(trips, nil)
+
+		// Create request
+		req, _ := http.NewRequest("GET", "/trips?status=concluido", nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []*domain.Trip
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, domain.StatusConcluded, response[0].Status)
+	})
+
+	t.Run("Approvers cannot set the concluded status", func(t *testing.T) {
+		// Arrange
+		router, mockTripRepo, _, _, _ := setupTripTestRouter()
+
+		// Create request
+		jsonBody, _ := json.Marshal(map[string]interface{}{"status": domain.StatusConcluded})
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/trips/%s/status", uuid.New()), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTripRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateTrip_Overlap(t *testing.T) {
+	// Arrange
+	router, mockTripRepo, _, _, userID := setupTripTestRouter()
+	clashingID := uuid.New()
+	startDate := time.Now().AddDate(0, 1, 0)
+
+	// Mock behavior
+	mockTripRepo.On("FindOverlapping", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{{ID: clashingID}}, nil)
+
+	// Create request
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"destination":    "Paris",
+		"start_date":     startDate,
+		"end_date":       startDate.AddDate(0, 0, 3),
+		"cost_center_id": uuid.New(),
+	})
+	req, _ := http.NewRequest("POST", "/trips", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		Error              string      `json:"error"`
+		ConflictingTripIDs []uuid.UUID `json:"conflicting_trip_ids"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{clashingID}, response.ConflictingTripIDs)
+	mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTripStatus_Overlap(t *testing.T) {
+	// Arrange
+	router, mockTripRepo, _, _, _ := setupTripTestRouter()
+	trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusCanceled, Destination: "Paris"}
+	clashingID := uuid.New()
+
+	// Mock behavior
+	mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+	mockTripRepo.On("UpdateStatus", mock.Anything, trip.ID, mock.Anything, domain.StatusApproved).Return(domain.ErrTripOverlap)
+	mockTripRepo.On("FindOverlapping", mock.Anything, trip.RequesterID, mock.Anything, mock.Anything, trip.ID).Return([]*domain.Trip{{ID: clashingID}}, nil)
+
+	// Create request
+	jsonBody, _ := json.Marshal(map[string]interface{}{"status": "aprovado"})
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("/trips/%s/status", trip.ID), bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		ConflictingTripIDs []uuid.UUID `json:"conflicting_trip_ids"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{clashingID}, response.ConflictingTripIDs)
+}
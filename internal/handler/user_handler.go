@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jimmmmisss/api-viagens/internal/config"
 	"github.com/jimmmmisss/api-viagens/internal/domain"
 	"github.com/jimmmmisss/api-viagens/internal/service"
@@ -12,9 +13,10 @@ import (
 )
 
 type registerRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Organization string `json:"organization" binding:"required"` // Organization slug
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	Password     string `json:"password" binding:"required"` // Length and strength are checked by the password policy
 }
 
 func (h *Handler) RegisterUser(c *gin.Context) {
@@ -25,12 +27,20 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Register(c.Request.Context(), req.Name, req.Email, req.Password)
+	user, err := h.userService.Register(c.Request.Context(), req.Organization, req.Name, req.Email, req.Password)
 	if err != nil {
 		if errors.Is(err, service.ErrUserAlreadyExists) {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrOrganizationNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrRegistrationClosed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 
 		// Check if it's a validation error
 		if validationErrs, ok := err.(*domain.ValidationErrors); ok {
@@ -45,6 +55,48 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, user)
 }
 
+type createUserRequest struct {
+	Name     string          `json:"name" binding:"required"`
+	Email    string          `json:"email" binding:"required,email"`
+	Password string          `json:"password" binding:"required"` // Length and strength are checked by the password policy
+	Role     domain.UserRole `json:"role" binding:"required"`
+}
+
+func (h *Handler) CreateUser(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req createUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	user, err := h.userService.CreateUser(c.Request.Context(), adminID, req.Name, req.Email, req.Password, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			// Check if it's a validation error
+			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
 type loginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -75,8 +127,12 @@ func (h *Handler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	cfg, _ := config.Load() // In a real app, inject config or get from context
-	token, err := utils.GenerateJWT(user.ID, cfg.JWTSecretKey, cfg.JWTExpirationHours)
+	cfg, err := config.Load() // In a real app, inject config or get from context
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	token, err := utils.GenerateJWT(user.ID, user.OrgID, cfg.JWTSecretKey, cfg.JWTExpirationHours)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
@@ -84,3 +140,89 @@ func (h *Handler) LoginUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+func (h *Handler) ChangePassword(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	err := h.userService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			// Check if it's a validation error
+			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+type updateRoleRequest struct {
+	Role      domain.UserRole `json:"role" binding:"required"`
+	ManagerID *uuid.UUID      `json:"manager_id"`
+}
+
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user context"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	var req updateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := parseValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors.GetErrors()})
+		return
+	}
+
+	user, err := h.userService.UpdateRole(c.Request.Context(), adminID, userID, req.Role, req.ManagerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			// Check if it's a validation error
+			if validationErrs, ok := err.(*domain.ValidationErrors); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.GetErrors()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role"})
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
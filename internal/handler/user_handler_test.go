@@ -25,7 +25,12 @@ func setupTestRouter() (*gin.Engine, *mocks.MockUserRepository) {
 	router := gin.Default()
 
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := service.NewUserService(mockUserRepo)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
+
+	// Every registration in these tests targets the same organization
+	org := &domain.Organization{ID: uuid.New(), Name: "Acme", Slug: "acme", AllowedEmailDomains: []string{"example.com"}}
+	mockOrgRepo.On("FindBySlug", mock.Anything, org.Slug).Return(org, nil).Maybe()
 
 	// We don't need to mock the trip service for user handler tests
 	mockTripRepo := new(mocks.MockTripRepository)
@@ -40,6 +45,27 @@ func setupTestRouter() (*gin.Engine, *mocks.MockUserRepository) {
 	return router, mockUserRepo
 }
 
+// Setup test router for routes that need an authenticated user
+func setupAuthenticatedUserTestRouter() (*gin.Engine, *mocks.MockUserRepository, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	userService := service.NewUserService(mockUserRepo, mockOrgRepo)
+	tripService := service.NewTripService(new(mocks.MockTripRepository), mockUserRepo, new(mocks.MockNotificationService))
+	h := handler.NewHandler(userService, tripService)
+
+	userID := uuid.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	router.PUT("/users/me/password", h.ChangePassword)
+
+	return router, mockUserRepo, userID
+}
+
 func TestRegisterUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
@@ -62,9 +88,10 @@ func TestRegisterUser(t *testing.T) {
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"name":     "Test User",
-			"email":    "test@example.com",
-			"password": "password123",
+			"organization": "acme",
+			"name":         "Test User",
+			"email":        "test@example.com",
+			"password":     "password123",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
@@ -86,13 +113,37 @@ func TestRegisterUser(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("Email outside the organization's domains", func(t *testing.T) {
+		// Arrange
+		router, mockUserRepo := setupTestRouter()
+
+		reqBody := map[string]interface{}{
+			"organization": "acme",
+			"name":         "Outsider",
+			"email":        "outsider@gmail.com",
+			"password":     "password123",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockUserRepo.AssertNotCalled(t, "Create")
+	})
+
 	t.Run("Invalid request body", func(t *testing.T) {
 		// Arrange
 		router, _ := setupTestRouter()
 
 		// Create request with invalid body
 		reqBody := map[string]interface{}{
-			"name": "Test User",
+			"organization": "acme",
+			"name":         "Test User",
 			// Missing email
 			"password": "password123",
 		}
@@ -144,9 +195,10 @@ func TestRegisterUser(t *testing.T) {
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"name":     "Existing User",
-			"email":    "existing@example.com",
-			"password": "password123",
+			"organization": "acme",
+			"name":         "Existing User",
+			"email":        "existing@example.com",
+			"password":     "password123",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
@@ -172,9 +224,10 @@ func TestRegisterUser(t *testing.T) {
 
 		// Create request
 		reqBody := map[string]interface{}{
-			"name":     "Test User",
-			"email":    "test@example.com",
-			"password": "password123",
+			"organization": "acme",
+			"name":         "Test User",
+			"email":        "test@example.com",
+			"password":     "password123",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 		req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
@@ -194,6 +247,8 @@ func TestLoginUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
 		router, mockUserRepo := setupTestRouter()
+		// The handler loads the configuration, which requires the attachment URL secret
+		t.Setenv("ATTACHMENT_URL_SECRET", "test-attachment-secret")
 
 		// Generate a valid hash for the password "password123"
 		password := "password123"
@@ -362,3 +417,65 @@ func TestLoginUser(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 }
+
+func TestChangePassword(t *testing.T) {
+	t.Run("Wrong current password", func(t *testing.T) {
+		// Arrange
+		router, mockUserRepo, userID := setupAuthenticatedUserTestRouter()
+
+		hashedPassword := "$2a$10$1ggfMVZV6Js0ybvJufLRUOWHS5f6KneuP0XwwHpJ8L8iw0hLyhsiG" // hashed "password123"
+		user := &domain.User{ID: userID, Name: "Test User", Email: "test@example.com", PasswordHash: hashedPassword}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+
+		reqBody := map[string]interface{}{
+			"current_password": "wrongpassword",
+			"new_password":     "brand-new-password",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("PUT", "/users/me/password", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUserRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+
+	t.Run("New password violates policy", func(t *testing.T) {
+		// Arrange
+		router, mockUserRepo, userID := setupAuthenticatedUserTestRouter()
+
+		hashedPassword, err := utils.HashPassword("password123")
+		assert.NoError(t, err)
+		user := &domain.User{ID: userID, Name: "Test User", Email: "test@example.com", PasswordHash: hashedPassword}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+
+		reqBody := map[string]interface{}{
+			"current_password": "password123",
+			"new_password":     "short",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("PUT", "/users/me/password", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string][]string
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response["errors"], "password must be at least 8 characters")
+		mockUserRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+}
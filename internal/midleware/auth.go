@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
 	"github.com/jimmmmisss/api-viagens/internal/utils"
 )
 
@@ -49,8 +50,24 @@ func AuthMiddleware(secretKey string) gin.HandlerFunc {
 			return
 		}
 
+		orgIDStr, ok := claims["org_id"].(string)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid organization in token"})
+			return
+		}
+
+		orgID, err := uuid.Parse(orgIDStr)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Malformed organization in token"})
+			return
+		}
+
 		// Set user ID in context for downstream handlers
 		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+
+		// Scope every repository call made while handling this request to the user's organization
+		c.Request = c.Request.WithContext(domain.ContextWithOrgID(c.Request.Context(), orgID))
 		c.Next()
 	}
 }
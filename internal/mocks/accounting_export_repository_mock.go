@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccountingExportRepository is a mock implementation of domain.AccountingExportRepository
+type MockAccountingExportRepository struct {
+	mock.Mock
+}
+
+// ListExportableReports mocks the ListExportableReports method
+func (m *MockAccountingExportRepository) ListExportableReports(ctx context.Context, from, to time.Time) ([]*domain.ExpenseReport, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ExpenseReport), args.Error(1)
+}
+
+// CreateBatch mocks the CreateBatch method
+func (m *MockAccountingExportRepository) CreateBatch(ctx context.Context, batch *domain.AccountingExportBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+// ListBatches mocks the ListBatches method
+func (m *MockAccountingExportRepository) ListBatches(ctx context.Context) ([]*domain.AccountingExportBatch, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccountingExportBatch), args.Error(1)
+}
+
+// FindBatch mocks the FindBatch method
+func (m *MockAccountingExportRepository) FindBatch(ctx context.Context, id uuid.UUID) (*domain.AccountingExportBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccountingExportBatch), args.Error(1)
+}
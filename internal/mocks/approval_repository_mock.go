@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockApprovalPolicyRepository is a mock implementation of domain.ApprovalPolicyRepository
+type MockApprovalPolicyRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockApprovalPolicyRepository) Create(ctx context.Context, policy *domain.ApprovalPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockApprovalPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// List mocks the List method
+func (m *MockApprovalPolicyRepository) List(ctx context.Context) ([]*domain.ApprovalPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ApprovalPolicy), args.Error(1)
+}
+
+// MockApprovalStepRepository is a mock implementation of domain.ApprovalStepRepository
+type MockApprovalStepRepository struct {
+	mock.Mock
+}
+
+// CreateSteps mocks the CreateSteps method
+func (m *MockApprovalStepRepository) CreateSteps(ctx context.Context, steps []*domain.ApprovalStep) error {
+	args := m.Called(ctx, steps)
+	return args.Error(0)
+}
+
+// FindByTripID mocks the FindByTripID method
+func (m *MockApprovalStepRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.ApprovalStep, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ApprovalStep), args.Error(1)
+}
+
+// UpdateDecision mocks the UpdateDecision method
+func (m *MockApprovalStepRepository) UpdateDecision(ctx context.Context, step *domain.ApprovalStep) error {
+	args := m.Called(ctx, step)
+	return args.Error(0)
+}
+
+// DeleteByTripID mocks the DeleteByTripID method
+func (m *MockApprovalStepRepository) DeleteByTripID(ctx context.Context, tripID uuid.UUID) error {
+	args := m.Called(ctx, tripID)
+	return args.Error(0)
+}
+
+// ListCurrent mocks the ListCurrent method
+func (m *MockApprovalStepRepository) ListCurrent(ctx context.Context, approverID uuid.UUID, role domain.UserRole) ([]*domain.ApprovalStep, error) {
+	args := m.Called(ctx, approverID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ApprovalStep), args.Error(1)
+}
+
+// ListStale mocks the ListStale method
+func (m *MockApprovalStepRepository) ListStale(ctx context.Context, waitingSince time.Time) ([]*domain.ApprovalStep, error) {
+	args := m.Called(ctx, waitingSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ApprovalStep), args.Error(1)
+}
+
+// UpdateEscalation mocks the UpdateEscalation method
+func (m *MockApprovalStepRepository) UpdateEscalation(ctx context.Context, step *domain.ApprovalStep) error {
+	args := m.Called(ctx, step)
+	return args.Error(0)
+}
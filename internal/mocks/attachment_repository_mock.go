@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttachmentRepository is a mock implementation of domain.AttachmentRepository
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockAttachmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+// ListByTripID mocks the ListByTripID method
+func (m *MockAttachmentRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.Attachment, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attachment), args.Error(1)
+}
+
+// FindByChecksum mocks the FindByChecksum method
+func (m *MockAttachmentRepository) FindByChecksum(ctx context.Context, checksum string) ([]*domain.Attachment, error) {
+	args := m.Called(ctx, checksum)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attachment), args.Error(1)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBlackoutCalendarRepository is a mock implementation of domain.BlackoutCalendarRepository
+type MockBlackoutCalendarRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockBlackoutCalendarRepository) Create(ctx context.Context, calendar *domain.BlackoutCalendar) error {
+	args := m.Called(ctx, calendar)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockBlackoutCalendarRepository) Update(ctx context.Context, calendar *domain.BlackoutCalendar) error {
+	args := m.Called(ctx, calendar)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockBlackoutCalendarRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.BlackoutCalendar, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlackoutCalendar), args.Error(1)
+}
+
+// Delete mocks the Delete method
+func (m *MockBlackoutCalendarRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// List mocks the List method
+func (m *MockBlackoutCalendarRepository) List(ctx context.Context) ([]*domain.BlackoutCalendar, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BlackoutCalendar), args.Error(1)
+}
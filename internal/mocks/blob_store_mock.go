@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBlobStore is a mock implementation of domain.BlobStore
+type MockBlobStore struct {
+	mock.Mock
+}
+
+// Put mocks the Put method
+func (m *MockBlobStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	args := m.Called(ctx, key, content, contentType)
+	return args.Error(0)
+}
+
+// Get mocks the Get method
+func (m *MockBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// Delete mocks the Delete method
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
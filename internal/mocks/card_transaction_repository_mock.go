@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCardTransactionRepository is a mock implementation of domain.CardTransactionRepository
+type MockCardTransactionRepository struct {
+	mock.Mock
+}
+
+// Import mocks the Import method
+func (m *MockCardTransactionRepository) Import(ctx context.Context, transactions []*domain.CardTransaction) ([]*domain.CardTransaction, error) {
+	args := m.Called(ctx, transactions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CardTransaction), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockCardTransactionRepository) Update(ctx context.Context, transaction *domain.CardTransaction) error {
+	args := m.Called(ctx, transaction)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockCardTransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CardTransaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CardTransaction), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockCardTransactionRepository) List(ctx context.Context, filter domain.CardTransactionFilter) ([]*domain.CardTransaction, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CardTransaction), args.Error(1)
+}
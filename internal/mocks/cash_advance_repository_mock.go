@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCashAdvanceRepository is a mock implementation of domain.CashAdvanceRepository
+type MockCashAdvanceRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockCashAdvanceRepository) Create(ctx context.Context, advance *domain.CashAdvance) error {
+	args := m.Called(ctx, advance)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockCashAdvanceRepository) Update(ctx context.Context, advance *domain.CashAdvance, from domain.CashAdvanceStatus) error {
+	args := m.Called(ctx, advance, from)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockCashAdvanceRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CashAdvance, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CashAdvance), args.Error(1)
+}
+
+// ListByTripID mocks the ListByTripID method
+func (m *MockCashAdvanceRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.CashAdvance, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CashAdvance), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockCashAdvanceRepository) List(ctx context.Context, status *domain.CashAdvanceStatus) ([]*domain.CashAdvance, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CashAdvance), args.Error(1)
+}
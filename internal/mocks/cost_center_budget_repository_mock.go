@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCostCenterBudgetRepository is a mock implementation of domain.CostCenterBudgetRepository
+type MockCostCenterBudgetRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockCostCenterBudgetRepository) Create(ctx context.Context, budget *domain.CostCenterBudget) error {
+	args := m.Called(ctx, budget)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockCostCenterBudgetRepository) Update(ctx context.Context, budget *domain.CostCenterBudget) error {
+	args := m.Called(ctx, budget)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockCostCenterBudgetRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CostCenterBudget, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CostCenterBudget), args.Error(1)
+}
+
+// ListByCostCenter mocks the ListByCostCenter method
+func (m *MockCostCenterBudgetRepository) ListByCostCenter(ctx context.Context, costCenterID *uuid.UUID) ([]*domain.CostCenterBudget, error) {
+	args := m.Called(ctx, costCenterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CostCenterBudget), args.Error(1)
+}
+
+// Lock mocks the Lock method
+func (m *MockCostCenterBudgetRepository) Lock(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
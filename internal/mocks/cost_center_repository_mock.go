@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCostCenterRepository is a mock implementation of domain.CostCenterRepository
+type MockCostCenterRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockCostCenterRepository) Create(ctx context.Context, costCenter *domain.CostCenter) error {
+	args := m.Called(ctx, costCenter)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockCostCenterRepository) Update(ctx context.Context, costCenter *domain.CostCenter) error {
+	args := m.Called(ctx, costCenter)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockCostCenterRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CostCenter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CostCenter), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockCostCenterRepository) List(ctx context.Context) ([]*domain.CostCenter, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CostCenter), args.Error(1)
+}
+
+// MockDepartmentRepository is a mock implementation of domain.DepartmentRepository
+type MockDepartmentRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockDepartmentRepository) Create(ctx context.Context, department *domain.Department) error {
+	args := m.Called(ctx, department)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockDepartmentRepository) Update(ctx context.Context, department *domain.Department) error {
+	args := m.Called(ctx, department)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockDepartmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Department, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Department), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockDepartmentRepository) List(ctx context.Context) ([]*domain.Department, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Department), args.Error(1)
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDelegationRepository is a mock implementation of domain.DelegationRepository
+type MockDelegationRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockDelegationRepository) Create(ctx context.Context, delegation *domain.Delegation) error {
+	args := m.Called(ctx, delegation)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockDelegationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Delegation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Delegation), args.Error(1)
+}
+
+// Delete mocks the Delete method
+func (m *MockDelegationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ListByUser mocks the ListByUser method
+func (m *MockDelegationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Delegation, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Delegation), args.Error(1)
+}
+
+// ListActive mocks the ListActive method
+func (m *MockDelegationRepository) ListActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]*domain.Delegation, error) {
+	args := m.Called(ctx, delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Delegation), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDestinationRepository is a mock implementation of domain.DestinationRepository
+type MockDestinationRepository struct {
+	mock.Mock
+}
+
+// Search mocks the Search method
+func (m *MockDestinationRepository) Search(ctx context.Context, query string, limit int) ([]*domain.Destination, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Destination), args.Error(1)
+}
+
+// FindByCode mocks the FindByCode method
+func (m *MockDestinationRepository) FindByCode(ctx context.Context, code string) (*domain.Destination, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Destination), args.Error(1)
+}
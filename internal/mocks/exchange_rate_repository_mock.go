@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExchangeRateRepository is a mock implementation of domain.ExchangeRateRepository
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+// Save mocks the Save method
+func (m *MockExchangeRateRepository) Save(ctx context.Context, rates []*domain.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+// FindEffective mocks the FindEffective method
+func (m *MockExchangeRateRepository) FindEffective(ctx context.Context, currency, baseCurrency string, at time.Time) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, currency, baseCurrency, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRate), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockExchangeRateRepository) List(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ExchangeRate), args.Error(1)
+}
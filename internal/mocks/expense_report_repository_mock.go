@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExpenseReportRepository is a mock implementation of domain.ExpenseReportRepository
+type MockExpenseReportRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockExpenseReportRepository) Create(ctx context.Context, report *domain.ExpenseReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockExpenseReportRepository) Update(ctx context.Context, report *domain.ExpenseReport, from domain.ExpenseReportStatus) error {
+	args := m.Called(ctx, report, from)
+	return args.Error(0)
+}
+
+// FindByTripID mocks the FindByTripID method
+func (m *MockExpenseReportRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) (*domain.ExpenseReport, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExpenseReport), args.Error(1)
+}
+
+// ListByTripIDs mocks the ListByTripIDs method
+func (m *MockExpenseReportRepository) ListByTripIDs(ctx context.Context, tripIDs []uuid.UUID) ([]*domain.ExpenseReport, error) {
+	args := m.Called(ctx, tripIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ExpenseReport), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockExpenseReportRepository) List(ctx context.Context, status *domain.ExpenseReportStatus) ([]*domain.ExpenseReport, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ExpenseReport), args.Error(1)
+}
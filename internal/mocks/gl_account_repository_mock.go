@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGLAccountRepository is a mock implementation of domain.GLAccountRepository
+type MockGLAccountRepository struct {
+	mock.Mock
+}
+
+// Save mocks the Save method
+func (m *MockGLAccountRepository) Save(ctx context.Context, account *domain.GLAccount) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+// List mocks the List method
+func (m *MockGLAccountRepository) List(ctx context.Context) ([]*domain.GLAccount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.GLAccount), args.Error(1)
+}
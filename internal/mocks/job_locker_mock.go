@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobLocker is a mock implementation of domain.JobLocker
+type MockJobLocker struct {
+	mock.Mock
+}
+
+// TryLock mocks the TryLock method
+func (m *MockJobLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(func()), args.Bool(1), args.Error(2)
+}
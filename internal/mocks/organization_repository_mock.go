@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrganizationRepository is a mock implementation of domain.OrganizationRepository
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+// FindByID mocks the FindByID method
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+// FindBySlug mocks the FindBySlug method
+func (m *MockOrganizationRepository) FindBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPerDiemRateRepository is a mock implementation of domain.PerDiemRateRepository
+type MockPerDiemRateRepository struct {
+	mock.Mock
+}
+
+// Save mocks the Save method
+func (m *MockPerDiemRateRepository) Save(ctx context.Context, rates []*domain.PerDiemRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockPerDiemRateRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.PerDiemRate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PerDiemRate), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockPerDiemRateRepository) List(ctx context.Context) ([]*domain.PerDiemRate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PerDiemRate), args.Error(1)
+}
+
+// Delete mocks the Delete method
+func (m *MockPerDiemRateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduledNotificationRepository is a mock implementation of domain.ScheduledNotificationRepository
+type MockScheduledNotificationRepository struct {
+	mock.Mock
+}
+
+// Schedule mocks the Schedule method
+func (m *MockScheduledNotificationRepository) Schedule(ctx context.Context, notification *domain.ScheduledNotification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+// DeletePendingByTripID mocks the DeletePendingByTripID method
+func (m *MockScheduledNotificationRepository) DeletePendingByTripID(ctx context.Context, tripID uuid.UUID) error {
+	args := m.Called(ctx, tripID)
+	return args.Error(0)
+}
+
+// ListDue mocks the ListDue method
+func (m *MockScheduledNotificationRepository) ListDue(ctx context.Context, at time.Time, limit int) ([]*domain.ScheduledNotification, error) {
+	args := m.Called(ctx, at, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ScheduledNotification), args.Error(1)
+}
+
+// MarkSent mocks the MarkSent method
+func (m *MockScheduledNotificationRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	args := m.Called(ctx, id, sentAt)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockScheduledNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransactor is a mock implementation of domain.Transactor. Unless the
+// call returns an error, it runs fn with the same context, without a transaction.
+type MockTransactor struct {
+	mock.Mock
+}
+
+// WithinTx mocks the WithinTx method
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTravelPolicyRepository is a mock implementation of domain.TravelPolicyRepository
+type MockTravelPolicyRepository struct {
+	mock.Mock
+}
+
+// Find mocks the Find method
+func (m *MockTravelPolicyRepository) Find(ctx context.Context) (*domain.TravelPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TravelPolicy), args.Error(1)
+}
+
+// Save mocks the Save method
+func (m *MockTravelPolicyRepository) Save(ctx context.Context, policy *domain.TravelPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
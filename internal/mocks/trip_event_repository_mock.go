@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTripEventRepository is a mock implementation of domain.TripEventRepository
+type MockTripEventRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockTripEventRepository) Create(ctx context.Context, event *domain.TripEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// ListByTripID mocks the ListByTripID method
+func (m *MockTripEventRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.TripEvent, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TripEvent), args.Error(1)
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jimmmmisss/api-viagens/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +29,12 @@ func (m *MockTripRepository) FindByID(ctx context.Context, id uuid.UUID) (*domai
 	return args.Get(0).(*domain.Trip), args.Error(1)
 }
 
+// Update mocks the Update method
+func (m *MockTripRepository) Update(ctx context.Context, trip *domain.Trip) error {
+	args := m.Called(ctx, trip)
+	return args.Error(0)
+}
+
 // List mocks the List method
 func (m *MockTripRepository) List(ctx context.Context, params domain.ListTripsParams) ([]*domain.Trip, error) {
 	args := m.Called(ctx, params)
@@ -36,8 +44,26 @@ func (m *MockTripRepository) List(ctx context.Context, params domain.ListTripsPa
 	return args.Get(0).([]*domain.Trip), args.Error(1)
 }
 
+// ConcludeEnded mocks the ConcludeEnded method
+func (m *MockTripRepository) ConcludeEnded(ctx context.Context, endedBefore time.Time) ([]*domain.Trip, error) {
+	args := m.Called(ctx, endedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Trip), args.Error(1)
+}
+
+// FindOverlapping mocks the FindOverlapping method
+func (m *MockTripRepository) FindOverlapping(ctx context.Context, requesterID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*domain.Trip, error) {
+	args := m.Called(ctx, requesterID, start, end, excludeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Trip), args.Error(1)
+}
+
 // UpdateStatus mocks the UpdateStatus method
-func (m *MockTripRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.TripStatus) error {
-	args := m.Called(ctx, id, status)
+func (m *MockTripRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.TripStatus) error {
+	args := m.Called(ctx, id, from, to)
 	return args.Error(0)
 }
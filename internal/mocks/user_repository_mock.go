@@ -35,3 +35,39 @@ func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domai
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
+
+// UpdatePassword mocks the UpdatePassword method
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+// UpdateRole mocks the UpdateRole method
+func (m *MockUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role domain.UserRole, managerID *uuid.UUID) error {
+	args := m.Called(ctx, id, role, managerID)
+	return args.Error(0)
+}
+
+// ListByRole mocks the ListByRole method
+func (m *MockUserRepository) ListByRole(ctx context.Context, role domain.UserRole) ([]*domain.User, error) {
+	args := m.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+// FindPasswordHistory mocks the FindPasswordHistory method
+func (m *MockUserRepository) FindPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// UpdateDepartment mocks the UpdateDepartment method
+func (m *MockUserRepository) UpdateDepartment(ctx context.Context, id uuid.UUID, departmentID *uuid.UUID) error {
+	args := m.Called(ctx, id, departmentID)
+	return args.Error(0)
+}
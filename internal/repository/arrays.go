@@ -0,0 +1,10 @@
+package repository
+
+// textArray makes sure a nil slice is stored as an empty array, since pgx
+// encodes nil slices as NULL and the array columns are NOT NULL
+func textArray(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
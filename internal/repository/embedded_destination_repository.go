@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// destinationsCSV is the destination catalog shipped with the binary
+//
+//go:embed data/destinations.csv
+var destinationsCSV []byte
+
+// Search ranks, best first
+const (
+	matchCode = iota
+	matchExact
+	matchPrefix
+	matchWordPrefix
+	matchContains
+	noMatch
+)
+
+type catalogEntry struct {
+	destination *domain.Destination
+	// names holds the normalized name and aliases
+	names []string
+}
+
+type embeddedDestinationRepository struct {
+	entries []catalogEntry
+	byCode  map[string]*domain.Destination
+}
+
+// NewEmbeddedDestinationRepository loads the destination catalog embedded in the binary
+func NewEmbeddedDestinationRepository() (domain.DestinationRepository, error) {
+	destinations, err := parseDestinationCatalog(destinationsCSV)
+	if err != nil {
+		return nil, fmt.Errorf("loading destination catalog: %w", err)
+	}
+
+	r := &embeddedDestinationRepository{byCode: make(map[string]*domain.Destination, len(destinations))}
+	for _, destination := range destinations {
+		entry := catalogEntry{destination: destination, names: []string{domain.NormalizeSearchText(destination.Name)}}
+		for _, alias := range destination.Aliases {
+			entry.names = append(entry.names, domain.NormalizeSearchText(alias))
+		}
+		r.entries = append(r.entries, entry)
+		r.byCode[destination.Code] = destination
+	}
+	return r, nil
+}
+
+func parseDestinationCatalog(data []byte) ([]*domain.Destination, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("the catalog is empty")
+	}
+
+	var destinations []*domain.Destination
+	for i, record := range records[1:] {
+		if len(record) != 9 {
+			return nil, fmt.Errorf("line %d: expected 9 fields, got %d", i+2, len(record))
+		}
+		latitude, err := strconv.ParseFloat(record[6], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid latitude: %w", i+2, err)
+		}
+		longitude, err := strconv.ParseFloat(record[7], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid longitude: %w", i+2, err)
+		}
+
+		destination := &domain.Destination{
+			Code:        record[0],
+			Kind:        domain.DestinationKind(record[1]),
+			Name:        record[2],
+			CityCode:    record[3],
+			CountryCode: record[4],
+			TimeZone:    record[5],
+			Latitude:    latitude,
+			Longitude:   longitude,
+		}
+		if record[8] != "" {
+			destination.Aliases = strings.Split(record[8], "|")
+		}
+		destinations = append(destinations, destination)
+	}
+	return destinations, nil
+}
+
+func (r *embeddedDestinationRepository) Search(ctx context.Context, query string, limit int) ([]*domain.Destination, error) {
+	query = domain.NormalizeSearchText(query)
+	if query == "" {
+		return []*domain.Destination{}, nil
+	}
+
+	type match struct {
+		destination *domain.Destination
+		rank        int
+	}
+	var matches []match
+	for _, entry := range r.entries {
+		if rank := entry.rank(query); rank != noMatch {
+			matches = append(matches, match{destination: entry.destination, rank: rank})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		// Cities go before their airports
+		if a.destination.Kind != b.destination.Kind {
+			return a.destination.Kind == domain.DestinationCity
+		}
+		return a.destination.Name < b.destination.Name
+	})
+
+	destinations := []*domain.Destination{}
+	for _, m := range matches {
+		if len(destinations) == limit {
+			break
+		}
+		destinations = append(destinations, m.destination)
+	}
+	return destinations, nil
+}
+
+func (r *embeddedDestinationRepository) FindByCode(ctx context.Context, code string) (*domain.Destination, error) {
+	return r.byCode[strings.ToUpper(strings.TrimSpace(code))], nil
+}
+
+// rank tells how well the normalized query matches the entry
+func (e catalogEntry) rank(query string) int {
+	if strings.EqualFold(e.destination.Code, query) {
+		return matchCode
+	}
+
+	best := noMatch
+	for _, name := range e.names {
+		switch {
+		case name == query:
+			return matchExact
+		case strings.HasPrefix(name, query):
+			best = min(best, matchPrefix)
+		case hasWordPrefix(name, query):
+			best = min(best, matchWordPrefix)
+		case strings.Contains(name, query):
+			best = min(best, matchContains)
+		}
+	}
+	return best
+}
+
+// hasWordPrefix reports whether a word of the name after the first starts with the query,
+// e.g. "guarulhos" in "sao paulo/guarulhos"
+func hasWordPrefix(name, query string) bool {
+	for i := 1; i < len(name); i++ {
+		if (name[i-1] == ' ' || name[i-1] == '/') && strings.HasPrefix(name[i:], query) {
+			return true
+		}
+	}
+	return false
+}
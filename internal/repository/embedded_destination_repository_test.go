@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmmmisss/api-viagens/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedDestinationRepository_Search(t *testing.T) {
+	repo, err := repository.NewEmbeddedDestinationRepository()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	codes := func(query string, limit int) []string {
+		destinations, err := repo.Search(ctx, query, limit)
+		require.NoError(t, err)
+		var codes []string
+		for _, destination := range destinations {
+			codes = append(codes, destination.Code)
+		}
+		return codes
+	}
+
+	t.Run("Ignores accents and case", func(t *testing.T) {
+		assert.Equal(t, "SAO", codes("SAO PAULO", 5)[0])
+		assert.Equal(t, "SAO", codes("são paulo", 5)[0])
+	})
+
+	t.Run("Finds aliases", func(t *testing.T) {
+		assert.Equal(t, "SAO", codes("sp", 5)[0])
+	})
+
+	t.Run("Code goes first", func(t *testing.T) {
+		assert.Equal(t, "GRU", codes("gru", 5)[0])
+	})
+
+	t.Run("Cities go before their airports", func(t *testing.T) {
+		found := codes("paris", 5)
+		require.NotEmpty(t, found)
+		assert.Equal(t, "PAR", found[0])
+		assert.Contains(t, found, "CDG")
+	})
+
+	t.Run("Respects the limit", func(t *testing.T) {
+		assert.Len(t, codes("a", 3), 3)
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		assert.Empty(t, codes("  ", 5))
+	})
+}
+
+func TestEmbeddedDestinationRepository_FindByCode(t *testing.T) {
+	repo, err := repository.NewEmbeddedDestinationRepository()
+	require.NoError(t, err)
+
+	destination, err := repo.FindByCode(context.Background(), "gru")
+	require.NoError(t, err)
+	require.NotNil(t, destination)
+	assert.Equal(t, "SAO", destination.ReferenceCode())
+
+	destination, err = repo.FindByCode(context.Background(), "XXX")
+	require.NoError(t, err)
+	assert.Nil(t, destination)
+}
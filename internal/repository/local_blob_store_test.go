@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := repository.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := "attachments/org/abc123"
+
+	t.Run("Stores and reads blobs", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, key, []byte("%PDF-1.4 receipt"), "application/pdf"))
+
+		content, err := store.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("%PDF-1.4 receipt"), content)
+	})
+
+	t.Run("Put replaces the blob", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, key, []byte("second"), "application/pdf"))
+
+		content, err := store.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("second"), content)
+	})
+
+	t.Run("Missing blobs are not found", func(t *testing.T) {
+		_, err := store.Get(ctx, "attachments/org/missing")
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+	})
+
+	t.Run("Deletes blobs", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, key))
+
+		_, err := store.Get(ctx, key)
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+		assert.NoError(t, store.Delete(ctx, key))
+	})
+
+	t.Run("Keys can't escape the directory", func(t *testing.T) {
+		assert.Error(t, store.Put(ctx, "../outside", []byte("x"), "application/pdf"))
+		_, err := store.Get(ctx, "attachments/../../etc/passwd")
+		assert.Error(t, err)
+	})
+
+	t.Run("Doesn't sign URLs", func(t *testing.T) {
+		_, ok := store.(domain.BlobURLSigner)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresAccountingExportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAccountingExportRepository(db *pgxpool.Pool) domain.AccountingExportRepository {
+	return &postgresAccountingExportRepository{db: db}
+}
+
+func (r *postgresAccountingExportRepository) ListExportableReports(ctx context.Context, from, to time.Time) ([]*domain.ExpenseReport, error) {
+	var reports []*domain.ExpenseReport
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + expenseReportColumns + ` FROM expense_reports
+				  WHERE status IN ('approved', 'reimbursed') AND reviewed_at >= $1 AND reviewed_at < $2
+				  AND ($3::uuid IS NULL OR org_id = $3)
+				  ORDER BY reviewed_at`
+		rows, err := tx.Query(ctx, query, from, to, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			report, err := scanExpenseReport(rows)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		if err := loadExpenseItems(ctx, tx, reports...); err != nil {
+			return err
+		}
+		return dropExportedItems(ctx, tx, reports)
+	})
+	return reports, err
+}
+
+// dropExportedItems removes from the reports the items some batch already exported
+func dropExportedItems(ctx context.Context, tx pgx.Tx, reports []*domain.ExpenseReport) error {
+	var ids []uuid.UUID
+	for _, report := range reports {
+		for _, item := range report.Items {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT expense_item_id FROM accounting_export_lines WHERE expense_item_id = ANY($1)
+								 AND ($2::uuid IS NULL OR org_id = $2)`, ids, tenantArg(ctx))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	exported := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		exported[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		items := report.Items[:0]
+		for _, item := range report.Items {
+			if !exported[item.ID] {
+				items = append(items, item)
+			}
+		}
+		report.Items = items
+	}
+	return nil
+}
+
+const accountingExportLineColumns = `expense_item_id, trip_id, traveler_id, destination, cost_center_code, gl_account, category,
+	expense_date, description, amount, currency`
+
+func (r *postgresAccountingExportRepository) CreateBatch(ctx context.Context, batch *domain.AccountingExportBatch) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO accounting_export_batches (id, org_id, period, created_by, created_at) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.Exec(ctx, query, batch.ID, batch.OrgID, batch.Period, batch.CreatedBy, batch.CreatedAt); err != nil {
+			return err
+		}
+
+		query = `INSERT INTO accounting_export_lines (org_id, batch_id, position, ` + accountingExportLineColumns + `)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+		for i, line := range batch.Lines {
+			_, err := tx.Exec(ctx, query, batch.OrgID, batch.ID, i, line.ExpenseItemID, line.TripID, line.TravelerID, line.Destination,
+				line.CostCenterCode, line.GLAccount, line.Category, line.Date, line.Description, line.Amount, line.Currency)
+			if err != nil {
+				return exportedError(err)
+			}
+		}
+		return nil
+	})
+}
+
+// exportedError turns a violation of the accounting_export_lines_item constraint into domain.ErrAlreadyExported
+func exportedError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "accounting_export_lines_item" {
+		return domain.ErrAlreadyExported
+	}
+	return err
+}
+
+func (r *postgresAccountingExportRepository) ListBatches(ctx context.Context) ([]*domain.AccountingExportBatch, error) {
+	var batches []*domain.AccountingExportBatch
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT b.id, b.org_id, b.period, b.created_by, b.created_at,
+				  (SELECT COUNT(*) FROM accounting_export_lines l WHERE l.batch_id = b.id)
+				  FROM accounting_export_batches b
+				  WHERE ($1::uuid IS NULL OR b.org_id = $1) ORDER BY b.created_at DESC`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var b domain.AccountingExportBatch
+			if err := rows.Scan(&b.ID, &b.OrgID, &b.Period, &b.CreatedBy, &b.CreatedAt, &b.LineCount); err != nil {
+				return err
+			}
+			batches = append(batches, &b)
+		}
+		return rows.Err()
+	})
+	return batches, err
+}
+
+func (r *postgresAccountingExportRepository) FindBatch(ctx context.Context, id uuid.UUID) (*domain.AccountingExportBatch, error) {
+	var batch *domain.AccountingExportBatch
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT id, org_id, period, created_by, created_at FROM accounting_export_batches
+				  WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var b domain.AccountingExportBatch
+		err := tx.QueryRow(ctx, query, id, tenantArg(ctx)).Scan(&b.ID, &b.OrgID, &b.Period, &b.CreatedBy, &b.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `SELECT `+accountingExportLineColumns+` FROM accounting_export_lines
+									 WHERE batch_id = $1 ORDER BY position`, b.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		b.Lines = []domain.AccountingExportLine{}
+		for rows.Next() {
+			var l domain.AccountingExportLine
+			if err := rows.Scan(&l.ExpenseItemID, &l.TripID, &l.TravelerID, &l.Destination, &l.CostCenterCode, &l.GLAccount,
+				&l.Category, &l.Date, &l.Description, &l.Amount, &l.Currency); err != nil {
+				return err
+			}
+			b.Lines = append(b.Lines, l)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		b.LineCount = len(b.Lines)
+		batch = &b
+		return nil
+	})
+	return batch, err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresApprovalPolicyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresApprovalPolicyRepository(db *pgxpool.Pool) domain.ApprovalPolicyRepository {
+	return &postgresApprovalPolicyRepository{db: db}
+}
+
+const approvalPolicyColumns = `id, org_id, name, priority, destinations, min_duration_days, steps, created_at, updated_at, min_budget, budget_currency, trip_type`
+
+func scanApprovalPolicy(row pgx.Row) (*domain.ApprovalPolicy, error) {
+	var policy domain.ApprovalPolicy
+	var steps []string
+	err := row.Scan(&policy.ID, &policy.OrgID, &policy.Name, &policy.Priority, &policy.Destinations,
+		&policy.MinDurationDays, &steps, &policy.CreatedAt, &policy.UpdatedAt, &policy.MinBudget, &policy.BudgetCurrency, &policy.TripType)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		policy.Steps = append(policy.Steps, domain.UserRole(step))
+	}
+	return &policy, nil
+}
+
+func (r *postgresApprovalPolicyRepository) Create(ctx context.Context, policy *domain.ApprovalPolicy) error {
+	steps := make([]string, len(policy.Steps))
+	for i, step := range policy.Steps {
+		steps[i] = string(step)
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO approval_policies (` + approvalPolicyColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+		_, err := tx.Exec(ctx, query, policy.ID, policy.OrgID, policy.Name, policy.Priority, textArray(policy.Destinations),
+			policy.MinDurationDays, steps, policy.CreatedAt, policy.UpdatedAt, policy.MinBudget, policy.BudgetCurrency, policy.TripType)
+		return err
+	})
+}
+
+func (r *postgresApprovalPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM approval_policies WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresApprovalPolicyRepository) List(ctx context.Context) ([]*domain.ApprovalPolicy, error) {
+	var policies []*domain.ApprovalPolicy
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + approvalPolicyColumns + ` FROM approval_policies
+				  WHERE ($1::uuid IS NULL OR org_id = $1) ORDER BY priority, name`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			policy, err := scanApprovalPolicy(rows)
+			if err != nil {
+				return err
+			}
+			policies = append(policies, policy)
+		}
+		return rows.Err()
+	})
+	return policies, err
+}
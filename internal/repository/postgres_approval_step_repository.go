@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresApprovalStepRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresApprovalStepRepository(db *pgxpool.Pool) domain.ApprovalStepRepository {
+	return &postgresApprovalStepRepository{db: db}
+}
+
+const approvalStepColumns = `id, org_id, trip_id, position, role, approver_id, status, decided_by, decided_at, reminded_at, escalated_at, created_at`
+
+func scanApprovalStep(row pgx.Row) (*domain.ApprovalStep, error) {
+	var step domain.ApprovalStep
+	err := row.Scan(&step.ID, &step.OrgID, &step.TripID, &step.Position, &step.Role, &step.ApproverID,
+		&step.Status, &step.DecidedBy, &step.DecidedAt, &step.RemindedAt, &step.EscalatedAt, &step.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+func (r *postgresApprovalStepRepository) CreateSteps(ctx context.Context, steps []*domain.ApprovalStep) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO trip_approval_steps (` + approvalStepColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+		for _, step := range steps {
+			_, err := tx.Exec(ctx, query, step.ID, step.OrgID, step.TripID, step.Position, step.Role, step.ApproverID,
+				step.Status, step.DecidedBy, step.DecidedAt, step.RemindedAt, step.EscalatedAt, step.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresApprovalStepRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.ApprovalStep, error) {
+	query := `SELECT ` + approvalStepColumns + ` FROM trip_approval_steps
+			  WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY position`
+	return r.list(ctx, query, tripID, tenantArg(ctx))
+}
+
+func (r *postgresApprovalStepRepository) UpdateDecision(ctx context.Context, step *domain.ApprovalStep) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		// Of concurrent decisions on a step, e.g. by a delegate and the approver, only the first one counts
+		query := `UPDATE trip_approval_steps SET status = $1, approver_id = $2, decided_by = $3, decided_at = $4
+				  WHERE id = $5 AND status = 'pending' AND ($6::uuid IS NULL OR org_id = $6)`
+		tag, err := tx.Exec(ctx, query, step.Status, step.ApproverID, step.DecidedBy, step.DecidedAt, step.ID, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrStepAlreadyDecided
+		}
+		return nil
+	})
+}
+
+func (r *postgresApprovalStepRepository) DeleteByTripID(ctx context.Context, tripID uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM trip_approval_steps WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, tripID, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresApprovalStepRepository) ListCurrent(ctx context.Context, approverID uuid.UUID, role domain.UserRole) ([]*domain.ApprovalStep, error) {
+	// The current step of a trip is its pending step with the lowest position
+	query := `SELECT ` + approvalStepColumns + ` FROM trip_approval_steps s
+			  WHERE s.status = 'pending'
+			    AND ($1::uuid IS NULL OR s.org_id = $1)
+			    AND NOT EXISTS (
+			        SELECT 1 FROM trip_approval_steps p
+			        WHERE p.trip_id = s.trip_id AND p.status = 'pending' AND p.position < s.position)
+			    AND (s.approver_id = $2 OR (s.approver_id IS NULL AND s.role = $3) OR $3 = 'admin')
+			  ORDER BY s.created_at`
+	return r.list(ctx, query, tenantArg(ctx), approverID, role)
+}
+
+func (r *postgresApprovalStepRepository) ListStale(ctx context.Context, waitingSince time.Time) ([]*domain.ApprovalStep, error) {
+	query := `SELECT ` + approvalStepColumns + ` FROM trip_approval_steps s
+			  WHERE s.status = 'pending'
+			    AND ($1::uuid IS NULL OR s.org_id = $1)
+			    AND NOT EXISTS (
+			        SELECT 1 FROM trip_approval_steps p
+			        WHERE p.trip_id = s.trip_id AND p.status = 'pending' AND p.position < s.position)
+			    AND EXISTS (SELECT 1 FROM trips t WHERE t.id = s.trip_id AND t.status = 'solicitado')
+			    AND COALESCE(s.escalated_at,
+			            (SELECT MAX(p.decided_at) FROM trip_approval_steps p
+			             WHERE p.trip_id = s.trip_id AND p.position < s.position),
+			            s.created_at) < $2
+			  ORDER BY s.created_at`
+	return r.list(ctx, query, tenantArg(ctx), waitingSince)
+}
+
+func (r *postgresApprovalStepRepository) UpdateEscalation(ctx context.Context, step *domain.ApprovalStep) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		// A decision taken meanwhile wins over the escalation
+		query := `UPDATE trip_approval_steps SET approver_id = $1, reminded_at = $2, escalated_at = $3
+				  WHERE id = $4 AND status = 'pending' AND ($5::uuid IS NULL OR org_id = $5)`
+		_, err := tx.Exec(ctx, query, step.ApproverID, step.RemindedAt, step.EscalatedAt, step.ID, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresApprovalStepRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.ApprovalStep, error) {
+	var steps []*domain.ApprovalStep
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			step, err := scanApprovalStep(rows)
+			if err != nil {
+				return err
+			}
+			steps = append(steps, step)
+		}
+		return rows.Err()
+	})
+	return steps, err
+}
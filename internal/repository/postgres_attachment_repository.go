@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresAttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAttachmentRepository(db *pgxpool.Pool) domain.AttachmentRepository {
+	return &postgresAttachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, org_id, trip_id, expense_item_id, uploaded_by, file_name, content_type, size, checksum, storage_key, created_at`
+
+func scanAttachment(row pgx.Row) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := row.Scan(&a.ID, &a.OrgID, &a.TripID, &a.ExpenseItemID, &a.UploadedBy, &a.FileName, &a.ContentType, &a.Size,
+		&a.Checksum, &a.StorageKey, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresAttachmentRepository) Create(ctx context.Context, a *domain.Attachment) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO attachments (` + attachmentColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+		_, err := tx.Exec(ctx, query, a.ID, a.OrgID, a.TripID, a.ExpenseItemID, a.UploadedBy, a.FileName, a.ContentType, a.Size,
+			a.Checksum, a.StorageKey, a.CreatedAt)
+		return err
+	})
+}
+
+func (r *postgresAttachmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	var attachment *domain.Attachment
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		attachment, err = scanAttachment(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return attachment, err
+}
+
+func (r *postgresAttachmentRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments
+			  WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY created_at`
+	return r.list(ctx, query, tripID, tenantArg(ctx))
+}
+
+func (r *postgresAttachmentRepository) FindByChecksum(ctx context.Context, checksum string) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments
+			  WHERE checksum = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY created_at`
+	return r.list(ctx, query, checksum, tenantArg(ctx))
+}
+
+func (r *postgresAttachmentRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.Attachment, error) {
+	var attachments []*domain.Attachment
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			attachment, err := scanAttachment(rows)
+			if err != nil {
+				return err
+			}
+			attachments = append(attachments, attachment)
+		}
+		return rows.Err()
+	})
+	return attachments, err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresBlackoutCalendarRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresBlackoutCalendarRepository(db *pgxpool.Pool) domain.BlackoutCalendarRepository {
+	return &postgresBlackoutCalendarRepository{db: db}
+}
+
+const blackoutCalendarColumns = `id, org_id, department_id, name, severity, periods, created_at, updated_at`
+
+func scanBlackoutCalendar(row pgx.Row) (*domain.BlackoutCalendar, error) {
+	var c domain.BlackoutCalendar
+	var periods []byte
+	err := row.Scan(&c.ID, &c.OrgID, &c.DepartmentID, &c.Name, &c.Severity, &periods, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(periods, &c.Periods); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *postgresBlackoutCalendarRepository) Create(ctx context.Context, c *domain.BlackoutCalendar) error {
+	periods, err := marshalBlackoutPeriods(c.Periods)
+	if err != nil {
+		return err
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO blackout_calendars (` + blackoutCalendarColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err := tx.Exec(ctx, query, c.ID, c.OrgID, c.DepartmentID, c.Name, c.Severity, periods, c.CreatedAt, c.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresBlackoutCalendarRepository) Update(ctx context.Context, c *domain.BlackoutCalendar) error {
+	periods, err := marshalBlackoutPeriods(c.Periods)
+	if err != nil {
+		return err
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE blackout_calendars SET department_id = $2, name = $3, severity = $4, periods = $5, updated_at = $6
+				  WHERE id = $1 AND ($7::uuid IS NULL OR org_id = $7)`
+		_, err := tx.Exec(ctx, query, c.ID, c.DepartmentID, c.Name, c.Severity, periods, c.UpdatedAt, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresBlackoutCalendarRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.BlackoutCalendar, error) {
+	var calendar *domain.BlackoutCalendar
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + blackoutCalendarColumns + ` FROM blackout_calendars WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		calendar, err = scanBlackoutCalendar(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return calendar, err
+}
+
+func (r *postgresBlackoutCalendarRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM blackout_calendars WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresBlackoutCalendarRepository) List(ctx context.Context) ([]*domain.BlackoutCalendar, error) {
+	var calendars []*domain.BlackoutCalendar
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + blackoutCalendarColumns + ` FROM blackout_calendars
+				  WHERE ($1::uuid IS NULL OR org_id = $1) ORDER BY name`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			calendar, err := scanBlackoutCalendar(rows)
+			if err != nil {
+				return err
+			}
+			calendars = append(calendars, calendar)
+		}
+		return rows.Err()
+	})
+	return calendars, err
+}
+
+// marshalBlackoutPeriods stores a calendar without periods as an empty array rather than null
+func marshalBlackoutPeriods(periods []domain.BlackoutPeriod) ([]byte, error) {
+	if periods == nil {
+		periods = []domain.BlackoutPeriod{}
+	}
+	return json.Marshal(periods)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresCardTransactionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCardTransactionRepository(db *pgxpool.Pool) domain.CardTransactionRepository {
+	return &postgresCardTransactionRepository{db: db}
+}
+
+const cardTransactionColumns = `id, org_id, cardholder_id, external_id, posted_on, merchant, amount, currency, converted, status,
+	trip_id, expense_item_id, note, reviewed_by, reviewed_at, created_at, updated_at`
+
+func scanCardTransaction(row pgx.Row) (*domain.CardTransaction, error) {
+	var t domain.CardTransaction
+	var converted []byte
+	err := row.Scan(&t.ID, &t.OrgID, &t.CardholderID, &t.ExternalID, &t.PostedOn, &t.Merchant, &t.Amount, &t.Currency, &converted,
+		&t.Status, &t.TripID, &t.ExpenseItemID, &t.Note, &t.ReviewedBy, &t.ReviewedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if converted != nil {
+		if err := json.Unmarshal(converted, &t.Converted); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (r *postgresCardTransactionRepository) Import(ctx context.Context, transactions []*domain.CardTransaction) ([]*domain.CardTransaction, error) {
+	var imported []*domain.CardTransaction
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO card_transactions (` + cardTransactionColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+				  ON CONFLICT ON CONSTRAINT card_transactions_external_id DO NOTHING`
+		for _, t := range transactions {
+			converted, err := marshalBudgetConversion(t.Converted)
+			if err != nil {
+				return err
+			}
+			tag, err := tx.Exec(ctx, query, t.ID, t.OrgID, t.CardholderID, t.ExternalID, t.PostedOn, t.Merchant, t.Amount, t.Currency,
+				converted, t.Status, t.TripID, t.ExpenseItemID, t.Note, t.ReviewedBy, t.ReviewedAt, t.CreatedAt, t.UpdatedAt)
+			if err != nil {
+				return matchedItemError(err)
+			}
+			if tag.RowsAffected() > 0 {
+				imported = append(imported, t)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+func (r *postgresCardTransactionRepository) Update(ctx context.Context, t *domain.CardTransaction) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE card_transactions SET status = $2, trip_id = $3, expense_item_id = $4, note = $5, reviewed_by = $6,
+				  reviewed_at = $7, updated_at = $8
+				  WHERE id = $1 AND ($9::uuid IS NULL OR org_id = $9)`
+		_, err := tx.Exec(ctx, query, t.ID, t.Status, t.TripID, t.ExpenseItemID, t.Note, t.ReviewedBy, t.ReviewedAt, t.UpdatedAt,
+			tenantArg(ctx))
+		return matchedItemError(err)
+	})
+}
+
+// matchedItemError turns a violation of the card_transactions_expense_item constraint into domain.ErrExpenseItemMatched
+func matchedItemError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "card_transactions_expense_item" {
+		return domain.ErrExpenseItemMatched
+	}
+	return err
+}
+
+func (r *postgresCardTransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CardTransaction, error) {
+	var transaction *domain.CardTransaction
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + cardTransactionColumns + ` FROM card_transactions WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		transaction, err = scanCardTransaction(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return transaction, err
+}
+
+func (r *postgresCardTransactionRepository) List(ctx context.Context, filter domain.CardTransactionFilter) ([]*domain.CardTransaction, error) {
+	var transactions []*domain.CardTransaction
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + cardTransactionColumns + ` FROM card_transactions
+				  WHERE ($1::text IS NULL OR status = $1) AND ($2::uuid IS NULL OR cardholder_id = $2)
+				  AND ($3::uuid IS NULL OR trip_id = $3) AND ($4::uuid IS NULL OR org_id = $4)
+				  ORDER BY posted_on, created_at`
+		rows, err := tx.Query(ctx, query, filter.Status, filter.CardholderID, filter.TripID, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			transaction, err := scanCardTransaction(rows)
+			if err != nil {
+				return err
+			}
+			transactions = append(transactions, transaction)
+		}
+		return rows.Err()
+	})
+	return transactions, err
+}
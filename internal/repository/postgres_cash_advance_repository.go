@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresCashAdvanceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCashAdvanceRepository(db *pgxpool.Pool) domain.CashAdvanceRepository {
+	return &postgresCashAdvanceRepository{db: db}
+}
+
+const cashAdvanceColumns = `id, org_id, trip_id, traveler_id, status, amount, currency, purpose, converted, rejection_reason,
+	reviewed_by, reviewed_at, paid_by, paid_at, settled_by, settled_at, created_at, updated_at`
+
+func scanCashAdvance(row pgx.Row) (*domain.CashAdvance, error) {
+	var a domain.CashAdvance
+	var converted []byte
+	err := row.Scan(&a.ID, &a.OrgID, &a.TripID, &a.TravelerID, &a.Status, &a.Amount, &a.Currency, &a.Purpose, &converted,
+		&a.RejectionReason, &a.ReviewedBy, &a.ReviewedAt, &a.PaidBy, &a.PaidAt, &a.SettledBy, &a.SettledAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if converted != nil {
+		if err := json.Unmarshal(converted, &a.Converted); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+func marshalBudgetConversion(conversion *domain.BudgetConversion) ([]byte, error) {
+	if conversion == nil {
+		return nil, nil
+	}
+	return json.Marshal(conversion)
+}
+
+func (r *postgresCashAdvanceRepository) Create(ctx context.Context, a *domain.CashAdvance) error {
+	converted, err := marshalBudgetConversion(a.Converted)
+	if err != nil {
+		return err
+	}
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO cash_advances (` + cashAdvanceColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+		_, err := tx.Exec(ctx, query, a.ID, a.OrgID, a.TripID, a.TravelerID, a.Status, a.Amount, a.Currency, a.Purpose, converted,
+			a.RejectionReason, a.ReviewedBy, a.ReviewedAt, a.PaidBy, a.PaidAt, a.SettledBy, a.SettledAt, a.CreatedAt, a.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresCashAdvanceRepository) Update(ctx context.Context, a *domain.CashAdvance, from domain.CashAdvanceStatus) error {
+	converted, err := marshalBudgetConversion(a.Converted)
+	if err != nil {
+		return err
+	}
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE cash_advances SET status = $2, converted = $3, rejection_reason = $4, reviewed_by = $5, reviewed_at = $6,
+				  paid_by = $7, paid_at = $8, settled_by = $9, settled_at = $10, updated_at = $11
+				  WHERE id = $1 AND status = $13 AND ($12::uuid IS NULL OR org_id = $12)`
+		tag, err := tx.Exec(ctx, query, a.ID, a.Status, converted, a.RejectionReason, a.ReviewedBy, a.ReviewedAt, a.PaidBy, a.PaidAt,
+			a.SettledBy, a.SettledAt, a.UpdatedAt, tenantArg(ctx), from)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrCashAdvanceStatusChanged
+		}
+		return nil
+	})
+}
+
+func (r *postgresCashAdvanceRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CashAdvance, error) {
+	var advance *domain.CashAdvance
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + cashAdvanceColumns + ` FROM cash_advances WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		advance, err = scanCashAdvance(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return advance, err
+}
+
+func (r *postgresCashAdvanceRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.CashAdvance, error) {
+	query := `SELECT ` + cashAdvanceColumns + ` FROM cash_advances
+			  WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY created_at`
+	return r.list(ctx, query, tripID, tenantArg(ctx))
+}
+
+func (r *postgresCashAdvanceRepository) List(ctx context.Context, status *domain.CashAdvanceStatus) ([]*domain.CashAdvance, error) {
+	query := `SELECT ` + cashAdvanceColumns + ` FROM cash_advances
+			  WHERE ($1::text IS NULL OR status = $1) AND ($2::uuid IS NULL OR org_id = $2) ORDER BY created_at`
+	return r.list(ctx, query, status, tenantArg(ctx))
+}
+
+func (r *postgresCashAdvanceRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.CashAdvance, error) {
+	var advances []*domain.CashAdvance
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			advance, err := scanCashAdvance(rows)
+			if err != nil {
+				return err
+			}
+			advances = append(advances, advance)
+		}
+		return rows.Err()
+	})
+	return advances, err
+}
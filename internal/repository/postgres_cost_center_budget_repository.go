@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresCostCenterBudgetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCostCenterBudgetRepository(db *pgxpool.Pool) domain.CostCenterBudgetRepository {
+	return &postgresCostCenterBudgetRepository{db: db}
+}
+
+const costCenterBudgetColumns = `id, org_id, cost_center_id, period_start, period_end, amount, currency, severity, created_at, updated_at`
+
+func scanCostCenterBudget(row pgx.Row) (*domain.CostCenterBudget, error) {
+	var b domain.CostCenterBudget
+	err := row.Scan(&b.ID, &b.OrgID, &b.CostCenterID, &b.PeriodStart, &b.PeriodEnd, &b.Amount, &b.Currency,
+		&b.Severity, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *postgresCostCenterBudgetRepository) Create(ctx context.Context, b *domain.CostCenterBudget) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO cost_center_budgets (` + costCenterBudgetColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		_, err := tx.Exec(ctx, query, b.ID, b.OrgID, b.CostCenterID, b.PeriodStart, b.PeriodEnd, b.Amount, b.Currency,
+			b.Severity, b.CreatedAt, b.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresCostCenterBudgetRepository) Update(ctx context.Context, b *domain.CostCenterBudget) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE cost_center_budgets SET period_start = $2, period_end = $3, amount = $4, severity = $5, updated_at = $6
+				  WHERE id = $1 AND ($7::uuid IS NULL OR org_id = $7)`
+		_, err := tx.Exec(ctx, query, b.ID, b.PeriodStart, b.PeriodEnd, b.Amount, b.Severity, b.UpdatedAt, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresCostCenterBudgetRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CostCenterBudget, error) {
+	var budget *domain.CostCenterBudget
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + costCenterBudgetColumns + ` FROM cost_center_budgets WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		budget, err = scanCostCenterBudget(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return budget, err
+}
+
+func (r *postgresCostCenterBudgetRepository) ListByCostCenter(ctx context.Context, costCenterID *uuid.UUID) ([]*domain.CostCenterBudget, error) {
+	var budgets []*domain.CostCenterBudget
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + costCenterBudgetColumns + ` FROM cost_center_budgets
+				  WHERE ($1::uuid IS NULL OR cost_center_id = $1) AND ($2::uuid IS NULL OR org_id = $2)
+				  ORDER BY period_start DESC`
+		rows, err := tx.Query(ctx, query, costCenterID, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			budget, err := scanCostCenterBudget(rows)
+			if err != nil {
+				return err
+			}
+			budgets = append(budgets, budget)
+		}
+		return rows.Err()
+	})
+	return budgets, err
+}
+
+func (r *postgresCostCenterBudgetRepository) Lock(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT id FROM cost_center_budgets WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2) FOR UPDATE`
+		_, err := tx.Exec(ctx, query, id, tenantArg(ctx))
+		return err
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresCostCenterRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCostCenterRepository(db *pgxpool.Pool) domain.CostCenterRepository {
+	return &postgresCostCenterRepository{db: db}
+}
+
+const costCenterColumns = `id, org_id, code, name, active, created_at, updated_at`
+
+func scanCostCenter(row pgx.Row) (*domain.CostCenter, error) {
+	var costCenter domain.CostCenter
+	err := row.Scan(&costCenter.ID, &costCenter.OrgID, &costCenter.Code, &costCenter.Name,
+		&costCenter.Active, &costCenter.CreatedAt, &costCenter.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+func (r *postgresCostCenterRepository) Create(ctx context.Context, costCenter *domain.CostCenter) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO cost_centers (id, org_id, code, name, active, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := tx.Exec(ctx, query, costCenter.ID, costCenter.OrgID, costCenter.Code, costCenter.Name,
+			costCenter.Active, costCenter.CreatedAt, costCenter.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresCostCenterRepository) Update(ctx context.Context, costCenter *domain.CostCenter) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE cost_centers SET code = $1, name = $2, active = $3, updated_at = $4
+				  WHERE id = $5 AND ($6::uuid IS NULL OR org_id = $6)`
+		_, err := tx.Exec(ctx, query, costCenter.Code, costCenter.Name, costCenter.Active, costCenter.UpdatedAt,
+			costCenter.ID, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresCostCenterRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CostCenter, error) {
+	var costCenter *domain.CostCenter
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + costCenterColumns + ` FROM cost_centers WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		costCenter, err = scanCostCenter(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return costCenter, err
+}
+
+func (r *postgresCostCenterRepository) List(ctx context.Context) ([]*domain.CostCenter, error) {
+	var costCenters []*domain.CostCenter
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + costCenterColumns + ` FROM cost_centers WHERE ($1::uuid IS NULL OR org_id = $1) ORDER BY code`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			costCenter, err := scanCostCenter(rows)
+			if err != nil {
+				return err
+			}
+			costCenters = append(costCenters, costCenter)
+		}
+		return rows.Err()
+	})
+	return costCenters, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresDelegationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDelegationRepository(db *pgxpool.Pool) domain.DelegationRepository {
+	return &postgresDelegationRepository{db: db}
+}
+
+const delegationColumns = `id, org_id, delegator_id, delegate_id, starts_at, ends_at, cost_center_id, created_at`
+
+func scanDelegation(row pgx.Row) (*domain.Delegation, error) {
+	var d domain.Delegation
+	err := row.Scan(&d.ID, &d.OrgID, &d.DelegatorID, &d.DelegateID, &d.StartsAt, &d.EndsAt, &d.CostCenterID, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *postgresDelegationRepository) Create(ctx context.Context, d *domain.Delegation) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO approval_delegations (` + delegationColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err := tx.Exec(ctx, query, d.ID, d.OrgID, d.DelegatorID, d.DelegateID, d.StartsAt, d.EndsAt, d.CostCenterID, d.CreatedAt)
+		return err
+	})
+}
+
+func (r *postgresDelegationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Delegation, error) {
+	var delegation *domain.Delegation
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + delegationColumns + ` FROM approval_delegations WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		delegation, err = scanDelegation(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return delegation, err
+}
+
+func (r *postgresDelegationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM approval_delegations WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresDelegationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Delegation, error) {
+	query := `SELECT ` + delegationColumns + ` FROM approval_delegations
+			  WHERE (delegator_id = $1 OR delegate_id = $1) AND ($2::uuid IS NULL OR org_id = $2)
+			  ORDER BY starts_at DESC`
+	return r.list(ctx, query, userID, tenantArg(ctx))
+}
+
+func (r *postgresDelegationRepository) ListActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]*domain.Delegation, error) {
+	query := `SELECT ` + delegationColumns + ` FROM approval_delegations
+			  WHERE delegate_id = $1 AND starts_at <= $2 AND ends_at > $2 AND ($3::uuid IS NULL OR org_id = $3)
+			  ORDER BY starts_at`
+	return r.list(ctx, query, delegateID, at, tenantArg(ctx))
+}
+
+func (r *postgresDelegationRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.Delegation, error) {
+	var delegations []*domain.Delegation
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			delegation, err := scanDelegation(rows)
+			if err != nil {
+				return err
+			}
+			delegations = append(delegations, delegation)
+		}
+		return rows.Err()
+	})
+	return delegations, err
+}
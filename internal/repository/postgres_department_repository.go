@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresDepartmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDepartmentRepository(db *pgxpool.Pool) domain.DepartmentRepository {
+	return &postgresDepartmentRepository{db: db}
+}
+
+const departmentColumns = `id, org_id, name, cost_center_id, created_at, updated_at`
+
+func scanDepartment(row pgx.Row) (*domain.Department, error) {
+	var department domain.Department
+	err := row.Scan(&department.ID, &department.OrgID, &department.Name, &department.CostCenterID,
+		&department.CreatedAt, &department.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (r *postgresDepartmentRepository) Create(ctx context.Context, department *domain.Department) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO departments (id, org_id, name, cost_center_id, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6)`
+		_, err := tx.Exec(ctx, query, department.ID, department.OrgID, department.Name, department.CostCenterID,
+			department.CreatedAt, department.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresDepartmentRepository) Update(ctx context.Context, department *domain.Department) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE departments SET name = $1, cost_center_id = $2, updated_at = $3
+				  WHERE id = $4 AND ($5::uuid IS NULL OR org_id = $5)`
+		_, err := tx.Exec(ctx, query, department.Name, department.CostCenterID, department.UpdatedAt,
+			department.ID, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresDepartmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Department, error) {
+	var department *domain.Department
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + departmentColumns + ` FROM departments WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		department, err = scanDepartment(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return department, err
+}
+
+func (r *postgresDepartmentRepository) List(ctx context.Context) ([]*domain.Department, error) {
+	var departments []*domain.Department
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + departmentColumns + ` FROM departments WHERE ($1::uuid IS NULL OR org_id = $1) ORDER BY name`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			department, err := scanDepartment(rows)
+			if err != nil {
+				return err
+			}
+			departments = append(departments, department)
+		}
+		return rows.Err()
+	})
+	return departments, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresExchangeRateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresExchangeRateRepository(db *pgxpool.Pool) domain.ExchangeRateRepository {
+	return &postgresExchangeRateRepository{db: db}
+}
+
+const exchangeRateColumns = `id, org_id, currency, base_currency, rate, effective_date, created_at`
+
+func scanExchangeRate(row pgx.Row) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := row.Scan(&rate.ID, &rate.OrgID, &rate.Currency, &rate.BaseCurrency, &rate.Rate, &rate.EffectiveDate, &rate.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *postgresExchangeRateRepository) Save(ctx context.Context, rates []*domain.ExchangeRate) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO exchange_rates (` + exchangeRateColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)
+				  ON CONFLICT (org_id, currency, base_currency, effective_date)
+				  DO UPDATE SET rate = EXCLUDED.rate, created_at = EXCLUDED.created_at
+				  RETURNING id`
+		for _, rate := range rates {
+			// A replaced rate keeps the ID it was first saved with
+			err := tx.QueryRow(ctx, query, rate.ID, rate.OrgID, rate.Currency, rate.BaseCurrency, rate.Rate,
+				rate.EffectiveDate, rate.CreatedAt).Scan(&rate.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresExchangeRateRepository) FindEffective(ctx context.Context, currency, baseCurrency string, at time.Time) (*domain.ExchangeRate, error) {
+	var rate *domain.ExchangeRate
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + exchangeRateColumns + ` FROM exchange_rates
+				  WHERE currency = $1 AND base_currency = $2 AND effective_date <= $3::date
+				  AND ($4::uuid IS NULL OR org_id = $4)
+				  ORDER BY effective_date DESC LIMIT 1`
+		var err error
+		rate, err = scanExchangeRate(tx.QueryRow(ctx, query, currency, baseCurrency, at.Format("2006-01-02"), tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return rate, err
+}
+
+func (r *postgresExchangeRateRepository) List(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	var rates []*domain.ExchangeRate
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + exchangeRateColumns + ` FROM exchange_rates
+				  WHERE ($1::uuid IS NULL OR org_id = $1)
+				  ORDER BY currency, effective_date DESC`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			rate, err := scanExchangeRate(rows)
+			if err != nil {
+				return err
+			}
+			rates = append(rates, rate)
+		}
+		return rows.Err()
+	})
+	return rates, err
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresExpenseReportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresExpenseReportRepository(db *pgxpool.Pool) domain.ExpenseReportRepository {
+	return &postgresExpenseReportRepository{db: db}
+}
+
+const expenseReportColumns = `id, org_id, trip_id, traveler_id, status, currency, rejection_reason, submitted_at, reviewed_by, reviewed_at,
+	reimbursed_by, reimbursed_at, created_at, updated_at`
+
+func scanExpenseReport(row pgx.Row) (*domain.ExpenseReport, error) {
+	var r domain.ExpenseReport
+	err := row.Scan(&r.ID, &r.OrgID, &r.TripID, &r.TravelerID, &r.Status, &r.Currency, &r.RejectionReason, &r.SubmittedAt,
+		&r.ReviewedBy, &r.ReviewedAt, &r.ReimbursedBy, &r.ReimbursedAt, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.Items = []domain.ExpenseItem{}
+	return &r, nil
+}
+
+func (r *postgresExpenseReportRepository) Create(ctx context.Context, report *domain.ExpenseReport) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO expense_reports (` + expenseReportColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+		_, err := tx.Exec(ctx, query, report.ID, report.OrgID, report.TripID, report.TravelerID, report.Status, report.Currency,
+			report.RejectionReason, report.SubmittedAt, report.ReviewedBy, report.ReviewedAt, report.ReimbursedBy, report.ReimbursedAt,
+			report.CreatedAt, report.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return insertExpenseItems(ctx, tx, report)
+	})
+}
+
+func (r *postgresExpenseReportRepository) Update(ctx context.Context, report *domain.ExpenseReport, from domain.ExpenseReportStatus) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE expense_reports SET status = $2, rejection_reason = $3, submitted_at = $4, reviewed_by = $5, reviewed_at = $6,
+				  reimbursed_by = $7, reimbursed_at = $8, updated_at = $9
+				  WHERE id = $1 AND status = $11 AND ($10::uuid IS NULL OR org_id = $10)`
+		tag, err := tx.Exec(ctx, query, report.ID, report.Status, report.RejectionReason, report.SubmittedAt, report.ReviewedBy,
+			report.ReviewedAt, report.ReimbursedBy, report.ReimbursedAt, report.UpdatedAt, tenantArg(ctx), from)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrExpenseReportStatusChanged
+		}
+
+		// Card transactions paying for items the report no longer has go back
+		// to review. The items kept are inserted again with the same IDs.
+		keptIDs := make([]uuid.UUID, 0, len(report.Items))
+		for _, item := range report.Items {
+			keptIDs = append(keptIDs, item.ID)
+		}
+		query = `UPDATE card_transactions SET status = $3, expense_item_id = NULL, reviewed_by = NULL, reviewed_at = NULL, updated_at = $4
+				 WHERE expense_item_id IN (SELECT id FROM expense_items WHERE report_id = $1) AND NOT (expense_item_id = ANY($2))
+				 AND ($5::uuid IS NULL OR org_id = $5)`
+		if _, err := tx.Exec(ctx, query, report.ID, keptIDs, domain.CardUnmatched, time.Now(), tenantArg(ctx)); err != nil {
+			return err
+		}
+
+		// The items are replaced as a whole
+		if _, err := tx.Exec(ctx, `DELETE FROM expense_items WHERE report_id = $1 AND ($2::uuid IS NULL OR org_id = $2)`, report.ID, tenantArg(ctx)); err != nil {
+			return err
+		}
+		return insertExpenseItems(ctx, tx, report)
+	})
+}
+
+func (r *postgresExpenseReportRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) (*domain.ExpenseReport, error) {
+	var report *domain.ExpenseReport
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + expenseReportColumns + ` FROM expense_reports WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		report, err = scanExpenseReport(tx.QueryRow(ctx, query, tripID, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		if err != nil {
+			return err
+		}
+		return loadExpenseItems(ctx, tx, report)
+	})
+	return report, err
+}
+
+func (r *postgresExpenseReportRepository) ListByTripIDs(ctx context.Context, tripIDs []uuid.UUID) ([]*domain.ExpenseReport, error) {
+	var reports []*domain.ExpenseReport
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + expenseReportColumns + ` FROM expense_reports WHERE trip_id = ANY($1) AND ($2::uuid IS NULL OR org_id = $2)`
+		rows, err := tx.Query(ctx, query, tripIDs, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			report, err := scanExpenseReport(rows)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		return loadExpenseItems(ctx, tx, reports...)
+	})
+	return reports, err
+}
+
+func (r *postgresExpenseReportRepository) List(ctx context.Context, status *domain.ExpenseReportStatus) ([]*domain.ExpenseReport, error) {
+	var reports []*domain.ExpenseReport
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + expenseReportColumns + ` FROM expense_reports
+				  WHERE ($1::text IS NULL OR status = $1) AND ($2::uuid IS NULL OR org_id = $2)
+				  ORDER BY COALESCE(submitted_at, created_at)`
+		rows, err := tx.Query(ctx, query, status, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			report, err := scanExpenseReport(rows)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		return loadExpenseItems(ctx, tx, reports...)
+	})
+	return reports, err
+}
+
+const expenseItemColumns = `id, org_id, report_id, position, expense_date, category, amount, currency, description, receipt_ref, converted`
+
+func insertExpenseItems(ctx context.Context, tx pgx.Tx, report *domain.ExpenseReport) error {
+	query := `INSERT INTO expense_items (` + expenseItemColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	for i, item := range report.Items {
+		var converted []byte
+		if item.Converted != nil {
+			var err error
+			if converted, err = json.Marshal(item.Converted); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, query, item.ID, report.OrgID, report.ID, i, item.Date, item.Category, item.Amount, item.Currency,
+			item.Description, item.ReceiptRef, converted)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadExpenseItems fills in the items of the reports with a single query
+func loadExpenseItems(ctx context.Context, tx pgx.Tx, reports ...*domain.ExpenseReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.ExpenseReport, len(reports))
+	ids := make([]uuid.UUID, 0, len(reports))
+	for _, report := range reports {
+		byID[report.ID] = report
+		ids = append(ids, report.ID)
+	}
+
+	query := `SELECT id, report_id, expense_date, category, amount, currency, description, receipt_ref, converted
+			  FROM expense_items WHERE report_id = ANY($1) ORDER BY report_id, position`
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.ExpenseItem
+		var reportID uuid.UUID
+		var converted []byte
+		if err := rows.Scan(&item.ID, &reportID, &item.Date, &item.Category, &item.Amount, &item.Currency, &item.Description,
+			&item.ReceiptRef, &converted); err != nil {
+			return err
+		}
+		if converted != nil {
+			if err := json.Unmarshal(converted, &item.Converted); err != nil {
+				return err
+			}
+		}
+		if report := byID[reportID]; report != nil {
+			report.Items = append(report.Items, item)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresGLAccountRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresGLAccountRepository(db *pgxpool.Pool) domain.GLAccountRepository {
+	return &postgresGLAccountRepository{db: db}
+}
+
+func (r *postgresGLAccountRepository) Save(ctx context.Context, account *domain.GLAccount) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO gl_accounts (org_id, category, account, updated_at) VALUES ($1, $2, $3, $4)
+				  ON CONFLICT (org_id, category) DO UPDATE SET account = EXCLUDED.account, updated_at = EXCLUDED.updated_at`
+		_, err := tx.Exec(ctx, query, account.OrgID, account.Category, account.Account, account.UpdatedAt)
+		return err
+	})
+}
+
+func (r *postgresGLAccountRepository) List(ctx context.Context) ([]*domain.GLAccount, error) {
+	var accounts []*domain.GLAccount
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT org_id, category, account, updated_at FROM gl_accounts
+				  WHERE ($1::uuid IS NULL OR org_id = $1) ORDER BY category`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var account domain.GLAccount
+			if err := rows.Scan(&account.OrgID, &account.Category, &account.Account, &account.UpdatedAt); err != nil {
+				return err
+			}
+			accounts = append(accounts, &account)
+		}
+		return rows.Err()
+	})
+	return accounts, err
+}
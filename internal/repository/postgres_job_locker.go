@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// postgresJobLocker uses session-level advisory locks. They belong to the
+// connection that took them, so the connection is held until the lock is released
+// and a replica that dies mid-job gives its locks back when its connections close.
+type postgresJobLocker struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresJobLocker(db *pgxpool.Pool) domain.JobLocker {
+	return &postgresJobLocker{db: db}
+}
+
+func (l *postgresJobLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	release := func() {
+		// ctx may already be canceled by a shutdown, the lock must go back anyway
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+			// Closing the connection drops every lock it holds
+			log.Printf("could not release lock %s: %v", name, err)
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+	return release, true, nil
+}
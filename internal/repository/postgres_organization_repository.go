@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresOrganizationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOrganizationRepository(db *pgxpool.Pool) domain.OrganizationRepository {
+	return &postgresOrganizationRepository{db: db}
+}
+
+func (r *postgresOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	query := `SELECT id, name, slug, created_at, updated_at, base_currency, allowed_email_domains FROM organizations WHERE id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+func (r *postgresOrganizationRepository) FindBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	query := `SELECT id, name, slug, created_at, updated_at, base_currency, allowed_email_domains FROM organizations WHERE slug = $1`
+	return r.findOne(ctx, query, slug)
+}
+
+func (r *postgresOrganizationRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.QueryRow(ctx, query, arg).Scan(&org.ID, &org.Name, &org.Slug, &org.CreatedAt, &org.UpdatedAt, &org.BaseCurrency,
+		&org.AllowedEmailDomains)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &org, nil
+}
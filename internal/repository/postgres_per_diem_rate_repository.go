@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresPerDiemRateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPerDiemRateRepository(db *pgxpool.Pool) domain.PerDiemRateRepository {
+	return &postgresPerDiemRateRepository{db: db}
+}
+
+const perDiemRateColumns = `id, org_id, country_code, city_code, currency, daily_amount, partial_day_percent,
+	breakfast_percent, lunch_percent, dinner_percent, created_at, updated_at`
+
+func scanPerDiemRate(row pgx.Row) (*domain.PerDiemRate, error) {
+	var rate domain.PerDiemRate
+	err := row.Scan(&rate.ID, &rate.OrgID, &rate.CountryCode, &rate.CityCode, &rate.Currency, &rate.DailyAmount,
+		&rate.PartialDayPercent, &rate.BreakfastPercent, &rate.LunchPercent, &rate.DinnerPercent, &rate.CreatedAt, &rate.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *postgresPerDiemRateRepository) Save(ctx context.Context, rates []*domain.PerDiemRate) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO per_diem_rates (` + perDiemRateColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				  ON CONFLICT (org_id, country_code, city_code)
+				  DO UPDATE SET currency = EXCLUDED.currency, daily_amount = EXCLUDED.daily_amount,
+				      partial_day_percent = EXCLUDED.partial_day_percent, breakfast_percent = EXCLUDED.breakfast_percent,
+				      lunch_percent = EXCLUDED.lunch_percent, dinner_percent = EXCLUDED.dinner_percent, updated_at = EXCLUDED.updated_at
+				  RETURNING id, created_at`
+		for _, rate := range rates {
+			// A replaced rate keeps the ID and creation date it was first saved with
+			err := tx.QueryRow(ctx, query, rate.ID, rate.OrgID, rate.CountryCode, rate.CityCode, rate.Currency, rate.DailyAmount,
+				rate.PartialDayPercent, rate.BreakfastPercent, rate.LunchPercent, rate.DinnerPercent, rate.CreatedAt, rate.UpdatedAt,
+			).Scan(&rate.ID, &rate.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresPerDiemRateRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.PerDiemRate, error) {
+	var rate *domain.PerDiemRate
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + perDiemRateColumns + ` FROM per_diem_rates WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		rate, err = scanPerDiemRate(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		return err
+	})
+	return rate, err
+}
+
+func (r *postgresPerDiemRateRepository) List(ctx context.Context) ([]*domain.PerDiemRate, error) {
+	var rates []*domain.PerDiemRate
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + perDiemRateColumns + ` FROM per_diem_rates WHERE ($1::uuid IS NULL OR org_id = $1)
+				  ORDER BY country_code, city_code`
+		rows, err := tx.Query(ctx, query, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			rate, err := scanPerDiemRate(rows)
+			if err != nil {
+				return err
+			}
+			rates = append(rates, rate)
+		}
+		return rows.Err()
+	})
+	return rates, err
+}
+
+func (r *postgresPerDiemRateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM per_diem_rates WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`, id, tenantArg(ctx))
+		return err
+	})
+}
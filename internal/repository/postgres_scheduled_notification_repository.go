@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresScheduledNotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresScheduledNotificationRepository(db *pgxpool.Pool) domain.ScheduledNotificationRepository {
+	return &postgresScheduledNotificationRepository{db: db}
+}
+
+const scheduledNotificationColumns = `id, org_id, recipient_id, trip_id, template, dedupe_key, due_at, sent_at, created_at`
+
+func (r *postgresScheduledNotificationRepository) Schedule(ctx context.Context, notification *domain.ScheduledNotification) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		// Notifications already sent keep their key so they aren't sent twice
+		query := `INSERT INTO scheduled_notifications (` + scheduledNotificationColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				  ON CONFLICT (org_id, dedupe_key) DO UPDATE
+				  SET recipient_id = EXCLUDED.recipient_id, due_at = EXCLUDED.due_at
+				  WHERE scheduled_notifications.sent_at IS NULL`
+		_, err := tx.Exec(ctx, query, notification.ID, notification.OrgID, notification.RecipientID, notification.TripID,
+			notification.Template, notification.DedupeKey, notification.DueAt, notification.SentAt, notification.CreatedAt)
+		return err
+	})
+}
+
+func (r *postgresScheduledNotificationRepository) DeletePendingByTripID(ctx context.Context, tripID uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM scheduled_notifications
+				  WHERE trip_id = $1 AND sent_at IS NULL AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, tripID, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresScheduledNotificationRepository) ListDue(ctx context.Context, at time.Time, limit int) ([]*domain.ScheduledNotification, error) {
+	var notifications []*domain.ScheduledNotification
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + scheduledNotificationColumns + ` FROM scheduled_notifications
+				  WHERE sent_at IS NULL AND due_at <= $1 AND ($2::uuid IS NULL OR org_id = $2)
+				  ORDER BY due_at LIMIT $3`
+		rows, err := tx.Query(ctx, query, at, tenantArg(ctx), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var n domain.ScheduledNotification
+			if err := rows.Scan(&n.ID, &n.OrgID, &n.RecipientID, &n.TripID, &n.Template, &n.DedupeKey,
+				&n.DueAt, &n.SentAt, &n.CreatedAt); err != nil {
+				return err
+			}
+			notifications = append(notifications, &n)
+		}
+		return rows.Err()
+	})
+	return notifications, err
+}
+
+func (r *postgresScheduledNotificationRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE scheduled_notifications SET sent_at = $1 WHERE id = $2 AND ($3::uuid IS NULL OR org_id = $3)`
+		_, err := tx.Exec(ctx, query, sentAt, id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresScheduledNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM scheduled_notifications WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		_, err := tx.Exec(ctx, query, id, tenantArg(ctx))
+		return err
+	})
+}
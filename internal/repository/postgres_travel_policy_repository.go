@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresTravelPolicyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTravelPolicyRepository(db *pgxpool.Pool) domain.TravelPolicyRepository {
+	return &postgresTravelPolicyRepository{db: db}
+}
+
+func (r *postgresTravelPolicyRepository) Find(ctx context.Context) (*domain.TravelPolicy, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	var policy *domain.TravelPolicy
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT org_id, rules, cancellation, updated_at FROM travel_policies WHERE org_id = $1`
+		var p domain.TravelPolicy
+		var rules, cancellation []byte
+		err := tx.QueryRow(ctx, query, orgID).Scan(&p.OrgID, &rules, &cancellation, &p.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // No policy configured
+		}
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(rules, &p.Rules); err != nil {
+			return err
+		}
+		if cancellation != nil {
+			if err := json.Unmarshal(cancellation, &p.Cancellation); err != nil {
+				return err
+			}
+		}
+		policy = &p
+		return nil
+	})
+	return policy, err
+}
+
+func (r *postgresTravelPolicyRepository) Save(ctx context.Context, policy *domain.TravelPolicy) error {
+	rules, err := json.Marshal(policy.Rules)
+	if err != nil {
+		return err
+	}
+	var cancellation []byte
+	if policy.Cancellation != nil {
+		if cancellation, err = json.Marshal(policy.Cancellation); err != nil {
+			return err
+		}
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO travel_policies (org_id, rules, cancellation, updated_at) VALUES ($1, $2, $3, $4)
+				  ON CONFLICT (org_id) DO UPDATE
+				  SET rules = EXCLUDED.rules, cancellation = EXCLUDED.cancellation, updated_at = EXCLUDED.updated_at`
+		_, err := tx.Exec(ctx, query, policy.OrgID, rules, cancellation, policy.UpdatedAt)
+		return err
+	})
+}
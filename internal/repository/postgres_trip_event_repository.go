@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type postgresTripEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTripEventRepository(db *pgxpool.Pool) domain.TripEventRepository {
+	return &postgresTripEventRepository{db: db}
+}
+
+const tripEventColumns = `id, org_id, trip_id, actor_id, action, on_behalf_of_id, justification, created_at`
+
+func (r *postgresTripEventRepository) Create(ctx context.Context, event *domain.TripEvent) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO trip_events (` + tripEventColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err := tx.Exec(ctx, query, event.ID, event.OrgID, event.TripID, event.ActorID, event.Action,
+			event.OnBehalfOfID, event.Justification, event.CreatedAt)
+		return err
+	})
+}
+
+func (r *postgresTripEventRepository) ListByTripID(ctx context.Context, tripID uuid.UUID) ([]*domain.TripEvent, error) {
+	var events []*domain.TripEvent
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + tripEventColumns + ` FROM trip_events
+				  WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY created_at`
+		rows, err := tx.Query(ctx, query, tripID, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var event domain.TripEvent
+			if err := rows.Scan(&event.ID, &event.OrgID, &event.TripID, &event.ActorID, &event.Action,
+				&event.OnBehalfOfID, &event.Justification, &event.CreatedAt); err != nil {
+				return err
+			}
+			events = append(events, &event)
+		}
+		return rows.Err()
+	})
+	return events, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jimmmmisss/api-viagens/internal/domain"
 )
@@ -21,53 +23,194 @@ func NewPostgresTripRepository(db *pgxpool.Pool) domain.TripRepository {
 	return &postgresTripRepository{db: db}
 }
 
-func (r *postgresTripRepository) Create(ctx context.Context, trip *domain.Trip) error {
-	query := `INSERT INTO trips (id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := r.db.Exec(ctx, query, trip.ID, trip.RequesterID, trip.Destination, trip.StartDate, trip.EndDate, trip.Status, trip.CreatedAt, trip.UpdatedAt)
-	return err
-}
+const tripColumns = `id, org_id, requester_id, cost_center_id, trip_type, destination, destination_code, start_date, end_date, status, policy_warnings, created_at, updated_at, time_zone, budget, per_diem`
 
-func (r *postgresTripRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error) {
-	query := `SELECT id, requester_id, destination, start_date, end_date, status, created_at, updated_at
-			  FROM trips WHERE id = $1`
+func scanTrip(row pgx.Row) (*domain.Trip, error) {
 	var trip domain.Trip
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&trip.ID, &trip.RequesterID, &trip.Destination, &trip.StartDate,
-		&trip.EndDate, &trip.Status, &trip.CreatedAt, &trip.UpdatedAt,
+	var budget, perDiem []byte
+	err := row.Scan(
+		&trip.ID, &trip.OrgID, &trip.RequesterID, &trip.CostCenterID, &trip.Type, &trip.Destination, &trip.DestinationCode, &trip.StartDate,
+		&trip.EndDate, &trip.Status, &trip.PolicyWarnings, &trip.CreatedAt, &trip.UpdatedAt, &trip.TimeZone, &budget, &perDiem,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // Not found
-		}
 		return nil, err
 	}
+	if budget != nil {
+		if err := json.Unmarshal(budget, &trip.Budget); err != nil {
+			return nil, err
+		}
+	}
+	if perDiem != nil {
+		if err := json.Unmarshal(perDiem, &trip.PerDiem); err != nil {
+			return nil, err
+		}
+	}
+	// Dates are stored as instants; they are shown in the trip's own zone
+	trip.LocalizeDates()
 	return &trip, nil
 }
 
+func (r *postgresTripRepository) Create(ctx context.Context, trip *domain.Trip) error {
+	budget, err := marshalTripBudget(trip.Budget)
+	if err != nil {
+		return err
+	}
+	perDiem, err := marshalTripPerDiem(trip.PerDiem)
+	if err != nil {
+		return err
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO trips (` + tripColumns + `)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+		_, err := tx.Exec(ctx, query, trip.ID, trip.OrgID, trip.RequesterID, trip.CostCenterID, trip.Type, trip.Destination, trip.DestinationCode,
+			trip.StartDate, trip.EndDate, trip.Status, textArray(trip.PolicyWarnings), trip.CreatedAt, trip.UpdatedAt, trip.TimeZone, budget, perDiem)
+		if err != nil {
+			return overlapError(err)
+		}
+		return insertTripLegs(ctx, tx, trip)
+	})
+}
+
+func (r *postgresTripRepository) Update(ctx context.Context, trip *domain.Trip) error {
+	budget, err := marshalTripBudget(trip.Budget)
+	if err != nil {
+		return err
+	}
+	perDiem, err := marshalTripPerDiem(trip.PerDiem)
+	if err != nil {
+		return err
+	}
+
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE trips SET cost_center_id = $1, trip_type = $2, destination = $3, destination_code = $4, start_date = $5,
+				  end_date = $6, policy_warnings = $7, updated_at = $8, time_zone = $9, budget = $10, per_diem = $11
+				  WHERE id = $12 AND status = $14 AND ($13::uuid IS NULL OR org_id = $13)`
+		tag, err := tx.Exec(ctx, query, trip.CostCenterID, trip.Type, trip.Destination, trip.DestinationCode, trip.StartDate, trip.EndDate,
+			textArray(trip.PolicyWarnings), trip.UpdatedAt, trip.TimeZone, budget, perDiem, trip.ID, tenantArg(ctx), trip.Status)
+		if err != nil {
+			return overlapError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrTripStatusChanged
+		}
+
+		// The itinerary is replaced as a whole
+		if _, err := tx.Exec(ctx, `DELETE FROM trip_legs WHERE trip_id = $1 AND ($2::uuid IS NULL OR org_id = $2)`, trip.ID, tenantArg(ctx)); err != nil {
+			return err
+		}
+		return insertTripLegs(ctx, tx, trip)
+	})
+}
+
+const tripLegColumns = `id, org_id, trip_id, position, origin, destination, depart_at, arrive_at, transport_mode`
+
+func insertTripLegs(ctx context.Context, tx pgx.Tx, trip *domain.Trip) error {
+	query := `INSERT INTO trip_legs (` + tripLegColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	for _, leg := range trip.Legs {
+		_, err := tx.Exec(ctx, query, leg.ID, trip.OrgID, trip.ID, leg.Position, leg.Origin, leg.Destination,
+			leg.DepartAt, leg.ArriveAt, leg.Mode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTripLegs fills in the itineraries of the trips with a single query
+func loadTripLegs(ctx context.Context, tx pgx.Tx, trips ...*domain.Trip) error {
+	if len(trips) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.Trip, len(trips))
+	ids := make([]uuid.UUID, 0, len(trips))
+	for _, trip := range trips {
+		byID[trip.ID] = trip
+		ids = append(ids, trip.ID)
+	}
+
+	query := `SELECT id, trip_id, position, origin, destination, depart_at, arrive_at, transport_mode
+			  FROM trip_legs WHERE trip_id = ANY($1) ORDER BY trip_id, position`
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var leg domain.ItineraryLeg
+		var tripID uuid.UUID
+		if err := rows.Scan(&leg.ID, &tripID, &leg.Position, &leg.Origin, &leg.Destination, &leg.DepartAt, &leg.ArriveAt, &leg.Mode); err != nil {
+			return err
+		}
+		if trip := byID[tripID]; trip != nil {
+			trip.Legs = append(trip.Legs, leg)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, trip := range trips {
+		trip.LocalizeDates()
+	}
+	return nil
+}
+
+func (r *postgresTripRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error) {
+	var trip *domain.Trip
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + tripColumns + ` FROM trips WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		trip, err = scanTrip(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Not found
+		}
+		if err != nil {
+			return err
+		}
+		return loadTripLegs(ctx, tx, trip)
+	})
+	return trip, err
+}
+
 func (r *postgresTripRepository) List(ctx context.Context, params domain.ListTripsParams) ([]*domain.Trip, error) {
 	var queryBuilder strings.Builder
-	queryBuilder.WriteString(`SELECT id, requester_id, destination, start_date, end_date, status, created_at, updated_at
-							   FROM trips WHERE 1=1`)
+	queryBuilder.WriteString(`SELECT ` + tripColumns + ` FROM trips WHERE 1=1`)
 
 	args := []interface{}{}
 	argID := 1
 
+	if orgID := tenantArg(ctx); orgID != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND org_id = $%d", argID))
+		args = append(args, *orgID)
+		argID++
+	}
 	if params.RequesterID != nil {
 		queryBuilder.WriteString(fmt.Sprintf(" AND requester_id = $%d", argID))
 		args = append(args, *params.RequesterID)
 		argID++
 	}
+	if params.CostCenterID != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND cost_center_id = $%d", argID))
+		args = append(args, *params.CostCenterID)
+		argID++
+	}
 	if params.Status != nil {
 		queryBuilder.WriteString(fmt.Sprintf(" AND status = $%d", argID))
 		args = append(args, *params.Status)
 		argID++
 	}
 	if params.Destination != nil {
-		queryBuilder.WriteString(fmt.Sprintf(" AND destination ILIKE $%d", argID))
+		// "Sao Paulo" finds trips to "São Paulo"
+		queryBuilder.WriteString(fmt.Sprintf(" AND unaccent(destination) ILIKE unaccent($%d)", argID))
 		args = append(args, "%"+*params.Destination+"%")
 		argID++
 	}
+	if params.DestinationCode != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND destination_code = $%d", argID))
+		args = append(args, strings.ToUpper(*params.DestinationCode))
+		argID++
+	}
 	if params.StartDate != nil {
 		queryBuilder.WriteString(fmt.Sprintf(" AND start_date >= $%d", argID))
 		args = append(args, *params.StartDate)
@@ -81,29 +224,123 @@ func (r *postgresTripRepository) List(ctx context.Context, params domain.ListTri
 
 	queryBuilder.WriteString(" ORDER BY created_at DESC")
 
-	rows, err := r.db.Query(ctx, queryBuilder.String(), args...)
+	var trips []*domain.Trip
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, queryBuilder.String(), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			trip, err := scanTrip(rows)
+			if err != nil {
+				return err
+			}
+			trips = append(trips, trip)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		return loadTripLegs(ctx, tx, trips...)
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
+	return trips, nil
+}
+
+func (r *postgresTripRepository) ConcludeEnded(ctx context.Context, endedBefore time.Time) ([]*domain.Trip, error) {
 	var trips []*domain.Trip
-	for rows.Next() {
-		var trip domain.Trip
-		if err := rows.Scan(
-			&trip.ID, &trip.RequesterID, &trip.Destination, &trip.StartDate,
-			&trip.EndDate, &trip.Status, &trip.CreatedAt, &trip.UpdatedAt,
-		); err != nil {
-			return nil, err
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE trips SET status = 'concluido', updated_at = $1
+				  WHERE status = 'aprovado' AND end_date < $2 AND ($3::uuid IS NULL OR org_id = $3)
+				  RETURNING ` + tripColumns
+		rows, err := tx.Query(ctx, query, time.Now(), endedBefore, tenantArg(ctx))
+		if err != nil {
+			return err
 		}
-		trips = append(trips, &trip)
-	}
+		defer rows.Close()
 
-	return trips, nil
+		for rows.Next() {
+			trip, err := scanTrip(rows)
+			if err != nil {
+				return err
+			}
+			trips = append(trips, trip)
+		}
+		return rows.Err()
+	})
+	return trips, err
+}
+
+func (r *postgresTripRepository) FindOverlapping(ctx context.Context, requesterID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*domain.Trip, error) {
+	// Same condition as the trips_no_overlap exclusion constraint
+	query := `SELECT ` + tripColumns + ` FROM trips
+			  WHERE requester_id = $1 AND id <> $2
+			    AND status NOT IN ('cancelado', 'retirado')
+			    AND tstzrange(start_date, end_date, '[]') && tstzrange($3, $4, '[]')
+			    AND ($5::uuid IS NULL OR org_id = $5)
+			  ORDER BY start_date`
+
+	var trips []*domain.Trip
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, requesterID, excludeID, start, end, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			trip, err := scanTrip(rows)
+			if err != nil {
+				return err
+			}
+			trips = append(trips, trip)
+		}
+		return rows.Err()
+	})
+	return trips, err
 }
 
-func (r *postgresTripRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.TripStatus) error {
-	query := `UPDATE trips SET status = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.Exec(ctx, query, status, time.Now(), id)
+// overlapError turns a violation of the trips_no_overlap constraint into domain.ErrTripOverlap
+func overlapError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "trips_no_overlap" {
+		return domain.ErrTripOverlap
+	}
 	return err
 }
+
+func (r *postgresTripRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.TripStatus) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE trips SET status = $1, updated_at = $2 WHERE id = $3 AND status = $5 AND ($4::uuid IS NULL OR org_id = $4)`
+		tag, err := tx.Exec(ctx, query, to, time.Now(), id, tenantArg(ctx), from)
+		if err != nil {
+			return overlapError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrTripStatusChanged
+		}
+		return nil
+	})
+}
+
+// marshalTripBudget stores a trip without a budget as NULL
+func marshalTripBudget(budget *domain.TripBudget) ([]byte, error) {
+	if budget == nil {
+		return nil, nil
+	}
+	return json.Marshal(budget)
+}
+
+// marshalTripPerDiem stores a trip without a per diem as NULL
+func marshalTripPerDiem(perDiem *domain.TripPerDiem) ([]byte, error) {
+	if perDiem == nil {
+		return nil, nil
+	}
+	return json.Marshal(perDiem)
+}
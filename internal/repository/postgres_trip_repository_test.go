@@ -25,17 +25,73 @@ func setupTripTestDB(t *testing.T) *pgxpool.Pool {
 	_, err = dbpool.Exec(context.Background(), `
 		CREATE TABLE IF NOT EXISTS trips (
 			id UUID PRIMARY KEY,
+			org_id UUID NOT NULL,
 			requester_id UUID NOT NULL,
+			cost_center_id UUID NOT NULL,
+			trip_type TEXT NOT NULL DEFAULT 'domestic',
 			destination TEXT NOT NULL,
 			start_date TIMESTAMP NOT NULL,
 			end_date TIMESTAMP NOT NULL,
 			status TEXT NOT NULL,
+			policy_warnings TEXT[] NOT NULL DEFAULT '{}',
 			created_at TIMESTAMP NOT NULL,
 			updated_at TIMESTAMP NOT NULL
 		)
 	`)
 	require.NoError(t, err, "Failed to create test table")
 
+	// Tables created before multi-tenancy don't have the org_id column yet
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS org_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the cost_center_id column added with cost centers
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS cost_center_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the policy_warnings column added with travel policies
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS policy_warnings TEXT[] NOT NULL DEFAULT '{}'`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the trip_type column added with cancellation windows
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS trip_type TEXT NOT NULL DEFAULT 'domestic'`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the destination_code column added with the destination catalog
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS destination_code VARCHAR(3)`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the time_zone column added with time-zone-aware dates
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT ''`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the budget column added with trip budgets
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS budget JSONB`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the per_diem column added with per diem rates
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE trips ADD COLUMN IF NOT EXISTS per_diem JSONB`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// The destination filter ignores accents
+	_, err = dbpool.Exec(context.Background(), `CREATE EXTENSION IF NOT EXISTS unaccent`)
+	require.NoError(t, err, "Failed to create the unaccent extension")
+
+	// Itinerary legs were added after the trips table
+	_, err = dbpool.Exec(context.Background(), `
+		CREATE TABLE IF NOT EXISTS trip_legs (
+			id UUID PRIMARY KEY,
+			org_id UUID NOT NULL,
+			trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+			position INT NOT NULL,
+			origin TEXT NOT NULL,
+			destination TEXT NOT NULL,
+			depart_at TIMESTAMPTZ NOT NULL,
+			arrive_at TIMESTAMPTZ NOT NULL,
+			transport_mode TEXT NOT NULL
+		)
+	`)
+	require.NoError(t, err, "Failed to create test table")
+
 	// Clean up existing test data
 	_, err = dbpool.Exec(context.Background(), "DELETE FROM trips")
 	require.NoError(t, err, "Failed to clean up test data")
@@ -54,7 +110,8 @@ func TestPostgresTripRepository_Create(t *testing.T) {
 	defer dbpool.Close()
 
 	repo := repository.NewPostgresTripRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 
 	// Test data
 	tripID := uuid.New()
@@ -65,7 +122,9 @@ func TestPostgresTripRepository_Create(t *testing.T) {
 
 	trip := &domain.Trip{
 		ID:          tripID,
+		OrgID:       orgID,
 		RequesterID: requesterID,
+		Type:        domain.TripInternational,
 		Destination: "Paris",
 		StartDate:   startDate,
 		EndDate:     endDate,
@@ -96,7 +155,8 @@ func TestPostgresTripRepository_FindByID(t *testing.T) {
 	defer dbpool.Close()
 
 	repo := repository.NewPostgresTripRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 
 	// Test data
 	tripID := uuid.New()
@@ -107,9 +167,9 @@ func TestPostgresTripRepository_FindByID(t *testing.T) {
 
 	// Insert test trip
 	_, err := dbpool.Exec(ctx, `
-		INSERT INTO trips (id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, tripID, requesterID, "Paris", startDate, endDate, domain.StatusRequested, now, now)
+		INSERT INTO trips (id, org_id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, tripID, orgID, requesterID, "Paris", startDate, endDate, domain.StatusRequested, now, now)
 	require.NoError(t, err)
 
 	// Test FindByID - existing trip
@@ -118,6 +178,7 @@ func TestPostgresTripRepository_FindByID(t *testing.T) {
 	assert.NotNil(t, trip)
 	assert.Equal(t, tripID, trip.ID)
 	assert.Equal(t, requesterID, trip.RequesterID)
+	assert.Equal(t, orgID, trip.OrgID)
 	assert.Equal(t, "Paris", trip.Destination)
 	assert.Equal(t, startDate, trip.StartDate)
 	assert.Equal(t, endDate, trip.EndDate)
@@ -130,6 +191,16 @@ func TestPostgresTripRepository_FindByID(t *testing.T) {
 	trip, err = repo.FindByID(ctx, nonExistentID)
 	assert.NoError(t, err) // Not finding a trip is not an error
 	assert.Nil(t, trip)
+
+	// Test FindByID - trip from another organization
+	otherOrgCtx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	trip, err = repo.FindByID(otherOrgCtx, tripID)
+	assert.NoError(t, err)
+	assert.Nil(t, trip)
+
+	// Test FindByID - no organization in context
+	_, err = repo.FindByID(context.Background(), tripID)
+	assert.Equal(t, domain.ErrMissingTenant, err)
 }
 
 func TestPostgresTripRepository_List(t *testing.T) {
@@ -143,7 +214,8 @@ func TestPostgresTripRepository_List(t *testing.T) {
 	defer dbpool.Close()
 
 	repo := repository.NewPostgresTripRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 
 	// Test data - create multiple trips with different properties
 	requesterID1 := uuid.New()
@@ -167,15 +239,16 @@ func TestPostgresTripRepository_List(t *testing.T) {
 
 	// Insert test trips
 	_, err := dbpool.Exec(ctx, `
-		INSERT INTO trips (id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
+		INSERT INTO trips (id, org_id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
 		VALUES 
-		($1, $2, $3, $4, $5, $6, $7, $8),
-		($9, $10, $11, $12, $13, $14, $15, $16),
-		($17, $18, $19, $20, $21, $22, $23, $24)
+		($1, $25, $2, $3, $4, $5, $6, $7, $8),
+		($9, $25, $10, $11, $12, $13, $14, $15, $16),
+		($17, $25, $18, $19, $20, $21, $22, $23, $24)
 	`,
 		trip1ID, requesterID1, "Paris", trip1StartDate, trip1EndDate, domain.StatusRequested, now, now,
 		trip2ID, requesterID1, "London", trip2StartDate, trip2EndDate, domain.StatusApproved, now.Add(time.Hour), now.Add(time.Hour),
-		trip3ID, requesterID2, "Rome", trip3StartDate, trip3EndDate, domain.StatusCanceled, now.Add(2*time.Hour), now.Add(2*time.Hour))
+		trip3ID, requesterID2, "Rome", trip3StartDate, trip3EndDate, domain.StatusCanceled, now.Add(2*time.Hour), now.Add(2*time.Hour),
+		orgID)
 	require.NoError(t, err)
 
 	// Test List - all trips
@@ -235,6 +308,12 @@ func TestPostgresTripRepository_List(t *testing.T) {
 	assert.Equal(t, requesterID1, trips[0].RequesterID)
 	assert.Equal(t, domain.StatusApproved, trips[0].Status)
 
+	// Test List - another organization sees nothing
+	otherOrgCtx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	trips, err = repo.List(otherOrgCtx, domain.ListTripsParams{})
+	assert.NoError(t, err)
+	assert.Len(t, trips, 0)
+
 	// Test List - no matching trips
 	nonExistentID := uuid.New()
 	trips, err = repo.List(ctx, domain.ListTripsParams{
@@ -255,7 +334,8 @@ func TestPostgresTripRepository_UpdateStatus(t *testing.T) {
 	defer dbpool.Close()
 
 	repo := repository.NewPostgresTripRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 
 	// Test data
 	tripID := uuid.New()
@@ -266,13 +346,13 @@ func TestPostgresTripRepository_UpdateStatus(t *testing.T) {
 
 	// Insert test trip with status "requested"
 	_, err := dbpool.Exec(ctx, `
-		INSERT INTO trips (id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, tripID, requesterID, "Paris", startDate, endDate, domain.StatusRequested, now, now)
+		INSERT INTO trips (id, org_id, requester_id, destination, start_date, end_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, tripID, orgID, requesterID, "Paris", startDate, endDate, domain.StatusRequested, now, now)
 	require.NoError(t, err)
 
 	// Test UpdateStatus - change to approved
-	err = repo.UpdateStatus(ctx, tripID, domain.StatusApproved)
+	err = repo.UpdateStatus(ctx, tripID, domain.StatusRequested, domain.StatusApproved)
 	assert.NoError(t, err)
 
 	// Verify the status was updated
@@ -284,7 +364,7 @@ func TestPostgresTripRepository_UpdateStatus(t *testing.T) {
 	assert.True(t, updatedAt.After(now), "updated_at should be updated")
 
 	// Test UpdateStatus - change to canceled
-	err = repo.UpdateStatus(ctx, tripID, domain.StatusCanceled)
+	err = repo.UpdateStatus(ctx, tripID, domain.StatusApproved, domain.StatusCanceled)
 	assert.NoError(t, err)
 
 	// Verify the status was updated again
@@ -292,10 +372,14 @@ func TestPostgresTripRepository_UpdateStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, string(domain.StatusCanceled), status)
 
+	// Test UpdateStatus - the trip no longer has the expected status
+	err = repo.UpdateStatus(ctx, tripID, domain.StatusRequested, domain.StatusWithdrawn)
+	assert.ErrorIs(t, err, domain.ErrTripStatusChanged)
+
 	// Test UpdateStatus - non-existent trip
 	nonExistentID := uuid.New()
-	err = repo.UpdateStatus(ctx, nonExistentID, domain.StatusApproved)
-	assert.NoError(t, err) // Should not error, but also not update anything
+	err = repo.UpdateStatus(ctx, nonExistentID, domain.StatusRequested, domain.StatusApproved)
+	assert.ErrorIs(t, err, domain.ErrTripStatusChanged)
 
 	// Verify no new trips were created
 	var count int
@@ -303,3 +387,211 @@ func TestPostgresTripRepository_UpdateStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, count)
 }
+
+func TestPostgresTripRepository_FindOverlapping(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup
+	dbpool := setupTripTestDB(t)
+	defer dbpool.Close()
+
+	repo := repository.NewPostgresTripRepository(dbpool)
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	// Test data
+	requesterID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	startDate := now.AddDate(0, 1, 0)
+	endDate := startDate.AddDate(0, 0, 7)
+
+	approvedID := uuid.New()
+	canceledID := uuid.New()
+	laterID := uuid.New()
+	for _, trip := range []struct {
+		id         uuid.UUID
+		start, end time.Time
+		status     domain.TripStatus
+	}{
+		{approvedID, startDate.AddDate(0, 0, 5), endDate.AddDate(0, 0, 5), domain.StatusApproved},
+		{canceledID, startDate, endDate, domain.StatusCanceled},
+		{laterID, endDate.AddDate(0, 0, 1), endDate.AddDate(0, 0, 3), domain.StatusRequested},
+	} {
+		_, err := dbpool.Exec(ctx, `
+			INSERT INTO trips (id, org_id, requester_id, cost_center_id, destination, start_date, end_date, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, trip.id, orgID, requesterID, uuid.New(), "Paris", trip.start, trip.end, trip.status, now, now)
+		require.NoError(t, err)
+	}
+
+	// Canceled trips and trips after the range don't count
+	trips, err := repo.FindOverlapping(ctx, requesterID, startDate, endDate, uuid.New())
+	assert.NoError(t, err)
+	require.Len(t, trips, 1)
+	assert.Equal(t, approvedID, trips[0].ID)
+
+	// The trip being edited doesn't clash with itself
+	trips, err = repo.FindOverlapping(ctx, requesterID, startDate, endDate, approvedID)
+	assert.NoError(t, err)
+	assert.Empty(t, trips)
+
+	// Other requesters are free to travel on the same dates
+	trips, err = repo.FindOverlapping(ctx, uuid.New(), startDate, endDate, uuid.New())
+	assert.NoError(t, err)
+	assert.Empty(t, trips)
+}
+
+func TestPostgresTripRepository_Legs(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup
+	dbpool := setupTripTestDB(t)
+	defer dbpool.Close()
+
+	repo := repository.NewPostgresTripRepository(dbpool)
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	startDate := now.AddDate(0, 1, 0)
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: uuid.New(),
+		Type:        domain.TripInternational,
+		StartDate:   startDate,
+		EndDate:     startDate.AddDate(0, 0, 7),
+		Status:      domain.StatusRequested,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	trip.SetLegs([]domain.ItineraryLeg{
+		{Origin: "São Paulo", Destination: "Lisbon", DepartAt: startDate, ArriveAt: startDate.Add(10 * time.Hour), Mode: domain.TransportFlight},
+		{Origin: "Lisbon", Destination: "São Paulo", DepartAt: startDate.AddDate(0, 0, 7), ArriveAt: startDate.AddDate(0, 0, 7).Add(10 * time.Hour), Mode: domain.TransportFlight},
+	})
+
+	// Test Create saves the itinerary
+	require.NoError(t, repo.Create(ctx, trip))
+
+	found, err := repo.FindByID(ctx, trip.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Legs, 2)
+	assert.Equal(t, "Lisbon", found.Destination)
+	assert.Equal(t, "São Paulo", found.Legs[0].Origin)
+	assert.Equal(t, 2, found.Legs[1].Position)
+	assert.True(t, startDate.Equal(found.Legs[0].DepartAt))
+
+	// Test Update replaces the itinerary
+	trip.SetLegs([]domain.ItineraryLeg{
+		{Origin: "São Paulo", Destination: "Berlin", DepartAt: startDate, ArriveAt: startDate.Add(12 * time.Hour), Mode: domain.TransportFlight},
+	})
+	require.NoError(t, repo.Update(ctx, trip))
+
+	trips, err := repo.List(ctx, domain.ListTripsParams{RequesterID: &trip.RequesterID})
+	require.NoError(t, err)
+	require.Len(t, trips, 1)
+	require.Len(t, trips[0].Legs, 1)
+	assert.Equal(t, "Berlin", trips[0].Destination)
+	assert.Equal(t, "Berlin", trips[0].Legs[0].Destination)
+}
+
+func TestPostgresTripRepository_TimeZone(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup
+	dbpool := setupTripTestDB(t)
+	defer dbpool.Close()
+
+	repo := repository.NewPostgresTripRepository(dbpool)
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: uuid.New(),
+		Type:        domain.TripInternational,
+		Destination: "Tóquio",
+		StartDate:   time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC),
+		Status:      domain.StatusRequested,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	trip.SetTimeZone("Asia/Tokyo")
+
+	// Test the dates come back local to the trip
+	require.NoError(t, repo.Create(ctx, trip))
+
+	found, err := repo.FindByID(ctx, trip.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", found.TimeZone)
+	assert.Equal(t, "2030-05-10T09:00:00+09:00", found.StartDate.Format(time.RFC3339))
+	assert.True(t, trip.EndDate.Equal(found.EndDate))
+}
+
+func TestPostgresTripRepository_Budget(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup
+	dbpool := setupTripTestDB(t)
+	defer dbpool.Close()
+
+	repo := repository.NewPostgresTripRepository(dbpool)
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	startDate := now.AddDate(0, 1, 0)
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: uuid.New(),
+		Type:        domain.TripDomestic,
+		Destination: "Recife",
+		StartDate:   startDate,
+		EndDate:     startDate.AddDate(0, 0, 3),
+		Status:      domain.StatusRequested,
+		Budget: &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{
+			{Category: domain.BudgetAirfare, Amount: 120000},
+			{Category: domain.BudgetLodging, Amount: 90000, Description: "3 nights"},
+		}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Test Create saves the budget
+	require.NoError(t, repo.Create(ctx, trip))
+
+	found, err := repo.FindByID(ctx, trip.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.Budget)
+	assert.Equal(t, trip.Budget.Items, found.Budget.Items)
+	assert.Equal(t, int64(210000), found.Budget.Total())
+
+	// Test Update removes it
+	trip.Budget = nil
+	require.NoError(t, repo.Update(ctx, trip))
+
+	found, err = repo.FindByID(ctx, trip.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.Budget)
+
+	// Test Update refuses a trip whose stored status changed meanwhile
+	require.NoError(t, repo.UpdateStatus(ctx, trip.ID, domain.StatusRequested, domain.StatusApproved))
+	trip.Destination = "Natal"
+	assert.ErrorIs(t, repo.Update(ctx, trip), domain.ErrTripStatusChanged)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -18,17 +19,12 @@ func NewPostgresUserRepository(db *pgxpool.Pool) domain.UserRepository {
 	return &postgresUserRepository{db: db}
 }
 
-func (r *postgresUserRepository) Create(ctx context.Context, user *domain.User) error {
-	query := `INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.Exec(ctx, query, user.ID, user.Name, user.Email, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
-	return err
-}
+const userColumns = `id, org_id, department_id, role, manager_id, name, email, password_hash, created_at, updated_at`
 
-func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `SELECT id, name, email, password_hash, created_at, updated_at FROM users WHERE email = $1`
+func scanUser(row pgx.Row) (*domain.User, error) {
 	var user domain.User
-	err := r.db.QueryRow(ctx, query, email).Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	err := row.Scan(&user.ID, &user.OrgID, &user.DepartmentID, &user.Role, &user.ManagerID, &user.Name, &user.Email,
+		&user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // Not found is not an error here
@@ -38,15 +34,120 @@ func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string)
 	return &user, nil
 }
 
+func (r *postgresUserRepository) Create(ctx context.Context, user *domain.User) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO users (id, org_id, department_id, role, manager_id, name, email, password_hash, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		if _, err := tx.Exec(ctx, query, user.ID, user.OrgID, user.DepartmentID, user.Role, user.ManagerID, user.Name, user.Email,
+			user.PasswordHash, user.CreatedAt, user.UpdatedAt); err != nil {
+			return err
+		}
+
+		// Keep the initial password in the history so it can't be reused later
+		historyQuery := `INSERT INTO password_history (user_id, org_id, password_hash, created_at) VALUES ($1, $2, $3, $4)`
+		_, err := tx.Exec(ctx, historyQuery, user.ID, user.OrgID, user.PasswordHash, user.CreatedAt)
+		return err
+	})
+}
+
+func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user *domain.User
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		user, err = scanUser(tx.QueryRow(ctx, query, email, tenantArg(ctx)))
+		return err
+	})
+	return user, err
+}
+
 func (r *postgresUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `SELECT id, name, email, password_hash, created_at, updated_at FROM users WHERE id = $1`
-	var user domain.User
-	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // Not found
+	var user *domain.User
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+		var err error
+		user, err = scanUser(tx.QueryRow(ctx, query, id, tenantArg(ctx)))
+		return err
+	})
+	return user, err
+}
+
+func (r *postgresUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		now := time.Now()
+		tag, err := tx.Exec(ctx, `UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3 AND ($4::uuid IS NULL OR org_id = $4)`,
+			passwordHash, now, id, tenantArg(ctx))
+		if err != nil {
+			return err
 		}
-		return nil, err
-	}
-	return &user, nil
+		if tag.RowsAffected() == 0 {
+			return nil // Nothing to record for a user outside the tenant
+		}
+
+		historyQuery := `INSERT INTO password_history (user_id, org_id, password_hash, created_at)
+						 SELECT id, org_id, $2, $3 FROM users WHERE id = $1`
+		_, err = tx.Exec(ctx, historyQuery, id, passwordHash, now)
+		return err
+	})
+}
+
+func (r *postgresUserRepository) UpdateDepartment(ctx context.Context, id uuid.UUID, departmentID *uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE users SET department_id = $1, updated_at = $2 WHERE id = $3 AND ($4::uuid IS NULL OR org_id = $4)`
+		_, err := tx.Exec(ctx, query, departmentID, time.Now(), id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role domain.UserRole, managerID *uuid.UUID) error {
+	return withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE users SET role = $1, manager_id = $2, updated_at = $3 WHERE id = $4 AND ($5::uuid IS NULL OR org_id = $5)`
+		_, err := tx.Exec(ctx, query, role, managerID, time.Now(), id, tenantArg(ctx))
+		return err
+	})
+}
+
+func (r *postgresUserRepository) ListByRole(ctx context.Context, role domain.UserRole) ([]*domain.User, error) {
+	var users []*domain.User
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + userColumns + ` FROM users WHERE role = $1 AND ($2::uuid IS NULL OR org_id = $2) ORDER BY name`
+		rows, err := tx.Query(ctx, query, role, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
+	return users, err
+}
+
+func (r *postgresUserRepository) FindPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	var hashes []string
+	err := withTenant(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT password_hash FROM password_history WHERE user_id = $1 AND ($3::uuid IS NULL OR org_id = $3)
+				  ORDER BY created_at DESC LIMIT $2`
+		rows, err := tx.Query(ctx, query, userID, limit, tenantArg(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				return err
+			}
+			hashes = append(hashes, hash)
+		}
+		return rows.Err()
+	})
+	return hashes, err
 }
@@ -26,6 +26,10 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 	_, err = dbpool.Exec(context.Background(), `
 		CREATE TABLE IF NOT EXISTS users (
 			id UUID PRIMARY KEY,
+			org_id UUID NOT NULL,
+			department_id UUID,
+			role TEXT NOT NULL DEFAULT 'employee',
+			manager_id UUID,
 			name TEXT NOT NULL,
 			email TEXT UNIQUE NOT NULL,
 			password_hash TEXT NOT NULL,
@@ -34,8 +38,39 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 		)
 	`)
 	require.NoError(t, err, "Failed to create test table")
+
+	// Tables created before multi-tenancy don't have the org_id column yet
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the department_id column added with departments
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE users ADD COLUMN IF NOT EXISTS department_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	// Nor the role and manager_id columns added with approval chains
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'employee'`)
+	require.NoError(t, err, "Failed to migrate test table")
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE users ADD COLUMN IF NOT EXISTS manager_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
+
+	_, err = dbpool.Exec(context.Background(), `
+		CREATE TABLE IF NOT EXISTS password_history (
+			id BIGSERIAL PRIMARY KEY,
+			user_id UUID NOT NULL,
+			org_id UUID,
+			password_hash TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	require.NoError(t, err, "Failed to create password tables")
+
+	// Tables created before the history was row-level secured don't have the org_id column yet
+	_, err = dbpool.Exec(context.Background(), `ALTER TABLE password_history ADD COLUMN IF NOT EXISTS org_id UUID`)
+	require.NoError(t, err, "Failed to migrate test table")
 	
 	// Clean up existing test data
+	_, err = dbpool.Exec(context.Background(), "DELETE FROM password_history")
+	require.NoError(t, err, "Failed to clean up test data")
 	_, err = dbpool.Exec(context.Background(), "DELETE FROM users")
 	require.NoError(t, err, "Failed to clean up test data")
 	
@@ -53,13 +88,15 @@ func TestPostgresUserRepository_Create(t *testing.T) {
 	defer dbpool.Close()
 	
 	repo := repository.NewPostgresUserRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 	
 	// Test data
 	userID := uuid.New()
 	now := time.Now().UTC().Truncate(time.Microsecond) // PostgreSQL truncates to microseconds
 	user := &domain.User{
 		ID:           userID,
+		OrgID:        orgID,
 		Name:         "Test User",
 		Email:        "test@example.com",
 		PasswordHash: "hashed_password",
@@ -80,6 +117,7 @@ func TestPostgresUserRepository_Create(t *testing.T) {
 	// Test duplicate email
 	duplicateUser := &domain.User{
 		ID:           uuid.New(),
+		OrgID:        orgID,
 		Name:         "Another User",
 		Email:        "test@example.com", // Same email
 		PasswordHash: "another_hashed_password",
@@ -102,7 +140,8 @@ func TestPostgresUserRepository_FindByEmail(t *testing.T) {
 	defer dbpool.Close()
 	
 	repo := repository.NewPostgresUserRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 	
 	// Test data
 	userID := uuid.New()
@@ -111,9 +150,9 @@ func TestPostgresUserRepository_FindByEmail(t *testing.T) {
 	
 	// Insert test user
 	_, err := dbpool.Exec(ctx, `
-		INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, userID, "Find By Email User", email, "hashed_password", now, now)
+		INSERT INTO users (id, org_id, name, email, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, userID, orgID, "Find By Email User", email, "hashed_password", now, now)
 	require.NoError(t, err)
 	
 	// Test FindByEmail - existing user
@@ -131,6 +170,17 @@ func TestPostgresUserRepository_FindByEmail(t *testing.T) {
 	user, err = repo.FindByEmail(ctx, "nonexistent@example.com")
 	assert.NoError(t, err) // Not finding a user is not an error
 	assert.Nil(t, user)
+
+	// Test FindByEmail - user from another organization
+	user, err = repo.FindByEmail(domain.ContextWithOrgID(context.Background(), uuid.New()), email)
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	// Test FindByEmail - lookup across all organizations (used by login)
+	user, err = repo.FindByEmail(domain.ContextWithAllTenants(context.Background()), email)
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, orgID, user.OrgID)
 }
 
 func TestPostgresUserRepository_FindByID(t *testing.T) {
@@ -144,7 +194,8 @@ func TestPostgresUserRepository_FindByID(t *testing.T) {
 	defer dbpool.Close()
 	
 	repo := repository.NewPostgresUserRepository(dbpool)
-	ctx := context.Background()
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
 	
 	// Test data
 	userID := uuid.New()
@@ -152,9 +203,9 @@ func TestPostgresUserRepository_FindByID(t *testing.T) {
 	
 	// Insert test user
 	_, err := dbpool.Exec(ctx, `
-		INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, userID, "Find By ID User", "find-by-id@example.com", "hashed_password", now, now)
+		INSERT INTO users (id, org_id, name, email, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, userID, orgID, "Find By ID User", "find-by-id@example.com", "hashed_password", now, now)
 	require.NoError(t, err)
 	
 	// Test FindByID - existing user
@@ -173,4 +224,56 @@ func TestPostgresUserRepository_FindByID(t *testing.T) {
 	user, err = repo.FindByID(ctx, nonExistentID)
 	assert.NoError(t, err) // Not finding a user is not an error
 	assert.Nil(t, user)
-}
\ No newline at end of file
+}
+
+func TestPostgresUserRepository_UpdatePassword(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup
+	dbpool := setupTestDB(t)
+	defer dbpool.Close()
+
+	repo := repository.NewPostgresUserRepository(dbpool)
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	// Test data
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	user := &domain.User{
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		Name:         "Password User",
+		Email:        "password-user@example.com",
+		PasswordHash: "first_hash",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, repo.Create(ctx, user))
+
+	// Test UpdatePassword
+	err := repo.UpdatePassword(ctx, user.ID, "second_hash")
+	assert.NoError(t, err)
+
+	// Verify the password was updated
+	found, err := repo.FindByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "second_hash", found.PasswordHash)
+
+	// Verify both hashes are in the history, newest first
+	history, err := repo.FindPasswordHistory(ctx, user.ID, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second_hash", "first_hash"}, history)
+
+	// Verify the limit is respected
+	history, err = repo.FindPasswordHistory(ctx, user.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second_hash"}, history)
+
+	// Verify another organization doesn't see the history
+	history, err = repo.FindPasswordHistory(domain.ContextWithOrgID(context.Background(), uuid.New()), user.ID, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// S3Config locates a bucket of an S3-compatible service, e.g. AWS S3 or MinIO
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle addresses the bucket in the path (endpoint/bucket/key)
+	// instead of the host (bucket.endpoint/key). Most S3-compatible services need it.
+	UsePathStyle bool
+}
+
+// s3BlobStore talks to the S3 REST API, signing requests with AWS Signature Version 4
+type s3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3BlobStore stores blobs as objects of the bucket
+func NewS3BlobStore(config S3Config) (domain.BlobStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.Region == "" || config.Bucket == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("the S3 region, bucket and credentials are required")
+	}
+	return &s3BlobStore{config: config, endpoint: endpoint, client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, sha256Hex(content), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("put", key, resp)
+	}
+	return nil
+}
+
+func (s *s3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, sha256Hex(nil), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, domain.ErrBlobNotFound
+	default:
+		return nil, s3Error("get", key, resp)
+	}
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, sha256Hex(nil), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", key, resp)
+	}
+	return nil
+}
+
+// SignedURL presigns a GET of the object that names the download after fileName.
+// S3 accepts presigned URLs for at most 7 days.
+func (s *s3BlobStore) SignedURL(ctx context.Context, key, fileName string, expiresAt time.Time) (string, error) {
+	now := time.Now().UTC()
+	expires := int64(expiresAt.Sub(now).Seconds())
+	if expires <= 0 || expires > 7*24*60*60 {
+		return "", fmt.Errorf("signed URLs must expire within 7 days")
+	}
+
+	u := s.objectURL(key)
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(amzDate)
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.config.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(expires, 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	query.Set("response-content-disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	canonicalQuery := canonicalS3Query(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet, u.EscapedPath(), canonicalQuery, "host:" + u.Host + "\n", "host", "UNSIGNED-PAYLOAD",
+	}, "\n")
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(amzDate, scope, canonicalRequest)
+	return u.String(), nil
+}
+
+// objectURL addresses the object of the key in the bucket
+func (s *s3BlobStore) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	prefix := strings.TrimSuffix(u.Path, "/")
+	if s.config.UsePathStyle {
+		prefix += "/" + s.config.Bucket
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+	}
+	u.Path = prefix + "/" + key
+	u.RawPath = s3Escape(prefix, false) + "/" + s3Escape(key, false)
+	return &u
+}
+
+// sign adds the Signature Version 4 authorization of the request, covering
+// its host, date, payload hash and content type
+func (s *s3BlobStore) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), canonicalS3Query(req.URL.Query()), canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	scope := s.scope(amzDate)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, s.signature(amzDate, scope, canonicalRequest)))
+}
+
+// scope is the credential scope of a signature made at amzDate
+func (s *s3BlobStore) scope(amzDate string) string {
+	return amzDate[:8] + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request with a key derived from the secret, the date and the scope
+func (s *s3BlobStore) signature(amzDate, scope, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), amzDate[:8])
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalS3Query sorts and encodes the query parameters the way they are signed
+func canonicalS3Query(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes everything but the unreserved characters of RFC 3986 and, in paths, the slashes
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3Error(operation, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", operation, key, resp.Status, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
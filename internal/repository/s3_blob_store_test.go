@@ -0,0 +1,254 @@
+package repository_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fakeS3Bucket = "receipts"
+	fakeS3Region = "sa-east-1"
+	fakeS3Key    = "AKIDEXAMPLE"
+	fakeS3Secret = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+type fakeS3Object struct {
+	content     []byte
+	contentType string
+}
+
+// fakeS3 stands in for an S3-compatible service with path-style addressing.
+// It checks the Signature Version 4 of every request, in headers or presigned in the query.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+func newFakeS3(t *testing.T) (*httptest.Server, *fakeS3) {
+	fake := &fakeS3{objects: map[string]fakeS3Object{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return server, fake
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !f.authorized(r, body) {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+fakeS3Bucket+"/")
+	if !ok {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = fakeS3Object{content: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		if disposition := r.URL.Query().Get("response-content-disposition"); disposition != "" {
+			w.Header().Set("Content-Disposition", disposition)
+		}
+		w.Write(object.content)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) authorized(r *http.Request, body []byte) bool {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Signature") != "" {
+		return f.presignAuthorized(r, query)
+	}
+
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	fields := map[string]string{}
+	for _, field := range strings.Split(auth, ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		fields[name] = value
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	sum := sha256.Sum256(body)
+	if payloadHash != hex.EncodeToString(sum[:]) {
+		return false
+	}
+	var headers strings.Builder
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), fakeS3Query(query), headers.String(), fields["SignedHeaders"], payloadHash}, "\n")
+	return fields["Credential"] == fakeS3Key+"/"+fakeS3Scope(amzDate) &&
+		hmac.Equal([]byte(fields["Signature"]), []byte(fakeS3Sign(amzDate, canonical)))
+}
+
+func (f *fakeS3) presignAuthorized(r *http.Request, query url.Values) bool {
+	amzDate := query.Get("X-Amz-Date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return false
+	}
+	expires, err := time.ParseDuration(query.Get("X-Amz-Expires") + "s")
+	if err != nil || time.Now().After(signedAt.Add(expires)) {
+		return false
+	}
+	signature := query.Get("X-Amz-Signature")
+	query.Del("X-Amz-Signature")
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), fakeS3Query(query), "host:" + r.Host + "\n", "host", "UNSIGNED-PAYLOAD"}, "\n")
+	return query.Get("X-Amz-Credential") == fakeS3Key+"/"+fakeS3Scope(amzDate) &&
+		hmac.Equal([]byte(signature), []byte(fakeS3Sign(amzDate, canonical)))
+}
+
+func fakeS3Query(query url.Values) string {
+	var pairs []string
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, fakeS3Escape(key)+"="+fakeS3Escape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+func fakeS3Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func fakeS3Scope(amzDate string) string {
+	if len(amzDate) < 8 {
+		return ""
+	}
+	return amzDate[:8] + "/" + fakeS3Region + "/s3/aws4_request"
+}
+
+func fakeS3Sign(amzDate, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	key := []byte("AWS4" + fakeS3Secret)
+	for _, part := range []string{amzDate[:8], fakeS3Region, "s3", "aws4_request"} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("AWS4-HMAC-SHA256\n" + amzDate + "\n" + fakeS3Scope(amzDate) + "\n" + hex.EncodeToString(hash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newTestS3BlobStore(t *testing.T, endpoint, secret string) domain.BlobStore {
+	store, err := repository.NewS3BlobStore(repository.S3Config{
+		Endpoint:        endpoint,
+		Region:          fakeS3Region,
+		Bucket:          fakeS3Bucket,
+		AccessKeyID:     fakeS3Key,
+		SecretAccessKey: secret,
+		UsePathStyle:    true,
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestS3BlobStore(t *testing.T) {
+	server, fake := newFakeS3(t)
+	store := newTestS3BlobStore(t, server.URL, fakeS3Secret)
+	ctx := context.Background()
+	key := "attachments/org/abc123"
+
+	t.Run("Stores and reads objects", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, key, []byte("%PDF-1.4 receipt"), "application/pdf"))
+		assert.Equal(t, "application/pdf", fake.objects[key].contentType)
+
+		content, err := store.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("%PDF-1.4 receipt"), content)
+	})
+
+	t.Run("Missing objects are not found", func(t *testing.T) {
+		_, err := store.Get(ctx, "attachments/org/missing")
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+	})
+
+	t.Run("Signed URLs download without credentials", func(t *testing.T) {
+		signer, ok := store.(domain.BlobURLSigner)
+		require.True(t, ok)
+
+		signed, err := signer.SignedURL(ctx, key, "recibo hotel.pdf", time.Now().Add(15*time.Minute))
+		require.NoError(t, err)
+		resp, err := http.Get(signed)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		content, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []byte("%PDF-1.4 receipt"), content)
+		assert.Equal(t, `attachment; filename="recibo hotel.pdf"`, resp.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("Tampered signed URLs are refused", func(t *testing.T) {
+		signer := store.(domain.BlobURLSigner)
+		signed, err := signer.SignedURL(ctx, key, "recibo.pdf", time.Now().Add(15*time.Minute))
+		require.NoError(t, err)
+
+		resp, err := http.Get(strings.Replace(signed, "abc123", "other", 1))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Signed URLs expire within 7 days", func(t *testing.T) {
+		signer := store.(domain.BlobURLSigner)
+		_, err := signer.SignedURL(ctx, key, "recibo.pdf", time.Now().Add(8*24*time.Hour))
+		assert.Error(t, err)
+	})
+
+	t.Run("Deletes objects", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, key))
+
+		_, err := store.Get(ctx, key)
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+	})
+
+	t.Run("Wrong credentials fail", func(t *testing.T) {
+		wrong := newTestS3BlobStore(t, server.URL, "not-the-secret")
+		assert.Error(t, wrong.Put(ctx, key, []byte("x"), "application/pdf"))
+	})
+}
+
+func TestNewS3BlobStore(t *testing.T) {
+	_, err := repository.NewS3BlobStore(repository.S3Config{Endpoint: "localhost:9000", Region: "us-east-1", Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s"})
+	assert.Error(t, err)
+
+	_, err = repository.NewS3BlobStore(repository.S3Config{Endpoint: "http://localhost:9000", Region: "us-east-1"})
+	assert.Error(t, err)
+}
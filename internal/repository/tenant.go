@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+type txContextKey struct{}
+
+// withTenant runs fn in a transaction whose row-level security settings match
+// the tenant carried by ctx. Queries should still filter by org_id explicitly;
+// the policies are a safety net for the ones that forget. When ctx comes from
+// postgresTransactor.WithinTx, fn runs in that transaction instead.
+func withTenant(ctx context.Context, db *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
+		return fn(tx)
+	}
+
+	orgID, scoped := domain.OrgIDFromContext(ctx)
+	if !scoped && !domain.IsAllTenantsContext(ctx) {
+		return domain.ErrMissingTenant
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if scoped {
+		_, err = tx.Exec(ctx, `SELECT set_config('app.current_org_id', $1, true)`, orgID.String())
+	} else {
+		_, err = tx.Exec(ctx, `SELECT set_config('app.bypass_tenant', 'on', true)`)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// tenantArg returns the organization to filter by, or nil when ctx is allowed to see all tenants.
+// It's meant for queries written as "($n::uuid IS NULL OR org_id = $n)".
+func tenantArg(ctx context.Context) *uuid.UUID {
+	if orgID, ok := domain.OrgIDFromContext(ctx); ok {
+		return &orgID
+	}
+	return nil
+}
+
+// postgresTransactor shares one withTenant transaction among the repository
+// calls made with the context it hands out. The tenant of the transaction is
+// the one of the context WithinTx was called with.
+type postgresTransactor struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTransactor(db *pgxpool.Pool) domain.Transactor {
+	return &postgresTransactor{db: db}
+}
+
+func (t *postgresTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return withTenant(ctx, t.db, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var (
+	ErrNothingToExport     = errors.New("no approved expenses left to export in the period")
+	ErrExportBatchNotFound = errors.New("export batch not found")
+)
+
+// AccountingExportService exports the approved expenses to the organization's
+// ERP, booked to the GL account of their category and the cost center of
+// their trip. Each export is recorded as a batch, so an expense is exported once.
+type AccountingExportService struct {
+	repo        domain.AccountingExportRepository
+	glAccounts  domain.GLAccountRepository
+	tripRepo    domain.TripRepository
+	costCenters domain.CostCenterRepository
+	userRepo    domain.UserRepository
+}
+
+func NewAccountingExportService(repo domain.AccountingExportRepository, glAccounts domain.GLAccountRepository,
+	tripRepo domain.TripRepository, costCenters domain.CostCenterRepository, userRepo domain.UserRepository) *AccountingExportService {
+	return &AccountingExportService{
+		repo:        repo,
+		glAccounts:  glAccounts,
+		tripRepo:    tripRepo,
+		costCenters: costCenters,
+		userRepo:    userRepo,
+	}
+}
+
+// ListGLAccounts returns the GL account of each mapped category. Admins and finance see them.
+func (s *AccountingExportService) ListGLAccounts(ctx context.Context, userID uuid.UUID) ([]*domain.GLAccount, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.glAccounts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if accounts == nil {
+		accounts = []*domain.GLAccount{}
+	}
+	return accounts, nil
+}
+
+// SaveGLAccount maps the expense category to a GL account, replacing the previous one
+func (s *AccountingExportService) SaveGLAccount(ctx context.Context, userID uuid.UUID, category domain.BudgetCategory, account string) (*domain.GLAccount, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	glAccount := &domain.GLAccount{
+		OrgID:     orgID,
+		Category:  category,
+		Account:   strings.TrimSpace(account),
+		UpdatedAt: time.Now(),
+	}
+	if err := glAccount.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.glAccounts.Save(ctx, glAccount); err != nil {
+		return nil, err
+	}
+	return glAccount, nil
+}
+
+// Export records a batch with the expenses of the reports approved in the
+// period (YYYY-MM) that weren't exported yet. Every category exported must
+// be mapped to a GL account.
+func (s *AccountingExportService) Export(ctx context.Context, userID uuid.UUID, period string) (*domain.AccountingExportBatch, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	from, to, err := domain.ParseAccountingPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.ListExportableReports(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountsByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &domain.AccountingExportBatch{
+		ID:        uuid.New(),
+		OrgID:     orgID,
+		Period:    period,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		Lines:     []domain.AccountingExportLine{},
+	}
+	unmapped := make(map[domain.BudgetCategory]bool)
+	costCenterCodes := make(map[uuid.UUID]string)
+	for _, report := range reports {
+		if len(report.Items) == 0 {
+			continue
+		}
+		trip, err := s.tripRepo.FindByID(ctx, report.TripID)
+		if err != nil {
+			return nil, err
+		}
+		if trip == nil {
+			continue
+		}
+		costCenterCode, err := s.costCenterCode(ctx, costCenterCodes, trip.CostCenterID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range report.Items {
+			account, ok := accounts[item.Category]
+			if !ok {
+				unmapped[item.Category] = true
+				continue
+			}
+			amount, _ := item.AmountIn(report.Currency)
+			batch.Lines = append(batch.Lines, domain.AccountingExportLine{
+				ExpenseItemID:  item.ID,
+				TripID:         trip.ID,
+				TravelerID:     report.TravelerID,
+				Destination:    trip.Destination,
+				CostCenterCode: costCenterCode,
+				GLAccount:      account,
+				Category:       item.Category,
+				Date:           item.Date,
+				Description:    item.Description,
+				Amount:         amount,
+				Currency:       report.Currency,
+			})
+		}
+	}
+
+	if len(unmapped) > 0 {
+		categories := make([]string, 0, len(unmapped))
+		for category := range unmapped {
+			categories = append(categories, string(category))
+		}
+		sort.Strings(categories)
+		validationErrors := domain.NewValidationErrors()
+		for _, category := range categories {
+			validationErrors.Add(fmt.Sprintf("no GL account for the %s category", category))
+		}
+		return nil, validationErrors
+	}
+	if len(batch.Lines) == 0 {
+		return nil, ErrNothingToExport
+	}
+
+	batch.LineCount = len(batch.Lines)
+	if err := s.repo.CreateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ListBatches returns the export batches, newest first, without their lines
+func (s *AccountingExportService) ListBatches(ctx context.Context, userID uuid.UUID) ([]*domain.AccountingExportBatch, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	batches, err := s.repo.ListBatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if batches == nil {
+		batches = []*domain.AccountingExportBatch{}
+	}
+	return batches, nil
+}
+
+// GetBatch returns a batch with its lines as they were exported, to download it again
+func (s *AccountingExportService) GetBatch(ctx context.Context, userID, batchID uuid.UUID) (*domain.AccountingExportBatch, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.repo.FindBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, ErrExportBatchNotFound
+	}
+	return batch, nil
+}
+
+func (s *AccountingExportService) accountsByCategory(ctx context.Context) (map[domain.BudgetCategory]string, error) {
+	accounts, err := s.glAccounts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCategory := make(map[domain.BudgetCategory]string, len(accounts))
+	for _, account := range accounts {
+		byCategory[account.Category] = account.Account
+	}
+	return byCategory, nil
+}
+
+// costCenterCode looks up the code of the cost center, remembering it in codes
+func (s *AccountingExportService) costCenterCode(ctx context.Context, codes map[uuid.UUID]string, id uuid.UUID) (string, error) {
+	if code, ok := codes[id]; ok {
+		return code, nil
+	}
+	costCenter, err := s.costCenters.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if costCenter != nil {
+		codes[id] = costCenter.Code
+	}
+	return codes[id], nil
+}
@@ -0,0 +1,242 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAccountingExportService() (*service.AccountingExportService, *mocks.MockAccountingExportRepository, *mocks.MockGLAccountRepository, *mocks.MockTripRepository, *mocks.MockCostCenterRepository, *mocks.MockUserRepository) {
+	mockExportRepo := new(mocks.MockAccountingExportRepository)
+	mockGLAccountRepo := new(mocks.MockGLAccountRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	accountingExportService := service.NewAccountingExportService(mockExportRepo, mockGLAccountRepo, mockTripRepo, mockCostCenterRepo, mockUserRepo)
+	return accountingExportService, mockExportRepo, mockGLAccountRepo, mockTripRepo, mockCostCenterRepo, mockUserRepo
+}
+
+func TestAccountingExportService_Export(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	costCenter := &domain.CostCenter{ID: uuid.New(), OrgID: orgID, Code: "CC-100"}
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, CostCenterID: costCenter.ID, Destination: "Nova York"}
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	report := &domain.ExpenseReport{
+		ID:         uuid.New(),
+		OrgID:      orgID,
+		TripID:     trip.ID,
+		TravelerID: uuid.New(),
+		Status:     domain.ExpenseApproved,
+		Currency:   "BRL",
+		Items: []domain.ExpenseItem{
+			{ID: uuid.New(), Category: domain.BudgetLodging, Amount: 80000, Currency: "BRL", Description: "Hotel"},
+			{ID: uuid.New(), Category: domain.BudgetGroundTransport, Amount: 5000, Currency: "USD", Description: "Taxi",
+				Converted: &domain.BudgetConversion{Total: domain.Money{Amount: 25000, Currency: "BRL"}}},
+		},
+	}
+	accounts := []*domain.GLAccount{
+		{OrgID: orgID, Category: domain.BudgetLodging, Account: "4.1.02.003"},
+		{OrgID: orgID, Category: domain.BudgetGroundTransport, Account: "4.1.02.005"},
+	}
+
+	t.Run("Success records a batch in the base currency", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, mockGLAccountRepo, mockTripRepo, mockCostCenterRepo, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("ListExportableReports", ctx, from, to).Return([]*domain.ExpenseReport{report}, nil)
+		mockGLAccountRepo.On("List", ctx).Return(accounts, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil).Once()
+		mockExportRepo.On("CreateBatch", ctx, mock.AnythingOfType("*domain.AccountingExportBatch")).Return(nil)
+
+		// Act
+		batch, err := accountingExportService.Export(ctx, finance.ID, "2024-05")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05", batch.Period)
+		assert.Equal(t, orgID, batch.OrgID)
+		assert.Equal(t, 2, batch.LineCount)
+		assert.Equal(t, "CC-100", batch.Lines[0].CostCenterCode)
+		assert.Equal(t, "4.1.02.003", batch.Lines[0].GLAccount)
+		assert.Equal(t, int64(25000), batch.Lines[1].Amount)
+		assert.Equal(t, "BRL", batch.Lines[1].Currency)
+		assert.Equal(t, trip.ID, batch.Lines[1].TripID)
+		mockExportRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing left to export", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, mockGLAccountRepo, _, _, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("ListExportableReports", ctx, from, to).Return([]*domain.ExpenseReport{}, nil)
+		mockGLAccountRepo.On("List", ctx).Return(accounts, nil)
+
+		// Act
+		_, err := accountingExportService.Export(ctx, finance.ID, "2024-05")
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrNothingToExport)
+		mockExportRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Every category needs a GL account", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, mockGLAccountRepo, mockTripRepo, mockCostCenterRepo, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("ListExportableReports", ctx, from, to).Return([]*domain.ExpenseReport{report}, nil)
+		mockGLAccountRepo.On("List", ctx).Return(accounts[:1], nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+
+		// Act
+		_, err := accountingExportService.Export(ctx, finance.ID, "2024-05")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no GL account for the ground_transport category")
+		mockExportRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expenses exported meanwhile", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, mockGLAccountRepo, mockTripRepo, mockCostCenterRepo, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("ListExportableReports", ctx, from, to).Return([]*domain.ExpenseReport{report}, nil)
+		mockGLAccountRepo.On("List", ctx).Return(accounts, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+		mockExportRepo.On("CreateBatch", ctx, mock.Anything).Return(domain.ErrAlreadyExported)
+
+		// Act
+		_, err := accountingExportService.Export(ctx, finance.ID, "2024-05")
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrAlreadyExported)
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		// Arrange
+		accountingExportService, _, _, _, _, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+
+		// Act
+		_, err := accountingExportService.Export(ctx, finance.ID, "May 2024")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "period must be YYYY-MM")
+	})
+
+	t.Run("Employees can't export", func(t *testing.T) {
+		// Arrange
+		accountingExportService, _, _, _, _, mockUserRepo := setupAccountingExportService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		_, err := accountingExportService.Export(ctx, employee.ID, "2024-05")
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
+
+func TestAccountingExportService_SaveGLAccount(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		accountingExportService, _, mockGLAccountRepo, _, _, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockGLAccountRepo.On("Save", ctx, mock.AnythingOfType("*domain.GLAccount")).Return(nil)
+
+		// Act
+		account, err := accountingExportService.SaveGLAccount(ctx, admin.ID, domain.BudgetAirfare, " 4.1.02.001 ")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, account.OrgID)
+		assert.Equal(t, "4.1.02.001", account.Account)
+	})
+
+	t.Run("Invalid category", func(t *testing.T) {
+		// Arrange
+		accountingExportService, _, mockGLAccountRepo, _, _, mockUserRepo := setupAccountingExportService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+
+		// Act
+		_, err := accountingExportService.SaveGLAccount(ctx, admin.ID, "meals", "4.1.02.001")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "category must be")
+		mockGLAccountRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountingExportService_GetBatch(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+
+	t.Run("Not found", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, _, _, _, mockUserRepo := setupAccountingExportService()
+		batchID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("FindBatch", ctx, batchID).Return(nil, nil)
+
+		// Act
+		_, err := accountingExportService.GetBatch(ctx, finance.ID, batchID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrExportBatchNotFound)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		// Arrange
+		accountingExportService, mockExportRepo, _, _, _, mockUserRepo := setupAccountingExportService()
+		batchID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockExportRepo.On("FindBatch", ctx, batchID).Return(nil, errors.New("db error"))
+
+		// Act
+		_, err := accountingExportService.GetBatch(ctx, finance.ID, batchID)
+
+		// Assert
+		assert.EqualError(t, err, "db error")
+	})
+}
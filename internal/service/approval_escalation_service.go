@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// ApprovalEscalationConfig sets when late approval steps are followed up
+type ApprovalEscalationConfig struct {
+	// RemindAfter is the approval SLA: once a step has waited this long its approvers are reminded
+	RemindAfter time.Duration
+	// EscalateAfter hands a step assigned to a single approver over to the
+	// approver's manager. Zero disables escalation.
+	EscalateAfter time.Duration
+}
+
+// ApprovalEscalationService follows up on trips waiting too long for a decision
+type ApprovalEscalationService struct {
+	stepRepo domain.ApprovalStepRepository
+	tripRepo domain.TripRepository
+	userRepo domain.UserRepository
+	notifier NotificationService
+	config   ApprovalEscalationConfig
+}
+
+func NewApprovalEscalationService(stepRepo domain.ApprovalStepRepository, tripRepo domain.TripRepository, userRepo domain.UserRepository, notifier NotificationService, config ApprovalEscalationConfig) *ApprovalEscalationService {
+	return &ApprovalEscalationService{
+		stepRepo: stepRepo,
+		tripRepo: tripRepo,
+		userRepo: userRepo,
+		notifier: notifier,
+		config:   config,
+	}
+}
+
+// EscalateStaleApprovals goes through the late steps of every organization.
+// A step is reminded once when it passes the SLA and, if it's still waiting
+// at the escalation threshold, is reassigned one level up the manager chain,
+// which starts the clock again for the new approver.
+// Steps open to a whole role have no chain to climb and are only reminded.
+func (s *ApprovalEscalationService) EscalateStaleApprovals(ctx context.Context) error {
+	now := time.Now()
+	allTenants := domain.ContextWithAllTenants(ctx)
+	var errs []error
+
+	// Escalations go first, so a step is never reminded and escalated in the same run
+	if s.config.EscalateAfter > 0 {
+		steps, err := s.stepRepo.ListStale(allTenants, now.Add(-s.config.EscalateAfter))
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if step.RemindedAt == nil || step.ApproverID == nil {
+				continue
+			}
+			if err := s.escalate(domain.ContextWithOrgID(ctx, step.OrgID), step, now); err != nil {
+				errs = append(errs, fmt.Errorf("escalating step %s: %w", step.ID, err))
+			}
+		}
+	}
+
+	steps, err := s.stepRepo.ListStale(allTenants, now.Add(-s.config.RemindAfter))
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if step.RemindedAt != nil {
+			continue
+		}
+		if err := s.remind(domain.ContextWithOrgID(ctx, step.OrgID), step, now); err != nil {
+			errs = append(errs, fmt.Errorf("reminding step %s: %w", step.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ApprovalEscalationService) remind(ctx context.Context, step *domain.ApprovalStep, now time.Time) error {
+	trip, err := s.tripRepo.FindByID(ctx, step.TripID)
+	if err != nil || trip == nil {
+		return err
+	}
+
+	var approvers []*domain.User
+	if step.ApproverID != nil {
+		approver, err := s.userRepo.FindByID(ctx, *step.ApproverID)
+		if err != nil {
+			return err
+		}
+		if approver != nil {
+			approvers = append(approvers, approver)
+		}
+	} else {
+		approvers, err = s.userRepo.ListByRole(ctx, step.Role)
+		if err != nil {
+			return err
+		}
+	}
+
+	message := fmt.Sprintf("Reminder: the trip to %s is still waiting for %s approval.", trip.Destination, step.Role)
+	for _, approver := range approvers {
+		if approver.ID != trip.RequesterID {
+			s.notifier.Send(approver, trip, message)
+		}
+	}
+
+	step.RemindedAt = &now
+	return s.stepRepo.UpdateEscalation(ctx, step)
+}
+
+func (s *ApprovalEscalationService) escalate(ctx context.Context, step *domain.ApprovalStep, now time.Time) error {
+	approver, err := s.userRepo.FindByID(ctx, *step.ApproverID)
+	if err != nil || approver == nil || approver.ManagerID == nil {
+		// Nobody further up the chain
+		return err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, step.TripID)
+	if err != nil || trip == nil {
+		return err
+	}
+	// Requesters can't approve their own trips
+	if *approver.ManagerID == trip.RequesterID {
+		return nil
+	}
+
+	manager, err := s.userRepo.FindByID(ctx, *approver.ManagerID)
+	if err != nil || manager == nil {
+		return err
+	}
+
+	step.ApproverID = &manager.ID
+	step.EscalatedAt = &now
+	step.RemindedAt = nil
+	if err := s.stepRepo.UpdateEscalation(ctx, step); err != nil {
+		return err
+	}
+
+	s.notifier.Send(manager, trip, fmt.Sprintf("The trip to %s was escalated to you after waiting too long for %s's approval.", trip.Destination, approver.Name))
+	s.notifier.Send(approver, trip, fmt.Sprintf("The trip to %s was escalated to %s after waiting too long for your approval.", trip.Destination, manager.Name))
+	return nil
+}
@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupApprovalEscalationService() (*service.ApprovalEscalationService, *mocks.MockApprovalStepRepository, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockNotificationService) {
+	mockStepRepo := new(mocks.MockApprovalStepRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	escalationService := service.NewApprovalEscalationService(mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier, service.ApprovalEscalationConfig{
+		RemindAfter:   48 * time.Hour,
+		EscalateAfter: 96 * time.Hour,
+	})
+	return escalationService, mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier
+}
+
+// The escalation pass looks further back than the reminder pass
+var (
+	escalationCutoff = mock.MatchedBy(func(at time.Time) bool { return time.Since(at) > 72*time.Hour })
+	reminderCutoff   = mock.MatchedBy(func(at time.Time) bool { return time.Since(at) < 72*time.Hour })
+)
+
+func TestApprovalEscalationService_EscalateStaleApprovals(t *testing.T) {
+	orgID := uuid.New()
+	director := &domain.User{ID: uuid.New(), Name: "Director", Role: domain.RoleDirector}
+	manager := &domain.User{ID: uuid.New(), Name: "Manager", Role: domain.RoleManager, ManagerID: &director.ID}
+	requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee, ManagerID: &manager.ID}
+
+	t.Run("Reminds the approver once the SLA passes", func(t *testing.T) {
+		// Arrange
+		escalationService, mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier := setupApprovalEscalationService()
+		trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: requester.ID, Destination: "Paris", Status: domain.StatusRequested}
+		step := &domain.ApprovalStep{ID: uuid.New(), OrgID: orgID, TripID: trip.ID, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending}
+
+		// Mock behavior
+		mockStepRepo.On("ListStale", mock.Anything, escalationCutoff).Return([]*domain.ApprovalStep{}, nil)
+		mockStepRepo.On("ListStale", mock.Anything, reminderCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", mock.Anything, manager.ID).Return(manager, nil)
+		mockNotifier.On("Send", manager, trip, mock.AnythingOfType("string")).Return()
+		mockStepRepo.On("UpdateEscalation", mock.Anything, step).Return(nil)
+
+		// Act
+		err := escalationService.EscalateStaleApprovals(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, step.RemindedAt)
+		assert.Equal(t, manager.ID, *step.ApproverID)
+		mockNotifier.AssertExpectations(t)
+		mockStepRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reminds everyone with the role of an unassigned step", func(t *testing.T) {
+		// Arrange
+		escalationService, mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier := setupApprovalEscalationService()
+		trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: director.ID, Destination: "Paris", Status: domain.StatusRequested}
+		step := &domain.ApprovalStep{ID: uuid.New(), OrgID: orgID, TripID: trip.ID, Role: domain.RoleDirector, Status: domain.StepPending}
+		otherDirector := &domain.User{ID: uuid.New(), Role: domain.RoleDirector}
+
+		// Mock behavior
+		mockStepRepo.On("ListStale", mock.Anything, escalationCutoff).Return([]*domain.ApprovalStep{}, nil)
+		mockStepRepo.On("ListStale", mock.Anything, reminderCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockUserRepo.On("ListByRole", mock.Anything, domain.RoleDirector).Return([]*domain.User{director, otherDirector}, nil)
+		mockNotifier.On("Send", otherDirector, trip, mock.AnythingOfType("string")).Return()
+		mockStepRepo.On("UpdateEscalation", mock.Anything, step).Return(nil)
+
+		// Act
+		err := escalationService.EscalateStaleApprovals(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		// The requester isn't reminded of their own trip
+		mockNotifier.AssertNotCalled(t, "Send", director, trip, mock.Anything)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Escalates to the approver's manager", func(t *testing.T) {
+		// Arrange
+		escalationService, mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier := setupApprovalEscalationService()
+		trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: requester.ID, Destination: "Paris", Status: domain.StatusRequested}
+		remindedAt := time.Now().Add(-50 * time.Hour)
+		step := &domain.ApprovalStep{ID: uuid.New(), OrgID: orgID, TripID: trip.ID, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending, RemindedAt: &remindedAt}
+
+		// Mock behavior
+		mockStepRepo.On("ListStale", mock.Anything, escalationCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockStepRepo.On("ListStale", mock.Anything, reminderCutoff).Return([]*domain.ApprovalStep{}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", mock.Anything, director.ID).Return(director, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockStepRepo.On("UpdateEscalation", mock.Anything, step).Return(nil)
+		mockNotifier.On("Send", director, trip, mock.AnythingOfType("string")).Return()
+		mockNotifier.On("Send", manager, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		err := escalationService.EscalateStaleApprovals(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, director.ID, *step.ApproverID)
+		assert.NotNil(t, step.EscalatedAt)
+		assert.Nil(t, step.RemindedAt)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Reminds before escalating", func(t *testing.T) {
+		// Arrange
+		escalationService, mockStepRepo, mockTripRepo, mockUserRepo, mockNotifier := setupApprovalEscalationService()
+		trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: requester.ID, Destination: "Paris", Status: domain.StatusRequested}
+		step := &domain.ApprovalStep{ID: uuid.New(), OrgID: orgID, TripID: trip.ID, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending}
+
+		// Mock behavior
+		mockStepRepo.On("ListStale", mock.Anything, escalationCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockStepRepo.On("ListStale", mock.Anything, reminderCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", mock.Anything, manager.ID).Return(manager, nil)
+		mockNotifier.On("Send", manager, trip, mock.AnythingOfType("string")).Return()
+		mockStepRepo.On("UpdateEscalation", mock.Anything, step).Return(nil)
+
+		// Act
+		err := escalationService.EscalateStaleApprovals(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, manager.ID, *step.ApproverID)
+		assert.Nil(t, step.EscalatedAt)
+		assert.NotNil(t, step.RemindedAt)
+	})
+
+	t.Run("Top of the chain", func(t *testing.T) {
+		// Arrange
+		escalationService, mockStepRepo, _, mockUserRepo, _ := setupApprovalEscalationService()
+		remindedAt := time.Now().Add(-50 * time.Hour)
+		step := &domain.ApprovalStep{ID: uuid.New(), OrgID: orgID, TripID: uuid.New(), Role: domain.RoleManager, ApproverID: &director.ID, Status: domain.StepPending, RemindedAt: &remindedAt}
+
+		// Mock behavior
+		mockStepRepo.On("ListStale", mock.Anything, escalationCutoff).Return([]*domain.ApprovalStep{step}, nil)
+		mockStepRepo.On("ListStale", mock.Anything, reminderCutoff).Return([]*domain.ApprovalStep{}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, director.ID).Return(director, nil)
+
+		// Act
+		err := escalationService.EscalateStaleApprovals(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, director.ID, *step.ApproverID)
+		mockStepRepo.AssertNotCalled(t, "UpdateEscalation", mock.Anything, mock.Anything)
+	})
+}
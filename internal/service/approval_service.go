@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// PendingApproval is a trip waiting on a decision of the current user
+type PendingApproval struct {
+	Trip *domain.Trip         `json:"trip"`
+	Step *domain.ApprovalStep `json:"step"`
+	// OnBehalfOfID is set when the user can decide the step through a delegation
+	OnBehalfOfID *uuid.UUID `json:"on_behalf_of_id,omitempty"`
+}
+
+// ApprovalPolicyInput holds the data of a new approval policy
+type ApprovalPolicyInput struct {
+	Name            string
+	Priority        int
+	Destinations    []string
+	MinDurationDays int
+	TripType        domain.TripType
+	Steps           []domain.UserRole
+	// MinBudget is in minor units of BudgetCurrency
+	MinBudget      int64
+	BudgetCurrency string
+}
+
+// ApprovalService manages approval policies and the approval queue. Deciding
+// a step is part of TripService.UpdateTripStatus.
+type ApprovalService struct {
+	policyRepo  domain.ApprovalPolicyRepository
+	stepRepo    domain.ApprovalStepRepository
+	tripRepo    domain.TripRepository
+	userRepo    domain.UserRepository
+	delegations domain.DelegationRepository
+}
+
+// ApprovalServiceOption configures optional ApprovalService collaborators
+type ApprovalServiceOption func(*ApprovalService)
+
+// WithDelegatedApprovals adds the steps the user can decide through active
+// delegations to their approval queue
+func WithDelegatedApprovals(repo domain.DelegationRepository) ApprovalServiceOption {
+	return func(s *ApprovalService) {
+		s.delegations = repo
+	}
+}
+
+func NewApprovalService(policyRepo domain.ApprovalPolicyRepository, stepRepo domain.ApprovalStepRepository, tripRepo domain.TripRepository, userRepo domain.UserRepository, opts ...ApprovalServiceOption) *ApprovalService {
+	s := &ApprovalService{
+		policyRepo: policyRepo,
+		stepRepo:   stepRepo,
+		tripRepo:   tripRepo,
+		userRepo:   userRepo,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ApprovalService) CreatePolicy(ctx context.Context, adminID uuid.UUID, input ApprovalPolicyInput) (*domain.ApprovalPolicy, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	policy := &domain.ApprovalPolicy{
+		ID:              uuid.New(),
+		OrgID:           orgID,
+		Name:            input.Name,
+		Priority:        input.Priority,
+		Destinations:    input.Destinations,
+		MinDurationDays: input.MinDurationDays,
+		TripType:        input.TripType,
+		Steps:           input.Steps,
+		MinBudget:       input.MinBudget,
+		BudgetCurrency:  input.BudgetCurrency,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *ApprovalService) ListPolicies(ctx context.Context) ([]*domain.ApprovalPolicy, error) {
+	return s.policyRepo.List(ctx)
+}
+
+// DeletePolicy removes a policy. Trips already created keep their steps.
+func (s *ApprovalService) DeletePolicy(ctx context.Context, adminID, policyID uuid.UUID) error {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return err
+	}
+	return s.policyRepo.Delete(ctx, policyID)
+}
+
+// ListPendingApprovals returns the trips whose current approval step the user can decide
+func (s *ApprovalService) ListPendingApprovals(ctx context.Context, userID uuid.UUID) ([]*PendingApproval, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	pending := make([]*PendingApproval, 0)
+	seen := make(map[uuid.UUID]bool)
+	if pending, err = s.appendPending(ctx, pending, seen, user, user, nil); err != nil {
+		return nil, err
+	}
+
+	if s.delegations == nil {
+		return pending, nil
+	}
+	delegations, err := s.delegations.ListActive(ctx, user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, delegation := range delegations {
+		delegator, err := s.userRepo.FindByID(ctx, delegation.DelegatorID)
+		if err != nil {
+			return nil, err
+		}
+		if delegator == nil {
+			continue
+		}
+		if pending, err = s.appendPending(ctx, pending, seen, user, delegator, delegation); err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+// appendPending adds the current steps the approver can decide to the queue
+// of user, skipping steps already queued. approver is the user themselves or,
+// through the delegation, the delegator.
+func (s *ApprovalService) appendPending(ctx context.Context, pending []*PendingApproval, seen map[uuid.UUID]bool, user, approver *domain.User, delegation *domain.Delegation) ([]*PendingApproval, error) {
+	steps, err := s.stepRepo.ListCurrent(ctx, approver.ID, approver.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range steps {
+		if seen[step.ID] {
+			continue
+		}
+		trip, err := s.tripRepo.FindByID(ctx, step.TripID)
+		if err != nil {
+			return nil, err
+		}
+		// Requesters never decide their own trips, even when they hold the step's role
+		if trip == nil || trip.RequesterID == user.ID || trip.RequesterID == approver.ID || trip.Status != domain.StatusRequested {
+			continue
+		}
+		if delegation != nil && !delegation.Covers(trip) {
+			continue
+		}
+
+		seen[step.ID] = true
+		item := &PendingApproval{Trip: trip, Step: step}
+		if delegation != nil {
+			item.OnBehalfOfID = &delegation.DelegatorID
+		}
+		pending = append(pending, item)
+	}
+	return pending, nil
+}
+
+// GetTripApprovals returns the approval steps of a trip to its requester and to approvers
+func (s *ApprovalService) GetTripApprovals(ctx context.Context, tripID, userID uuid.UUID) ([]*domain.ApprovalStep, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+
+	if trip.RequesterID != userID {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.Role == domain.RoleEmployee {
+			return nil, ErrPermissionDenied
+		}
+	}
+
+	return s.stepRepo.FindByTripID(ctx, tripID)
+}
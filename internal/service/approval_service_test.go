@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupApprovalService() (*service.ApprovalService, *mocks.MockApprovalPolicyRepository, *mocks.MockApprovalStepRepository, *mocks.MockTripRepository, *mocks.MockUserRepository) {
+	mockPolicyRepo := new(mocks.MockApprovalPolicyRepository)
+	mockStepRepo := new(mocks.MockApprovalStepRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	approvalService := service.NewApprovalService(mockPolicyRepo, mockStepRepo, mockTripRepo, mockUserRepo)
+	return approvalService, mockPolicyRepo, mockStepRepo, mockTripRepo, mockUserRepo
+}
+
+func TestApprovalService_CreatePolicy(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	input := service.ApprovalPolicyInput{
+		Name:         "International",
+		Destinations: []string{"Paris"},
+		Steps:        []domain.UserRole{domain.RoleManager, domain.RoleDirector},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		approvalService, mockPolicyRepo, _, _, mockUserRepo := setupApprovalService()
+		admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockPolicyRepo.On("Create", ctx, mock.AnythingOfType("*domain.ApprovalPolicy")).Return(nil)
+
+		// Act
+		policy, err := approvalService.CreatePolicy(ctx, admin.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, input.Steps, policy.Steps)
+		mockPolicyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only admins manage policies", func(t *testing.T) {
+		// Arrange
+		approvalService, mockPolicyRepo, _, _, mockUserRepo := setupApprovalService()
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+
+		// Act
+		policy, err := approvalService.CreatePolicy(ctx, manager.ID, input)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, policy)
+		mockPolicyRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestApprovalService_ListPendingApprovals(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Skips the user's own trips", func(t *testing.T) {
+		// Arrange
+		approvalService, _, mockStepRepo, mockTripRepo, mockUserRepo := setupApprovalService()
+		director := &domain.User{ID: uuid.New(), Role: domain.RoleDirector}
+		otherTrip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+		ownTrip := &domain.Trip{ID: uuid.New(), RequesterID: director.ID, Status: domain.StatusRequested}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: otherTrip.ID, Role: domain.RoleDirector, Status: domain.StepPending},
+			{ID: uuid.New(), TripID: ownTrip.ID, Role: domain.RoleDirector, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, director.ID).Return(director, nil)
+		mockStepRepo.On("ListCurrent", ctx, director.ID, domain.RoleDirector).Return(steps, nil)
+		mockTripRepo.On("FindByID", ctx, otherTrip.ID).Return(otherTrip, nil)
+		mockTripRepo.On("FindByID", ctx, ownTrip.ID).Return(ownTrip, nil)
+
+		// Act
+		pending, err := approvalService.ListPendingApprovals(ctx, director.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, otherTrip.ID, pending[0].Trip.ID)
+	})
+}
+
+func TestApprovalService_ListPendingApprovals_Delegations(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	// Arrange
+	mockStepRepo := new(mocks.MockApprovalStepRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockDelegationRepo := new(mocks.MockDelegationRepository)
+	approvalService := service.NewApprovalService(new(mocks.MockApprovalPolicyRepository), mockStepRepo, mockTripRepo, mockUserRepo,
+		service.WithDelegatedApprovals(mockDelegationRepo))
+
+	delegate := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	director := &domain.User{ID: uuid.New(), Role: domain.RoleDirector}
+	trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+	step := &domain.ApprovalStep{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleDirector, Status: domain.StepPending}
+
+	// Mock behavior
+	mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+	mockStepRepo.On("ListCurrent", ctx, delegate.ID, domain.RoleEmployee).Return([]*domain.ApprovalStep{}, nil)
+	mockDelegationRepo.On("ListActive", ctx, delegate.ID, mock.AnythingOfType("time.Time")).Return([]*domain.Delegation{
+		{DelegatorID: director.ID, DelegateID: delegate.ID},
+	}, nil)
+	mockUserRepo.On("FindByID", ctx, director.ID).Return(director, nil)
+	mockStepRepo.On("ListCurrent", ctx, director.ID, domain.RoleDirector).Return([]*domain.ApprovalStep{step}, nil)
+	mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+	// Act
+	pending, err := approvalService.ListPendingApprovals(ctx, delegate.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, trip, pending[0].Trip)
+	assert.Equal(t, director.ID, *pending[0].OnBehalfOfID)
+}
+
+func TestApprovalService_GetTripApprovals(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Employees only see their own trips", func(t *testing.T) {
+		// Arrange
+		approvalService, _, mockStepRepo, mockTripRepo, mockUserRepo := setupApprovalService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New()}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		steps, err := approvalService.GetTripApprovals(ctx, trip.ID, employee.ID)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, steps)
+		mockStepRepo.AssertNotCalled(t, "FindByTripID")
+	})
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var (
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("the file is larger than the attachment size limit")
+	ErrUnsupportedAttachment = errors.New("attachments must be PDF, JPEG, PNG or WebP files")
+	ErrInvalidDownloadURL    = errors.New("the download URL is invalid or expired")
+)
+
+// AttachmentConfig sets the limits of uploads and how downloads are signed
+type AttachmentConfig struct {
+	// MaxSize is the largest file accepted, in bytes
+	MaxSize int64
+	// URLTTL is how long download URLs stay valid
+	URLTTL time.Duration
+	// DownloadBaseURL is the public URL of the API. Blobs of stores that don't
+	// sign URLs themselves are downloaded from it.
+	DownloadBaseURL string
+	// URLSecret signs the download URLs served by the API
+	URLSecret string
+}
+
+// AttachmentInput is an uploaded file
+type AttachmentInput struct {
+	FileName string
+	Content  []byte
+	// ExpenseItemID attaches the file to an expense of the trip, e.g. as its receipt
+	ExpenseItemID *uuid.UUID
+}
+
+// AttachmentURL is a time-limited URL that downloads an attachment without credentials
+type AttachmentURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AttachmentService stores the files uploaded to trips and their expenses.
+// Contents are deduplicated by checksum: uploading a file the trip already has
+// returns the existing attachment, and the organization stores each content once.
+type AttachmentService struct {
+	repo     domain.AttachmentRepository
+	tripRepo domain.TripRepository
+	expenses domain.ExpenseReportRepository
+	userRepo domain.UserRepository
+	store    domain.BlobStore
+	config   AttachmentConfig
+}
+
+func NewAttachmentService(repo domain.AttachmentRepository, tripRepo domain.TripRepository, expenses domain.ExpenseReportRepository,
+	userRepo domain.UserRepository, store domain.BlobStore, config AttachmentConfig) *AttachmentService {
+	return &AttachmentService{
+		repo:     repo,
+		tripRepo: tripRepo,
+		expenses: expenses,
+		userRepo: userRepo,
+		store:    store,
+		config:   config,
+	}
+}
+
+// MaxSize is the largest file accepted, in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+// Upload attaches a file to a trip of the user. The returned flag is false when
+// the trip already had the same file and the existing attachment is returned.
+func (s *AttachmentService) Upload(ctx context.Context, userID, tripID uuid.UUID, input AttachmentInput) (*domain.Attachment, bool, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, false, err
+	}
+	if trip.RequesterID != userID {
+		return nil, false, ErrPermissionDenied
+	}
+
+	if int64(len(input.Content)) > s.config.MaxSize {
+		return nil, false, ErrAttachmentTooLarge
+	}
+	if len(input.Content) == 0 {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("the file is empty")
+		return nil, false, validationErrors
+	}
+	contentType, ok := domain.SniffAttachmentType(input.Content)
+	if !ok {
+		return nil, false, ErrUnsupportedAttachment
+	}
+	if input.ExpenseItemID != nil {
+		if err := s.checkExpenseItem(ctx, tripID, *input.ExpenseItemID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	checksum := sha256.Sum256(input.Content)
+	attachment := &domain.Attachment{
+		ID:            uuid.New(),
+		OrgID:         trip.OrgID,
+		TripID:        trip.ID,
+		ExpenseItemID: input.ExpenseItemID,
+		UploadedBy:    userID,
+		FileName:      domain.CleanFileName(input.FileName),
+		ContentType:   contentType,
+		Size:          int64(len(input.Content)),
+		Checksum:      hex.EncodeToString(checksum[:]),
+		StorageKey:    domain.AttachmentStorageKey(trip.OrgID, hex.EncodeToString(checksum[:])),
+		CreatedAt:     time.Now(),
+	}
+	if err := attachment.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.repo.FindByChecksum(ctx, attachment.Checksum)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, other := range existing {
+		if other.SameTarget(attachment) {
+			return other, false, nil
+		}
+	}
+	// The content is stored with the first attachment that has it
+	if len(existing) == 0 {
+		if err := s.store.Put(ctx, attachment.StorageKey, input.Content, contentType); err != nil {
+			return nil, false, err
+		}
+	} else {
+		attachment.StorageKey = existing[0].StorageKey
+	}
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		return nil, false, err
+	}
+	return attachment, true, nil
+}
+
+// ListAttachments returns the attachments of a trip. The traveler, admins and finance see them.
+func (s *AttachmentService) ListAttachments(ctx context.Context, userID, tripID uuid.UUID) ([]*domain.Attachment, error) {
+	if _, err := s.viewableTrip(ctx, userID, tripID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []*domain.Attachment{}
+	}
+	return attachments, nil
+}
+
+// DownloadURL returns a signed URL that downloads the attachment until it
+// expires. Stores that sign URLs are downloaded from directly; the others
+// through the API.
+func (s *AttachmentService) DownloadURL(ctx context.Context, userID, tripID, attachmentID uuid.UUID) (*AttachmentURL, error) {
+	attachment, err := s.findAttachment(ctx, userID, tripID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.config.URLTTL).Truncate(time.Second)
+	if signer, ok := s.store.(domain.BlobURLSigner); ok {
+		signed, err := signer.SignedURL(ctx, attachment.StorageKey, attachment.FileName, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		return &AttachmentURL{URL: signed, ExpiresAt: expiresAt}, nil
+	}
+
+	query := url.Values{}
+	query.Set("org", attachment.OrgID.String())
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", s.downloadSignature(attachment.ID, attachment.OrgID, expiresAt.Unix()))
+	return &AttachmentURL{
+		URL:       fmt.Sprintf("%s/attachments/%s/download?%s", strings.TrimSuffix(s.config.DownloadBaseURL, "/"), attachment.ID, query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// OpenDownload checks the signature of a download URL made by DownloadURL and
+// returns the attachment with its content. The URL is the credential: it
+// carries the organization and needs no user.
+func (s *AttachmentService) OpenDownload(ctx context.Context, attachmentID, orgID uuid.UUID, expires int64, signature string) (*domain.Attachment, []byte, error) {
+	expected := s.downloadSignature(attachmentID, orgID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) || time.Now().Unix() > expires {
+		return nil, nil, ErrInvalidDownloadURL
+	}
+
+	ctx = domain.ContextWithOrgID(ctx, orgID)
+	attachment, err := s.repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (s *AttachmentService) downloadSignature(attachmentID, orgID uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.URLSecret))
+	fmt.Fprintf(mac, "%s|%s|%d", attachmentID, orgID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkExpenseItem makes sure the expense is in the trip's report and the report can still be changed
+func (s *AttachmentService) checkExpenseItem(ctx context.Context, tripID, itemID uuid.UUID) error {
+	report, err := s.expenses.FindByTripID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	if report == nil || report.FindItem(itemID) == nil {
+		return ErrExpenseItemNotFound
+	}
+	if !report.Editable() {
+		return ErrInvalidStatus
+	}
+	return nil
+}
+
+func (s *AttachmentService) findTrip(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+	return trip, nil
+}
+
+// viewableTrip returns the trip when the user is its traveler, an admin or finance
+func (s *AttachmentService) viewableTrip(ctx context.Context, userID, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID != userID {
+		if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+	return trip, nil
+}
+
+func (s *AttachmentService) findAttachment(ctx context.Context, userID, tripID, attachmentID uuid.UUID) (*domain.Attachment, error) {
+	if _, err := s.viewableTrip(ctx, userID, tripID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil || attachment.TripID != tripID {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
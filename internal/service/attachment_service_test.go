@@ -0,0 +1,280 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAttachmentService() (*service.AttachmentService, *mocks.MockAttachmentRepository, *mocks.MockTripRepository, *mocks.MockExpenseReportRepository, *mocks.MockUserRepository, *mocks.MockBlobStore) {
+	mockAttachmentRepo := new(mocks.MockAttachmentRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockReportRepo := new(mocks.MockExpenseReportRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockStore := new(mocks.MockBlobStore)
+	attachmentService := service.NewAttachmentService(mockAttachmentRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockStore, service.AttachmentConfig{
+		MaxSize:         1024,
+		URLTTL:          15 * time.Minute,
+		DownloadBaseURL: "https://api.example.com/",
+		URLSecret:       "test-secret",
+	})
+	return attachmentService, mockAttachmentRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockStore
+}
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+
+func TestAttachmentService_Upload(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	travelerID := uuid.New()
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: travelerID, Status: domain.StatusApproved}
+	input := service.AttachmentInput{FileName: `C:\scans\recibo "hotel".pdf`, Content: testPDF}
+
+	t.Run("Stores a new file", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, mockStore := setupAttachmentService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAttachmentRepo.On("FindByChecksum", ctx, mock.AnythingOfType("string")).Return([]*domain.Attachment{}, nil)
+		mockStore.On("Put", ctx, mock.AnythingOfType("string"), testPDF, "application/pdf").Return(nil)
+		mockAttachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		// Act
+		attachment, created, err := attachmentService.Upload(ctx, travelerID, trip.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "application/pdf", attachment.ContentType)
+		assert.Equal(t, "recibo hotel.pdf", attachment.FileName)
+		assert.Equal(t, int64(len(testPDF)), attachment.Size)
+		assert.Equal(t, domain.AttachmentStorageKey(orgID, attachment.Checksum), attachment.StorageKey)
+		mockStore.AssertCalled(t, "Put", ctx, attachment.StorageKey, testPDF, "application/pdf")
+	})
+
+	t.Run("Same file on the trip returns the existing attachment", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, mockStore := setupAttachmentService()
+		existing := &domain.Attachment{ID: uuid.New(), OrgID: orgID, TripID: trip.ID, StorageKey: "attachments/existing"}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAttachmentRepo.On("FindByChecksum", ctx, mock.AnythingOfType("string")).Return([]*domain.Attachment{existing}, nil)
+
+		// Act
+		attachment, created, err := attachmentService.Upload(ctx, travelerID, trip.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existing, attachment)
+		mockStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockAttachmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Same file elsewhere shares the stored content", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, mockStore := setupAttachmentService()
+		existing := &domain.Attachment{ID: uuid.New(), OrgID: orgID, TripID: uuid.New(), StorageKey: "attachments/existing"}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAttachmentRepo.On("FindByChecksum", ctx, mock.AnythingOfType("string")).Return([]*domain.Attachment{existing}, nil)
+		mockAttachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		// Act
+		attachment, created, err := attachmentService.Upload(ctx, travelerID, trip.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.NotEqual(t, existing.ID, attachment.ID)
+		assert.Equal(t, "attachments/existing", attachment.StorageKey)
+		mockStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, _, _, _ := setupAttachmentService()
+		large := append(append([]byte{}, testPDF...), make([]byte, 1024)...)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		_, _, err := attachmentService.Upload(ctx, travelerID, trip.ID, service.AttachmentInput{FileName: "big.pdf", Content: large})
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrAttachmentTooLarge)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, _, _, _ := setupAttachmentService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		_, _, err := attachmentService.Upload(ctx, travelerID, trip.ID, service.AttachmentInput{
+			FileName: "recibo.pdf",
+			Content:  []byte("<html><script>alert(1)</script></html>"),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrUnsupportedAttachment)
+	})
+
+	t.Run("Only the traveler uploads", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, _, _, _ := setupAttachmentService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		_, _, err := attachmentService.Upload(ctx, uuid.New(), trip.ID, input)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+
+	t.Run("Expense of a submitted report", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, mockReportRepo, _, _ := setupAttachmentService()
+		itemID := uuid.New()
+		report := &domain.ExpenseReport{TripID: trip.ID, Status: domain.ExpenseSubmitted, Items: []domain.ExpenseItem{{ID: itemID}}}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+
+		// Act
+		_, _, err := attachmentService.Upload(ctx, travelerID, trip.ID, service.AttachmentInput{
+			FileName: "recibo.pdf", Content: testPDF, ExpenseItemID: &itemID,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Unknown expense", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, mockReportRepo, _, _ := setupAttachmentService()
+		itemID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(&domain.ExpenseReport{Status: domain.ExpenseDraft}, nil)
+
+		// Act
+		_, _, err := attachmentService.Upload(ctx, travelerID, trip.ID, service.AttachmentInput{
+			FileName: "recibo.pdf", Content: testPDF, ExpenseItemID: &itemID,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrExpenseItemNotFound)
+	})
+}
+
+func TestAttachmentService_Download(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	travelerID := uuid.New()
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: travelerID}
+	attachment := &domain.Attachment{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		TripID:      trip.ID,
+		FileName:    "recibo.pdf",
+		ContentType: "application/pdf",
+		StorageKey:  "attachments/key",
+	}
+
+	signedURL := func(t *testing.T, attachmentService *service.AttachmentService, mockAttachmentRepo *mocks.MockAttachmentRepository,
+		mockTripRepo *mocks.MockTripRepository) url.Values {
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAttachmentRepo.On("FindByID", mock.Anything, attachment.ID).Return(attachment, nil)
+
+		signed, err := attachmentService.DownloadURL(ctx, travelerID, trip.ID, attachment.ID)
+		require.NoError(t, err)
+		u, err := url.Parse(signed.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.example.com/attachments/"+attachment.ID.String()+"/download", u.Scheme+"://"+u.Host+u.Path)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), signed.ExpiresAt, time.Minute)
+		return u.Query()
+	}
+
+	t.Run("Signed URL opens the attachment", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, mockStore := setupAttachmentService()
+		query := signedURL(t, attachmentService, mockAttachmentRepo, mockTripRepo)
+		expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+		// Mock behavior
+		mockStore.On("Get", mock.Anything, "attachments/key").Return(testPDF, nil)
+
+		// Act
+		opened, content, err := attachmentService.OpenDownload(context.Background(), attachment.ID, orgID, expires, query.Get("signature"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, attachment, opened)
+		assert.Equal(t, testPDF, content)
+		// The URL carries the tenant of the download
+		orgFromCtx, _ := domain.OrgIDFromContext(mockAttachmentRepo.Calls[len(mockAttachmentRepo.Calls)-1].Arguments.Get(0).(context.Context))
+		assert.Equal(t, orgID, orgFromCtx)
+	})
+
+	t.Run("Tampered URL", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, _ := setupAttachmentService()
+		query := signedURL(t, attachmentService, mockAttachmentRepo, mockTripRepo)
+		expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+		// Act
+		_, _, err := attachmentService.OpenDownload(context.Background(), attachment.ID, uuid.New(), expires, query.Get("signature"))
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidDownloadURL)
+	})
+
+	t.Run("Expired URL", func(t *testing.T) {
+		// Arrange
+		attachmentService, mockAttachmentRepo, mockTripRepo, _, _, _ := setupAttachmentService()
+		query := signedURL(t, attachmentService, mockAttachmentRepo, mockTripRepo)
+
+		// Act
+		_, _, err := attachmentService.OpenDownload(context.Background(), attachment.ID, orgID, time.Now().Add(-time.Minute).Unix(), query.Get("signature"))
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidDownloadURL)
+	})
+
+	t.Run("Other users can't sign URLs", func(t *testing.T) {
+		// Arrange
+		attachmentService, _, mockTripRepo, _, mockUserRepo, _ := setupAttachmentService()
+		other := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", ctx, other.ID).Return(other, nil)
+
+		// Act
+		_, err := attachmentService.DownloadURL(ctx, other.ID, trip.ID, attachment.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
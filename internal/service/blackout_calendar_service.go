@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrBlackoutCalendarNotFound = errors.New("blackout calendar not found")
+
+// BlackoutCalendarInput holds the data of a blackout calendar
+type BlackoutCalendarInput struct {
+	// DepartmentID limits the calendar to a department; it covers the whole organization when nil
+	DepartmentID *uuid.UUID
+	Name         string
+	Severity     domain.RuleSeverity
+	Periods      []domain.BlackoutPeriod
+}
+
+// BlackoutCalendarService manages the blackout calendars of an organization.
+// Trips are checked against them by TripService when WithBlackoutCalendars is set.
+type BlackoutCalendarService struct {
+	repo           domain.BlackoutCalendarRepository
+	departmentRepo domain.DepartmentRepository
+	userRepo       domain.UserRepository
+}
+
+func NewBlackoutCalendarService(repo domain.BlackoutCalendarRepository, departmentRepo domain.DepartmentRepository, userRepo domain.UserRepository) *BlackoutCalendarService {
+	return &BlackoutCalendarService{
+		repo:           repo,
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// CreateCalendar adds a blackout calendar. Only admins manage calendars.
+func (s *BlackoutCalendarService) CreateCalendar(ctx context.Context, userID uuid.UUID, input BlackoutCalendarInput) (*domain.BlackoutCalendar, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	calendar := &domain.BlackoutCalendar{
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		DepartmentID: input.DepartmentID,
+		Name:         input.Name,
+		Severity:     input.Severity,
+		Periods:      input.Periods,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.validateCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// ImportCalendar replaces the periods of a calendar with the events of an iCalendar file
+func (s *BlackoutCalendarService) ImportCalendar(ctx context.Context, userID, calendarID uuid.UUID, document []byte) (*domain.BlackoutCalendar, error) {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.repo.FindByID(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar == nil {
+		return nil, ErrBlackoutCalendarNotFound
+	}
+
+	periods, err := domain.ParseICalendar(document)
+	if err != nil {
+		return nil, err
+	}
+	calendar.Periods = periods
+	calendar.UpdatedAt = time.Now()
+
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *BlackoutCalendarService) ListCalendars(ctx context.Context) ([]*domain.BlackoutCalendar, error) {
+	calendars, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if calendars == nil {
+		calendars = []*domain.BlackoutCalendar{}
+	}
+	return calendars, nil
+}
+
+func (s *BlackoutCalendarService) DeleteCalendar(ctx context.Context, userID, calendarID uuid.UUID) error {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return err
+	}
+
+	calendar, err := s.repo.FindByID(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if calendar == nil {
+		return ErrBlackoutCalendarNotFound
+	}
+	return s.repo.Delete(ctx, calendarID)
+}
+
+func (s *BlackoutCalendarService) validateCalendar(ctx context.Context, calendar *domain.BlackoutCalendar) error {
+	if err := calendar.Validate(); err != nil {
+		return err
+	}
+
+	if calendar.DepartmentID != nil {
+		department, err := s.departmentRepo.FindByID(ctx, *calendar.DepartmentID)
+		if err != nil {
+			return err
+		}
+		if department == nil {
+			return ErrDepartmentNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupBlackoutCalendarService() (*service.BlackoutCalendarService, *mocks.MockBlackoutCalendarRepository, *mocks.MockDepartmentRepository, *mocks.MockUserRepository) {
+	mockRepo := new(mocks.MockBlackoutCalendarRepository)
+	mockDepartmentRepo := new(mocks.MockDepartmentRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	blackoutCalendarService := service.NewBlackoutCalendarService(mockRepo, mockDepartmentRepo, mockUserRepo)
+	return blackoutCalendarService, mockRepo, mockDepartmentRepo, mockUserRepo
+}
+
+func TestBlackoutCalendarService_CreateCalendar(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, mockDepartmentRepo, mockUserRepo := setupBlackoutCalendarService()
+		departmentID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockDepartmentRepo.On("FindByID", ctx, departmentID).Return(&domain.Department{ID: departmentID}, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.BlackoutCalendar")).Return(nil)
+
+		// Act
+		calendar, err := blackoutCalendarService.CreateCalendar(ctx, admin.ID, service.BlackoutCalendarInput{
+			DepartmentID: &departmentID,
+			Name:         "Finance",
+			Severity:     domain.SeverityBlock,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, calendar.OrgID)
+		assert.Equal(t, &departmentID, calendar.DepartmentID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown department", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, mockDepartmentRepo, mockUserRepo := setupBlackoutCalendarService()
+		departmentID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockDepartmentRepo.On("FindByID", ctx, departmentID).Return(nil, nil)
+
+		// Act
+		_, err := blackoutCalendarService.CreateCalendar(ctx, admin.ID, service.BlackoutCalendarInput{
+			DepartmentID: &departmentID,
+			Name:         "Finance",
+			Severity:     domain.SeverityWarn,
+		})
+
+		// Assert
+		assert.Equal(t, service.ErrDepartmentNotFound, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Only admins manage calendars", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, _, mockUserRepo := setupBlackoutCalendarService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		_, err := blackoutCalendarService.CreateCalendar(ctx, employee.ID, service.BlackoutCalendarInput{Name: "Finance", Severity: domain.SeverityWarn})
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBlackoutCalendarService_ImportCalendar(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	document := []byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Tiradentes\nDTSTART;VALUE=DATE:20300421\nEND:VEVENT\nEND:VCALENDAR\n")
+
+	t.Run("Replaces the periods", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, _, mockUserRepo := setupBlackoutCalendarService()
+		calendar := &domain.BlackoutCalendar{ID: uuid.New(), OrgID: uuid.New(), Name: "Holidays", Severity: domain.SeverityWarn,
+			Periods: []domain.BlackoutPeriod{{Summary: "Old"}}}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByID", ctx, calendar.ID).Return(calendar, nil)
+		mockRepo.On("Update", ctx, calendar).Return(nil)
+
+		// Act
+		imported, err := blackoutCalendarService.ImportCalendar(ctx, admin.ID, calendar.ID, document)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, imported.Periods, 1)
+		assert.Equal(t, "Tiradentes", imported.Periods[0].Summary)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, _, mockUserRepo := setupBlackoutCalendarService()
+		calendar := &domain.BlackoutCalendar{ID: uuid.New(), Name: "Holidays", Severity: domain.SeverityWarn}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByID", ctx, calendar.ID).Return(calendar, nil)
+
+		// Act
+		_, err := blackoutCalendarService.ImportCalendar(ctx, admin.ID, calendar.ID, []byte("not a calendar"))
+
+		// Assert
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Calendar not found", func(t *testing.T) {
+		// Arrange
+		blackoutCalendarService, mockRepo, _, mockUserRepo := setupBlackoutCalendarService()
+		calendarID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByID", ctx, calendarID).Return(nil, nil)
+
+		// Act
+		_, err := blackoutCalendarService.ImportCalendar(ctx, admin.ID, calendarID, document)
+
+		// Assert
+		assert.Equal(t, service.ErrBlackoutCalendarNotFound, err)
+	})
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// BudgetInput holds the data of a cost center budget
+type BudgetInput struct {
+	// CostCenterID is only used when the budget is created
+	CostCenterID uuid.UUID
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	Amount       int64
+	Severity     domain.RuleSeverity
+}
+
+// BudgetService manages the travel budgets of cost centers. Budgets are in
+// the organization's base currency, so trips in other currencies count with
+// their converted total, and concluded trips count what their approved
+// expense reports spent.
+type BudgetService struct {
+	repo           domain.CostCenterBudgetRepository
+	tripRepo       domain.TripRepository
+	expenseRepo    domain.ExpenseReportRepository
+	costCenterRepo domain.CostCenterRepository
+	orgRepo        domain.OrganizationRepository
+	userRepo       domain.UserRepository
+}
+
+func NewBudgetService(repo domain.CostCenterBudgetRepository, tripRepo domain.TripRepository, expenseRepo domain.ExpenseReportRepository,
+	costCenterRepo domain.CostCenterRepository, orgRepo domain.OrganizationRepository, userRepo domain.UserRepository) *BudgetService {
+	return &BudgetService{
+		repo:           repo,
+		tripRepo:       tripRepo,
+		expenseRepo:    expenseRepo,
+		costCenterRepo: costCenterRepo,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// CreateBudget sets the budget of a cost center for a period. Only admins manage budgets.
+func (s *BudgetService) CreateBudget(ctx context.Context, adminID uuid.UUID, input BudgetInput) (*domain.CostCenterBudget, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	// A missing cost center is reported by the validation
+	if input.CostCenterID != uuid.Nil {
+		costCenter, err := s.costCenterRepo.FindByID(ctx, input.CostCenterID)
+		if err != nil {
+			return nil, err
+		}
+		if costCenter == nil {
+			return nil, ErrCostCenterNotFound
+		}
+	}
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &domain.CostCenterBudget{
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		CostCenterID: input.CostCenterID,
+		PeriodStart:  input.PeriodStart,
+		PeriodEnd:    input.PeriodEnd,
+		Amount:       input.Amount,
+		Currency:     currency,
+		Severity:     input.Severity,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.validateBudget(ctx, budget); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// UpdateBudget changes the period, amount and severity of a budget
+func (s *BudgetService) UpdateBudget(ctx context.Context, adminID, budgetID uuid.UUID, input BudgetInput) (*domain.CostCenterBudget, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.repo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget == nil {
+		return nil, ErrBudgetNotFound
+	}
+
+	budget.PeriodStart = input.PeriodStart
+	budget.PeriodEnd = input.PeriodEnd
+	budget.Amount = input.Amount
+	budget.Severity = input.Severity
+	budget.UpdatedAt = time.Now()
+	if err := s.validateBudget(ctx, budget); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// ListBudgets returns the budgets of a cost center, or of all of them when costCenterID is nil.
+// Admins and finance see budgets.
+func (s *BudgetService) ListBudgets(ctx context.Context, userID uuid.UUID, costCenterID *uuid.UUID) ([]*domain.CostCenterBudget, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.repo.ListByCostCenter(ctx, costCenterID)
+	if err != nil {
+		return nil, err
+	}
+	if budgets == nil {
+		budgets = []*domain.CostCenterBudget{}
+	}
+	return budgets, nil
+}
+
+// GetBudget returns a budget with the trips charged to it and what is left
+func (s *BudgetService) GetBudget(ctx context.Context, userID, budgetID uuid.UUID) (*domain.BudgetReport, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.repo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget == nil {
+		return nil, ErrBudgetNotFound
+	}
+
+	consumption, err := budgetConsumption(ctx, s.tripRepo, s.expenseRepo, budget)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BudgetReport{CostCenterBudget: budget, Consumption: consumption}, nil
+}
+
+// validateBudget runs the domain validation and checks the period doesn't
+// overlap another budget of the cost center, so a trip is charged to a single budget
+func (s *BudgetService) validateBudget(ctx context.Context, budget *domain.CostCenterBudget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	budgets, err := s.repo.ListByCostCenter(ctx, &budget.CostCenterID)
+	if err != nil {
+		return err
+	}
+	for _, other := range budgets {
+		if other.ID != budget.ID && other.Overlaps(budget) {
+			validationErrors := domain.NewValidationErrors()
+			validationErrors.Add(fmt.Sprintf("period overlaps the budget of the cost center for %s to %s",
+				other.PeriodStart.Format("2006-01-02"), other.PeriodEnd.Format("2006-01-02")))
+			return validationErrors
+		}
+	}
+	return nil
+}
+
+// requireBudgetViewer lets admins and finance users through
+func requireBudgetViewer(ctx context.Context, repo domain.UserRepository, userID uuid.UUID) error {
+	user, err := repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || (user.Role != domain.RoleAdmin && user.Role != domain.RoleFinance) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// budgetConsumption adds up the approved and concluded trips charged to the
+// budget, with the expense reports of the concluded ones
+func budgetConsumption(ctx context.Context, tripRepo domain.TripRepository, expenseRepo domain.ExpenseReportRepository,
+	budget *domain.CostCenterBudget) (domain.BudgetConsumption, error) {
+	// A trip starting the day before in UTC may still start inside the period in its own zone
+	from := budget.PeriodStart.AddDate(0, 0, -1)
+
+	var trips []*domain.Trip
+	for _, status := range []domain.TripStatus{domain.StatusApproved, domain.StatusConcluded} {
+		found, err := tripRepo.List(ctx, domain.ListTripsParams{CostCenterID: &budget.CostCenterID, Status: &status, StartDate: &from})
+		if err != nil {
+			return domain.BudgetConsumption{}, err
+		}
+		trips = append(trips, found...)
+	}
+
+	var concludedIDs []uuid.UUID
+	for _, trip := range trips {
+		if trip.Status == domain.StatusConcluded {
+			concludedIDs = append(concludedIDs, trip.ID)
+		}
+	}
+	var reports []*domain.ExpenseReport
+	if len(concludedIDs) > 0 {
+		var err error
+		if reports, err = expenseRepo.ListByTripIDs(ctx, concludedIDs); err != nil {
+			return domain.BudgetConsumption{}, err
+		}
+	}
+	return budget.Consumption(trips, reports), nil
+}
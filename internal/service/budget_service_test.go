@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBudgetService_CreateBudget(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	costCenterID := uuid.New()
+	input := service.BudgetInput{
+		CostCenterID: costCenterID,
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       1000000,
+		Severity:     domain.SeverityBlock,
+	}
+
+	setup := func(existing []*domain.CostCenterBudget) (*service.BudgetService, *mocks.MockCostCenterBudgetRepository) {
+		mockRepo := new(mocks.MockCostCenterBudgetRepository)
+		mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockCostCenterRepo.On("FindByID", ctx, costCenterID).Return(&domain.CostCenter{ID: costCenterID, Active: true}, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRepo.On("ListByCostCenter", ctx, &costCenterID).Return(existing, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.CostCenterBudget")).Return(nil)
+		budgetService := service.NewBudgetService(mockRepo, new(mocks.MockTripRepository), new(mocks.MockExpenseReportRepository), mockCostCenterRepo, mockOrgRepo, mockUserRepo)
+		return budgetService, mockRepo
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		budgetService, mockRepo := setup(nil)
+
+		// Act
+		budget, err := budgetService.CreateBudget(ctx, admin.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, budget.OrgID)
+		assert.Equal(t, "BRL", budget.Currency)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Overlapping period", func(t *testing.T) {
+		// Arrange
+		budgetService, mockRepo := setup([]*domain.CostCenterBudget{{
+			ID:           uuid.New(),
+			CostCenterID: costCenterID,
+			PeriodStart:  time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:    time.Date(2030, 8, 31, 0, 0, 0, 0, time.UTC),
+		}})
+
+		// Act
+		budget, err := budgetService.CreateBudget(ctx, admin.ID, input)
+
+		// Assert
+		assert.Nil(t, budget)
+		assert.Contains(t, err.Error(), "period overlaps the budget of the cost center for 2030-06-01 to 2030-08-31")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Finance cannot create budgets", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(mocks.MockUserRepository)
+		budgetService := service.NewBudgetService(new(mocks.MockCostCenterBudgetRepository), new(mocks.MockTripRepository),
+			new(mocks.MockExpenseReportRepository), new(mocks.MockCostCenterRepository), new(mocks.MockOrganizationRepository), mockUserRepo)
+		financeUser := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, financeUser.ID).Return(financeUser, nil)
+
+		// Act
+		budget, err := budgetService.CreateBudget(ctx, financeUser.ID, input)
+
+		// Assert
+		assert.Nil(t, budget)
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
+
+func TestBudgetService_GetBudget(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	budget := &domain.CostCenterBudget{
+		ID:           uuid.New(),
+		CostCenterID: uuid.New(),
+		PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:       1000000,
+		Currency:     "BRL",
+		Severity:     domain.SeverityBlock,
+	}
+
+	// Arrange
+	mockRepo := new(mocks.MockCostCenterBudgetRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockExpenseRepo := new(mocks.MockExpenseReportRepository)
+	budgetService := service.NewBudgetService(mockRepo, mockTripRepo, mockExpenseRepo, new(mocks.MockCostCenterRepository),
+		new(mocks.MockOrganizationRepository), mockUserRepo)
+	trip := func(status domain.TripStatus, amount int64) *domain.Trip {
+		return &domain.Trip{ID: uuid.New(), CostCenterID: budget.CostCenterID, Status: status, StartDate: time.Date(2030, 5, 2, 9, 0, 0, 0, time.UTC),
+			Budget: &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetLodging, Amount: amount}}}}
+	}
+
+	// Mock behavior
+	mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+	mockRepo.On("FindByID", ctx, budget.ID).Return(budget, nil)
+	mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+		return *params.Status == domain.StatusApproved && *params.CostCenterID == budget.CostCenterID
+	})).Return([]*domain.Trip{trip(domain.StatusApproved, 250000)}, nil)
+	concluded, reported := trip(domain.StatusConcluded, 100000), trip(domain.StatusConcluded, 50000)
+	mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+		return *params.Status == domain.StatusConcluded
+	})).Return([]*domain.Trip{concluded, reported}, nil)
+	mockExpenseRepo.On("ListByTripIDs", ctx, []uuid.UUID{concluded.ID, reported.ID}).Return([]*domain.ExpenseReport{{
+		TripID: reported.ID, Status: domain.ExpenseApproved, Currency: "BRL",
+		Items: []domain.ExpenseItem{{Amount: 80000, Currency: "BRL"}},
+	}}, nil)
+
+	// Act
+	report, err := budgetService.GetBudget(ctx, finance.ID, budget.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, budget, report.CostCenterBudget)
+	// The concluded trip without an approved report still counts its estimate
+	assert.Equal(t, int64(350000), report.Consumption.Committed)
+	assert.Equal(t, int64(80000), report.Consumption.Actual)
+	assert.Equal(t, int64(570000), report.Consumption.Remaining)
+	mockTripRepo.AssertExpectations(t)
+	mockExpenseRepo.AssertExpectations(t)
+}
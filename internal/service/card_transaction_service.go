@@ -0,0 +1,478 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrCardTransactionNotFound = errors.New("card transaction not found")
+
+// CardTransactionService imports the statements of the travelers' corporate
+// cards and reconciles their transactions with the expense reports: each
+// purchase is matched to the expense it pays for, and the card spend of each
+// trip is compared with its approved budget.
+type CardTransactionService struct {
+	repo          domain.CardTransactionRepository
+	tripRepo      domain.TripRepository
+	expenses      domain.ExpenseReportRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	exchangeRates domain.ExchangeRateRepository
+	notifier      NotificationService
+}
+
+func NewCardTransactionService(repo domain.CardTransactionRepository, tripRepo domain.TripRepository, expenses domain.ExpenseReportRepository,
+	userRepo domain.UserRepository, orgRepo domain.OrganizationRepository, exchangeRates domain.ExchangeRateRepository,
+	notifier NotificationService) *CardTransactionService {
+	return &CardTransactionService{
+		repo:          repo,
+		tripRepo:      tripRepo,
+		expenses:      expenses,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		exchangeRates: exchangeRates,
+		notifier:      notifier,
+	}
+}
+
+// ImportStatement stores the purchases of an OFX or CSV statement of the
+// cardholder's card, skipping the ones imported before, and matches the
+// cardholder's unmatched transactions to their expenses. Finance is notified
+// of the trips whose card spend went over their budget. Admins and finance import statements.
+func (s *CardTransactionService) ImportStatement(ctx context.Context, userID, cardholderID uuid.UUID, document []byte) (*domain.CardStatementImport, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	cardholder, err := s.userRepo.FindByID(ctx, cardholderID)
+	if err != nil {
+		return nil, err
+	}
+	if cardholder == nil {
+		return nil, ErrUserNotFound
+	}
+
+	transactions, err := domain.ParseCardStatement(document)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepareTransactions(ctx, orgID, cardholderID, transactions); err != nil {
+		return nil, err
+	}
+
+	imported, err := s.repo.Import(ctx, transactions)
+	if err != nil {
+		return nil, err
+	}
+	if imported == nil {
+		imported = []*domain.CardTransaction{}
+	}
+	result := &domain.CardStatementImport{
+		Imported:        len(imported),
+		Duplicates:      len(transactions) - len(imported),
+		Transactions:    imported,
+		OverBudgetTrips: []*domain.CardSpend{},
+	}
+
+	matched, unmatched, err := s.reconcile(ctx, cardholderID, imported)
+	if err != nil {
+		return nil, err
+	}
+	result.Matched, result.Unmatched = matched, unmatched
+
+	tripIDs := make(map[uuid.UUID]bool)
+	for _, transaction := range imported {
+		if transaction.TripID != nil {
+			tripIDs[*transaction.TripID] = true
+		}
+	}
+	for tripID := range tripIDs {
+		trip, spend, err := s.tripCardSpend(ctx, tripID)
+		if err != nil {
+			return nil, err
+		}
+		if trip == nil || !spend.OverBudget {
+			continue
+		}
+		result.OverBudgetTrips = append(result.OverBudgetTrips, spend)
+		s.notifyFinance(ctx, trip, fmt.Sprintf("Corporate card spend on the trip to %s is over its budget: %s of %s",
+			trip.Destination, domain.Money{Amount: spend.Spent, Currency: spend.Currency},
+			domain.Money{Amount: *spend.Budget, Currency: spend.Currency}))
+	}
+	return result, nil
+}
+
+// ListTransactions returns the transactions the filter selects, e.g. the
+// unmatched ones waiting for review. Admins and finance see every cardholder's,
+// other users only their own.
+func (s *CardTransactionService) ListTransactions(ctx context.Context, userID uuid.UUID, filter domain.CardTransactionFilter) ([]*domain.CardTransaction, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		if !errors.Is(err, ErrPermissionDenied) {
+			return nil, err
+		}
+		filter.CardholderID = &userID
+	}
+
+	transactions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if transactions == nil {
+		transactions = []*domain.CardTransaction{}
+	}
+	return transactions, nil
+}
+
+// MatchTransaction matches an unmatched transaction to an expense item of a
+// trip of the cardholder, for the purchases the automatic matching left out.
+// Items another transaction already pays for are refused.
+// The cardholder, admins and finance match transactions.
+func (s *CardTransactionService) MatchTransaction(ctx context.Context, userID, transactionID, tripID, itemID uuid.UUID) (*domain.CardTransaction, error) {
+	transaction, err := s.findTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.CardholderID != userID {
+		if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+	if transaction.Status != domain.CardUnmatched {
+		return nil, ErrInvalidStatus
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil || trip.RequesterID != transaction.CardholderID {
+		return nil, ErrTripNotFound
+	}
+	report, err := s.expenses.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil || report.FindItem(itemID) == nil {
+		return nil, ErrExpenseItemNotFound
+	}
+	matchedStatus := domain.CardMatched
+	matched, err := s.repo.List(ctx, domain.CardTransactionFilter{Status: &matchedStatus, TripID: &trip.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range matched {
+		if other.ExpenseItemID != nil && *other.ExpenseItemID == itemID {
+			return nil, domain.ErrExpenseItemMatched
+		}
+	}
+
+	now := time.Now()
+	transaction.Status = domain.CardMatched
+	transaction.TripID, transaction.ExpenseItemID = &trip.ID, &itemID
+	transaction.ReviewedBy, transaction.ReviewedAt = &userID, &now
+	transaction.UpdatedAt = now
+	if err := s.repo.Update(ctx, transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// IgnoreTransaction sets aside an unmatched transaction that isn't a travel
+// expense, e.g. a personal purchase, with a note on why. Admins and finance ignore transactions.
+func (s *CardTransactionService) IgnoreTransaction(ctx context.Context, userID, transactionID uuid.UUID, note string) (*domain.CardTransaction, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	transaction, err := s.findTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != domain.CardUnmatched {
+		return nil, ErrInvalidStatus
+	}
+	note = strings.TrimSpace(note)
+	if note == "" {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("note is required")
+		return nil, validationErrors
+	}
+
+	now := time.Now()
+	transaction.Status = domain.CardIgnored
+	transaction.Note = note
+	transaction.ReviewedBy, transaction.ReviewedAt = &userID, &now
+	transaction.UpdatedAt = now
+	if err := s.repo.Update(ctx, transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// GetTripCardSpend compares the card spend of a trip with its budget. The
+// traveler, admins and finance see it.
+func (s *CardTransactionService) GetTripCardSpend(ctx context.Context, userID, tripID uuid.UUID) (*domain.CardSpend, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+	if trip.RequesterID != userID {
+		if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, spend, err := s.tripCardSpend(ctx, tripID)
+	return spend, err
+}
+
+// ListOverBudgetTrips returns the card spend of the trips whose card spend
+// went over their budget. Admins and finance list them.
+func (s *CardTransactionService) ListOverBudgetTrips(ctx context.Context, userID uuid.UUID) ([]*domain.CardSpend, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.repo.List(ctx, domain.CardTransactionFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tripIDs []uuid.UUID
+	byTrip := make(map[uuid.UUID][]*domain.CardTransaction)
+	for _, transaction := range transactions {
+		if transaction.TripID == nil {
+			continue
+		}
+		if _, ok := byTrip[*transaction.TripID]; !ok {
+			tripIDs = append(tripIDs, *transaction.TripID)
+		}
+		byTrip[*transaction.TripID] = append(byTrip[*transaction.TripID], transaction)
+	}
+
+	spends := []*domain.CardSpend{}
+	for _, tripID := range tripIDs {
+		trip, err := s.tripRepo.FindByID(ctx, tripID)
+		if err != nil {
+			return nil, err
+		}
+		if trip == nil {
+			continue
+		}
+		if spend := domain.SummarizeCardSpend(trip, currency, byTrip[tripID]); spend.OverBudget {
+			spends = append(spends, spend)
+		}
+	}
+	return spends, nil
+}
+
+// prepareTransactions fills in the parsed transactions and converts the ones
+// in another currency with the rate of the day they were posted
+func (s *CardTransactionService) prepareTransactions(ctx context.Context, orgID, cardholderID uuid.UUID, transactions []*domain.CardTransaction) error {
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	validationErrors := domain.NewValidationErrors()
+	for i, transaction := range transactions {
+		transaction.ID = uuid.New()
+		transaction.OrgID = orgID
+		transaction.CardholderID = cardholderID
+		transaction.Status = domain.CardUnmatched
+		transaction.CreatedAt, transaction.UpdatedAt = now, now
+
+		prefix := fmt.Sprintf("transaction %d: ", i+1)
+		if err := transaction.Validate(); err != nil {
+			for _, message := range err.(*domain.ValidationErrors).GetErrors() {
+				validationErrors.Add(prefix + message)
+			}
+			continue
+		}
+		if transaction.Currency == currency {
+			continue
+		}
+		rate, err := s.exchangeRates.FindEffective(ctx, transaction.Currency, currency, transaction.PostedOn)
+		if err != nil {
+			return err
+		}
+		if rate == nil {
+			validationErrors.Add(prefix + fmt.Sprintf("no exchange rate from %s to %s on %s",
+				transaction.Currency, currency, transaction.PostedOn.Format("2006-01-02")))
+			continue
+		}
+		transaction.ConvertWith(rate, now)
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// reconcile matches the unmatched transactions of the cardholder to the
+// expense items of their trips no transaction paid for yet, and charges the
+// ones left unmatched to the trip they were made during. The imported
+// transactions are updated in place. It returns how many transactions were
+// matched and how many were left unmatched.
+func (s *CardTransactionService) reconcile(ctx context.Context, cardholderID uuid.UUID, imported []*domain.CardTransaction) (int, int, error) {
+	unmatchedStatus, matchedStatus := domain.CardUnmatched, domain.CardMatched
+	pending, err := s.repo.List(ctx, domain.CardTransactionFilter{Status: &unmatchedStatus, CardholderID: &cardholderID})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(pending) == 0 {
+		return 0, 0, nil
+	}
+	byID := make(map[uuid.UUID]*domain.CardTransaction)
+	for _, transaction := range imported {
+		byID[transaction.ID] = transaction
+	}
+	for i, transaction := range pending {
+		if own, ok := byID[transaction.ID]; ok {
+			pending[i] = own
+		}
+	}
+
+	matched, err := s.repo.List(ctx, domain.CardTransactionFilter{Status: &matchedStatus, CardholderID: &cardholderID})
+	if err != nil {
+		return 0, 0, err
+	}
+	paid := make(map[uuid.UUID]bool)
+	for _, transaction := range matched {
+		if transaction.ExpenseItemID != nil {
+			paid[*transaction.ExpenseItemID] = true
+		}
+	}
+
+	trips, err := s.tripRepo.List(ctx, domain.ListTripsParams{RequesterID: &cardholderID})
+	if err != nil {
+		return 0, 0, err
+	}
+	var candidates []domain.CardMatchCandidate
+	var charged []*domain.Trip
+	for _, trip := range trips {
+		if trip.Status != domain.StatusApproved && trip.Status != domain.StatusConcluded {
+			continue
+		}
+		charged = append(charged, trip)
+		report, err := s.expenses.FindByTripID(ctx, trip.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if report == nil {
+			continue
+		}
+		for _, item := range report.Items {
+			if !paid[item.ID] {
+				candidates = append(candidates, domain.CardMatchCandidate{TripID: trip.ID, Item: item})
+			}
+		}
+	}
+
+	count, unmatched := 0, 0
+	for _, transaction := range pending {
+		var available []domain.CardMatchCandidate
+		for _, candidate := range candidates {
+			if !paid[candidate.Item.ID] {
+				available = append(available, candidate)
+			}
+		}
+
+		tripID := transaction.TripID
+		if match := domain.MatchCardTransaction(transaction, available); match != nil {
+			paid[match.Item.ID] = true
+			transaction.Status = domain.CardMatched
+			transaction.TripID, transaction.ExpenseItemID = &match.TripID, &match.Item.ID
+			count++
+		} else {
+			transaction.TripID = tripDuring(charged, transaction.PostedOn)
+			unmatched++
+			if sameTrip(tripID, transaction.TripID) {
+				continue
+			}
+		}
+
+		transaction.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, transaction); err != nil {
+			return 0, 0, err
+		}
+	}
+	return count, unmatched, nil
+}
+
+// tripDuring returns the ID of the trip whose dates include the day, or nil
+func tripDuring(trips []*domain.Trip, day time.Time) *uuid.UUID {
+	date := day.Format("2006-01-02")
+	for _, trip := range trips {
+		if trip.StartDate.Format("2006-01-02") <= date && date <= trip.EndDate.Format("2006-01-02") {
+			return &trip.ID
+		}
+	}
+	return nil
+}
+
+func sameTrip(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// tripCardSpend compares the card spend of the trip with its budget. The
+// trip is nil when it no longer exists.
+func (s *CardTransactionService) tripCardSpend(ctx context.Context, tripID uuid.UUID) (*domain.Trip, *domain.CardSpend, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil || trip == nil {
+		return nil, nil, err
+	}
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+	transactions, err := s.repo.List(ctx, domain.CardTransactionFilter{TripID: &tripID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if transactions == nil {
+		transactions = []*domain.CardTransaction{}
+	}
+	return trip, domain.SummarizeCardSpend(trip, currency, transactions), nil
+}
+
+func (s *CardTransactionService) findTransaction(ctx context.Context, transactionID uuid.UUID) (*domain.CardTransaction, error) {
+	transaction, err := s.repo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, ErrCardTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// notifyFinance tells the finance users about a trip whose card spend went over its budget
+func (s *CardTransactionService) notifyFinance(ctx context.Context, trip *domain.Trip, message string) {
+	reviewers, err := s.userRepo.ListByRole(ctx, domain.RoleFinance)
+	if err != nil {
+		return
+	}
+	for _, reviewer := range reviewers {
+		s.notifier.Send(reviewer, trip, message)
+	}
+}
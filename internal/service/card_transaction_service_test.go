@@ -0,0 +1,353 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCardTransactionService() (*service.CardTransactionService, *mocks.MockCardTransactionRepository, *mocks.MockTripRepository, *mocks.MockExpenseReportRepository,
+	*mocks.MockUserRepository, *mocks.MockOrganizationRepository, *mocks.MockExchangeRateRepository, *mocks.MockNotificationService) {
+	mockTransactionRepo := new(mocks.MockCardTransactionRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockReportRepo := new(mocks.MockExpenseReportRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	cardTransactionService := service.NewCardTransactionService(mockTransactionRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier)
+	return cardTransactionService, mockTransactionRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier
+}
+
+func TestCardTransactionService_ImportStatement(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	cardholder := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	org := &domain.Organization{ID: orgID, BaseCurrency: "BRL"}
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: cardholder.ID,
+		Destination: "São Paulo",
+		StartDate:   time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC),
+		Status:      domain.StatusConcluded,
+		Budget:      &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetLodging, Amount: 50000}}},
+	}
+	hotel := domain.ExpenseItem{
+		ID: uuid.New(), Date: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), Category: domain.BudgetLodging,
+		Amount: 48000, Currency: "BRL", Description: "Hotel Ibis, 2 nights",
+	}
+	report := &domain.ExpenseReport{ID: uuid.New(), TripID: trip.ID, Currency: "BRL", Items: []domain.ExpenseItem{hotel}}
+	statement := []byte("date,merchant,amount,currency\n2024-05-05,HOTEL IBIS SAO PAULO,480.00,BRL\n2024-05-03,RESTAURANTE FASANO,150.00,BRL\n")
+	unmatched := domain.CardUnmatched
+	matched := domain.CardMatched
+
+	t.Run("Matches expenses and flags trips over budget", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, _, mockNotifier := setupCardTransactionService()
+		var imported []*domain.CardTransaction
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, cardholder.ID).Return(cardholder, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(org, nil)
+		importCall := mockTransactionRepo.On("Import", ctx, mock.Anything)
+		importCall.Run(func(args mock.Arguments) {
+			imported = args.Get(1).([]*domain.CardTransaction)
+			importCall.ReturnArguments = mock.Arguments{imported, nil}
+		})
+		pendingCall := mockTransactionRepo.On("List", ctx, domain.CardTransactionFilter{Status: &unmatched, CardholderID: &cardholder.ID})
+		pendingCall.Run(func(args mock.Arguments) {
+			// The stored copies of the imported transactions
+			stored := make([]*domain.CardTransaction, len(imported))
+			for i, transaction := range imported {
+				copied := *transaction
+				stored[i] = &copied
+			}
+			pendingCall.ReturnArguments = mock.Arguments{stored, nil}
+		})
+		mockTransactionRepo.On("List", ctx, domain.CardTransactionFilter{Status: &matched, CardholderID: &cardholder.ID}).Return([]*domain.CardTransaction{}, nil)
+		mockTripRepo.On("List", ctx, domain.ListTripsParams{RequesterID: &cardholder.ID}).Return([]*domain.Trip{trip}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockTransactionRepo.On("Update", ctx, mock.AnythingOfType("*domain.CardTransaction")).Return(nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		tripCall := mockTransactionRepo.On("List", ctx, domain.CardTransactionFilter{TripID: &trip.ID})
+		tripCall.Run(func(args mock.Arguments) {
+			tripCall.ReturnArguments = mock.Arguments{imported, nil}
+		})
+		mockUserRepo.On("ListByRole", ctx, domain.RoleFinance).Return([]*domain.User{finance}, nil)
+		mockNotifier.On("Send", finance, trip, "Corporate card spend on the trip to São Paulo is over its budget: BRL 630.00 of BRL 500.00").Return()
+
+		// Act
+		result, err := cardTransactionService.ImportStatement(ctx, finance.ID, cardholder.ID, statement)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Matched)
+		assert.Equal(t, 1, result.Unmatched)
+		assert.Equal(t, domain.CardMatched, result.Transactions[0].Status)
+		assert.Equal(t, hotel.ID, *result.Transactions[0].ExpenseItemID)
+		assert.Equal(t, domain.CardUnmatched, result.Transactions[1].Status)
+		// Made during the trip, the unmatched purchase is charged to it
+		assert.Equal(t, trip.ID, *result.Transactions[1].TripID)
+		assert.Len(t, result.OverBudgetTrips, 1)
+		assert.Equal(t, int64(63000), result.OverBudgetTrips[0].Spent)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Statements imported before are skipped", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, mockOrgRepo, _, _ := setupCardTransactionService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, cardholder.ID).Return(cardholder, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(org, nil)
+		mockTransactionRepo.On("Import", ctx, mock.Anything).Return([]*domain.CardTransaction{}, nil)
+		mockTransactionRepo.On("List", ctx, domain.CardTransactionFilter{Status: &unmatched, CardholderID: &cardholder.ID}).Return([]*domain.CardTransaction{}, nil)
+
+		// Act
+		result, err := cardTransactionService.ImportStatement(ctx, finance.ID, cardholder.ID, statement)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Equal(t, 2, result.Duplicates)
+		assert.Empty(t, result.OverBudgetTrips)
+	})
+
+	t.Run("Purchases in another currency need a rate", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, mockOrgRepo, mockRateRepo, _ := setupCardTransactionService()
+		posted := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, cardholder.ID).Return(cardholder, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(org, nil)
+		mockRateRepo.On("FindEffective", ctx, "USD", "BRL", posted).Return(nil, nil)
+
+		// Act
+		_, err := cardTransactionService.ImportStatement(ctx, finance.ID, cardholder.ID,
+			[]byte("date,merchant,amount,currency\n2024-06-01,HILTON,120.50,USD\n"))
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction 1: no exchange rate from USD to BRL on 2024-06-01")
+		mockTransactionRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown cardholder", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, _, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+		unknownID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, unknownID).Return(nil, nil)
+
+		// Act
+		_, err := cardTransactionService.ImportStatement(ctx, finance.ID, unknownID, statement)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+
+	t.Run("Employees can't import", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, _, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, cardholder.ID).Return(cardholder, nil)
+
+		// Act
+		_, err := cardTransactionService.ImportStatement(ctx, cardholder.ID, cardholder.ID, statement)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
+
+func TestCardTransactionService_MatchTransaction(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	cardholder := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: cardholder.ID, Status: domain.StatusConcluded}
+	itemID := uuid.New()
+	report := &domain.ExpenseReport{ID: uuid.New(), TripID: trip.ID, Items: []domain.ExpenseItem{{ID: itemID}}}
+	newTransaction := func() *domain.CardTransaction {
+		return &domain.CardTransaction{ID: uuid.New(), OrgID: orgID, CardholderID: cardholder.ID, Status: domain.CardUnmatched}
+	}
+	matchedStatus := domain.CardMatched
+	matchedOnTrip := domain.CardTransactionFilter{Status: &matchedStatus, TripID: &trip.ID}
+
+	t.Run("The cardholder matches", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, mockTripRepo, mockReportRepo, _, _, _, _ := setupCardTransactionService()
+		transaction := newTransaction()
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockTransactionRepo.On("List", ctx, matchedOnTrip).Return([]*domain.CardTransaction{}, nil)
+		mockTransactionRepo.On("Update", ctx, transaction).Return(nil)
+
+		// Act
+		matched, err := cardTransactionService.MatchTransaction(ctx, cardholder.ID, transaction.ID, trip.ID, itemID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CardMatched, matched.Status)
+		assert.Equal(t, itemID, *matched.ExpenseItemID)
+		assert.Equal(t, cardholder.ID, *matched.ReviewedBy)
+	})
+
+	t.Run("Item already matched", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, mockTripRepo, mockReportRepo, _, _, _, _ := setupCardTransactionService()
+		transaction := newTransaction()
+		other := &domain.CardTransaction{ID: uuid.New(), OrgID: orgID, CardholderID: cardholder.ID, Status: domain.CardMatched,
+			TripID: &trip.ID, ExpenseItemID: &itemID}
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockTransactionRepo.On("List", ctx, matchedOnTrip).Return([]*domain.CardTransaction{other}, nil)
+
+		// Act
+		_, err := cardTransactionService.MatchTransaction(ctx, cardholder.ID, transaction.ID, trip.ID, itemID)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrExpenseItemMatched)
+		assert.Equal(t, domain.CardUnmatched, transaction.Status)
+		mockTransactionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Item matched concurrently", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, mockTripRepo, mockReportRepo, _, _, _, _ := setupCardTransactionService()
+		transaction := newTransaction()
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockTransactionRepo.On("List", ctx, matchedOnTrip).Return([]*domain.CardTransaction{}, nil)
+		mockTransactionRepo.On("Update", ctx, transaction).Return(domain.ErrExpenseItemMatched)
+
+		// Act
+		_, err := cardTransactionService.MatchTransaction(ctx, cardholder.ID, transaction.ID, trip.ID, itemID)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrExpenseItemMatched)
+	})
+
+	t.Run("Only trips of the cardholder", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, mockTripRepo, _, _, _, _, _ := setupCardTransactionService()
+		transaction := newTransaction()
+		otherTrip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: uuid.New()}
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockTripRepo.On("FindByID", ctx, otherTrip.ID).Return(otherTrip, nil)
+
+		// Act
+		_, err := cardTransactionService.MatchTransaction(ctx, cardholder.ID, transaction.ID, otherTrip.ID, itemID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrTripNotFound)
+	})
+
+	t.Run("Other users can't match", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+		transaction := newTransaction()
+		colleague := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockUserRepo.On("FindByID", ctx, colleague.ID).Return(colleague, nil)
+
+		// Act
+		_, err := cardTransactionService.MatchTransaction(ctx, colleague.ID, transaction.ID, trip.ID, itemID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
+
+func TestCardTransactionService_IgnoreTransaction(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+		transaction := &domain.CardTransaction{ID: uuid.New(), Status: domain.CardUnmatched}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+		mockTransactionRepo.On("Update", ctx, transaction).Return(nil)
+
+		// Act
+		ignored, err := cardTransactionService.IgnoreTransaction(ctx, finance.ID, transaction.ID, " Personal purchase ")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CardIgnored, ignored.Status)
+		assert.Equal(t, "Personal purchase", ignored.Note)
+	})
+
+	t.Run("Only unmatched transactions", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+		transaction := &domain.CardTransaction{ID: uuid.New(), Status: domain.CardMatched}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTransactionRepo.On("FindByID", ctx, transaction.ID).Return(transaction, nil)
+
+		// Act
+		_, err := cardTransactionService.IgnoreTransaction(ctx, finance.ID, transaction.ID, "Personal purchase")
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+}
+
+func TestCardTransactionService_ListTransactions(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Employees see their own", func(t *testing.T) {
+		// Arrange
+		cardTransactionService, mockTransactionRepo, _, _, mockUserRepo, _, _, _ := setupCardTransactionService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		unmatched := domain.CardUnmatched
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+		mockTransactionRepo.On("List", ctx, domain.CardTransactionFilter{Status: &unmatched, CardholderID: &employee.ID}).Return(nil, nil)
+
+		// Act
+		transactions, err := cardTransactionService.ListTransactions(ctx, employee.ID, domain.CardTransactionFilter{Status: &unmatched})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, transactions)
+		assert.Empty(t, transactions)
+	})
+}
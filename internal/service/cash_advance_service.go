@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrCashAdvanceNotFound = errors.New("cash advance not found")
+
+// CashAdvanceInput holds the data of an advance request
+type CashAdvanceInput struct {
+	Amount   int64
+	Currency string
+	Purpose  string
+}
+
+// CashAdvanceService manages the money paid to travelers before their trips.
+// The traveler requests an advance, finance approves and pays it, and once the
+// trip's expense report is approved the advances are settled against the
+// expenses: the traveler returns what wasn't spent or is reimbursed the rest.
+type CashAdvanceService struct {
+	repo          domain.CashAdvanceRepository
+	tripRepo      domain.TripRepository
+	expenses      domain.ExpenseReportRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	exchangeRates domain.ExchangeRateRepository
+	notifier      NotificationService
+	transactor    domain.Transactor
+}
+
+func NewCashAdvanceService(repo domain.CashAdvanceRepository, tripRepo domain.TripRepository, expenses domain.ExpenseReportRepository,
+	userRepo domain.UserRepository, orgRepo domain.OrganizationRepository, exchangeRates domain.ExchangeRateRepository,
+	notifier NotificationService, transactor domain.Transactor) *CashAdvanceService {
+	return &CashAdvanceService{
+		repo:          repo,
+		tripRepo:      tripRepo,
+		expenses:      expenses,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		exchangeRates: exchangeRates,
+		notifier:      notifier,
+		transactor:    transactor,
+	}
+}
+
+// RequestAdvance asks finance for an advance for a trip of the traveler that
+// is waiting for approval or approved and hasn't ended
+func (s *CashAdvanceService) RequestAdvance(ctx context.Context, travelerID, tripID uuid.UUID, input CashAdvanceInput) (*domain.CashAdvance, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID != travelerID {
+		return nil, ErrPermissionDenied
+	}
+	if (trip.Status != domain.StatusRequested && trip.Status != domain.StatusApproved) || time.Now().After(trip.EndDate) {
+		return nil, ErrInvalidStatus
+	}
+
+	now := time.Now()
+	advance := &domain.CashAdvance{
+		ID:         uuid.New(),
+		OrgID:      trip.OrgID,
+		TripID:     trip.ID,
+		TravelerID: travelerID,
+		Status:     domain.AdvanceRequested,
+		Amount:     input.Amount,
+		Currency:   strings.ToUpper(input.Currency),
+		Purpose:    strings.TrimSpace(input.Purpose),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := advance.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, advance); err != nil {
+		return nil, err
+	}
+
+	s.notifyFinance(ctx, trip, fmt.Sprintf("An advance of %s was requested for the trip to %s: %s",
+		domain.Money{Amount: advance.Amount, Currency: advance.Currency}, trip.Destination, advance.Purpose))
+	return advance, nil
+}
+
+// ListTripAdvances returns the advances of a trip. The traveler, admins and finance see them.
+func (s *CashAdvanceService) ListTripAdvances(ctx context.Context, userID, tripID uuid.UUID) ([]*domain.CashAdvance, error) {
+	if _, err := s.viewableTrip(ctx, userID, tripID); err != nil {
+		return nil, err
+	}
+
+	advances, err := s.repo.ListByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if advances == nil {
+		advances = []*domain.CashAdvance{}
+	}
+	return advances, nil
+}
+
+// ListAdvances returns the advances in the status, or all of them when status
+// is nil, e.g. the requested ones waiting for review. Admins and finance list advances.
+func (s *CashAdvanceService) ListAdvances(ctx context.Context, userID uuid.UUID, status *domain.CashAdvanceStatus) ([]*domain.CashAdvance, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	advances, err := s.repo.List(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if advances == nil {
+		advances = []*domain.CashAdvance{}
+	}
+	return advances, nil
+}
+
+// ApproveAdvance accepts a requested advance of an approved trip for payment
+func (s *CashAdvanceService) ApproveAdvance(ctx context.Context, reviewerID, tripID, advanceID uuid.UUID) (*domain.CashAdvance, error) {
+	trip, advance, err := s.reviewedAdvance(ctx, reviewerID, tripID, advanceID, domain.AdvanceRequested)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != domain.StatusApproved {
+		return nil, ErrInvalidStatus
+	}
+
+	now := time.Now()
+	advance.Status = domain.AdvanceApproved
+	advance.ReviewedBy, advance.ReviewedAt = &reviewerID, &now
+	advance.UpdatedAt = now
+
+	if err := s.update(ctx, advance, domain.AdvanceRequested); err != nil {
+		return nil, err
+	}
+	s.notifyTraveler(ctx, trip, fmt.Sprintf("Your advance of %s for the trip to %s was approved.",
+		domain.Money{Amount: advance.Amount, Currency: advance.Currency}, trip.Destination))
+	return advance, nil
+}
+
+// RejectAdvance refuses a requested advance
+func (s *CashAdvanceService) RejectAdvance(ctx context.Context, reviewerID, tripID, advanceID uuid.UUID, reason string) (*domain.CashAdvance, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("reason is required")
+		return nil, validationErrors
+	}
+
+	trip, advance, err := s.reviewedAdvance(ctx, reviewerID, tripID, advanceID, domain.AdvanceRequested)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	advance.Status = domain.AdvanceRejected
+	advance.RejectionReason = reason
+	advance.ReviewedBy, advance.ReviewedAt = &reviewerID, &now
+	advance.UpdatedAt = now
+
+	if err := s.update(ctx, advance, domain.AdvanceRequested); err != nil {
+		return nil, err
+	}
+	s.notifyTraveler(ctx, trip, fmt.Sprintf("Your advance of %s for the trip to %s was rejected: %s",
+		domain.Money{Amount: advance.Amount, Currency: advance.Currency}, trip.Destination, reason))
+	return advance, nil
+}
+
+// PayAdvance records that an approved advance was paid to the traveler. An
+// advance in another currency is converted to the base currency with the rate
+// in effect on the day it's paid, which is what it's settled with.
+func (s *CashAdvanceService) PayAdvance(ctx context.Context, userID, tripID, advanceID uuid.UUID) (*domain.CashAdvance, error) {
+	trip, advance, err := s.reviewedAdvance(ctx, userID, tripID, advanceID, domain.AdvanceApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	advance.Converted = nil
+	if advance.Currency != currency {
+		rate, err := s.exchangeRates.FindEffective(ctx, advance.Currency, currency, now)
+		if err != nil {
+			return nil, err
+		}
+		if rate == nil {
+			validationErrors := domain.NewValidationErrors()
+			validationErrors.Add(fmt.Sprintf("no exchange rate from %s to %s on %s", advance.Currency, currency, now.Format("2006-01-02")))
+			return nil, validationErrors
+		}
+		advance.ConvertWith(rate, now)
+	}
+
+	advance.Status = domain.AdvancePaid
+	advance.PaidBy, advance.PaidAt = &userID, &now
+	advance.UpdatedAt = now
+
+	// Only one of two payments recorded at the same time goes through
+	if err := s.update(ctx, advance, domain.AdvanceApproved); err != nil {
+		return nil, err
+	}
+	s.notifyTraveler(ctx, trip, fmt.Sprintf("Your advance of %s for the trip to %s was paid. Keep the receipts of what you spend with it.",
+		domain.Money{Amount: advance.Amount, Currency: advance.Currency}, trip.Destination))
+	return advance, nil
+}
+
+// GetReconciliation balances the advances paid for a trip against its
+// expenses. It's final once the expense report is approved. The traveler,
+// admins and finance see it.
+func (s *CashAdvanceService) GetReconciliation(ctx context.Context, userID, tripID uuid.UUID) (*domain.AdvanceReconciliation, error) {
+	if _, err := s.viewableTrip(ctx, userID, tripID); err != nil {
+		return nil, err
+	}
+	return s.reconcile(ctx, tripID)
+}
+
+// SettleAdvances closes the paid advances of a trip against its approved
+// expense report and tells the traveler what to return or to be reimbursed.
+// The advances are settled together, and only once when settled twice at the same time.
+func (s *CashAdvanceService) SettleAdvances(ctx context.Context, userID, tripID uuid.UUID) (*domain.AdvanceReconciliation, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID == userID {
+		return nil, ErrPermissionDenied
+	}
+
+	reconciliation, err := s.reconcile(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if !reconciliation.Final {
+		return nil, ErrInvalidStatus
+	}
+	var paid []*domain.CashAdvance
+	for _, advance := range reconciliation.Advances {
+		if advance.Status == domain.AdvancePaid {
+			paid = append(paid, advance)
+		}
+	}
+	if len(paid) == 0 {
+		return nil, ErrInvalidStatus
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for _, advance := range paid {
+			advance.Status = domain.AdvanceSettled
+			advance.SettledBy, advance.SettledAt = &userID, &now
+			advance.UpdatedAt = now
+			if err := s.update(ctx, advance, domain.AdvancePaid); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your advances for the trip to %s were settled against your expenses", trip.Destination)
+	switch {
+	case reconciliation.ToReturn > 0:
+		message += fmt.Sprintf(": please return %s.", domain.Money{Amount: reconciliation.ToReturn, Currency: reconciliation.Currency})
+	case reconciliation.ToReimburse > 0:
+		// Paid with the reimbursement of the expense report, not on top of it
+		message += fmt.Sprintf(": %s will be reimbursed with your expenses.", domain.Money{Amount: reconciliation.ToReimburse, Currency: reconciliation.Currency})
+	default:
+		message += ": nothing is due."
+	}
+	s.notifyTraveler(ctx, trip, message)
+	return reconciliation, nil
+}
+
+// reconcile balances the advances of the trip against its expense report
+func (s *CashAdvanceService) reconcile(ctx context.Context, tripID uuid.UUID) (*domain.AdvanceReconciliation, error) {
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.expenses.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	advances, err := s.repo.ListByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if advances == nil {
+		advances = []*domain.CashAdvance{}
+	}
+	return domain.ReconcileAdvances(currency, report, advances), nil
+}
+
+func (s *CashAdvanceService) findTrip(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+	return trip, nil
+}
+
+// viewableTrip returns the trip when the user is its traveler, an admin or finance
+func (s *CashAdvanceService) viewableTrip(ctx context.Context, userID, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID != userID {
+		if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+	return trip, nil
+}
+
+// reviewedAdvance returns the advance of the trip in the status when the user
+// is an admin or finance. Travelers never review their own advances.
+func (s *CashAdvanceService) reviewedAdvance(ctx context.Context, userID, tripID, advanceID uuid.UUID,
+	status domain.CashAdvanceStatus) (*domain.Trip, *domain.CashAdvance, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, nil, err
+	}
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trip.RequesterID == userID {
+		return nil, nil, ErrPermissionDenied
+	}
+
+	advance, err := s.repo.FindByID(ctx, advanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if advance == nil || advance.TripID != tripID {
+		return nil, nil, ErrCashAdvanceNotFound
+	}
+	if advance.Status != status {
+		return nil, nil, ErrInvalidStatus
+	}
+	return trip, advance, nil
+}
+
+// update stores the advance if it's still in the from status, so that the
+// same review, payment or settlement recorded twice at once only counts once
+func (s *CashAdvanceService) update(ctx context.Context, advance *domain.CashAdvance, from domain.CashAdvanceStatus) error {
+	err := s.repo.Update(ctx, advance, from)
+	if errors.Is(err, domain.ErrCashAdvanceStatusChanged) {
+		return ErrInvalidStatus
+	}
+	return err
+}
+
+func (s *CashAdvanceService) notifyTraveler(ctx context.Context, trip *domain.Trip, message string) {
+	traveler, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err == nil && traveler != nil {
+		s.notifier.Send(traveler, trip, message)
+	}
+}
+
+// notifyFinance tells the finance users about an advance waiting for their review
+func (s *CashAdvanceService) notifyFinance(ctx context.Context, trip *domain.Trip, message string) {
+	reviewers, err := s.userRepo.ListByRole(ctx, domain.RoleFinance)
+	if err != nil {
+		return
+	}
+	for _, reviewer := range reviewers {
+		if reviewer.ID != trip.RequesterID {
+			s.notifier.Send(reviewer, trip, message)
+		}
+	}
+}
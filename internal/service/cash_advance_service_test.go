@@ -0,0 +1,408 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCashAdvanceService() (*service.CashAdvanceService, *mocks.MockCashAdvanceRepository, *mocks.MockTripRepository, *mocks.MockExpenseReportRepository, *mocks.MockUserRepository,
+	*mocks.MockOrganizationRepository, *mocks.MockExchangeRateRepository, *mocks.MockNotificationService, *mocks.MockTransactor) {
+	mockAdvanceRepo := new(mocks.MockCashAdvanceRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockReportRepo := new(mocks.MockExpenseReportRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	mockTransactor := new(mocks.MockTransactor)
+	cashAdvanceService := service.NewCashAdvanceService(mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier,
+		mockTransactor)
+	return cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier, mockTransactor
+}
+
+func TestCashAdvanceService_RequestAdvance(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	traveler := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: traveler.ID,
+		Destination: "Nova York",
+		StartDate:   time.Now().AddDate(0, 0, 10),
+		EndDate:     time.Now().AddDate(0, 0, 15),
+		Status:      domain.StatusRequested,
+	}
+	input := service.CashAdvanceInput{Amount: 50000, Currency: "usd", Purpose: " Taxis and meals "}
+
+	t.Run("Success notifies finance", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, _, _, mockNotifier, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("Create", ctx, mock.AnythingOfType("*domain.CashAdvance")).Return(nil)
+		mockUserRepo.On("ListByRole", ctx, domain.RoleFinance).Return([]*domain.User{finance}, nil)
+		mockNotifier.On("Send", finance, trip, "An advance of USD 500.00 was requested for the trip to Nova York: Taxis and meals").Return()
+
+		// Act
+		advance, err := cashAdvanceService.RequestAdvance(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AdvanceRequested, advance.Status)
+		assert.Equal(t, "USD", advance.Currency)
+		assert.Equal(t, "Taxis and meals", advance.Purpose)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Only the traveler requests", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, _, mockTripRepo, _, _, _, _, _, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		_, err := cashAdvanceService.RequestAdvance(ctx, finance.ID, trip.ID, input)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+
+	t.Run("Not for canceled trips", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, _, mockTripRepo, _, _, _, _, _, _ := setupCashAdvanceService()
+		canceled := *trip
+		canceled.Status = domain.StatusCanceled
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(&canceled, nil)
+
+		// Act
+		_, err := cashAdvanceService.RequestAdvance(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Invalid amount", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, _, mockTripRepo, _, _, _, _, _, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		_, err := cashAdvanceService.RequestAdvance(ctx, traveler.ID, trip.ID, service.CashAdvanceInput{Amount: -1, Currency: "USD", Purpose: "Meals"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "amount must be positive")
+	})
+}
+
+func TestCashAdvanceService_Review(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	traveler := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: traveler.ID, Destination: "Nova York", Status: domain.StatusApproved}
+	advance := func(status domain.CashAdvanceStatus) *domain.CashAdvance {
+		return &domain.CashAdvance{
+			ID: uuid.New(), OrgID: orgID, TripID: trip.ID, TravelerID: traveler.ID, Status: status,
+			Amount: 50000, Currency: "USD", Purpose: "Meals",
+		}
+	}
+
+	t.Run("Finance approves", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, _, _, mockNotifier, _ := setupCashAdvanceService()
+		requested := advance(domain.AdvanceRequested)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("FindByID", ctx, requested.ID).Return(requested, nil)
+		mockAdvanceRepo.On("Update", ctx, requested, domain.AdvanceRequested).Return(nil)
+		mockNotifier.On("Send", traveler, trip, "Your advance of USD 500.00 for the trip to Nova York was approved.").Return()
+
+		// Act
+		approved, err := cashAdvanceService.ApproveAdvance(ctx, finance.ID, trip.ID, requested.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AdvanceApproved, approved.Status)
+		assert.Equal(t, finance.ID, *approved.ReviewedBy)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Not before the trip is approved", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, _, _, _, _ := setupCashAdvanceService()
+		requested := advance(domain.AdvanceRequested)
+		pending := *trip
+		pending.Status = domain.StatusRequested
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(&pending, nil)
+		mockAdvanceRepo.On("FindByID", ctx, requested.ID).Return(requested, nil)
+
+		// Act
+		_, err := cashAdvanceService.ApproveAdvance(ctx, finance.ID, trip.ID, requested.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Employees can't approve", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, _, _, _, mockUserRepo, _, _, _, _ := setupCashAdvanceService()
+		other := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, other.ID).Return(other, nil)
+
+		// Act
+		_, err := cashAdvanceService.ApproveAdvance(ctx, other.ID, trip.ID, uuid.New())
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+
+	t.Run("Advance of another trip", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, _, _, _, _ := setupCashAdvanceService()
+		elsewhere := advance(domain.AdvanceRequested)
+		elsewhere.TripID = uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("FindByID", ctx, elsewhere.ID).Return(elsewhere, nil)
+
+		// Act
+		_, err := cashAdvanceService.ApproveAdvance(ctx, finance.ID, trip.ID, elsewhere.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrCashAdvanceNotFound)
+	})
+
+	t.Run("Reject requires a reason", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, _, _, _, _, _, _, _, _ := setupCashAdvanceService()
+
+		// Act
+		_, err := cashAdvanceService.RejectAdvance(ctx, finance.ID, trip.ID, uuid.New(), "  ")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "reason is required")
+	})
+
+	t.Run("Paying converts to the base currency", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier, _ := setupCashAdvanceService()
+		approved := advance(domain.AdvanceApproved)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("FindByID", ctx, approved.ID).Return(approved, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRateRepo.On("FindEffective", ctx, "USD", "BRL", mock.AnythingOfType("time.Time")).Return(&domain.ExchangeRate{
+			Currency: "USD", BaseCurrency: "BRL", Rate: 5, EffectiveDate: time.Now().Truncate(24 * time.Hour),
+		}, nil)
+		mockAdvanceRepo.On("Update", ctx, approved, domain.AdvanceApproved).Return(nil)
+		mockNotifier.On("Send", traveler, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		paid, err := cashAdvanceService.PayAdvance(ctx, finance.ID, trip.ID, approved.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AdvancePaid, paid.Status)
+		assert.Equal(t, domain.Money{Amount: 250000, Currency: "BRL"}, paid.Converted.Total)
+		assert.NotNil(t, paid.PaidAt)
+	})
+
+	t.Run("Paid twice at once", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, mockOrgRepo, _, mockNotifier, _ := setupCashAdvanceService()
+		approved := advance(domain.AdvanceApproved)
+		approved.Currency = "BRL"
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("FindByID", ctx, approved.ID).Return(approved, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockAdvanceRepo.On("Update", ctx, approved, domain.AdvanceApproved).Return(domain.ErrCashAdvanceStatusChanged)
+
+		// Act
+		paid, err := cashAdvanceService.PayAdvance(ctx, finance.ID, trip.ID, approved.ID)
+
+		// Assert
+		assert.Nil(t, paid)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Paying without an exchange rate", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, _, mockUserRepo, mockOrgRepo, mockRateRepo, _, _ := setupCashAdvanceService()
+		approved := advance(domain.AdvanceApproved)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockAdvanceRepo.On("FindByID", ctx, approved.ID).Return(approved, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRateRepo.On("FindEffective", ctx, "USD", "BRL", mock.AnythingOfType("time.Time")).Return(nil, nil)
+
+		// Act
+		_, err := cashAdvanceService.PayAdvance(ctx, finance.ID, trip.ID, approved.ID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no exchange rate from USD to BRL")
+		mockAdvanceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCashAdvanceService_SettleAdvances(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	traveler := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	trip := &domain.Trip{ID: uuid.New(), OrgID: orgID, RequesterID: traveler.ID, Destination: "Nova York", Status: domain.StatusConcluded}
+	report := func(status domain.ExpenseReportStatus) *domain.ExpenseReport {
+		return &domain.ExpenseReport{TripID: trip.ID, Status: status, Currency: "BRL", Items: []domain.ExpenseItem{
+			{Amount: 180000, Currency: "BRL"},
+		}}
+	}
+	paid := func() *domain.CashAdvance {
+		return &domain.CashAdvance{
+			ID: uuid.New(), TripID: trip.ID, Status: domain.AdvancePaid, Amount: 50000, Currency: "USD",
+			Converted: &domain.BudgetConversion{Total: domain.Money{Amount: 250000, Currency: "BRL"}},
+		}
+	}
+
+	t.Run("Settles the paid advances and tells the traveler what to return", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, _, mockNotifier, mockTransactor := setupCashAdvanceService()
+		advance := paid()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report(domain.ExpenseApproved), nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{advance}, nil)
+		mockAdvanceRepo.On("Update", ctx, advance, domain.AdvancePaid).Return(nil)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+		mockNotifier.On("Send", traveler, trip,
+			"Your advances for the trip to Nova York were settled against your expenses: please return BRL 700.00.").Return()
+
+		// Act
+		reconciliation, err := cashAdvanceService.SettleAdvances(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(70000), reconciliation.ToReturn)
+		assert.Equal(t, domain.AdvanceSettled, advance.Status)
+		assert.Equal(t, finance.ID, *advance.SettledBy)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Settled twice at once", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, _, mockNotifier, mockTransactor := setupCashAdvanceService()
+		advance := paid()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report(domain.ExpenseApproved), nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{advance}, nil)
+		mockAdvanceRepo.On("Update", ctx, advance, domain.AdvancePaid).Return(domain.ErrCashAdvanceStatusChanged)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+
+		// Act
+		_, err := cashAdvanceService.SettleAdvances(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not before the expense report is approved", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, _, _, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report(domain.ExpenseSubmitted), nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{paid()}, nil)
+
+		// Act
+		_, err := cashAdvanceService.SettleAdvances(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockAdvanceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Nothing to settle", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, mockUserRepo, mockOrgRepo, _, _, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report(domain.ExpenseReimbursed), nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return(nil, nil)
+
+		// Act
+		_, err := cashAdvanceService.SettleAdvances(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Traveler sees the reconciliation", func(t *testing.T) {
+		// Arrange
+		cashAdvanceService, mockAdvanceRepo, mockTripRepo, mockReportRepo, _, mockOrgRepo, _, _, _ := setupCashAdvanceService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(nil, nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{paid()}, nil)
+
+		// Act
+		reconciliation, err := cashAdvanceService.GetReconciliation(ctx, traveler.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, reconciliation.Final)
+		assert.Equal(t, int64(250000), reconciliation.ToReturn)
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var (
+	ErrCostCenterNotFound = errors.New("cost center not found")
+	ErrDepartmentNotFound = errors.New("department not found")
+)
+
+// CostCenterService manages the cost centers and departments of an organization.
+// The organization always comes from the context.
+type CostCenterService struct {
+	costCenterRepo domain.CostCenterRepository
+	departmentRepo domain.DepartmentRepository
+	userRepo       domain.UserRepository
+}
+
+func NewCostCenterService(costCenterRepo domain.CostCenterRepository, departmentRepo domain.DepartmentRepository, userRepo domain.UserRepository) *CostCenterService {
+	return &CostCenterService{
+		costCenterRepo: costCenterRepo,
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *CostCenterService) CreateCostCenter(ctx context.Context, adminID uuid.UUID, code, name string) (*domain.CostCenter, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	costCenter := &domain.CostCenter{
+		ID:        uuid.New(),
+		OrgID:     orgID,
+		Code:      code,
+		Name:      name,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := costCenter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.costCenterRepo.Create(ctx, costCenter); err != nil {
+		return nil, err
+	}
+	return costCenter, nil
+}
+
+func (s *CostCenterService) UpdateCostCenter(ctx context.Context, adminID, id uuid.UUID, code, name string, active bool) (*domain.CostCenter, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	costCenter, err := s.costCenterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if costCenter == nil {
+		return nil, ErrCostCenterNotFound
+	}
+
+	costCenter.Code = code
+	costCenter.Name = name
+	costCenter.Active = active
+	costCenter.UpdatedAt = time.Now()
+
+	if err := costCenter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.costCenterRepo.Update(ctx, costCenter); err != nil {
+		return nil, err
+	}
+	return costCenter, nil
+}
+
+func (s *CostCenterService) ListCostCenters(ctx context.Context) ([]*domain.CostCenter, error) {
+	return s.costCenterRepo.List(ctx)
+}
+
+func (s *CostCenterService) CreateDepartment(ctx context.Context, adminID uuid.UUID, name string, costCenterID uuid.UUID) (*domain.Department, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	department := &domain.Department{
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		Name:         name,
+		CostCenterID: costCenterID,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.validateDepartment(ctx, department); err != nil {
+		return nil, err
+	}
+
+	if err := s.departmentRepo.Create(ctx, department); err != nil {
+		return nil, err
+	}
+	return department, nil
+}
+
+func (s *CostCenterService) UpdateDepartment(ctx context.Context, adminID, id uuid.UUID, name string, costCenterID uuid.UUID) (*domain.Department, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	department, err := s.departmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if department == nil {
+		return nil, ErrDepartmentNotFound
+	}
+
+	department.Name = name
+	department.CostCenterID = costCenterID
+	department.UpdatedAt = time.Now()
+
+	if err := s.validateDepartment(ctx, department); err != nil {
+		return nil, err
+	}
+
+	if err := s.departmentRepo.Update(ctx, department); err != nil {
+		return nil, err
+	}
+	return department, nil
+}
+
+func (s *CostCenterService) ListDepartments(ctx context.Context) ([]*domain.Department, error) {
+	return s.departmentRepo.List(ctx)
+}
+
+// AssignUserDepartment moves a user to a department, or removes them from any when departmentID is nil
+func (s *CostCenterService) AssignUserDepartment(ctx context.Context, adminID, userID uuid.UUID, departmentID *uuid.UUID) error {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if departmentID != nil {
+		department, err := s.departmentRepo.FindByID(ctx, *departmentID)
+		if err != nil {
+			return err
+		}
+		if department == nil {
+			return ErrDepartmentNotFound
+		}
+	}
+
+	return s.userRepo.UpdateDepartment(ctx, userID, departmentID)
+}
+
+// validateDepartment runs the domain validation and checks the default cost center belongs to the organization
+func (s *CostCenterService) validateDepartment(ctx context.Context, department *domain.Department) error {
+	if err := department.Validate(); err != nil {
+		return err
+	}
+
+	costCenter, err := s.costCenterRepo.FindByID(ctx, department.CostCenterID)
+	if err != nil {
+		return err
+	}
+	if costCenter == nil || !costCenter.Active {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("cost_center_id must reference an active cost center")
+		return validationErrors
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCostCenterService() (*service.CostCenterService, *mocks.MockCostCenterRepository, *mocks.MockDepartmentRepository, *mocks.MockUserRepository, uuid.UUID) {
+	mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+	mockDepartmentRepo := new(mocks.MockDepartmentRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	mockUserRepo.On("FindByID", mock.Anything, admin.ID).Return(admin, nil)
+	costCenterService := service.NewCostCenterService(mockCostCenterRepo, mockDepartmentRepo, mockUserRepo)
+	return costCenterService, mockCostCenterRepo, mockDepartmentRepo, mockUserRepo, admin.ID
+}
+
+func TestCostCenterService_CreateCostCenter(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _, adminID := setupCostCenterService()
+
+		// Mock behavior
+		mockCostCenterRepo.On("Create", ctx, mock.AnythingOfType("*domain.CostCenter")).Return(nil)
+
+		// Act
+		costCenter, err := costCenterService.CreateCostCenter(ctx, adminID, "CC-100", "Sales")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, costCenter.OrgID)
+		assert.Equal(t, "CC-100", costCenter.Code)
+		assert.True(t, costCenter.Active)
+		mockCostCenterRepo.AssertExpectations(t)
+	})
+
+	t.Run("Missing organization", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _, adminID := setupCostCenterService()
+
+		// Act
+		costCenter, err := costCenterService.CreateCostCenter(context.Background(), adminID, "CC-100", "Sales")
+
+		// Assert
+		assert.Equal(t, domain.ErrMissingTenant, err)
+		assert.Nil(t, costCenter)
+		mockCostCenterRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestCostCenterService_UpdateCostCenter(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Deactivate", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _, adminID := setupCostCenterService()
+		costCenter := &domain.CostCenter{ID: uuid.New(), OrgID: uuid.New(), Code: "CC-100", Name: "Sales", Active: true}
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+		mockCostCenterRepo.On("Update", ctx, costCenter).Return(nil)
+
+		// Act
+		updated, err := costCenterService.UpdateCostCenter(ctx, adminID, costCenter.ID, "CC-100", "Sales", false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, updated.Active)
+		mockCostCenterRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _, adminID := setupCostCenterService()
+		id := uuid.New()
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", ctx, id).Return(nil, nil)
+
+		// Act
+		updated, err := costCenterService.UpdateCostCenter(ctx, adminID, id, "CC-100", "Sales", true)
+
+		// Assert
+		assert.Equal(t, service.ErrCostCenterNotFound, err)
+		assert.Nil(t, updated)
+		mockCostCenterRepo.AssertNotCalled(t, "Update")
+	})
+}
+
+func TestCostCenterService_CreateDepartment(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, mockDepartmentRepo, _, adminID := setupCostCenterService()
+		costCenter := &domain.CostCenter{ID: uuid.New(), Active: true}
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+		mockDepartmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Department")).Return(nil)
+
+		// Act
+		department, err := costCenterService.CreateDepartment(ctx, adminID, "Engineering", costCenter.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, costCenter.ID, department.CostCenterID)
+		mockDepartmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Inactive cost center", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, mockDepartmentRepo, _, adminID := setupCostCenterService()
+		costCenter := &domain.CostCenter{ID: uuid.New(), Active: false}
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+
+		// Act
+		department, err := costCenterService.CreateDepartment(ctx, adminID, "Engineering", costCenter.ID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cost_center_id must reference an active cost center")
+		assert.Nil(t, department)
+		mockDepartmentRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestCostCenterService_AssignUserDepartment(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, mockDepartmentRepo, mockUserRepo, adminID := setupCostCenterService()
+		user := &domain.User{ID: uuid.New()}
+		department := &domain.Department{ID: uuid.New()}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockDepartmentRepo.On("FindByID", ctx, department.ID).Return(department, nil)
+		mockUserRepo.On("UpdateDepartment", ctx, user.ID, &department.ID).Return(nil)
+
+		// Act
+		err := costCenterService.AssignUserDepartment(ctx, adminID, user.ID, &department.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Remove from department", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, mockDepartmentRepo, mockUserRepo, adminID := setupCostCenterService()
+		user := &domain.User{ID: uuid.New()}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("UpdateDepartment", ctx, user.ID, (*uuid.UUID)(nil)).Return(nil)
+
+		// Act
+		err := costCenterService.AssignUserDepartment(ctx, adminID, user.ID, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockDepartmentRepo.AssertNotCalled(t, "FindByID")
+	})
+
+	t.Run("Department not found", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, mockDepartmentRepo, mockUserRepo, adminID := setupCostCenterService()
+		user := &domain.User{ID: uuid.New()}
+		departmentID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockDepartmentRepo.On("FindByID", ctx, departmentID).Return(nil, nil)
+
+		// Act
+		err := costCenterService.AssignUserDepartment(ctx, adminID, user.ID, &departmentID)
+
+		// Assert
+		assert.Equal(t, service.ErrDepartmentNotFound, err)
+		mockUserRepo.AssertNotCalled(t, "UpdateDepartment")
+	})
+}
+
+func TestCostCenterService_RequiresAdmin(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	costCenterID := uuid.New()
+	departmentID := uuid.New()
+
+	setup := func() (*service.CostCenterService, *mocks.MockCostCenterRepository, *mocks.MockDepartmentRepository, *mocks.MockUserRepository) {
+		costCenterService, mockCostCenterRepo, mockDepartmentRepo, mockUserRepo, _ := setupCostCenterService()
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+		return costCenterService, mockCostCenterRepo, mockDepartmentRepo, mockUserRepo
+	}
+
+	t.Run("Create cost center", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _ := setup()
+
+		// Act
+		costCenter, err := costCenterService.CreateCostCenter(ctx, employee.ID, "CC-100", "Sales")
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, costCenter)
+		mockCostCenterRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deactivate cost center", func(t *testing.T) {
+		// Arrange
+		costCenterService, mockCostCenterRepo, _, _ := setup()
+
+		// Act
+		costCenter, err := costCenterService.UpdateCostCenter(ctx, employee.ID, costCenterID, "CC-100", "Sales", false)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, costCenter)
+		mockCostCenterRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create department", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, mockDepartmentRepo, _ := setup()
+
+		// Act
+		department, err := costCenterService.CreateDepartment(ctx, employee.ID, "Engineering", costCenterID)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, department)
+		mockDepartmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update department", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, mockDepartmentRepo, _ := setup()
+
+		// Act
+		department, err := costCenterService.UpdateDepartment(ctx, employee.ID, departmentID, "Engineering", costCenterID)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, department)
+		mockDepartmentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Move themselves to another department", func(t *testing.T) {
+		// Arrange
+		costCenterService, _, _, mockUserRepo := setup()
+
+		// Act
+		err := costCenterService.AssignUserDepartment(ctx, employee.ID, employee.ID, &departmentID)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockUserRepo.AssertNotCalled(t, "UpdateDepartment", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
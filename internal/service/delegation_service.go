@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrDelegationNotFound = errors.New("delegation not found")
+
+// DelegationInput holds the data of a new approval delegation
+type DelegationInput struct {
+	// DelegatorID defaults to the current user; only admins delegate on behalf of others
+	DelegatorID  *uuid.UUID
+	DelegateID   uuid.UUID
+	StartsAt     time.Time
+	EndsAt       time.Time
+	CostCenterID *uuid.UUID
+}
+
+// DelegationService manages approval delegations. They are honored by
+// TripService.UpdateTripStatus and the approval queue.
+type DelegationService struct {
+	repo     domain.DelegationRepository
+	userRepo domain.UserRepository
+}
+
+func NewDelegationService(repo domain.DelegationRepository, userRepo domain.UserRepository) *DelegationService {
+	return &DelegationService{repo: repo, userRepo: userRepo}
+}
+
+func (s *DelegationService) CreateDelegation(ctx context.Context, userID uuid.UUID, input DelegationInput) (*domain.Delegation, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	delegatorID := userID
+	if input.DelegatorID != nil && *input.DelegatorID != userID {
+		if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+		delegatorID = *input.DelegatorID
+	}
+
+	delegation := &domain.Delegation{
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		DelegatorID:  delegatorID,
+		DelegateID:   input.DelegateID,
+		StartsAt:     input.StartsAt,
+		EndsAt:       input.EndsAt,
+		CostCenterID: input.CostCenterID,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := delegation.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Both users must belong to the organization
+	for _, id := range []uuid.UUID{delegation.DelegatorID, delegation.DelegateID} {
+		user, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	if err := s.repo.Create(ctx, delegation); err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// ListDelegations returns the delegations the user gave or received
+func (s *DelegationService) ListDelegations(ctx context.Context, userID uuid.UUID) ([]*domain.Delegation, error) {
+	delegations, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if delegations == nil {
+		delegations = []*domain.Delegation{}
+	}
+	return delegations, nil
+}
+
+// DeleteDelegation revokes a delegation. Only its delegator and admins can revoke it.
+func (s *DelegationService) DeleteDelegation(ctx context.Context, userID, delegationID uuid.UUID) error {
+	delegation, err := s.repo.FindByID(ctx, delegationID)
+	if err != nil {
+		return err
+	}
+	if delegation == nil {
+		return ErrDelegationNotFound
+	}
+
+	if delegation.DelegatorID != userID {
+		if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, delegationID)
+}
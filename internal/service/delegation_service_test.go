@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupDelegationService() (*service.DelegationService, *mocks.MockDelegationRepository, *mocks.MockUserRepository) {
+	mockDelegationRepo := new(mocks.MockDelegationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	delegationService := service.NewDelegationService(mockDelegationRepo, mockUserRepo)
+	return delegationService, mockDelegationRepo, mockUserRepo
+}
+
+func TestDelegationService_CreateDelegation(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	startsAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, mockUserRepo := setupDelegationService()
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		delegate := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+		mockDelegationRepo.On("Create", ctx, mock.AnythingOfType("*domain.Delegation")).Return(nil)
+
+		// Act
+		delegation, err := delegationService.CreateDelegation(ctx, manager.ID, service.DelegationInput{
+			DelegateID: delegate.ID,
+			StartsAt:   startsAt,
+			EndsAt:     startsAt.AddDate(0, 0, 14),
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, delegation.OrgID)
+		assert.Equal(t, manager.ID, delegation.DelegatorID)
+		assert.Equal(t, delegate.ID, delegation.DelegateID)
+		mockDelegationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only admins delegate for others", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, mockUserRepo := setupDelegationService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		delegatorID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		delegation, err := delegationService.CreateDelegation(ctx, employee.ID, service.DelegationInput{
+			DelegatorID: &delegatorID,
+			DelegateID:  employee.ID,
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.AddDate(0, 0, 14),
+		})
+
+		// Assert
+		assert.Nil(t, delegation)
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockDelegationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown delegate", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, mockUserRepo := setupDelegationService()
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		delegateID := uuid.New()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, delegateID).Return(nil, nil)
+
+		// Act
+		delegation, err := delegationService.CreateDelegation(ctx, manager.ID, service.DelegationInput{
+			DelegateID: delegateID,
+			StartsAt:   startsAt,
+			EndsAt:     startsAt.AddDate(0, 0, 14),
+		})
+
+		// Assert
+		assert.Nil(t, delegation)
+		assert.Equal(t, service.ErrUserNotFound, err)
+		mockDelegationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelegationService_DeleteDelegation(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Delegator revokes", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, _ := setupDelegationService()
+		delegation := &domain.Delegation{ID: uuid.New(), DelegatorID: uuid.New(), DelegateID: uuid.New()}
+
+		// Mock behavior
+		mockDelegationRepo.On("FindByID", ctx, delegation.ID).Return(delegation, nil)
+		mockDelegationRepo.On("Delete", ctx, delegation.ID).Return(nil)
+
+		// Act
+		err := delegationService.DeleteDelegation(ctx, delegation.DelegatorID, delegation.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockDelegationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delegate cannot revoke", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, mockUserRepo := setupDelegationService()
+		delegate := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		delegation := &domain.Delegation{ID: uuid.New(), DelegatorID: uuid.New(), DelegateID: delegate.ID}
+
+		// Mock behavior
+		mockDelegationRepo.On("FindByID", ctx, delegation.ID).Return(delegation, nil)
+		mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+
+		// Act
+		err := delegationService.DeleteDelegation(ctx, delegate.ID, delegation.ID)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockDelegationRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		// Arrange
+		delegationService, mockDelegationRepo, _ := setupDelegationService()
+		delegationID := uuid.New()
+
+		// Mock behavior
+		mockDelegationRepo.On("FindByID", ctx, delegationID).Return(nil, nil)
+
+		// Act
+		err := delegationService.DeleteDelegation(ctx, uuid.New(), delegationID)
+
+		// Assert
+		assert.Equal(t, service.ErrDelegationNotFound, err)
+	})
+}
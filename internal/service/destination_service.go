@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+const (
+	defaultDestinationLimit = 10
+	maxDestinationLimit     = 50
+)
+
+// DestinationService searches the destination catalog
+type DestinationService struct {
+	repo domain.DestinationRepository
+}
+
+func NewDestinationService(repo domain.DestinationRepository) *DestinationService {
+	return &DestinationService{repo: repo}
+}
+
+// SearchDestinations autocompletes destinations by code, name or alias, ignoring case and accents.
+// limit defaults to 10 and is capped at 50.
+func (s *DestinationService) SearchDestinations(ctx context.Context, query string, limit int) ([]*domain.Destination, error) {
+	if limit <= 0 {
+		limit = defaultDestinationLimit
+	}
+	if limit > maxDestinationLimit {
+		limit = maxDestinationLimit
+	}
+	return s.repo.Search(ctx, query, limit)
+}
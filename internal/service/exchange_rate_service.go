@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// ExchangeRateInput holds the data of an exchange rate to the organization's base currency
+type ExchangeRateInput struct {
+	Currency      string
+	Rate          float64
+	EffectiveDate time.Time
+}
+
+// ExchangeRateService manages the exchange rates trip budgets are converted with.
+// Rates always convert to the organization's base currency.
+type ExchangeRateService struct {
+	repo     domain.ExchangeRateRepository
+	orgRepo  domain.OrganizationRepository
+	userRepo domain.UserRepository
+}
+
+func NewExchangeRateService(repo domain.ExchangeRateRepository, orgRepo domain.OrganizationRepository, userRepo domain.UserRepository) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo, orgRepo: orgRepo, userRepo: userRepo}
+}
+
+// CreateRate saves a rate, replacing the one of the same currency and date. Only admins manage rates.
+func (s *ExchangeRateService) CreateRate(ctx context.Context, adminID uuid.UUID, input ExchangeRateInput) (*domain.ExchangeRate, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	rates := []*domain.ExchangeRate{{Currency: input.Currency, Rate: input.Rate, EffectiveDate: input.EffectiveDate}}
+	if err := s.saveRates(ctx, rates, false); err != nil {
+		return nil, err
+	}
+	return rates[0], nil
+}
+
+// ImportRates saves the rates of a CSV file, see domain.ParseExchangeRatesCSV
+func (s *ExchangeRateService) ImportRates(ctx context.Context, adminID uuid.UUID, document []byte) ([]*domain.ExchangeRate, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	rates, err := domain.ParseExchangeRatesCSV(document)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveRates(ctx, rates, true); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (s *ExchangeRateService) ListRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	rates, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rates == nil {
+		rates = []*domain.ExchangeRate{}
+	}
+	return rates, nil
+}
+
+// saveRates fills in the organization and base currency of the rates and saves them.
+// Imported rates are validated as a batch, with the line of each rate in the errors.
+func (s *ExchangeRateService) saveRates(ctx context.Context, rates []*domain.ExchangeRate, imported bool) error {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return domain.ErrMissingTenant
+	}
+	base, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return err
+	}
+
+	for _, rate := range rates {
+		rate.ID = uuid.New()
+		rate.OrgID = orgID
+		rate.BaseCurrency = base
+		rate.CreatedAt = time.Now()
+	}
+
+	if imported {
+		err = domain.ValidateExchangeRates(rates)
+	} else {
+		err = rates[0].Validate()
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.Save(ctx, rates)
+}
+
+// baseCurrency returns the currency the organization of the context reports budgets in
+func baseCurrency(ctx context.Context, orgRepo domain.OrganizationRepository) (string, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return "", domain.ErrMissingTenant
+	}
+	org, err := orgRepo.FindByID(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	if org == nil {
+		return "", ErrOrganizationNotFound
+	}
+	return org.BaseCurrency, nil
+}
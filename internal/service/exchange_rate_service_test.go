@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupExchangeRateService() (*service.ExchangeRateService, *mocks.MockExchangeRateRepository, *mocks.MockOrganizationRepository, *mocks.MockUserRepository) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	exchangeRateService := service.NewExchangeRateService(mockRepo, mockOrgRepo, mockUserRepo)
+	return exchangeRateService, mockRepo, mockOrgRepo, mockUserRepo
+}
+
+func TestExchangeRateService_ImportRates(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	org := &domain.Organization{ID: orgID, BaseCurrency: "BRL"}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		exchangeRateService, mockRepo, mockOrgRepo, mockUserRepo := setupExchangeRateService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(org, nil)
+		mockRepo.On("Save", ctx, mock.AnythingOfType("[]*domain.ExchangeRate")).Return(nil)
+
+		// Act
+		rates, err := exchangeRateService.ImportRates(ctx, admin.ID, []byte("currency,rate,effective_date\nUSD,5.4321,2030-05-01\nEUR,5.8765,2030-05-01\n"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, rates, 2)
+		assert.Equal(t, orgID, rates[1].OrgID)
+		assert.Equal(t, "BRL", rates[1].BaseCurrency)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rate in the base currency", func(t *testing.T) {
+		// Arrange
+		exchangeRateService, mockRepo, mockOrgRepo, mockUserRepo := setupExchangeRateService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(org, nil)
+
+		// Act
+		rates, err := exchangeRateService.ImportRates(ctx, admin.ID, []byte("currency,rate,effective_date\nUSD,5.4321,2030-05-01\nBRL,1,2030-05-01\n"))
+
+		// Assert
+		assert.Nil(t, rates)
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Contains(t, err.Error(), "line 3: currency must differ from the base currency")
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Only admins", func(t *testing.T) {
+		// Arrange
+		exchangeRateService, mockRepo, _, mockUserRepo := setupExchangeRateService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		_, err := exchangeRateService.ImportRates(ctx, employee.ID, []byte("currency,rate,effective_date\nUSD,5.4321,2030-05-01\n"))
+
+		// Assert
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var (
+	ErrExpenseReportNotFound = errors.New("expense report not found")
+	ErrExpenseItemNotFound   = errors.New("expense item not found")
+)
+
+// ExpenseItemInput holds the data of an expense of a trip
+type ExpenseItemInput struct {
+	Date        time.Time
+	Category    domain.BudgetCategory
+	Amount      int64
+	Currency    string
+	Description string
+	ReceiptRef  string
+}
+
+// ExpenseService manages the expense reports of trips. The traveler records
+// the expenses of an approved or concluded trip and submits them; admins and
+// finance approve or reject the report and reimburse it once approved. The
+// reimbursement is what the expenses exceed the cash advances paid for the trip.
+type ExpenseService struct {
+	repo          domain.ExpenseReportRepository
+	tripRepo      domain.TripRepository
+	advances      domain.CashAdvanceRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	exchangeRates domain.ExchangeRateRepository
+	notifier      NotificationService
+}
+
+func NewExpenseService(repo domain.ExpenseReportRepository, tripRepo domain.TripRepository, advances domain.CashAdvanceRepository,
+	userRepo domain.UserRepository, orgRepo domain.OrganizationRepository, exchangeRates domain.ExchangeRateRepository,
+	notifier NotificationService) *ExpenseService {
+	return &ExpenseService{
+		repo:          repo,
+		tripRepo:      tripRepo,
+		advances:      advances,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		exchangeRates: exchangeRates,
+		notifier:      notifier,
+	}
+}
+
+// GetReport returns the expense report of a trip compared with the trip
+// budget. The traveler, admins and finance see it.
+func (s *ExpenseService) GetReport(ctx context.Context, userID, tripID uuid.UUID) (*domain.ExpenseReportDetails, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID != userID {
+		if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := s.repo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrExpenseReportNotFound
+	}
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// ListReports returns the expense reports in the status, or all of them when
+// status is nil, e.g. the submitted ones waiting for review. Admins and finance list reports.
+func (s *ExpenseService) ListReports(ctx context.Context, userID uuid.UUID, status *domain.ExpenseReportStatus) ([]*domain.ExpenseReport, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.List(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if reports == nil {
+		reports = []*domain.ExpenseReport{}
+	}
+	return reports, nil
+}
+
+// AddItem records an expense of the trip, starting its report with the first one
+func (s *ExpenseService) AddItem(ctx context.Context, travelerID, tripID uuid.UUID, input ExpenseItemInput) (*domain.ExpenseReportDetails, error) {
+	trip, err := s.travelerTrip(ctx, travelerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	isNew := report == nil
+	if isNew {
+		if report, err = s.newReport(ctx, trip); err != nil {
+			return nil, err
+		}
+	}
+	if !report.Editable() {
+		return nil, ErrInvalidStatus
+	}
+
+	report.Items = append(report.Items, domain.ExpenseItem{ID: uuid.New()})
+	return s.saveItem(ctx, trip, report, len(report.Items)-1, input, isNew)
+}
+
+// UpdateItem changes an expense of a report that is still a draft or was rejected
+func (s *ExpenseService) UpdateItem(ctx context.Context, travelerID, tripID, itemID uuid.UUID, input ExpenseItemInput) (*domain.ExpenseReportDetails, error) {
+	trip, report, err := s.editableReport(ctx, travelerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Items {
+		if report.Items[i].ID == itemID {
+			return s.saveItem(ctx, trip, report, i, input, false)
+		}
+	}
+	return nil, ErrExpenseItemNotFound
+}
+
+// DeleteItem removes an expense of a report that is still a draft or was rejected
+func (s *ExpenseService) DeleteItem(ctx context.Context, travelerID, tripID, itemID uuid.UUID) (*domain.ExpenseReportDetails, error) {
+	trip, report, err := s.editableReport(ctx, travelerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.FindItem(itemID) == nil {
+		return nil, ErrExpenseItemNotFound
+	}
+	items := make([]domain.ExpenseItem, 0, len(report.Items)-1)
+	for _, item := range report.Items {
+		if item.ID != itemID {
+			items = append(items, item)
+		}
+	}
+	report.Items = items
+	report.UpdatedAt = time.Now()
+
+	if err := s.update(ctx, report, report.Status); err != nil {
+		return nil, err
+	}
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// SubmitReport sends the report for review. A rejected report can be submitted again after being corrected.
+func (s *ExpenseService) SubmitReport(ctx context.Context, travelerID, tripID uuid.UUID) (*domain.ExpenseReportDetails, error) {
+	trip, report, err := s.editableReport(ctx, travelerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Items) == 0 {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("the report has no expenses to submit")
+		return nil, validationErrors
+	}
+
+	now := time.Now()
+	from := report.Status
+	report.Status = domain.ExpenseSubmitted
+	report.SubmittedAt = &now
+	report.RejectionReason = ""
+	report.ReviewedBy, report.ReviewedAt = nil, nil
+	report.UpdatedAt = now
+
+	if err := s.update(ctx, report, from); err != nil {
+		return nil, err
+	}
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// ApproveReport accepts a submitted report for reimbursement
+func (s *ExpenseService) ApproveReport(ctx context.Context, reviewerID, tripID uuid.UUID) (*domain.ExpenseReportDetails, error) {
+	trip, report, err := s.reviewedReport(ctx, reviewerID, tripID, domain.ExpenseSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report.Status = domain.ExpenseApproved
+	report.ReviewedBy, report.ReviewedAt = &reviewerID, &now
+	report.UpdatedAt = now
+
+	if err := s.update(ctx, report, domain.ExpenseSubmitted); err != nil {
+		return nil, err
+	}
+	reconciliation, err := s.reconcile(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("Your expenses for the trip to %s were approved", trip.Destination)
+	switch {
+	case reconciliation.Advanced == 0:
+		message += fmt.Sprintf(": %s will be reimbursed.", domain.Money{Amount: reconciliation.ToReimburse, Currency: report.Currency})
+	case reconciliation.ToReimburse > 0:
+		message += fmt.Sprintf(": %s will be reimbursed, after your advances of %s.",
+			domain.Money{Amount: reconciliation.ToReimburse, Currency: report.Currency},
+			domain.Money{Amount: reconciliation.Advanced, Currency: report.Currency})
+	default:
+		message += fmt.Sprintf(": your advances of %s cover them.", domain.Money{Amount: reconciliation.Advanced, Currency: report.Currency})
+	}
+	s.notifyTraveler(ctx, trip, message)
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// RejectReport sends a submitted report back to the traveler, who can correct and submit it again
+func (s *ExpenseService) RejectReport(ctx context.Context, reviewerID, tripID uuid.UUID, reason string) (*domain.ExpenseReportDetails, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("reason is required")
+		return nil, validationErrors
+	}
+
+	trip, report, err := s.reviewedReport(ctx, reviewerID, tripID, domain.ExpenseSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report.Status = domain.ExpenseRejected
+	report.RejectionReason = reason
+	report.ReviewedBy, report.ReviewedAt = &reviewerID, &now
+	report.UpdatedAt = now
+
+	if err := s.update(ctx, report, domain.ExpenseSubmitted); err != nil {
+		return nil, err
+	}
+	s.notifyTraveler(ctx, trip, fmt.Sprintf("Your expenses for the trip to %s were rejected: %s", trip.Destination, reason))
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// ReimburseReport records that the traveler was paid the approved expenses
+// less the advances paid for the trip, which settling the advances doesn't pay again
+func (s *ExpenseService) ReimburseReport(ctx context.Context, userID, tripID uuid.UUID) (*domain.ExpenseReportDetails, error) {
+	trip, report, err := s.reviewedReport(ctx, userID, tripID, domain.ExpenseApproved)
+	if err != nil {
+		return nil, err
+	}
+	// Reconciled while the report is approved, before the reimbursement clears what's owed
+	reconciliation, err := s.reconcile(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report.Status = domain.ExpenseReimbursed
+	report.ReimbursedBy, report.ReimbursedAt = &userID, &now
+	report.UpdatedAt = now
+
+	if err := s.update(ctx, report, domain.ExpenseApproved); err != nil {
+		return nil, err
+	}
+	if reconciliation.ToReimburse > 0 {
+		s.notifyTraveler(ctx, trip, fmt.Sprintf("Your expenses for the trip to %s were reimbursed: %s.", trip.Destination,
+			domain.Money{Amount: reconciliation.ToReimburse, Currency: report.Currency}))
+	} else {
+		s.notifyTraveler(ctx, trip, fmt.Sprintf("Your expenses for the trip to %s were closed: your advances covered them.", trip.Destination))
+	}
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+func (s *ExpenseService) findTrip(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+	return trip, nil
+}
+
+// travelerTrip returns the trip when the user is its traveler and it was
+// approved or concluded, the trips that have expenses
+func (s *ExpenseService) travelerTrip(ctx context.Context, travelerID, tripID uuid.UUID) (*domain.Trip, error) {
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.RequesterID != travelerID {
+		return nil, ErrPermissionDenied
+	}
+	if trip.Status != domain.StatusApproved && trip.Status != domain.StatusConcluded {
+		return nil, ErrInvalidStatus
+	}
+	return trip, nil
+}
+
+// editableReport returns the report of the traveler's trip when the items can still be changed
+func (s *ExpenseService) editableReport(ctx context.Context, travelerID, tripID uuid.UUID) (*domain.Trip, *domain.ExpenseReport, error) {
+	trip, err := s.travelerTrip(ctx, travelerID, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err := s.repo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if report == nil {
+		return nil, nil, ErrExpenseReportNotFound
+	}
+	if !report.Editable() {
+		return nil, nil, ErrInvalidStatus
+	}
+	return trip, report, nil
+}
+
+// reviewedReport returns the report in the status when the user is an admin
+// or finance. Travelers never review their own expenses.
+func (s *ExpenseService) reviewedReport(ctx context.Context, userID, tripID uuid.UUID, status domain.ExpenseReportStatus) (*domain.Trip, *domain.ExpenseReport, error) {
+	if err := requireBudgetViewer(ctx, s.userRepo, userID); err != nil {
+		return nil, nil, err
+	}
+	trip, err := s.findTrip(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trip.RequesterID == userID {
+		return nil, nil, ErrPermissionDenied
+	}
+
+	report, err := s.repo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if report == nil {
+		return nil, nil, ErrExpenseReportNotFound
+	}
+	if report.Status != status {
+		return nil, nil, ErrInvalidStatus
+	}
+	return trip, report, nil
+}
+
+// newReport starts the report of the trip in the organization's base currency
+func (s *ExpenseService) newReport(ctx context.Context, trip *domain.Trip) (*domain.ExpenseReport, error) {
+	currency, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &domain.ExpenseReport{
+		ID:         uuid.New(),
+		OrgID:      trip.OrgID,
+		TripID:     trip.ID,
+		TravelerID: trip.RequesterID,
+		Status:     domain.ExpenseDraft,
+		Currency:   currency,
+		Items:      []domain.ExpenseItem{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// saveItem fills in the i-th item of the report, converts it to the report
+// currency and stores the report
+func (s *ExpenseService) saveItem(ctx context.Context, trip *domain.Trip, report *domain.ExpenseReport, i int,
+	input ExpenseItemInput, isNew bool) (*domain.ExpenseReportDetails, error) {
+	item := &report.Items[i]
+	item.Date = input.Date
+	item.Category = input.Category
+	item.Amount = input.Amount
+	item.Currency = strings.ToUpper(input.Currency)
+	item.Description = strings.TrimSpace(input.Description)
+	item.ReceiptRef = strings.TrimSpace(input.ReceiptRef)
+	item.Converted = nil
+	report.UpdatedAt = time.Now()
+
+	if err := report.Validate(); err != nil {
+		return nil, err
+	}
+	validationErrors := domain.NewValidationErrors()
+	prefix := fmt.Sprintf("items[%d]: ", i)
+	if item.AfterTrip(trip) {
+		validationErrors.Add(prefix + "date must not be after the last day of the trip")
+		return nil, validationErrors
+	}
+	if item.Currency != report.Currency {
+		rate, err := s.exchangeRates.FindEffective(ctx, item.Currency, report.Currency, item.Date)
+		if err != nil {
+			return nil, err
+		}
+		if rate == nil {
+			validationErrors.Add(fmt.Sprintf("%sno exchange rate from %s to %s on %s", prefix, item.Currency, report.Currency,
+				item.Date.Format("2006-01-02")))
+			return nil, validationErrors
+		}
+		item.ConvertWith(rate, report.UpdatedAt)
+	}
+
+	var err error
+	if isNew {
+		err = s.repo.Create(ctx, report)
+	} else {
+		err = s.update(ctx, report, report.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ExpenseReportDetails{ExpenseReport: report, Comparison: report.Compare(trip)}, nil
+}
+
+// reconcile balances the advances paid for the trip against the report
+func (s *ExpenseService) reconcile(ctx context.Context, report *domain.ExpenseReport) (*domain.AdvanceReconciliation, error) {
+	advances, err := s.advances.ListByTripID(ctx, report.TripID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ReconcileAdvances(report.Currency, report, advances), nil
+}
+
+// update stores the report if it's still in the from status, so that two
+// reviews or a review and an edit at the same time don't both go through
+func (s *ExpenseService) update(ctx context.Context, report *domain.ExpenseReport, from domain.ExpenseReportStatus) error {
+	err := s.repo.Update(ctx, report, from)
+	if errors.Is(err, domain.ErrExpenseReportStatusChanged) {
+		return ErrInvalidStatus
+	}
+	return err
+}
+
+func (s *ExpenseService) notifyTraveler(ctx context.Context, trip *domain.Trip, message string) {
+	traveler, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err == nil && traveler != nil {
+		s.notifier.Send(traveler, trip, message)
+	}
+}
@@ -0,0 +1,422 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupExpenseService() (*service.ExpenseService, *mocks.MockExpenseReportRepository, *mocks.MockTripRepository, *mocks.MockCashAdvanceRepository,
+	*mocks.MockUserRepository, *mocks.MockOrganizationRepository, *mocks.MockExchangeRateRepository, *mocks.MockNotificationService) {
+	mockReportRepo := new(mocks.MockExpenseReportRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockAdvanceRepo := new(mocks.MockCashAdvanceRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	expenseService := service.NewExpenseService(mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier)
+	return expenseService, mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, mockOrgRepo, mockRateRepo, mockNotifier
+}
+
+func TestExpenseService_AddItem(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	traveler := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: traveler.ID,
+		Destination: "Lisboa",
+		StartDate:   time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2030, 5, 14, 18, 0, 0, 0, time.UTC),
+		Status:      domain.StatusApproved,
+		Budget: &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{
+			{Category: domain.BudgetLodging, Amount: 200000},
+		}},
+	}
+	input := service.ExpenseItemInput{
+		Date:        time.Date(2030, 5, 11, 0, 0, 0, 0, time.UTC),
+		Category:    domain.BudgetLodging,
+		Amount:      20000,
+		Currency:    "eur",
+		Description: "Hotel",
+		ReceiptRef:  "NF-1234",
+	}
+
+	t.Run("First expense starts the report and is converted", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, mockOrgRepo, mockRateRepo, _ := setupExpenseService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(nil, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRateRepo.On("FindEffective", ctx, "EUR", "BRL", input.Date).Return(&domain.ExchangeRate{
+			Currency: "EUR", BaseCurrency: "BRL", Rate: 6, EffectiveDate: time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC),
+		}, nil)
+		mockReportRepo.On("Create", ctx, mock.AnythingOfType("*domain.ExpenseReport")).Return(nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseDraft, report.Status)
+		assert.Equal(t, "BRL", report.Currency)
+		assert.Len(t, report.Items, 1)
+		assert.Equal(t, "EUR", report.Items[0].Currency)
+		assert.Equal(t, domain.Money{Amount: 120000, Currency: "BRL"}, report.Items[0].Converted.Total)
+		assert.Equal(t, int64(120000), report.Comparison.Total)
+		assert.Equal(t, int64(80000), *report.Comparison.Remaining)
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("No exchange rate on the day of the expense", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, mockOrgRepo, mockRateRepo, _ := setupExpenseService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(nil, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRateRepo.On("FindEffective", ctx, "EUR", "BRL", input.Date).Return(nil, nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.Nil(t, report)
+		assert.Contains(t, err.Error(), "items[0]: no exchange rate from EUR to BRL on 2030-05-11")
+		mockReportRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expense after the trip", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, mockOrgRepo, _, _ := setupExpenseService()
+		late := input
+		late.Date = time.Date(2030, 5, 15, 0, 0, 0, 0, time.UTC)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(nil, nil)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, traveler.ID, trip.ID, late)
+
+		// Assert
+		assert.Nil(t, report)
+		assert.Contains(t, err.Error(), "items[0]: date must not be after the last day of the trip")
+	})
+
+	t.Run("Submitted report can't be changed", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, _, _, _ := setupExpenseService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(&domain.ExpenseReport{TripID: trip.ID, Status: domain.ExpenseSubmitted}, nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.Nil(t, report)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Trip not approved", func(t *testing.T) {
+		// Arrange
+		expenseService, _, mockTripRepo, _, _, _, _, _ := setupExpenseService()
+		requested := *trip
+		requested.Status = domain.StatusRequested
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(&requested, nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, traveler.ID, trip.ID, input)
+
+		// Assert
+		assert.Nil(t, report)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Only the traveler records expenses", func(t *testing.T) {
+		// Arrange
+		expenseService, _, mockTripRepo, _, _, _, _, _ := setupExpenseService()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		report, err := expenseService.AddItem(ctx, uuid.New(), trip.ID, input)
+
+		// Assert
+		assert.Nil(t, report)
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+	})
+}
+
+func TestExpenseService_Workflow(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	traveler := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	finance := &domain.User{ID: uuid.New(), Role: domain.RoleFinance}
+	trip := &domain.Trip{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		RequesterID: traveler.ID,
+		Destination: "Lisboa",
+		Status:      domain.StatusConcluded,
+	}
+	newReport := func(status domain.ExpenseReportStatus) *domain.ExpenseReport {
+		return &domain.ExpenseReport{
+			ID:         uuid.New(),
+			TripID:     trip.ID,
+			TravelerID: traveler.ID,
+			Status:     status,
+			Currency:   "BRL",
+			Items: []domain.ExpenseItem{{
+				ID: uuid.New(), Category: domain.BudgetLodging, Amount: 45000, Currency: "BRL", Description: "Hotel",
+			}},
+		}
+	}
+
+	t.Run("Submit", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, _, _, _ := setupExpenseService()
+		report := newReport(domain.ExpenseRejected)
+		report.RejectionReason = "Missing the hotel invoice"
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseRejected).Return(nil)
+
+		// Act
+		submitted, err := expenseService.SubmitReport(ctx, traveler.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseSubmitted, submitted.Status)
+		assert.NotNil(t, submitted.SubmittedAt)
+		assert.Empty(t, submitted.RejectionReason)
+	})
+
+	t.Run("Submit without expenses", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, _, _, _, _ := setupExpenseService()
+		report := newReport(domain.ExpenseDraft)
+		report.Items = []domain.ExpenseItem{}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+
+		// Act
+		submitted, err := expenseService.SubmitReport(ctx, traveler.ID, trip.ID)
+
+		// Assert
+		assert.Nil(t, submitted)
+		assert.Contains(t, err.Error(), "the report has no expenses to submit")
+	})
+
+	t.Run("Approve", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseSubmitted)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseSubmitted).Return(nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return(nil, nil)
+		mockNotifier.On("Send", traveler, trip, "Your expenses for the trip to Lisboa were approved: BRL 450.00 will be reimbursed.").Return()
+
+		// Act
+		approved, err := expenseService.ApproveReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseApproved, approved.Status)
+		assert.Equal(t, finance.ID, *approved.ReviewedBy)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Approve deducts the advances paid", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseSubmitted)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseSubmitted).Return(nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{
+			{Status: domain.AdvancePaid, Amount: 30000, Currency: "BRL"},
+		}, nil)
+		mockNotifier.On("Send", traveler, trip,
+			"Your expenses for the trip to Lisboa were approved: BRL 150.00 will be reimbursed, after your advances of BRL 300.00.").Return()
+
+		// Act
+		_, err := expenseService.ApproveReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Approve a report reviewed meanwhile", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseSubmitted)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseSubmitted).Return(domain.ErrExpenseReportStatusChanged)
+
+		// Act
+		approved, err := expenseService.ApproveReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.Nil(t, approved)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Travelers don't review their own expenses", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, mockUserRepo, _, _, _ := setupExpenseService()
+		financeTraveler := &domain.User{ID: traveler.ID, Role: domain.RoleFinance}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(financeTraveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		approved, err := expenseService.ApproveReport(ctx, traveler.ID, trip.ID)
+
+		// Assert
+		assert.Nil(t, approved)
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+		mockReportRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reject requires a reason", func(t *testing.T) {
+		// Arrange
+		expenseService, _, _, _, _, _, _, _ := setupExpenseService()
+
+		// Act
+		rejected, err := expenseService.RejectReport(ctx, finance.ID, trip.ID, "  ")
+
+		// Assert
+		assert.Nil(t, rejected)
+		assert.Contains(t, err.Error(), "reason is required")
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseSubmitted)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseSubmitted).Return(nil)
+		mockNotifier.On("Send", traveler, trip, "Your expenses for the trip to Lisboa were rejected: Missing the hotel invoice").Return()
+
+		// Act
+		rejected, err := expenseService.RejectReport(ctx, finance.ID, trip.ID, "Missing the hotel invoice")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseRejected, rejected.Status)
+		assert.Equal(t, "Missing the hotel invoice", rejected.RejectionReason)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Reimburse only approved reports", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, _, mockUserRepo, _, _, _ := setupExpenseService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(newReport(domain.ExpenseSubmitted), nil)
+
+		// Act
+		reimbursed, err := expenseService.ReimburseReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.Nil(t, reimbursed)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	})
+
+	t.Run("Reimburse", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseApproved)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseApproved).Return(nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{
+			{Status: domain.AdvancePaid, Amount: 30000, Currency: "BRL"},
+		}, nil)
+		mockNotifier.On("Send", traveler, trip, "Your expenses for the trip to Lisboa were reimbursed: BRL 150.00.").Return()
+
+		// Act
+		reimbursed, err := expenseService.ReimburseReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseReimbursed, reimbursed.Status)
+		assert.Equal(t, finance.ID, *reimbursed.ReimbursedBy)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Reimburse what the advances covered", func(t *testing.T) {
+		// Arrange
+		expenseService, mockReportRepo, mockTripRepo, mockAdvanceRepo, mockUserRepo, _, _, mockNotifier := setupExpenseService()
+		report := newReport(domain.ExpenseApproved)
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, finance.ID).Return(finance, nil)
+		mockUserRepo.On("FindByID", ctx, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockReportRepo.On("FindByTripID", ctx, trip.ID).Return(report, nil)
+		mockReportRepo.On("Update", ctx, report, domain.ExpenseApproved).Return(nil)
+		mockAdvanceRepo.On("ListByTripID", ctx, trip.ID).Return([]*domain.CashAdvance{
+			{Status: domain.AdvancePaid, Amount: 60000, Currency: "BRL"},
+		}, nil)
+		mockNotifier.On("Send", traveler, trip, "Your expenses for the trip to Lisboa were closed: your advances covered them.").Return()
+
+		// Act
+		reimbursed, err := expenseService.ReimburseReport(ctx, finance.ID, trip.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExpenseReimbursed, reimbursed.Status)
+		mockNotifier.AssertExpectations(t)
+	})
+}
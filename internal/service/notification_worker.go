@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+const notificationBatchSize = 100
+
+// NotificationWorker sends the scheduled notifications once they are due
+type NotificationWorker struct {
+	repo     domain.ScheduledNotificationRepository
+	tripRepo domain.TripRepository
+	userRepo domain.UserRepository
+	notifier NotificationService
+}
+
+func NewNotificationWorker(repo domain.ScheduledNotificationRepository, tripRepo domain.TripRepository, userRepo domain.UserRepository, notifier NotificationService) *NotificationWorker {
+	return &NotificationWorker{
+		repo:     repo,
+		tripRepo: tripRepo,
+		userRepo: userRepo,
+		notifier: notifier,
+	}
+}
+
+// SendDue sends the due notifications of every organization. Notifications
+// that no longer apply, e.g. a reminder of a trip that isn't approved anymore,
+// are dropped. A notification is marked sent after it's handed to the
+// notifier, so a crash in between sends it again on the next run.
+func (w *NotificationWorker) SendDue(ctx context.Context) error {
+	now := time.Now()
+	for {
+		due, err := w.repo.ListDue(domain.ContextWithAllTenants(ctx), now, notificationBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, notification := range due {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := w.send(domain.ContextWithOrgID(ctx, notification.OrgID), notification, now); err != nil {
+				errs = append(errs, fmt.Errorf("notification %s: %w", notification.ID, err))
+			}
+		}
+
+		// Failed notifications stay due, so stop rather than fetch them again
+		if len(errs) > 0 || len(due) < notificationBatchSize {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func (w *NotificationWorker) send(ctx context.Context, notification *domain.ScheduledNotification, now time.Time) error {
+	recipient, err := w.userRepo.FindByID(ctx, notification.RecipientID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil {
+		return w.repo.Delete(ctx, notification.ID)
+	}
+
+	switch notification.Template {
+	case domain.TemplateTripReminder:
+		if notification.TripID == nil {
+			return w.repo.Delete(ctx, notification.ID)
+		}
+		trip, err := w.tripRepo.FindByID(ctx, *notification.TripID)
+		if err != nil {
+			return err
+		}
+		if trip == nil || trip.Status != domain.StatusApproved {
+			return w.repo.Delete(ctx, notification.ID)
+		}
+		message := fmt.Sprintf("Reminder: your trip to %s starts on %s. Itinerary: %s.",
+			trip.Destination, trip.StartDate.Format("Mon, 02 Jan 2006"), trip.ItinerarySummary())
+		w.notifier.Send(recipient, trip, message)
+	default:
+		return fmt.Errorf("unknown template %q", notification.Template)
+	}
+
+	return w.repo.MarkSent(ctx, notification.ID, now)
+}
@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupNotificationWorker() (*service.NotificationWorker, *mocks.MockScheduledNotificationRepository, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockNotificationService) {
+	mockRepo := new(mocks.MockScheduledNotificationRepository)
+	mockTripRepo := new(mocks.MockTripRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotificationService)
+	worker := service.NewNotificationWorker(mockRepo, mockTripRepo, mockUserRepo, mockNotifier)
+	return worker, mockRepo, mockTripRepo, mockUserRepo, mockNotifier
+}
+
+func TestNotificationWorker_SendDue(t *testing.T) {
+	traveler := &domain.User{ID: uuid.New(), Name: "Traveler"}
+
+	t.Run("Sends the trip reminder with its itinerary", func(t *testing.T) {
+		// Arrange
+		worker, mockRepo, mockTripRepo, mockUserRepo, mockNotifier := setupNotificationWorker()
+		trip := &domain.Trip{
+			ID:          uuid.New(),
+			RequesterID: traveler.ID,
+			Type:        domain.TripInternational,
+			Destination: "Lisboa",
+			StartDate:   time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(2026, 3, 15, 18, 0, 0, 0, time.UTC),
+			Status:      domain.StatusApproved,
+		}
+		notification := &domain.ScheduledNotification{ID: uuid.New(), OrgID: uuid.New(), RecipientID: traveler.ID, TripID: &trip.ID, Template: domain.TemplateTripReminder}
+
+		// Mock behavior
+		mockRepo.On("ListDue", mock.MatchedBy(domain.IsAllTenantsContext), mock.AnythingOfType("time.Time"), 100).Return([]*domain.ScheduledNotification{notification}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockNotifier.On("Send", traveler, trip,
+			"Reminder: your trip to Lisboa starts on Tue, 10 Mar 2026. Itinerary: Lisboa (international), 10 Mar 2026 to 15 Mar 2026.").Return()
+		mockRepo.On("MarkSent", mock.Anything, notification.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		// Act
+		err := worker.SendDue(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Drops reminders of trips no longer approved", func(t *testing.T) {
+		// Arrange
+		worker, mockRepo, mockTripRepo, mockUserRepo, mockNotifier := setupNotificationWorker()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: traveler.ID, Status: domain.StatusCanceled}
+		notification := &domain.ScheduledNotification{ID: uuid.New(), OrgID: uuid.New(), RecipientID: traveler.ID, TripID: &trip.ID, Template: domain.TemplateTripReminder}
+
+		// Mock behavior
+		mockRepo.On("ListDue", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]*domain.ScheduledNotification{notification}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, traveler.ID).Return(traveler, nil)
+		mockTripRepo.On("FindByID", mock.Anything, trip.ID).Return(trip, nil)
+		mockRepo.On("Delete", mock.Anything, notification.ID).Return(nil)
+
+		// Act
+		err := worker.SendDue(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown template", func(t *testing.T) {
+		// Arrange
+		worker, mockRepo, _, mockUserRepo, _ := setupNotificationWorker()
+		notification := &domain.ScheduledNotification{ID: uuid.New(), OrgID: uuid.New(), RecipientID: traveler.ID, Template: "unknown"}
+
+		// Mock behavior
+		mockRepo.On("ListDue", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]*domain.ScheduledNotification{notification}, nil)
+		mockUserRepo.On("FindByID", mock.Anything, traveler.ID).Return(traveler, nil)
+
+		// Act
+		err := worker.SendDue(context.Background())
+
+		// Assert
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
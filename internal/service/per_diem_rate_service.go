@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+var ErrPerDiemRateNotFound = errors.New("per diem rate not found")
+
+// PerDiemRateService manages the per diem rates trip allowances are computed with
+type PerDiemRateService struct {
+	repo     domain.PerDiemRateRepository
+	userRepo domain.UserRepository
+}
+
+func NewPerDiemRateService(repo domain.PerDiemRateRepository, userRepo domain.UserRepository) *PerDiemRateService {
+	return &PerDiemRateService{repo: repo, userRepo: userRepo}
+}
+
+// SaveRate saves a rate, replacing the one of the same country and city. Only admins manage rates.
+func (s *PerDiemRateService) SaveRate(ctx context.Context, adminID uuid.UUID, rate *domain.PerDiemRate) (*domain.PerDiemRate, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveRates(ctx, []*domain.PerDiemRate{rate}, false); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// ImportRates saves the rates of a CSV file, see domain.ParsePerDiemRatesCSV
+func (s *PerDiemRateService) ImportRates(ctx context.Context, adminID uuid.UUID, document []byte) ([]*domain.PerDiemRate, error) {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	rates, err := domain.ParsePerDiemRatesCSV(document)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveRates(ctx, rates, true); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (s *PerDiemRateService) ListRates(ctx context.Context) ([]*domain.PerDiemRate, error) {
+	rates, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rates == nil {
+		rates = []*domain.PerDiemRate{}
+	}
+	return rates, nil
+}
+
+// DeleteRate removes a rate. Trips keep the per diem they were computed with.
+func (s *PerDiemRateService) DeleteRate(ctx context.Context, adminID, rateID uuid.UUID) error {
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return err
+	}
+
+	rate, err := s.repo.FindByID(ctx, rateID)
+	if err != nil {
+		return err
+	}
+	if rate == nil {
+		return ErrPerDiemRateNotFound
+	}
+	return s.repo.Delete(ctx, rateID)
+}
+
+// saveRates fills in the organization of the rates and saves them.
+// Imported rates are validated as a batch, with the line of each rate in the errors.
+func (s *PerDiemRateService) saveRates(ctx context.Context, rates []*domain.PerDiemRate, imported bool) error {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return domain.ErrMissingTenant
+	}
+
+	for _, rate := range rates {
+		rate.ID = uuid.New()
+		rate.OrgID = orgID
+		rate.CreatedAt = time.Now()
+		rate.UpdatedAt = time.Now()
+	}
+
+	var err error
+	if imported {
+		err = domain.ValidatePerDiemRates(rates)
+	} else {
+		err = rates[0].Validate()
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.Save(ctx, rates)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// Scheduler runs background jobs at a fixed interval. Each run takes the job's
+// lock first, so when several replicas are up only one of them runs it.
+type Scheduler struct {
+	locker domain.JobLocker
+	jobs   []scheduledJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+func NewScheduler(locker domain.JobLocker) *Scheduler {
+	return &Scheduler{locker: locker}
+}
+
+// Register adds a job to the scheduler. It must be called before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// Start runs every registered job in the background until Shutdown is called.
+// The first run of a job happens one interval after Start.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Shutdown stops scheduling new runs and waits for the running ones to finish.
+// Running jobs see their context canceled. If ctx ends first, its error is returned.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// select picks at random when Shutdown and the ticker are both ready
+			if ctx.Err() == nil {
+				s.runOnce(ctx, job)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job scheduledJob) {
+	release, acquired, err := s.locker.TryLock(ctx, job.name)
+	if err != nil {
+		log.Printf("job %s: could not take lock: %v", job.name, err)
+		return
+	}
+	if !acquired {
+		// Another replica is running it
+		return
+	}
+	defer release()
+
+	// Runs cut short by Shutdown aren't failures
+	if err := job.run(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("job %s: %v", job.name, err)
+	}
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("Runs jobs holding their lock", func(t *testing.T) {
+		// Arrange
+		mockLocker := new(mocks.MockJobLocker)
+		scheduler := service.NewScheduler(mockLocker)
+		var runs, releases atomic.Int32
+		scheduler.Register("test-job", 10*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+
+		// Mock behavior
+		mockLocker.On("TryLock", mock.Anything, "test-job").Return(func() { releases.Add(1) }, true, nil)
+
+		// Act
+		scheduler.Start()
+		assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		err := scheduler.Shutdown(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, runs.Load(), releases.Load())
+	})
+
+	t.Run("Skips runs when another replica holds the lock", func(t *testing.T) {
+		// Arrange
+		mockLocker := new(mocks.MockJobLocker)
+		scheduler := service.NewScheduler(mockLocker)
+		var runs, attempts atomic.Int32
+		scheduler.Register("test-job", 10*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+
+		// Mock behavior
+		mockLocker.On("TryLock", mock.Anything, "test-job").Return(nil, false, nil).Run(func(mock.Arguments) { attempts.Add(1) })
+
+		// Act
+		scheduler.Start()
+		assert.Eventually(t, func() bool { return attempts.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		err := scheduler.Shutdown(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, runs.Load())
+	})
+
+	t.Run("Shutdown waits for the running job", func(t *testing.T) {
+		// Arrange
+		mockLocker := new(mocks.MockJobLocker)
+		scheduler := service.NewScheduler(mockLocker)
+		started := make(chan struct{})
+		var finished atomic.Bool
+		scheduler.Register("test-job", 10*time.Millisecond, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			finished.Store(true)
+			return ctx.Err()
+		})
+
+		// Mock behavior
+		mockLocker.On("TryLock", mock.Anything, "test-job").Return(func() {}, true, nil).Once()
+
+		// Act
+		scheduler.Start()
+		<-started
+		err := scheduler.Shutdown(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, finished.Load())
+	})
+}
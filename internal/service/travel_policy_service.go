@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+)
+
+// TravelPolicyService manages the travel policy document of an organization.
+// Trips are checked against it by TripService.
+type TravelPolicyService struct {
+	repo     domain.TravelPolicyRepository
+	userRepo domain.UserRepository
+}
+
+func NewTravelPolicyService(repo domain.TravelPolicyRepository, userRepo domain.UserRepository) *TravelPolicyService {
+	return &TravelPolicyService{repo: repo, userRepo: userRepo}
+}
+
+// GetPolicy returns the organization's policy, which is empty until one is saved
+func (s *TravelPolicyService) GetPolicy(ctx context.Context) (*domain.TravelPolicy, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	policy, err := s.repo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &domain.TravelPolicy{OrgID: orgID, Rules: []domain.TravelRule{}}, nil
+	}
+	return policy, nil
+}
+
+// SavePolicy replaces the organization's policy with the given JSON or YAML document
+func (s *TravelPolicyService) SavePolicy(ctx context.Context, adminID uuid.UUID, document []byte) (*domain.TravelPolicy, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+	if err := requireAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	policy, err := domain.ParseTravelPolicy(document)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy.OrgID = orgID
+	policy.UpdatedAt = time.Now()
+	if policy.Rules == nil {
+		policy.Rules = []domain.TravelRule{}
+	}
+
+	if err := s.repo.Save(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
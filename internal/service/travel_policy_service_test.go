@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jimmmmisss/api-viagens/internal/domain"
+	"github.com/jimmmmisss/api-viagens/internal/mocks"
+	"github.com/jimmmmisss/api-viagens/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTravelPolicyService() (*service.TravelPolicyService, *mocks.MockTravelPolicyRepository, *mocks.MockUserRepository) {
+	mockPolicyRepo := new(mocks.MockTravelPolicyRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	travelPolicyService := service.NewTravelPolicyService(mockPolicyRepo, mockUserRepo)
+	return travelPolicyService, mockPolicyRepo, mockUserRepo
+}
+
+func TestTravelPolicyService_GetPolicy(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+
+	t.Run("No policy saved yet", func(t *testing.T) {
+		// Arrange
+		travelPolicyService, mockPolicyRepo, _ := setupTravelPolicyService()
+
+		// Mock behavior
+		mockPolicyRepo.On("Find", ctx).Return(nil, nil)
+
+		// Act
+		policy, err := travelPolicyService.GetPolicy(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, policy.OrgID)
+		assert.Empty(t, policy.Rules)
+	})
+}
+
+func TestTravelPolicyService_SavePolicy(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	document := []byte("rules:\n  - type: min_advance_days\n    severity: warn\n    days: 7\n")
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		travelPolicyService, mockPolicyRepo, mockUserRepo := setupTravelPolicyService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockPolicyRepo.On("Save", ctx, mock.AnythingOfType("*domain.TravelPolicy")).Return(nil)
+
+		// Act
+		policy, err := travelPolicyService.SavePolicy(ctx, admin.ID, document)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, orgID, policy.OrgID)
+		assert.Len(t, policy.Rules, 1)
+		assert.Equal(t, 7, policy.Rules[0].Days)
+		mockPolicyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not an admin", func(t *testing.T) {
+		// Arrange
+		travelPolicyService, mockPolicyRepo, mockUserRepo := setupTravelPolicyService()
+		employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+		// Act
+		policy, err := travelPolicyService.SavePolicy(ctx, employee.ID, document)
+
+		// Assert
+		assert.Nil(t, policy)
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+		mockPolicyRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		// Arrange
+		travelPolicyService, mockPolicyRepo, mockUserRepo := setupTravelPolicyService()
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+
+		// Act
+		policy, err := travelPolicyService.SavePolicy(ctx, admin.ID, []byte(`{"rules": [{"type": "max_trip_days", "severity": "block"}]}`))
+
+		// Assert
+		assert.Nil(t, policy)
+		assert.Contains(t, err.Error(), "days must be positive")
+		mockPolicyRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
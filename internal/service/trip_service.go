@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,41 +17,344 @@ var (
 	ErrPermissionDenied = errors.New("permission denied")
 	ErrSelfApproval     = errors.New("requester cannot approve or cancel their own trip status")
 	ErrInvalidStatus    = errors.New("invalid status for this operation")
-	ErrCancelNotAllowed = errors.New("cannot cancel a trip that starts in 7 days or less")
+	ErrCancelNotAllowed = errors.New("cannot cancel a trip inside its cancellation window")
+	ErrNotApprover      = errors.New("user cannot decide the current approval step of this trip")
+	// ErrMissingApprovalChain is returned when deciding a requested trip without approval steps.
+	// Editing the trip builds its chain again.
+	ErrMissingApprovalChain = errors.New("trip has no approval chain, edit it to start one")
 )
 
 type TripService struct {
-	tripRepo domain.TripRepository
-	userRepo domain.UserRepository // Needed to fetch user for notifications
-	notifier NotificationService
+	tripRepo       domain.TripRepository
+	userRepo       domain.UserRepository // Needed to fetch user for notifications
+	notifier       NotificationService
+	costCenterRepo domain.CostCenterRepository
+	departmentRepo domain.DepartmentRepository
+	policyRepo     domain.ApprovalPolicyRepository
+	stepRepo       domain.ApprovalStepRepository
+	travelPolicies domain.TravelPolicyRepository
+	events         domain.TripEventRepository
+	delegations    domain.DelegationRepository
+	reminders      domain.ScheduledNotificationRepository
+	reminderDays   []int
+	blackouts      domain.BlackoutCalendarRepository
+	destinations   domain.DestinationRepository
+	exchangeRates  domain.ExchangeRateRepository
+	orgRepo        domain.OrganizationRepository
+	budgets        domain.CostCenterBudgetRepository
+	expenseReports domain.ExpenseReportRepository
+	perDiemRates   domain.PerDiemRateRepository
+	transactor     domain.Transactor
 }
 
-func NewTripService(tripRepo domain.TripRepository, userRepo domain.UserRepository, notifier NotificationService) *TripService {
-	return &TripService{
+// TripServiceOption configures optional TripService collaborators
+type TripServiceOption func(*TripService)
+
+// WithCostCenters enables defaulting the cost center from the requester's
+// department and checking that the chosen cost center is active
+func WithCostCenters(costCenterRepo domain.CostCenterRepository, departmentRepo domain.DepartmentRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.costCenterRepo = costCenterRepo
+		s.departmentRepo = departmentRepo
+	}
+}
+
+// WithApprovalChains makes trips go through the approval steps generated by
+// the organization's approval policies instead of a single approval
+func WithApprovalChains(policyRepo domain.ApprovalPolicyRepository, stepRepo domain.ApprovalStepRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.policyRepo = policyRepo
+		s.stepRepo = stepRepo
+	}
+}
+
+// WithTravelPolicies checks trips against the organization's travel policy
+// when they are created or edited
+func WithTravelPolicies(repo domain.TravelPolicyRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.travelPolicies = repo
+	}
+}
+
+// WithTripHistory records creations, edits, decisions and cancellations in the trip history
+func WithTripHistory(repo domain.TripEventRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.events = repo
+	}
+}
+
+// WithDelegations lets delegates decide approval steps on behalf of their delegators
+func WithDelegations(repo domain.DelegationRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.delegations = repo
+	}
+}
+
+// WithTripReminders schedules reminders to the traveler the given number of
+// days before an approved trip starts
+func WithTripReminders(repo domain.ScheduledNotificationRepository, daysBefore ...int) TripServiceOption {
+	return func(s *TripService) {
+		s.reminders = repo
+		s.reminderDays = daysBefore
+	}
+}
+
+// WithBlackoutCalendars checks trips against the blackout calendars of the
+// organization and of the requester's department
+func WithBlackoutCalendars(repo domain.BlackoutCalendarRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.blackouts = repo
+	}
+}
+
+// WithDestinationCatalog checks the destination codes of trips against the
+// destination catalog and names the destination after the catalog entry
+func WithDestinationCatalog(repo domain.DestinationRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.destinations = repo
+	}
+}
+
+// WithExchangeRates converts trip budgets in foreign currencies to the
+// organization's base currency when trips are requested
+func WithExchangeRates(repo domain.ExchangeRateRepository, orgRepo domain.OrganizationRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.exchangeRates = repo
+		s.orgRepo = orgRepo
+	}
+}
+
+// WithCostCenterBudgets checks approvals against the budget of the trip's
+// cost center for the period the trip starts in. Concluded trips count what
+// their approved expense reports spent.
+func WithCostCenterBudgets(repo domain.CostCenterBudgetRepository, expenseReports domain.ExpenseReportRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.budgets = repo
+		s.expenseReports = expenseReports
+	}
+}
+
+// WithPerDiem computes the daily allowance of trips from the organization's
+// per diem rates and the destination catalog when trips are requested or edited
+func WithPerDiem(repo domain.PerDiemRateRepository) TripServiceOption {
+	return func(s *TripService) {
+		s.perDiemRates = repo
+	}
+}
+
+// WithTransactions decides approval steps and changes the trip status in a
+// single transaction, so concurrent decisions can't both go through
+func WithTransactions(transactor domain.Transactor) TripServiceOption {
+	return func(s *TripService) {
+		s.transactor = transactor
+	}
+}
+
+func NewTripService(tripRepo domain.TripRepository, userRepo domain.UserRepository, notifier NotificationService, opts ...TripServiceOption) *TripService {
+	s := &TripService{
 		tripRepo: tripRepo,
 		userRepo: userRepo,
 		notifier: notifier,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// TripInput holds the data a requester provides when creating or editing a trip
+type TripInput struct {
+	// Type defaults to domestic for new trips and is kept for edits when empty
+	Type        domain.TripType
+	Destination string
+	StartDate   time.Time
+	EndDate     time.Time
+	// Legs is the itinerary of the trip; when given, Destination is derived from it
+	Legs []domain.ItineraryLeg
+	// DestinationCode is the catalog code of the destination, a city or one of its airports
+	DestinationCode string
+	// CostCenterID is optional; when nil the requester's department cost center
+	// is used for new trips and the current one is kept for edits
+	CostCenterID *uuid.UUID
+	// TimeZone is the IANA zone the rules of the trip are computed in, and its
+	// dates shown in. It defaults to the zone of the destination in the catalog.
+	TimeZone string
+	// Budget is the estimated cost of the trip, optional
+	Budget *domain.TripBudget
+	// ProvidedMeals are deducted from the per diem of their days
+	ProvidedMeals []domain.ProvidedMeals
 }
 
-func (s *TripService) CreateTrip(ctx context.Context, requesterID uuid.UUID, dest string, start, end time.Time) (*domain.Trip, error) {
+func (s *TripService) CreateTrip(ctx context.Context, requesterID uuid.UUID, input TripInput) (*domain.Trip, error) {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	costCenterID, err := s.resolveCostCenter(ctx, requesterID, input.CostCenterID)
+	if err != nil {
+		return nil, err
+	}
+
+	tripType := input.Type
+	if tripType == "" {
+		tripType = domain.TripDomestic
+	}
+
 	trip := &domain.Trip{
-		ID:          uuid.New(),
-		RequesterID: requesterID,
-		Destination: dest,
-		StartDate:   start,
-		EndDate:     end,
-		Status:      domain.StatusRequested,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:           uuid.New(),
+		OrgID:        orgID,
+		RequesterID:  requesterID,
+		CostCenterID: costCenterID,
+		Type:         tripType,
+		Destination:  input.Destination,
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+		Status:       domain.StatusRequested,
+		Budget:       input.Budget,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	trip.SetLegs(input.Legs)
+	if err := s.resolveDestination(ctx, trip, input.DestinationCode); err != nil {
+		return nil, err
+	}
+	if err := s.resolveTimeZone(ctx, trip, input.TimeZone); err != nil {
+		return nil, err
+	}
+	if err := s.convertBudget(ctx, trip); err != nil {
+		return nil, err
+	}
+	if err := s.calculatePerDiem(ctx, trip, input.ProvidedMeals); err != nil {
+		return nil, err
 	}
 
 	// Validate trip before saving
-	if err := trip.Validate(); err != nil {
+	if err := s.checkTrip(ctx, trip); err != nil {
 		return nil, err
 	}
 
-	if err := s.tripRepo.Create(ctx, trip); err != nil {
+	var steps []*domain.ApprovalStep
+	if s.stepRepo != nil {
+		if steps, err = s.buildApprovalSteps(ctx, trip); err != nil {
+			return nil, err
+		}
+	}
+
+	// A trip is never stored without its approval chain
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.tripRepo.Create(ctx, trip); err != nil {
+			return err
+		}
+		if len(steps) > 0 {
+			if err := s.stepRepo.CreateSteps(ctx, steps); err != nil {
+				return err
+			}
+		}
+		return s.recordEvent(ctx, trip, domain.TripEvent{ActorID: requesterID, Action: domain.EventCreated})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTripOverlap) {
+			// A concurrent save took the dates after checkTrip
+			if conflict := s.checkOverlap(ctx, trip); conflict != nil {
+				return nil, conflict
+			}
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+// UpdateTrip lets the requester edit a trip that is still waiting for approval.
+// The travel policy is checked again and the approval chain starts over. The
+// trip is only saved if nobody decided or withdrew it in the meantime.
+func (s *TripService) UpdateTrip(ctx context.Context, tripID, requesterID uuid.UUID, input TripInput) (*domain.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, ErrTripNotFound
+	}
+	if trip.RequesterID != requesterID {
+		return nil, ErrPermissionDenied
+	}
+	if trip.Status != domain.StatusRequested {
+		return nil, ErrInvalidStatus
+	}
+
+	requested := input.CostCenterID
+	if requested == nil {
+		requested = &trip.CostCenterID
+	}
+	costCenterID, err := s.resolveCostCenter(ctx, requesterID, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	trip.CostCenterID = costCenterID
+	if input.Type != "" {
+		trip.Type = input.Type
+	}
+	trip.Destination = input.Destination
+	trip.SetLegs(input.Legs)
+	trip.StartDate = input.StartDate
+	trip.EndDate = input.EndDate
+	trip.Budget = input.Budget
+	trip.UpdatedAt = time.Now()
+	if err := s.resolveDestination(ctx, trip, input.DestinationCode); err != nil {
+		return nil, err
+	}
+	if err := s.resolveTimeZone(ctx, trip, input.TimeZone); err != nil {
+		return nil, err
+	}
+	if err := s.convertBudget(ctx, trip); err != nil {
+		return nil, err
+	}
+	if err := s.calculatePerDiem(ctx, trip, input.ProvidedMeals); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	var steps []*domain.ApprovalStep
+	if s.stepRepo != nil {
+		if steps, err = s.buildApprovalSteps(ctx, trip); err != nil {
+			return nil, err
+		}
+	}
+
+	// The old chain is only replaced together with the trip
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.tripRepo.Update(ctx, trip); err != nil {
+			return err
+		}
+		if s.stepRepo != nil {
+			if err := s.stepRepo.DeleteByTripID(ctx, trip.ID); err != nil {
+				return err
+			}
+			if err := s.stepRepo.CreateSteps(ctx, steps); err != nil {
+				return err
+			}
+		}
+		return s.recordEvent(ctx, trip, domain.TripEvent{ActorID: requesterID, Action: domain.EventUpdated})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTripOverlap) {
+			// A concurrent save took the dates after checkTrip
+			if conflict := s.checkOverlap(ctx, trip); conflict != nil {
+				return nil, conflict
+			}
+		}
+		if errors.Is(err, domain.ErrTripStatusChanged) {
+			// Decided or withdrawn after the trip was read
+			return nil, ErrInvalidStatus
+		}
+		return nil, err
+	}
+	if err := s.syncReminders(ctx, trip); err != nil {
 		return nil, err
 	}
 	return trip, nil
@@ -90,7 +395,74 @@ func (s *TripService) UpdateTripStatus(ctx context.Context, tripID, updaterID uu
 		return ErrSelfApproval
 	}
 
-	if err := s.tripRepo.UpdateStatus(ctx, tripID, newStatus); err != nil {
+	// Withdrawn and concluded trips are closed for good
+	if trip.Status == domain.StatusWithdrawn || trip.Status == domain.StatusConcluded {
+		return ErrInvalidStatus
+	}
+
+	action := domain.EventApproved
+	if newStatus == domain.StatusCanceled {
+		action = domain.EventRejected
+	}
+
+	event := domain.TripEvent{ActorID: updaterID, Action: action}
+
+	// A rejection cancels the trip right away, while an approval only counts
+	// once the last step passes. Trips created before approval chains have no
+	// steps and change immediately.
+	var steps []*domain.ApprovalStep
+	var step *domain.ApprovalStep
+	if s.stepRepo != nil {
+		steps, step, event.OnBehalfOfID, err = s.approvalStep(ctx, trip, updaterID)
+		if err != nil {
+			return err
+		}
+	}
+	final := step == nil || newStatus == domain.StatusCanceled || step.Position == steps[len(steps)-1].Position
+
+	// The decision on the step and the status change stand or fall together
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		// Only the approval that approves the trip is checked against the
+		// budget, and only once the updater is known to be allowed to give it.
+		// The budget stays locked until the trip is approved.
+		if final && newStatus == domain.StatusApproved && trip.Status == domain.StatusRequested {
+			if err := s.checkBudget(ctx, trip); err != nil {
+				return err
+			}
+		}
+		if step != nil {
+			if err := s.recordDecision(ctx, step, updaterID, newStatus); err != nil {
+				return err
+			}
+			if !final {
+				return s.recordEvent(ctx, trip, event)
+			}
+		}
+		if err := s.tripRepo.UpdateStatus(ctx, tripID, trip.Status, newStatus); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, trip, event)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTripOverlap) {
+			// Approving a cancelled trip brings its dates back
+			if conflict := s.checkOverlap(ctx, trip); conflict != nil {
+				return conflict
+			}
+		}
+		if errors.Is(err, domain.ErrTripStatusChanged) {
+			// Decided, withdrawn or edited by someone else after the trip was read
+			return ErrInvalidStatus
+		}
+		return err
+	}
+	if !final {
+		s.notifyNextStep(ctx, trip, steps, step)
+		return nil
+	}
+
+	trip.Status = newStatus
+	if err := s.syncReminders(ctx, trip); err != nil {
 		return err
 	}
 
@@ -104,7 +476,11 @@ func (s *TripService) UpdateTripStatus(ctx context.Context, tripID, updaterID uu
 	return nil
 }
 
-func (s *TripService) CancelApprovedTrip(ctx context.Context, tripID, cancelingUserID uuid.UUID) error {
+// CancelApprovedTrip cancels an approved trip. The requester can cancel it
+// until the cancellation deadline set by the travel policy. Admins and the
+// requester's manager can also cancel it on the requester's behalf or after
+// the deadline, as long as they give a justification for the trip history.
+func (s *TripService) CancelApprovedTrip(ctx context.Context, tripID, cancelingUserID uuid.UUID, justification string) error {
 	trip, err := s.tripRepo.FindByID(ctx, tripID)
 	if err != nil {
 		return err
@@ -113,8 +489,10 @@ func (s *TripService) CancelApprovedTrip(ctx context.Context, tripID, cancelingU
 		return ErrTripNotFound
 	}
 
-	// Rule: Only the requester can cancel their own approved trip.
-	if trip.RequesterID != cancelingUserID {
+	isRequester := trip.RequesterID == cancelingUserID
+
+	// Rule: Only the requester can cancel their own approved trip, unless overriding.
+	if !isRequester && justification == "" {
 		return ErrPermissionDenied
 	}
 
@@ -122,12 +500,47 @@ func (s *TripService) CancelApprovedTrip(ctx context.Context, tripID, cancelingU
 		return ErrInvalidStatus
 	}
 
-	// Business Rule: Cannot cancel a trip that starts within the next 7 days.
-	if time.Until(trip.StartDate) < 7*24*time.Hour {
-		return ErrCancelNotAllowed
+	deadline, err := s.cancellationDeadline(ctx, trip)
+	if err != nil {
+		return err
+	}
+
+	action := domain.EventCanceled
+	if !isRequester || !time.Now().Before(deadline) {
+		if justification == "" {
+			return ErrCancelNotAllowed
+		}
+		allowed, err := s.canOverrideCancellation(ctx, trip, cancelingUserID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			if isRequester {
+				return ErrCancelNotAllowed
+			}
+			return ErrPermissionDenied
+		}
+		action = domain.EventCancellationOverride
+	} else {
+		// The justification only matters for overrides
+		justification = ""
 	}
 
-	if err := s.tripRepo.UpdateStatus(ctx, tripID, domain.StatusCanceled); err != nil {
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.tripRepo.UpdateStatus(ctx, tripID, domain.StatusApproved, domain.StatusCanceled); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, trip, domain.TripEvent{ActorID: cancelingUserID, Action: action, Justification: justification})
+	})
+	if errors.Is(err, domain.ErrTripStatusChanged) {
+		// Cancelled or concluded after the trip was read
+		return ErrInvalidStatus
+	}
+	if err != nil {
+		return err
+	}
+	trip.Status = domain.StatusCanceled
+	if err := s.syncReminders(ctx, trip); err != nil {
 		return err
 	}
 
@@ -135,8 +548,672 @@ func (s *TripService) CancelApprovedTrip(ctx context.Context, tripID, cancelingU
 	requester, err := s.userRepo.FindByID(ctx, trip.RequesterID)
 	if err == nil && requester != nil {
 		message := fmt.Sprintf("Your trip to %s has been canceled.", trip.Destination)
+		if action == domain.EventCancellationOverride {
+			message = fmt.Sprintf("Your trip to %s has been canceled: %s", trip.Destination, justification)
+		}
 		s.notifier.Send(requester, trip, message)
 	}
 
 	return nil
 }
+
+// WithdrawTrip lets the requester give up on a trip that is still waiting for
+// approval. Unlike a cancellation there is no notice period.
+func (s *TripService) WithdrawTrip(ctx context.Context, tripID, requesterID uuid.UUID) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	if trip == nil {
+		return ErrTripNotFound
+	}
+	if trip.RequesterID != requesterID {
+		return ErrPermissionDenied
+	}
+	if trip.Status != domain.StatusRequested {
+		return ErrInvalidStatus
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.tripRepo.UpdateStatus(ctx, tripID, domain.StatusRequested, domain.StatusWithdrawn); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, trip, domain.TripEvent{ActorID: requesterID, Action: domain.EventWithdrawn})
+	})
+	if errors.Is(err, domain.ErrTripStatusChanged) {
+		// Decided after the trip was read
+		return ErrInvalidStatus
+	}
+	return err
+}
+
+// ConcludeEndedTrips concludes the approved trips of every organization whose
+// end date has passed and asks the travelers to submit their expenses
+func (s *TripService) ConcludeEndedTrips(ctx context.Context) error {
+	trips, err := s.tripRepo.ConcludeEnded(domain.ContextWithAllTenants(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, trip := range trips {
+		requester, err := s.userRepo.FindByID(domain.ContextWithOrgID(ctx, trip.OrgID), trip.RequesterID)
+		if err == nil && requester != nil {
+			message := fmt.Sprintf("Your trip to %s has ended. Please submit your expenses.", trip.Destination)
+			s.notifier.Send(requester, trip, message)
+		}
+	}
+	return nil
+}
+
+// GetTripHistory returns the history of a trip the user can see
+func (s *TripService) GetTripHistory(ctx context.Context, tripID, userID uuid.UUID) ([]*domain.TripEvent, error) {
+	if _, err := s.GetTripByID(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+	if s.events == nil {
+		return []*domain.TripEvent{}, nil
+	}
+
+	events, err := s.events.ListByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []*domain.TripEvent{}
+	}
+	return events, nil
+}
+
+// cancellationDeadline applies the cancellation settings of the organization's
+// travel policy, or the default notice when there are none
+func (s *TripService) cancellationDeadline(ctx context.Context, trip *domain.Trip) (time.Time, error) {
+	var cancellation *domain.CancellationPolicy
+	if s.travelPolicies != nil {
+		policy, err := s.travelPolicies.Find(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if policy != nil {
+			cancellation = policy.Cancellation
+		}
+	}
+
+	// Rules for a region match on the catalog entry of the destination
+	var destination *domain.Destination
+	if cancellation != nil && s.destinations != nil && trip.DestinationCode != nil {
+		var err error
+		if destination, err = s.destinations.FindByCode(ctx, *trip.DestinationCode); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return cancellation.CancellationDeadline(trip, destination), nil
+}
+
+// canOverrideCancellation reports whether the user is an admin or the requester's manager
+func (s *TripService) canOverrideCancellation(ctx context.Context, trip *domain.Trip, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, nil
+	}
+	if user.Role == domain.RoleAdmin {
+		return true, nil
+	}
+
+	requester, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err != nil {
+		return false, err
+	}
+	return requester != nil && requester.ManagerID != nil && *requester.ManagerID == userID, nil
+}
+
+// recordEvent adds an entry to the trip history when it is enabled. The
+// event only needs the actor, the action and its details.
+func (s *TripService) recordEvent(ctx context.Context, trip *domain.Trip, event domain.TripEvent) error {
+	if s.events == nil {
+		return nil
+	}
+	event.ID = uuid.New()
+	event.OrgID = trip.OrgID
+	event.TripID = trip.ID
+	event.CreatedAt = time.Now()
+	return s.events.Create(ctx, &event)
+}
+
+// syncReminders replaces the pending reminders of the trip, so they follow
+// its dates: approved trips get one for each reminder day still ahead and
+// any other trip none
+func (s *TripService) syncReminders(ctx context.Context, trip *domain.Trip) error {
+	if s.reminders == nil {
+		return nil
+	}
+	if err := s.reminders.DeletePendingByTripID(ctx, trip.ID); err != nil {
+		return err
+	}
+	if trip.Status != domain.StatusApproved {
+		return nil
+	}
+
+	now := time.Now()
+	for _, days := range s.reminderDays {
+		dueAt := trip.StartDate.AddDate(0, 0, -days)
+		if !dueAt.After(now) {
+			continue
+		}
+		// The start date is part of the key, so moving the trip brings back
+		// reminders that were already sent for the old date
+		err := s.reminders.Schedule(ctx, &domain.ScheduledNotification{
+			ID:          uuid.New(),
+			OrgID:       trip.OrgID,
+			RecipientID: trip.RequesterID,
+			TripID:      &trip.ID,
+			Template:    domain.TemplateTripReminder,
+			DedupeKey:   fmt.Sprintf("trip_reminder:%s:%s:%d", trip.ID, trip.StartDate.Format("2006-01-02"), days),
+			DueAt:       dueAt,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOverlap returns a *domain.TripConflictError when the trip overlaps
+// other trips of its requester
+func (s *TripService) checkOverlap(ctx context.Context, trip *domain.Trip) error {
+	overlapping, err := s.tripRepo.FindOverlapping(ctx, trip.RequesterID, trip.StartDate, trip.EndDate, trip.ID)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) == 0 {
+		return nil
+	}
+
+	conflict := &domain.TripConflictError{}
+	for _, other := range overlapping {
+		conflict.TripIDs = append(conflict.TripIDs, other.ID)
+	}
+	return conflict
+}
+
+// resolveDestination stores the catalog reference of the trip's destination.
+// Airports are stored as the city they serve, and trips without an itinerary
+// are named after that city.
+func (s *TripService) resolveDestination(ctx context.Context, trip *domain.Trip, code string) error {
+	trip.DestinationCode = nil
+	if code == "" {
+		return nil
+	}
+	if s.destinations == nil {
+		code = strings.ToUpper(code)
+		trip.DestinationCode = &code
+		return nil
+	}
+
+	destination, err := s.destinations.FindByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if destination != nil && destination.ReferenceCode() != destination.Code {
+		destination, err = s.destinations.FindByCode(ctx, destination.ReferenceCode())
+		if err != nil {
+			return err
+		}
+	}
+	if destination == nil {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add(fmt.Sprintf("unknown destination_code %q", code))
+		return validationErrors
+	}
+
+	trip.DestinationCode = &destination.Code
+	if len(trip.Legs) == 0 {
+		trip.Destination = destination.Name
+	}
+	return nil
+}
+
+// resolveTimeZone sets the time zone of the trip: the one requested, else the
+// one of its destination in the catalog
+func (s *TripService) resolveTimeZone(ctx context.Context, trip *domain.Trip, name string) error {
+	if name == "" && trip.DestinationCode != nil && s.destinations != nil {
+		destination, err := s.destinations.FindByCode(ctx, *trip.DestinationCode)
+		if err != nil {
+			return err
+		}
+		if destination != nil {
+			name = destination.TimeZone
+		}
+	}
+	trip.SetTimeZone(name)
+	return nil
+}
+
+// convertBudget records the budget total in the organization's base currency,
+// with the rate in effect today, when the budget is in another currency
+func (s *TripService) convertBudget(ctx context.Context, trip *domain.Trip) error {
+	if trip.Budget == nil {
+		return nil
+	}
+	trip.Budget.Converted = nil
+	if s.exchangeRates == nil {
+		return nil
+	}
+
+	base, err := baseCurrency(ctx, s.orgRepo)
+	if err != nil {
+		return err
+	}
+	if trip.Budget.Currency == base || !domain.IsCurrencyCode(trip.Budget.Currency) {
+		return nil
+	}
+
+	now := time.Now()
+	rate, err := s.exchangeRates.FindEffective(ctx, trip.Budget.Currency, base, now)
+	if err != nil {
+		return err
+	}
+	if rate == nil {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add(fmt.Sprintf("budget: no exchange rate from %s to %s", trip.Budget.Currency, base))
+		return validationErrors
+	}
+	trip.Budget.ConvertWith(rate, now)
+	return nil
+}
+
+// checkBudget compares the trip with what is left of the budget it's charged
+// to. Over a blocking budget the approval is refused; over a warning one the
+// trip keeps the warning and the approval goes on. The budget is locked first,
+// so approvals running at the same time can't both take what is left.
+func (s *TripService) checkBudget(ctx context.Context, trip *domain.Trip) error {
+	if s.budgets == nil {
+		return nil
+	}
+
+	budgets, err := s.budgets.ListByCostCenter(ctx, &trip.CostCenterID)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		if !budget.Covers(trip) {
+			continue
+		}
+		if err := s.budgets.Lock(ctx, budget.ID); err != nil {
+			return err
+		}
+		consumption, err := budgetConsumption(ctx, s.tripRepo, s.expenseReports, budget)
+		if err != nil {
+			return err
+		}
+		violation := budget.Check(trip, consumption)
+		if violation == nil {
+			return nil
+		}
+		if violation.Severity == domain.SeverityBlock {
+			cost, _ := trip.Budget.TotalIn(budget.Currency)
+			return &domain.BudgetExceededError{
+				BudgetID:  budget.ID,
+				Remaining: domain.Money{Amount: consumption.Remaining, Currency: budget.Currency},
+				TripCost:  domain.Money{Amount: cost, Currency: budget.Currency},
+			}
+		}
+		if slices.Contains(trip.PolicyWarnings, violation.Message) {
+			return nil
+		}
+		trip.PolicyWarnings = append(trip.PolicyWarnings, violation.Message)
+		return s.tripRepo.Update(ctx, trip)
+	}
+	return nil
+}
+
+// calculatePerDiem computes the daily allowance of the trip from the per diem
+// rates of the organization. Organizations without rates don't pay per diem,
+// and neither do trips to places outside the catalog or without a rate.
+func (s *TripService) calculatePerDiem(ctx context.Context, trip *domain.Trip, meals []domain.ProvidedMeals) error {
+	trip.PerDiem = nil
+	// Missing or inverted dates are reported by the trip validation
+	if s.perDiemRates == nil || s.destinations == nil || trip.StartDate.IsZero() || !trip.EndDate.After(trip.StartDate) {
+		return nil
+	}
+
+	rates, err := s.perDiemRates.List(ctx)
+	if err != nil || len(rates) == 0 {
+		return err
+	}
+	stops, err := s.perDiemStops(ctx, trip)
+	if err != nil {
+		return err
+	}
+
+	perDiem, err := domain.CalculatePerDiem(trip, stops, rates, meals, time.Now())
+	if err != nil {
+		return err
+	}
+	trip.PerDiem = perDiem
+	return nil
+}
+
+// perDiemStops finds the destinations of the trip in the catalog: the
+// destination code, or the destination of each leg except the way back home.
+// It returns no stops when any of them is not in the catalog.
+func (s *TripService) perDiemStops(ctx context.Context, trip *domain.Trip) ([]domain.PerDiemStop, error) {
+	if len(trip.Legs) == 0 {
+		if trip.DestinationCode == nil {
+			return nil, nil
+		}
+		destination, err := s.destinations.FindByCode(ctx, *trip.DestinationCode)
+		if err != nil || destination == nil {
+			return nil, err
+		}
+		return []domain.PerDiemStop{{Arrival: trip.StartDate, Destination: destination}}, nil
+	}
+
+	home := domain.NormalizeSearchText(trip.Legs[0].Origin)
+	var stops []domain.PerDiemStop
+	for _, leg := range trip.Legs {
+		if domain.NormalizeSearchText(leg.Destination) == home {
+			continue
+		}
+		destination, err := s.findCatalogCity(ctx, leg.Destination)
+		if err != nil {
+			return nil, err
+		}
+		if destination == nil {
+			return nil, nil
+		}
+		stops = append(stops, domain.PerDiemStop{Arrival: leg.ArriveAt, Destination: destination})
+	}
+	return stops, nil
+}
+
+// findCatalogCity looks a place up in the destination catalog by code, name
+// or alias, and returns its city
+func (s *TripService) findCatalogCity(ctx context.Context, place string) (*domain.Destination, error) {
+	destination, err := s.destinations.FindByCode(ctx, strings.ToUpper(strings.TrimSpace(place)))
+	if err != nil {
+		return nil, err
+	}
+	if destination == nil {
+		candidates, err := s.destinations.Search(ctx, place, 10)
+		if err != nil {
+			return nil, err
+		}
+		name := domain.NormalizeSearchText(place)
+		for _, candidate := range candidates {
+			if candidate.MatchesName(name) {
+				destination = candidate
+				break
+			}
+		}
+	}
+	if destination != nil && destination.ReferenceCode() != destination.Code {
+		return s.destinations.FindByCode(ctx, destination.ReferenceCode())
+	}
+	return destination, nil
+}
+
+// checkTrip runs the checks a trip must pass before it's saved
+func (s *TripService) checkTrip(ctx context.Context, trip *domain.Trip) error {
+	if err := trip.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkTravelPolicy(ctx, trip); err != nil {
+		return err
+	}
+	return s.checkOverlap(ctx, trip)
+}
+
+// checkTravelPolicy rejects the trip when it breaks a blocking rule of the
+// travel policy or falls in a blocking blackout period, and keeps the warnings of the others
+func (s *TripService) checkTravelPolicy(ctx context.Context, trip *domain.Trip) error {
+	violations, err := s.travelPolicyViolations(ctx, trip)
+	if err != nil {
+		return err
+	}
+	blackouts, err := s.blackoutViolations(ctx, trip)
+	if err != nil {
+		return err
+	}
+	violations = append(violations, blackouts...)
+
+	trip.PolicyWarnings = nil
+	validationErrors := domain.NewValidationErrors()
+	for _, violation := range violations {
+		if violation.Severity == domain.SeverityBlock {
+			validationErrors.Add(violation.Message)
+		} else {
+			trip.PolicyWarnings = append(trip.PolicyWarnings, violation.Message)
+		}
+	}
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+func (s *TripService) travelPolicyViolations(ctx context.Context, trip *domain.Trip) ([]domain.PolicyViolation, error) {
+	if s.travelPolicies == nil {
+		return nil, nil
+	}
+
+	policy, err := s.travelPolicies.Find(ctx)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	return policy.Evaluate(trip, time.Now()), nil
+}
+
+// blackoutViolations checks the trip against the organization-wide calendars
+// and the ones of the requester's department
+func (s *TripService) blackoutViolations(ctx context.Context, trip *domain.Trip) ([]domain.PolicyViolation, error) {
+	if s.blackouts == nil {
+		return nil, nil
+	}
+
+	calendars, err := s.blackouts.List(ctx)
+	if err != nil || len(calendars) == 0 {
+		return nil, err
+	}
+
+	requester, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+	var departmentID *uuid.UUID
+	if requester != nil {
+		departmentID = requester.DepartmentID
+	}
+
+	var violations []domain.PolicyViolation
+	for _, calendar := range calendars {
+		if calendar.AppliesTo(departmentID) {
+			violations = append(violations, calendar.Evaluate(trip)...)
+		}
+	}
+	return violations, nil
+}
+
+// buildApprovalSteps turns the approval chain of the trip into pending steps.
+// The manager step goes to the requester's manager when they have one.
+func (s *TripService) buildApprovalSteps(ctx context.Context, trip *domain.Trip) ([]*domain.ApprovalStep, error) {
+	policies, err := s.policyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requester, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := domain.BuildApprovalChain(policies, trip)
+	steps := make([]*domain.ApprovalStep, len(chain))
+	for i, role := range chain {
+		steps[i] = &domain.ApprovalStep{
+			ID:        uuid.New(),
+			OrgID:     trip.OrgID,
+			TripID:    trip.ID,
+			Position:  i + 1,
+			Role:      role,
+			Status:    domain.StepPending,
+			CreatedAt: trip.CreatedAt,
+		}
+		if role == domain.RoleManager && requester != nil && requester.ManagerID != nil {
+			steps[i].ApproverID = requester.ManagerID
+		}
+	}
+	return steps, nil
+}
+
+// approvalStep returns the approval steps of the trip and the current one,
+// once the updater is known to be allowed to decide it. Trips decided before
+// approval chains have no steps and no current step. When the updater decides
+// through a delegation, the delegator is returned.
+func (s *TripService) approvalStep(ctx context.Context, trip *domain.Trip, updaterID uuid.UUID) ([]*domain.ApprovalStep, *domain.ApprovalStep, *uuid.UUID, error) {
+	steps, err := s.stepRepo.FindByTripID(ctx, trip.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(steps) == 0 {
+		// The trips still requested when approval chains came in got their
+		// steps in 000005_approval_chains, so a requested trip without any
+		// lost its chain and nobody may approve it
+		if trip.Status == domain.StatusRequested {
+			return nil, nil, nil, ErrMissingApprovalChain
+		}
+		return nil, nil, nil, nil
+	}
+
+	step := domain.CurrentApprovalStep(steps)
+	if trip.Status != domain.StatusRequested || step == nil {
+		return nil, nil, nil, ErrInvalidStatus
+	}
+
+	updater, err := s.userRepo.FindByID(ctx, updaterID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if updater == nil {
+		return nil, nil, nil, ErrNotApprover
+	}
+
+	var onBehalfOf *uuid.UUID
+	if !step.CanBeDecidedBy(updater) {
+		delegator, err := s.findDelegator(ctx, trip, step, updaterID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if delegator == nil {
+			return nil, nil, nil, ErrNotApprover
+		}
+		onBehalfOf = &delegator.ID
+	}
+	return steps, step, onBehalfOf, nil
+}
+
+// recordDecision records the updater's decision on the current approval step.
+// It fails with domain.ErrStepAlreadyDecided when someone else decided it first.
+func (s *TripService) recordDecision(ctx context.Context, step *domain.ApprovalStep, updaterID uuid.UUID, newStatus domain.TripStatus) error {
+	now := time.Now()
+	step.DecidedBy = &updaterID
+	step.DecidedAt = &now
+	step.Status = domain.StepApproved
+	if newStatus == domain.StatusCanceled {
+		step.Status = domain.StepRejected
+	}
+	return s.stepRepo.UpdateDecision(ctx, step)
+}
+
+// notifyNextStep lets the requester know the trip moved on to its next approval step
+func (s *TripService) notifyNextStep(ctx context.Context, trip *domain.Trip, steps []*domain.ApprovalStep, step *domain.ApprovalStep) {
+	requester, err := s.userRepo.FindByID(ctx, trip.RequesterID)
+	if err == nil && requester != nil {
+		next := domain.CurrentApprovalStep(steps)
+		message := fmt.Sprintf("Your trip to %s was approved by %s and now awaits %s approval.", trip.Destination, step.Role, next.Role)
+		s.notifier.Send(requester, trip, message)
+	}
+}
+
+// withinTx runs fn in a transaction when the service has a transactor, or as is otherwise
+func (s *TripService) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.transactor == nil {
+		return fn(ctx)
+	}
+	return s.transactor.WithinTx(ctx, fn)
+}
+
+// findDelegator returns a user who delegated their approval authority to the
+// updater and could decide the step, or nil when there is none
+func (s *TripService) findDelegator(ctx context.Context, trip *domain.Trip, step *domain.ApprovalStep, updaterID uuid.UUID) (*domain.User, error) {
+	if s.delegations == nil {
+		return nil, nil
+	}
+
+	delegations, err := s.delegations.ListActive(ctx, updaterID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, delegation := range delegations {
+		// Requesters can't approve their own trips, not even through a delegate
+		if !delegation.Covers(trip) || delegation.DelegatorID == trip.RequesterID {
+			continue
+		}
+		delegator, err := s.userRepo.FindByID(ctx, delegation.DelegatorID)
+		if err != nil {
+			return nil, err
+		}
+		if delegator != nil && step.CanBeDecidedBy(delegator) {
+			return delegator, nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveCostCenter returns the requested cost center or, when none was given,
+// the default cost center of the requester's department. A nil result is left
+// for Trip.Validate to report.
+func (s *TripService) resolveCostCenter(ctx context.Context, requesterID uuid.UUID, requested *uuid.UUID) (uuid.UUID, error) {
+	if s.costCenterRepo == nil {
+		if requested == nil {
+			return uuid.Nil, nil
+		}
+		return *requested, nil
+	}
+
+	costCenterID := uuid.Nil
+	if requested != nil {
+		costCenterID = *requested
+	} else {
+		requester, err := s.userRepo.FindByID(ctx, requesterID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if requester == nil || requester.DepartmentID == nil {
+			return uuid.Nil, nil
+		}
+
+		department, err := s.departmentRepo.FindByID(ctx, *requester.DepartmentID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if department == nil {
+			return uuid.Nil, nil
+		}
+		costCenterID = department.CostCenterID
+	}
+
+	costCenter, err := s.costCenterRepo.FindByID(ctx, costCenterID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if costCenter == nil || !costCenter.Active {
+		validationErrors := domain.NewValidationErrors()
+		validationErrors.Add("cost_center_id must reference an active cost center")
+		return uuid.Nil, validationErrors
+	}
+	return costCenterID, nil
+}
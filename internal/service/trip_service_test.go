@@ -15,7 +15,7 @@ import (
 )
 
 func TestTripService_CreateTrip(t *testing.T) {
-	ctx := context.Background()
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange - create new mocks for this test case
@@ -25,20 +25,30 @@ func TestTripService_CreateTrip(t *testing.T) {
 		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
 
 		requesterID := uuid.New()
+		costCenterID := uuid.New()
 		destination := "Paris"
 		startDate := time.Now().AddDate(0, 1, 0) // 1 month from now
 		endDate := startDate.AddDate(0, 0, 7)    // 7 days after start
 
 		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
 		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
 
 		// Act
-		trip, err := tripService.CreateTrip(ctx, requesterID, destination, startDate, endDate)
+		trip, err := tripService.CreateTrip(ctx, requesterID, service.TripInput{
+			Destination:  destination,
+			StartDate:    startDate,
+			EndDate:      endDate,
+			CostCenterID: &costCenterID,
+		})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, trip)
+		orgID, _ := domain.OrgIDFromContext(ctx)
+		assert.Equal(t, orgID, trip.OrgID)
 		assert.Equal(t, requesterID, trip.RequesterID)
+		assert.Equal(t, costCenterID, trip.CostCenterID)
 		assert.Equal(t, destination, trip.Destination)
 		assert.Equal(t, startDate, trip.StartDate)
 		assert.Equal(t, endDate, trip.EndDate)
@@ -54,16 +64,23 @@ func TestTripService_CreateTrip(t *testing.T) {
 		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
 
 		requesterID := uuid.New()
+		costCenterID := uuid.New()
 		destination := "Paris"
 		startDate := time.Now().AddDate(0, 1, 0)
 		endDate := startDate.AddDate(0, 0, 7)
 		dbError := errors.New("database error")
 
 		// Mock behavior - use AnythingOfType to match any Trip object
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
 		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(dbError)
 
 		// Act
-		trip, err := tripService.CreateTrip(ctx, requesterID, destination, startDate, endDate)
+		trip, err := tripService.CreateTrip(ctx, requesterID, service.TripInput{
+			Destination:  destination,
+			StartDate:    startDate,
+			EndDate:      endDate,
+			CostCenterID: &costCenterID,
+		})
 
 		// Assert
 		assert.Error(t, err)
@@ -72,6 +89,29 @@ func TestTripService_CreateTrip(t *testing.T) {
 		mockTripRepo.AssertExpectations(t)
 	})
 
+	t.Run("Missing organization", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
+
+		startDate := time.Now().AddDate(0, 1, 0)
+		endDate := startDate.AddDate(0, 0, 7)
+
+		// Act
+		trip, err := tripService.CreateTrip(context.Background(), uuid.New(), service.TripInput{
+			Destination: "Paris",
+			StartDate:   startDate,
+			EndDate:     endDate,
+		})
+
+		// Assert
+		assert.Equal(t, domain.ErrMissingTenant, err)
+		assert.Nil(t, trip)
+		mockTripRepo.AssertNotCalled(t, "Create")
+	})
+
 	t.Run("Validation error - end date before start date", func(t *testing.T) {
 		// Arrange - create new mocks for this test case
 		mockTripRepo := new(mocks.MockTripRepository)
@@ -80,6 +120,7 @@ func TestTripService_CreateTrip(t *testing.T) {
 		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
 
 		requesterID := uuid.New()
+		costCenterID := uuid.New()
 		destination := "Paris"
 		startDate := time.Now().AddDate(0, 1, 0)
 		endDate := startDate.AddDate(0, 0, -1) // Invalid: end date before start date
@@ -87,7 +128,12 @@ func TestTripService_CreateTrip(t *testing.T) {
 		// No mock behavior needed as validation should fail before repository is called
 
 		// Act
-		trip, err := tripService.CreateTrip(ctx, requesterID, destination, startDate, endDate)
+		trip, err := tripService.CreateTrip(ctx, requesterID, service.TripInput{
+			Destination:  destination,
+			StartDate:    startDate,
+			EndDate:      endDate,
+			CostCenterID: &costCenterID,
+		})
 
 		// Assert
 		assert.Error(t, err)
@@ -98,13 +144,107 @@ func TestTripService_CreateTrip(t *testing.T) {
 	})
 }
 
+func TestTripService_CreateTrip_CostCenter(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+	endDate := startDate.AddDate(0, 0, 7)
+
+	t.Run("Defaults to the department cost center", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+		mockDepartmentRepo := new(mocks.MockDepartmentRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithCostCenters(mockCostCenterRepo, mockDepartmentRepo))
+
+		departmentID := uuid.New()
+		requester := &domain.User{ID: uuid.New(), DepartmentID: &departmentID}
+		department := &domain.Department{ID: departmentID, CostCenterID: uuid.New()}
+		costCenter := &domain.CostCenter{ID: department.CostCenterID, Active: true}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockDepartmentRepo.On("FindByID", ctx, departmentID).Return(department, nil)
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, service.TripInput{
+			Destination: "Paris",
+			StartDate:   startDate,
+			EndDate:     endDate,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, costCenter.ID, trip.CostCenterID)
+		mockTripRepo.AssertExpectations(t)
+		mockDepartmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Requester without department must choose a cost center", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithCostCenters(new(mocks.MockCostCenterRepository), new(mocks.MockDepartmentRepository)))
+
+		requester := &domain.User{ID: uuid.New()}
+
+		// Mock behavior
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, service.TripInput{
+			Destination: "Paris",
+			StartDate:   startDate,
+			EndDate:     endDate,
+		})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cost_center_id is required")
+		assert.Nil(t, trip)
+		mockTripRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Inactive cost center", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockCostCenterRepo := new(mocks.MockCostCenterRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithCostCenters(mockCostCenterRepo, new(mocks.MockDepartmentRepository)))
+
+		costCenter := &domain.CostCenter{ID: uuid.New(), Active: false}
+
+		// Mock behavior
+		mockCostCenterRepo.On("FindByID", ctx, costCenter.ID).Return(costCenter, nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      endDate,
+			CostCenterID: &costCenter.ID,
+		})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cost_center_id must reference an active cost center")
+		assert.Nil(t, trip)
+		mockTripRepo.AssertNotCalled(t, "Create")
+	})
+}
+
 func TestTripService_GetTripByID(t *testing.T) {
 	// Arrange
 	mockTripRepo := new(mocks.MockTripRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockNotifier := new(mocks.MockNotificationService)
 	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
-	ctx := context.Background()
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
@@ -196,7 +336,7 @@ func TestTripService_UpdateTripStatus(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockNotifier := new(mocks.MockNotificationService)
 	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
-	ctx := context.Background()
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
@@ -220,7 +360,7 @@ func TestTripService_UpdateTripStatus(t *testing.T) {
 
 		// Mock behavior
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", ctx, tripID, newStatus).Return(nil)
+		mockTripRepo.On("UpdateStatus", ctx, tripID, mock.Anything, newStatus).Return(nil)
 		mockUserRepo.On("FindByID", ctx, requesterID).Return(user, nil)
 		mockNotifier.On("Send", user, trip, mock.AnythingOfType("string")).Return()
 
@@ -311,7 +451,7 @@ func TestTripService_UpdateTripStatus(t *testing.T) {
 
 		// Mock behavior
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", ctx, tripID, newStatus).Return(dbError)
+		mockTripRepo.On("UpdateStatus", ctx, tripID, mock.Anything, newStatus).Return(dbError)
 
 		// Act
 		err := tripService.UpdateTripStatus(ctx, tripID, updaterID, newStatus)
@@ -321,6 +461,386 @@ func TestTripService_UpdateTripStatus(t *testing.T) {
 		assert.Equal(t, dbError, err)
 		mockTripRepo.AssertExpectations(t)
 	})
+
+	t.Run("Trip decided by someone else meanwhile", func(t *testing.T) {
+		// Arrange
+		tripID := uuid.New()
+		trip := &domain.Trip{
+			ID:          tripID,
+			RequesterID: uuid.New(),
+			Status:      domain.StatusRequested,
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, tripID, domain.StatusRequested, domain.StatusCanceled).Return(domain.ErrTripStatusChanged)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, tripID, uuid.New(), domain.StatusCanceled)
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidStatus, err)
+		mockTripRepo.AssertExpectations(t)
+	})
+}
+
+func TestTripService_ApprovalChain(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+	endDate := startDate.AddDate(0, 0, 7)
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockNotificationService, *mocks.MockApprovalPolicyRepository, *mocks.MockApprovalStepRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockPolicyRepo := new(mocks.MockApprovalPolicyRepository)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithApprovalChains(mockPolicyRepo, mockStepRepo))
+		return tripService, mockTripRepo, mockUserRepo, mockNotifier, mockPolicyRepo, mockStepRepo
+	}
+
+	t.Run("Create generates steps from matching policies", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, _, mockPolicyRepo, mockStepRepo := setup()
+
+		managerID := uuid.New()
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee, ManagerID: &managerID}
+		policies := []*domain.ApprovalPolicy{
+			{Name: "International", Destinations: []string{"Paris"}, Steps: []domain.UserRole{domain.RoleManager, domain.RoleDirector}},
+			{Name: "Long trips", MinDurationDays: 30, Steps: []domain.UserRole{domain.RoleFinance}},
+		}
+		costCenterID := uuid.New()
+
+		// Mock behavior
+		mockPolicyRepo.On("List", ctx).Return(policies, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+		mockStepRepo.On("CreateSteps", ctx, mock.MatchedBy(func(steps []*domain.ApprovalStep) bool {
+			return len(steps) == 2 &&
+				steps[0].Role == domain.RoleManager && steps[0].ApproverID != nil && *steps[0].ApproverID == managerID &&
+				steps[1].Role == domain.RoleDirector && steps[1].ApproverID == nil && steps[1].Position == 2
+		})).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, service.TripInput{
+			Destination:  "Paris, France",
+			StartDate:    startDate,
+			EndDate:      endDate,
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusRequested, trip.Status)
+		mockStepRepo.AssertExpectations(t)
+	})
+
+	t.Run("Approving an intermediate step keeps the trip requested", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier, _, mockStepRepo := setup()
+
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested, Destination: "Paris"}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, Status: domain.StepPending},
+			{ID: uuid.New(), TripID: trip.ID, Position: 2, Role: domain.RoleDirector, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockStepRepo.On("UpdateDecision", ctx, steps[0]).Return(nil)
+		mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, manager.ID, domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StepApproved, steps[0].Status)
+		assert.Equal(t, manager.ID, *steps[0].DecidedBy)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus")
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Approving the last step approves the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier, _, mockStepRepo := setup()
+
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		director := &domain.User{ID: uuid.New(), Role: domain.RoleDirector}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested, Destination: "Paris"}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, Status: domain.StepApproved},
+			{ID: uuid.New(), TripID: trip.ID, Position: 2, Role: domain.RoleDirector, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, director.ID).Return(director, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockStepRepo.On("UpdateDecision", ctx, steps[1]).Return(nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, director.ID, domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejecting a step cancels the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier, _, mockStepRepo := setup()
+
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested, Destination: "Paris"}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, Status: domain.StepPending},
+			{ID: uuid.New(), TripID: trip.ID, Position: 2, Role: domain.RoleDirector, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockStepRepo.On("UpdateDecision", ctx, steps[0]).Return(nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusCanceled).Return(nil)
+		mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, manager.ID, domain.StatusCanceled)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StepRejected, steps[0].Status)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("A step decided meanwhile leaves the trip alone", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		mockTransactor := new(mocks.MockTransactor)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithApprovalChains(new(mocks.MockApprovalPolicyRepository), mockStepRepo),
+			service.WithTransactions(mockTransactor),
+		)
+
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested, Destination: "Paris"}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, Status: domain.StepPending},
+		}
+
+		// Mock behavior: a delegate rejected the step after it was loaded
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+		mockStepRepo.On("UpdateDecision", ctx, steps[0]).Return(domain.ErrStepAlreadyDecided)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, manager.ID, domain.StatusApproved)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrStepAlreadyDecided)
+		mockTransactor.AssertExpectations(t)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Requested trip without steps cannot be approved", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _, _, _, mockStepRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return([]*domain.ApprovalStep{}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.Equal(t, service.ErrMissingApprovalChain, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create stores the trip and its steps in one transaction", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockPolicyRepo := new(mocks.MockApprovalPolicyRepository)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		mockTransactor := new(mocks.MockTransactor)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithApprovalChains(mockPolicyRepo, mockStepRepo),
+			service.WithTransactions(mockTransactor),
+		)
+		requesterID := uuid.New()
+		costCenterID := uuid.New()
+		failure := errors.New("database error")
+
+		// Mock behavior
+		mockPolicyRepo.On("List", ctx).Return([]*domain.ApprovalPolicy{}, nil)
+		mockUserRepo.On("FindByID", ctx, requesterID).Return(nil, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Run(func(mock.Arguments) {
+			mockTransactor.AssertNumberOfCalls(t, "WithinTx", 1)
+		}).Return(nil)
+		mockStepRepo.On("CreateSteps", ctx, mock.Anything).Return(failure)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requesterID, service.TripInput{
+			Destination: "Paris", StartDate: startDate, EndDate: endDate, CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.Nil(t, trip)
+		assert.Equal(t, failure, err)
+		mockTransactor.AssertExpectations(t)
+	})
+
+	t.Run("Only the assigned approver can decide the step", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, _, _, mockStepRepo := setup()
+
+		assignedManagerID := uuid.New()
+		otherManager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &assignedManagerID, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, otherManager.ID).Return(otherManager, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, otherManager.ID, domain.StatusApproved)
+
+		// Assert
+		assert.Equal(t, service.ErrNotApprover, err)
+		mockStepRepo.AssertNotCalled(t, "UpdateDecision")
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus")
+	})
+}
+
+func TestTripService_Delegations(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockApprovalStepRepository, *mocks.MockDelegationRepository, *mocks.MockTripEventRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		mockDelegationRepo := new(mocks.MockDelegationRepository)
+		mockEventRepo := new(mocks.MockTripEventRepository)
+		mockNotifier.On("Send", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return()
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithApprovalChains(new(mocks.MockApprovalPolicyRepository), mockStepRepo),
+			service.WithTripHistory(mockEventRepo),
+			service.WithDelegations(mockDelegationRepo))
+		return tripService, mockTripRepo, mockUserRepo, mockStepRepo, mockDelegationRepo, mockEventRepo
+	}
+
+	manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+	requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee, ManagerID: &manager.ID}
+	delegate := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+	t.Run("Delegate approves on behalf of the manager", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockStepRepo, mockDelegationRepo, mockEventRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested, Destination: "Paris"}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+		}
+		delegations := []*domain.Delegation{{DelegatorID: manager.ID, DelegateID: delegate.ID}}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+		mockDelegationRepo.On("ListActive", ctx, delegate.ID, mock.AnythingOfType("time.Time")).Return(delegations, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockStepRepo.On("UpdateDecision", ctx, steps[0]).Return(nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockEventRepo.On("Create", ctx, mock.MatchedBy(func(event *domain.TripEvent) bool {
+			return event.ActorID == delegate.ID && event.Action == domain.EventApproved &&
+				event.OnBehalfOfID != nil && *event.OnBehalfOfID == manager.ID
+		})).Return(nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, delegate.ID, domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, delegate.ID, *steps[0].DecidedBy)
+		mockTripRepo.AssertExpectations(t)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delegation outside its cost center", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockStepRepo, mockDelegationRepo, _ := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, CostCenterID: uuid.New(), Status: domain.StatusRequested}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+		}
+		otherCostCenter := uuid.New()
+		delegations := []*domain.Delegation{{DelegatorID: manager.ID, DelegateID: delegate.ID, CostCenterID: &otherCostCenter}}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+		mockDelegationRepo.On("ListActive", ctx, delegate.ID, mock.AnythingOfType("time.Time")).Return(delegations, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, delegate.ID, domain.StatusApproved)
+
+		// Assert
+		assert.Equal(t, service.ErrNotApprover, err)
+		mockStepRepo.AssertNotCalled(t, "UpdateDecision", mock.Anything, mock.Anything)
+	})
+
+	t.Run("No active delegation", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockStepRepo, mockDelegationRepo, _ := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Status: domain.StatusRequested}
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+		}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, delegate.ID).Return(delegate, nil)
+		mockDelegationRepo.On("ListActive", ctx, delegate.ID, mock.AnythingOfType("time.Time")).Return([]*domain.Delegation{}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, delegate.ID, domain.StatusApproved)
+
+		// Assert
+		assert.Equal(t, service.ErrNotApprover, err)
+	})
 }
 
 func TestTripService_CancelApprovedTrip(t *testing.T) {
@@ -329,7 +849,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockNotifier := new(mocks.MockNotificationService)
 	tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
-	ctx := context.Background()
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
@@ -353,12 +873,12 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 
 		// Mock behavior
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", ctx, tripID, domain.StatusCanceled).Return(nil)
+		mockTripRepo.On("UpdateStatus", ctx, tripID, domain.StatusApproved, domain.StatusCanceled).Return(nil)
 		mockUserRepo.On("FindByID", ctx, requesterID).Return(user, nil)
 		mockNotifier.On("Send", user, trip, mock.AnythingOfType("string")).Return()
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.NoError(t, err)
@@ -376,7 +896,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.On("FindByID", ctx, tripID).Return(nil, nil)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -400,7 +920,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, cancelingUserID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, cancelingUserID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -423,7 +943,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -448,7 +968,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -466,7 +986,7 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.On("FindByID", ctx, tripID).Return(nil, dbError)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -490,10 +1010,10 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 
 		// Mock behavior
 		mockTripRepo.On("FindByID", ctx, tripID).Return(trip, nil)
-		mockTripRepo.On("UpdateStatus", ctx, tripID, domain.StatusCanceled).Return(dbError)
+		mockTripRepo.On("UpdateStatus", ctx, tripID, domain.StatusApproved, domain.StatusCanceled).Return(dbError)
 
 		// Act
-		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID)
+		err := tripService.CancelApprovedTrip(ctx, tripID, requesterID, "")
 
 		// Assert
 		assert.Error(t, err)
@@ -501,3 +1021,1441 @@ func TestTripService_CancelApprovedTrip(t *testing.T) {
 		mockTripRepo.AssertExpectations(t)
 	})
 }
+
+func TestTripService_CancellationWindow(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	fourteen := 14
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockNotificationService, *mocks.MockTripEventRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockPolicyRepo := new(mocks.MockTravelPolicyRepository)
+		mockEventRepo := new(mocks.MockTripEventRepository)
+		mockPolicyRepo.On("Find", ctx).Return(&domain.TravelPolicy{
+			Cancellation: &domain.CancellationPolicy{NoticeDays: &fourteen},
+		}, nil)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithTravelPolicies(mockPolicyRepo), service.WithTripHistory(mockEventRepo))
+		return tripService, mockTripRepo, mockUserRepo, mockNotifier, mockEventRepo
+	}
+
+	// Ten days ahead: outside the default 7 days, inside the configured 14
+	newTrip := func(requesterID uuid.UUID) *domain.Trip {
+		return &domain.Trip{
+			ID:          uuid.New(),
+			RequesterID: requesterID,
+			Type:        domain.TripDomestic,
+			Destination: "Paris",
+			Status:      domain.StatusApproved,
+			StartDate:   time.Now().AddDate(0, 0, 10),
+		}
+	}
+
+	t.Run("Requester inside the configured window", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _, _, _ := setup()
+		trip := newTrip(uuid.New())
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, trip.RequesterID, "")
+
+		// Assert
+		assert.Equal(t, service.ErrCancelNotAllowed, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Country rule matches the catalog destination", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockPolicyRepo := new(mocks.MockTravelPolicyRepository)
+		mockDestinationRepo := new(mocks.MockDestinationRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithTravelPolicies(mockPolicyRepo), service.WithDestinationCatalog(mockDestinationRepo))
+
+		// Ten days ahead is fine by default, but not for France
+		trip := newTrip(uuid.New())
+		code := "PAR"
+		trip.DestinationCode = &code
+
+		// Mock behavior
+		mockPolicyRepo.On("Find", ctx).Return(&domain.TravelPolicy{
+			Cancellation: &domain.CancellationPolicy{Rules: []domain.CancellationRule{
+				{Countries: []string{"FR"}, NoticeDays: &fourteen},
+			}},
+		}, nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockDestinationRepo.On("FindByCode", ctx, code).Return(&domain.Destination{Code: code, Kind: domain.DestinationCity, CountryCode: "FR"}, nil)
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, trip.RequesterID, "")
+
+		// Assert
+		assert.Equal(t, service.ErrCancelNotAllowed, err)
+		mockDestinationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Admin overrides with a justification", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier, mockEventRepo := setup()
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+		trip := newTrip(requester.ID)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, domain.StatusApproved, domain.StatusCanceled).Return(nil)
+		mockEventRepo.On("Create", ctx, mock.MatchedBy(func(event *domain.TripEvent) bool {
+			return event.TripID == trip.ID && event.ActorID == admin.ID &&
+				event.Action == domain.EventCancellationOverride && event.Justification == "client meeting was called off"
+		})).Return(nil)
+		mockNotifier.On("Send", requester, trip, "Your trip to Paris has been canceled: client meeting was called off").Return()
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, admin.ID, "client meeting was called off")
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+		mockEventRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Requester's manager overrides with a justification", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier, mockEventRepo := setup()
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee, ManagerID: &manager.ID}
+		trip := newTrip(requester.ID)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, domain.StatusApproved, domain.StatusCanceled).Return(nil)
+		mockEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.TripEvent")).Return(nil)
+		mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, manager.ID, "project postponed")
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Another manager cannot override", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, _, _ := setup()
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+		requester := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		trip := newTrip(requester.ID)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, manager.ID, "project postponed")
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Override requires a justification", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _, _, _ := setup()
+		trip := newTrip(uuid.New())
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, uuid.New(), "")
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_GetTripHistory(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	t.Run("Decisions are recorded and listed", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockEventRepo := new(mocks.MockTripEventRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier, service.WithTripHistory(mockEventRepo))
+
+		requester := &domain.User{ID: uuid.New()}
+		approverID := uuid.New()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requester.ID, Destination: "Paris", Status: domain.StatusRequested}
+		events := []*domain.TripEvent{{TripID: trip.ID, ActorID: approverID, Action: domain.EventApproved}}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockEventRepo.On("Create", ctx, mock.MatchedBy(func(event *domain.TripEvent) bool {
+			return event.ActorID == approverID && event.Action == domain.EventApproved
+		})).Return(nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockNotifier.On("Send", requester, trip, mock.AnythingOfType("string")).Return()
+		mockEventRepo.On("ListByTripID", ctx, trip.ID).Return(events, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, approverID, domain.StatusApproved)
+		assert.NoError(t, err)
+		history, err := tripService.GetTripHistory(ctx, trip.ID, requester.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, events, history)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other users cannot see the history", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockEventRepo := new(mocks.MockTripEventRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithTripHistory(mockEventRepo))
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New()}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		history, err := tripService.GetTripHistory(ctx, trip.ID, uuid.New())
+
+		// Assert
+		assert.Nil(t, history)
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockEventRepo.AssertNotCalled(t, "ListByTripID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_WithdrawTrip(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockTripEventRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockEventRepo := new(mocks.MockTripEventRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithTripHistory(mockEventRepo))
+		return tripService, mockTripRepo, mockEventRepo
+	}
+
+	t.Run("Success even right before the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockEventRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested, StartDate: time.Now().AddDate(0, 0, 1)}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, domain.StatusRequested, domain.StatusWithdrawn).Return(nil)
+		mockEventRepo.On("Create", ctx, mock.MatchedBy(func(event *domain.TripEvent) bool {
+			return event.ActorID == trip.RequesterID && event.Action == domain.EventWithdrawn
+		})).Return(nil)
+
+		// Act
+		err := tripService.WithdrawTrip(ctx, trip.ID, trip.RequesterID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not the requester", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _ := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.WithdrawTrip(ctx, trip.ID, uuid.New())
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Trip already approved", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _ := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusApproved}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.WithdrawTrip(ctx, trip.ID, trip.RequesterID)
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidStatus, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Trip approved while withdrawing", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockEventRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, domain.StatusRequested, domain.StatusWithdrawn).Return(domain.ErrTripStatusChanged)
+
+		// Act
+		err := tripService.WithdrawTrip(ctx, trip.ID, trip.RequesterID)
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidStatus, err)
+		mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Withdrawn trips cannot be decided", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _ := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusWithdrawn}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidStatus, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_ConcludeEndedTrips(t *testing.T) {
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockNotificationService) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier)
+		return tripService, mockTripRepo, mockUserRepo, mockNotifier
+	}
+
+	t.Run("Asks the travelers for their expenses", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockNotifier := setup()
+		requester := &domain.User{ID: uuid.New()}
+		trip := &domain.Trip{ID: uuid.New(), OrgID: uuid.New(), RequesterID: requester.ID, Destination: "Paris", Status: domain.StatusConcluded}
+
+		// Mock behavior
+		mockTripRepo.On("ConcludeEnded", mock.MatchedBy(domain.IsAllTenantsContext), mock.AnythingOfType("time.Time")).Return([]*domain.Trip{trip}, nil)
+		mockUserRepo.On("FindByID", mock.MatchedBy(func(ctx context.Context) bool {
+			orgID, ok := domain.OrgIDFromContext(ctx)
+			return ok && orgID == trip.OrgID
+		}), requester.ID).Return(requester, nil)
+		mockNotifier.On("Send", requester, trip, "Your trip to Paris has ended. Please submit your expenses.").Return()
+
+		// Act
+		err := tripService.ConcludeEndedTrips(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Concluded trips cannot be decided", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _, _ := setup()
+		ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusConcluded}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusCanceled)
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidStatus, err)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_TripReminders(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockUserRepository, *mocks.MockScheduledNotificationRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockReminderRepo := new(mocks.MockScheduledNotificationRepository)
+		mockNotifier.On("Send", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return()
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithTripReminders(mockReminderRepo, 7, 1))
+		return tripService, mockTripRepo, mockUserRepo, mockReminderRepo
+	}
+
+	t.Run("Approval schedules the reminders", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockReminderRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested, StartDate: time.Now().AddDate(0, 0, 30)}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockUserRepo.On("FindByID", ctx, trip.RequesterID).Return(&domain.User{ID: trip.RequesterID}, nil)
+		mockReminderRepo.On("DeletePendingByTripID", ctx, trip.ID).Return(nil)
+		for _, days := range []int{7, 1} {
+			dueAt := trip.StartDate.AddDate(0, 0, -days)
+			mockReminderRepo.On("Schedule", ctx, mock.MatchedBy(func(n *domain.ScheduledNotification) bool {
+				return n.RecipientID == trip.RequesterID && *n.TripID == trip.ID &&
+					n.Template == domain.TemplateTripReminder && n.DueAt.Equal(dueAt)
+			})).Return(nil).Once()
+		}
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		mockReminderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reminders already due are skipped", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockReminderRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusRequested, StartDate: time.Now().AddDate(0, 0, 3)}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockUserRepo.On("FindByID", ctx, trip.RequesterID).Return(&domain.User{ID: trip.RequesterID}, nil)
+		mockReminderRepo.On("DeletePendingByTripID", ctx, trip.ID).Return(nil)
+		mockReminderRepo.On("Schedule", ctx, mock.AnythingOfType("*domain.ScheduledNotification")).Return(nil).Once()
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		mockReminderRepo.AssertNumberOfCalls(t, "Schedule", 1)
+	})
+
+	t.Run("Cancellation drops the reminders", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockUserRepo, mockReminderRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), Status: domain.StatusApproved, StartDate: time.Now().AddDate(0, 0, 30)}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusCanceled).Return(nil)
+		mockUserRepo.On("FindByID", ctx, trip.RequesterID).Return(&domain.User{ID: trip.RequesterID}, nil)
+		mockReminderRepo.On("DeletePendingByTripID", ctx, trip.ID).Return(nil)
+
+		// Act
+		err := tripService.CancelApprovedTrip(ctx, trip.ID, trip.RequesterID, "")
+
+		// Assert
+		assert.NoError(t, err)
+		mockReminderRepo.AssertExpectations(t)
+		mockReminderRepo.AssertNotCalled(t, "Schedule", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_OverlappingTrips(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+	endDate := startDate.AddDate(0, 0, 5)
+	costCenterID := uuid.New()
+	input := service.TripInput{Destination: "Paris", StartDate: startDate, EndDate: endDate, CostCenterID: &costCenterID}
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService))
+		return tripService, mockTripRepo
+	}
+
+	t.Run("Create lists the clashing trips", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup()
+		requesterID := uuid.New()
+		clashing := []*domain.Trip{{ID: uuid.New()}, {ID: uuid.New()}}
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, requesterID, startDate, endDate, mock.AnythingOfType("uuid.UUID")).Return(clashing, nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requesterID, input)
+
+		// Assert
+		assert.Nil(t, trip)
+		assert.ErrorIs(t, err, domain.ErrTripOverlap)
+		var conflict *domain.TripConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []uuid.UUID{clashing[0].ID, clashing[1].ID}, conflict.TripIDs)
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update leaves the trip itself out", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), CostCenterID: costCenterID, Type: domain.TripDomestic, Status: domain.StatusRequested}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("FindOverlapping", ctx, trip.RequesterID, startDate, endDate, trip.ID).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Update", ctx, trip).Return(nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, trip.RequesterID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, startDate, updated.StartDate)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent trip caught by the constraint", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup()
+		requesterID := uuid.New()
+		clashing := &domain.Trip{ID: uuid.New()}
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, requesterID, startDate, endDate, mock.AnythingOfType("uuid.UUID")).Return([]*domain.Trip{}, nil).Once()
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(domain.ErrTripOverlap)
+		mockTripRepo.On("FindOverlapping", ctx, requesterID, startDate, endDate, mock.AnythingOfType("uuid.UUID")).Return([]*domain.Trip{clashing}, nil).Once()
+
+		// Act
+		_, err := tripService.CreateTrip(ctx, requesterID, input)
+
+		// Assert
+		var conflict *domain.TripConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []uuid.UUID{clashing.ID}, conflict.TripIDs)
+	})
+
+	t.Run("Approving a cancelled trip whose dates were taken", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), StartDate: startDate, EndDate: endDate, Status: domain.StatusCanceled}
+		clashing := &domain.Trip{ID: uuid.New()}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(domain.ErrTripOverlap)
+		mockTripRepo.On("FindOverlapping", ctx, trip.RequesterID, startDate, endDate, trip.ID).Return([]*domain.Trip{clashing}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		var conflict *domain.TripConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []uuid.UUID{clashing.ID}, conflict.TripIDs)
+		assert.Equal(t, domain.StatusCanceled, trip.Status)
+	})
+}
+
+func TestTripService_TravelPolicy(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+	costCenterID := uuid.New()
+
+	setup := func(policy *domain.TravelPolicy) (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockPolicyRepo := new(mocks.MockTravelPolicyRepository)
+		mockPolicyRepo.On("Find", ctx).Return(policy, nil)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithTravelPolicies(mockPolicyRepo))
+		return tripService, mockTripRepo
+	}
+
+	t.Run("Blocking rule rejects the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(&domain.TravelPolicy{Rules: []domain.TravelRule{
+			{Type: domain.RuleMaxTripDays, Severity: domain.SeverityBlock, Days: 5},
+		}})
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 10),
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.Nil(t, trip)
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Contains(t, err.Error(), "trips cannot last more than 5 days")
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Warning rule is stored on the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(&domain.TravelPolicy{Rules: []domain.TravelRule{
+			{Type: domain.RuleDestinations, Severity: domain.SeverityWarn, Destinations: []string{"Paris"}, Message: "Paris needs a security briefing"},
+		}})
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 3),
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Paris needs a security briefing"}, trip.PolicyWarnings)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("Organization without a policy", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(nil)
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 30),
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, trip.PolicyWarnings)
+	})
+}
+
+func TestTripService_BlackoutCalendars(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0).Truncate(24 * time.Hour)
+	costCenterID := uuid.New()
+	financeID := uuid.New()
+	requester := &domain.User{ID: uuid.New(), DepartmentID: &financeID}
+	input := service.TripInput{Destination: "Paris", StartDate: startDate, EndDate: startDate.AddDate(0, 0, 3), CostCenterID: &costCenterID}
+	period := domain.BlackoutPeriod{Summary: "Fiscal close", StartsAt: startDate.AddDate(0, 0, 2), EndsAt: startDate.AddDate(0, 0, 5)}
+
+	setup := func(calendars ...*domain.BlackoutCalendar) (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockBlackoutRepo := new(mocks.MockBlackoutCalendarRepository)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockBlackoutRepo.On("List", ctx).Return(calendars, nil)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithBlackoutCalendars(mockBlackoutRepo))
+		return tripService, mockTripRepo
+	}
+
+	t.Run("Blocking calendar rejects the trip", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(&domain.BlackoutCalendar{Name: "Finance", Severity: domain.SeverityBlock, DepartmentID: &financeID, Periods: []domain.BlackoutPeriod{period}})
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, input)
+
+		// Assert
+		assert.Nil(t, trip)
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Contains(t, err.Error(), `blackout period "Fiscal close" of the Finance calendar`)
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Warning calendar is shown to the approvers", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(&domain.BlackoutCalendar{Name: "Company", Severity: domain.SeverityWarn, Periods: []domain.BlackoutPeriod{period}})
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, trip.PolicyWarnings, 1)
+		assert.Contains(t, trip.PolicyWarnings[0], `blackout period "Fiscal close" of the Company calendar`)
+	})
+
+	t.Run("Calendar of another department", func(t *testing.T) {
+		// Arrange
+		otherDepartmentID := uuid.New()
+		tripService, mockTripRepo := setup(&domain.BlackoutCalendar{Name: "Sales", Severity: domain.SeverityBlock, DepartmentID: &otherDepartmentID, Periods: []domain.BlackoutPeriod{period}})
+
+		// Mock behavior
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, requester.ID, input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, trip.PolicyWarnings)
+	})
+}
+
+func TestTripService_UpdateTrip(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+
+	newTrip := func(status domain.TripStatus) *domain.Trip {
+		return &domain.Trip{
+			ID:           uuid.New(),
+			RequesterID:  uuid.New(),
+			CostCenterID: uuid.New(),
+			Type:         domain.TripDomestic,
+			Destination:  "Paris",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 7),
+			Status:       status,
+		}
+	}
+
+	t.Run("Success restarts the approval chain", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockPolicyRepo := new(mocks.MockApprovalPolicyRepository)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithApprovalChains(mockPolicyRepo, mockStepRepo))
+
+		trip := newTrip(domain.StatusRequested)
+		costCenterID := trip.CostCenterID
+		requester := &domain.User{ID: trip.RequesterID, Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockPolicyRepo.On("List", ctx).Return([]*domain.ApprovalPolicy{}, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Update", ctx, trip).Return(nil)
+		mockStepRepo.On("DeleteByTripID", ctx, trip.ID).Return(nil)
+		mockStepRepo.On("CreateSteps", ctx, mock.MatchedBy(func(steps []*domain.ApprovalStep) bool {
+			return len(steps) == 1 && steps[0].Role == domain.RoleManager && steps[0].Status == domain.StepPending
+		})).Return(nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, requester.ID, service.TripInput{
+			Destination: "Lisboa",
+			StartDate:   startDate.AddDate(0, 0, 1),
+			EndDate:     startDate.AddDate(0, 0, 4),
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Lisboa", updated.Destination)
+		assert.Equal(t, costCenterID, updated.CostCenterID)
+		mockTripRepo.AssertExpectations(t)
+		mockStepRepo.AssertExpectations(t)
+	})
+
+	t.Run("Trip decided while being edited", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockPolicyRepo := new(mocks.MockApprovalPolicyRepository)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		mockTransactor := new(mocks.MockTransactor)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithApprovalChains(mockPolicyRepo, mockStepRepo), service.WithTransactions(mockTransactor))
+
+		trip := newTrip(domain.StatusRequested)
+		requester := &domain.User{ID: trip.RequesterID, Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockPolicyRepo.On("List", ctx).Return([]*domain.ApprovalPolicy{}, nil)
+		mockUserRepo.On("FindByID", ctx, requester.ID).Return(requester, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+		mockTripRepo.On("Update", ctx, trip).Return(domain.ErrTripStatusChanged)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, requester.ID, service.TripInput{
+			Destination: "Lisboa",
+			StartDate:   trip.StartDate,
+			EndDate:     trip.EndDate,
+		})
+
+		// Assert
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockStepRepo.AssertNotCalled(t, "DeleteByTripID", mock.Anything, mock.Anything)
+		mockStepRepo.AssertNotCalled(t, "CreateSteps", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not the requester", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService))
+		trip := newTrip(domain.StatusRequested)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, uuid.New(), service.TripInput{
+			Destination: "Lisboa",
+			StartDate:   trip.StartDate,
+			EndDate:     trip.EndDate,
+		})
+
+		// Assert
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, service.ErrPermissionDenied)
+		mockTripRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Trip already decided", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService))
+		trip := newTrip(domain.StatusApproved)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, trip.RequesterID, service.TripInput{
+			Destination: "Lisboa",
+			StartDate:   trip.StartDate,
+			EndDate:     trip.EndDate,
+		})
+
+		// Assert
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, service.ErrInvalidStatus)
+		mockTripRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Trip not found", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService))
+		tripID := uuid.New()
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, tripID).Return(nil, nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, tripID, uuid.New(), service.TripInput{})
+
+		// Assert
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, service.ErrTripNotFound)
+	})
+}
+
+func TestTripService_DestinationCatalog(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	startDate := time.Now().AddDate(0, 1, 0)
+	costCenterID := uuid.New()
+	guarulhos := &domain.Destination{Code: "GRU", Kind: domain.DestinationAirport, Name: "São Paulo/Guarulhos", CityCode: "SAO"}
+	saoPaulo := &domain.Destination{Code: "SAO", Kind: domain.DestinationCity, Name: "São Paulo"}
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockDestinationRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockDestinationRepo := new(mocks.MockDestinationRepository)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithDestinationCatalog(mockDestinationRepo))
+		return tripService, mockTripRepo, mockDestinationRepo
+	}
+
+	t.Run("Airport is stored as its city", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockDestinationRepo := setup()
+
+		// Mock behavior
+		mockDestinationRepo.On("FindByCode", ctx, "gru").Return(guarulhos, nil)
+		mockDestinationRepo.On("FindByCode", ctx, "SAO").Return(saoPaulo, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "gru",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 2),
+			CostCenterID:    &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		if assert.NotNil(t, trip.DestinationCode) {
+			assert.Equal(t, "SAO", *trip.DestinationCode)
+		}
+		assert.Equal(t, "São Paulo", trip.Destination)
+	})
+
+	t.Run("Unknown code", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockDestinationRepo := setup()
+
+		// Mock behavior
+		mockDestinationRepo.On("FindByCode", ctx, "XYZ").Return(nil, nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "XYZ",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 2),
+			CostCenterID:    &costCenterID,
+		})
+
+		// Assert
+		assert.Nil(t, trip)
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Contains(t, err.Error(), `unknown destination_code "XYZ"`)
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_TimeZone(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	costCenterID := uuid.New()
+	startDate := time.Date(time.Now().Year()+1, 5, 10, 0, 0, 0, 0, time.UTC)
+	lisbon := &domain.Destination{Code: "LIS", Kind: domain.DestinationCity, Name: "Lisboa", TimeZone: "Europe/Lisbon"}
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockDestinationRepo := new(mocks.MockDestinationRepository)
+		mockDestinationRepo.On("FindByCode", ctx, "LIS").Return(lisbon, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithDestinationCatalog(mockDestinationRepo))
+		return tripService, mockTripRepo
+	}
+
+	t.Run("Zone of the destination", func(t *testing.T) {
+		// Arrange
+		tripService, _ := setup()
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "LIS",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 5),
+			CostCenterID:    &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Lisbon", trip.TimeZone)
+		assert.True(t, startDate.Equal(trip.StartDate))
+		assert.Equal(t, "01:00 +0100", trip.StartDate.Format("15:04 -0700"))
+	})
+
+	t.Run("Requested zone", func(t *testing.T) {
+		// Arrange
+		tripService, _ := setup()
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "LIS",
+			TimeZone:        "America/Sao_Paulo",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 5),
+			CostCenterID:    &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "America/Sao_Paulo", trip.TimeZone)
+		assert.True(t, startDate.Equal(trip.StartDate))
+		assert.Equal(t, "21:00 -0300", trip.StartDate.Format("15:04 -0700"))
+	})
+
+	t.Run("Unknown zone", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup()
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Lisboa",
+			TimeZone:     "Lisbon",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 5),
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.Nil(t, trip)
+		assert.Contains(t, err.Error(), "time_zone must be an IANA time zone")
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_BudgetConversion(t *testing.T) {
+	orgID := uuid.New()
+	ctx := domain.ContextWithOrgID(context.Background(), orgID)
+	costCenterID := uuid.New()
+	startDate := time.Now().AddDate(0, 1, 0)
+	input := service.TripInput{
+		Destination:  "Paris",
+		StartDate:    startDate,
+		EndDate:      startDate.AddDate(0, 0, 5),
+		CostCenterID: &costCenterID,
+		Budget:       &domain.TripBudget{Currency: "EUR", Items: []domain.BudgetItem{{Category: domain.BudgetLodging, Amount: 100000}}},
+	}
+
+	setup := func(rate *domain.ExchangeRate) (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockRateRepo := new(mocks.MockExchangeRateRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		mockOrgRepo.On("FindByID", ctx, orgID).Return(&domain.Organization{ID: orgID, BaseCurrency: "BRL"}, nil)
+		mockRateRepo.On("FindEffective", ctx, "EUR", "BRL", mock.AnythingOfType("time.Time")).Return(rate, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithExchangeRates(mockRateRepo, mockOrgRepo))
+		return tripService, mockTripRepo
+	}
+
+	t.Run("Snapshot of the rate", func(t *testing.T) {
+		// Arrange
+		rateDate := time.Now().AddDate(0, 0, -1).Truncate(24 * time.Hour)
+		tripService, _ := setup(&domain.ExchangeRate{Currency: "EUR", BaseCurrency: "BRL", Rate: 5.5, EffectiveDate: rateDate})
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), input)
+
+		// Assert
+		assert.NoError(t, err)
+		if assert.NotNil(t, trip.Budget.Converted) {
+			assert.Equal(t, domain.Money{Amount: 550000, Currency: "BRL"}, trip.Budget.Converted.Total)
+			assert.Equal(t, 5.5, trip.Budget.Converted.Rate)
+			assert.Equal(t, rateDate, trip.Budget.Converted.RateDate)
+		}
+	})
+
+	t.Run("No rate", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo := setup(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), input)
+
+		// Assert
+		assert.Nil(t, trip)
+		var validationErrs *domain.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Contains(t, err.Error(), "budget: no exchange rate from EUR to BRL")
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_CostCenterBudgets(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	costCenterID := uuid.New()
+	startDate := time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC)
+	brl := func(amount int64) *domain.TripBudget {
+		return &domain.TripBudget{Currency: "BRL", Items: []domain.BudgetItem{{Category: domain.BudgetAirfare, Amount: amount}}}
+	}
+	approved := &domain.Trip{ID: uuid.New(), CostCenterID: costCenterID, StartDate: startDate, Status: domain.StatusApproved, Budget: brl(700000)}
+
+	setup := func(severity domain.RuleSeverity, trip *domain.Trip) (*service.TripService, *mocks.MockTripRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+		mockBudgetRepo.On("ListByCostCenter", ctx, &costCenterID).Return([]*domain.CostCenterBudget{{
+			ID:           uuid.New(),
+			CostCenterID: costCenterID,
+			PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+			Amount:       1000000,
+			Currency:     "BRL",
+			Severity:     severity,
+		}}, nil)
+		mockBudgetRepo.On("Lock", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil)
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+			return *params.Status == domain.StatusApproved
+		})).Return([]*domain.Trip{approved}, nil)
+		mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+			return *params.Status == domain.StatusConcluded
+		})).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved).Return(nil)
+		mockUserRepo.On("FindByID", ctx, trip.RequesterID).Return(nil, nil)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithCostCenterBudgets(mockBudgetRepo, new(mocks.MockExpenseReportRepository)))
+		return tripService, mockTripRepo
+	}
+	newTrip := func(amount int64) *domain.Trip {
+		return &domain.Trip{ID: uuid.New(), RequesterID: uuid.New(), CostCenterID: costCenterID, StartDate: startDate,
+			Status: domain.StatusRequested, Destination: "Recife", Budget: brl(amount)}
+	}
+
+	t.Run("Within the budget", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(300000)
+		tripService, mockTripRepo := setup(domain.SeverityBlock, trip)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertCalled(t, "UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved)
+	})
+
+	t.Run("Blocking budget exceeded", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(300001)
+		tripService, mockTripRepo := setup(domain.SeverityBlock, trip)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrBudgetExceeded)
+		var exceeded *domain.BudgetExceededError
+		if assert.ErrorAs(t, err, &exceeded) {
+			assert.Equal(t, domain.Money{Amount: 300000, Currency: "BRL"}, exceeded.Remaining)
+			assert.Equal(t, domain.Money{Amount: 300001, Currency: "BRL"}, exceeded.TripCost)
+		}
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Warning budget exceeded", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(500000)
+		tripService, mockTripRepo := setup(domain.SeverityWarn, trip)
+		mockTripRepo.On("Update", ctx, trip).Return(nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"trip costs BRL 5000.00, more than what is left of the cost center budget for 2030-04-01 to 2030-06-30"}, trip.PolicyWarnings)
+		mockTripRepo.AssertCalled(t, "Update", ctx, trip)
+		mockTripRepo.AssertCalled(t, "UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusApproved)
+	})
+
+	t.Run("Rejections are not checked", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(5000000)
+		tripService, mockTripRepo := setup(domain.SeverityBlock, trip)
+		mockTripRepo.On("UpdateStatus", ctx, trip.ID, mock.Anything, domain.StatusCanceled).Return(nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusCanceled)
+
+		// Assert
+		assert.NoError(t, err)
+		mockTripRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concluded trips count their approved expenses", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+		mockExpenseRepo := new(mocks.MockExpenseReportRepository)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithCostCenterBudgets(mockBudgetRepo, mockExpenseRepo))
+		budget := &domain.CostCenterBudget{ID: uuid.New(), CostCenterID: costCenterID, PeriodStart: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd: time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC), Amount: 1000000, Currency: "BRL", Severity: domain.SeverityBlock}
+		// Estimated at BRL 3000.00, but BRL 9000.00 were spent
+		concluded := &domain.Trip{ID: uuid.New(), CostCenterID: costCenterID, StartDate: startDate, Status: domain.StatusConcluded, Budget: brl(300000)}
+		trip := newTrip(100001)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockBudgetRepo.On("ListByCostCenter", ctx, &costCenterID).Return([]*domain.CostCenterBudget{budget}, nil)
+		mockBudgetRepo.On("Lock", ctx, budget.ID).Return(nil)
+		mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+			return *params.Status == domain.StatusApproved
+		})).Return([]*domain.Trip{}, nil)
+		mockTripRepo.On("List", ctx, mock.MatchedBy(func(params domain.ListTripsParams) bool {
+			return *params.Status == domain.StatusConcluded
+		})).Return([]*domain.Trip{concluded}, nil)
+		mockExpenseRepo.On("ListByTripIDs", ctx, []uuid.UUID{concluded.ID}).Return([]*domain.ExpenseReport{{
+			TripID: concluded.ID, Status: domain.ExpenseReimbursed, Currency: "BRL",
+			Items: []domain.ExpenseItem{{Amount: 900000, Currency: "BRL"}},
+		}}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		var exceeded *domain.BudgetExceededError
+		if assert.ErrorAs(t, err, &exceeded) {
+			assert.Equal(t, domain.Money{Amount: 100000, Currency: "BRL"}, exceeded.Remaining)
+		}
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("The budget is locked inside the approval's transaction", func(t *testing.T) {
+		// Arrange
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+		mockTransactor := new(mocks.MockTransactor)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, new(mocks.MockNotificationService),
+			service.WithCostCenterBudgets(mockBudgetRepo, new(mocks.MockExpenseReportRepository)),
+			service.WithTransactions(mockTransactor))
+		budget := &domain.CostCenterBudget{ID: uuid.New(), CostCenterID: costCenterID, PeriodStart: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd: time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC), Amount: 1000000, Currency: "BRL", Severity: domain.SeverityBlock}
+		trip := newTrip(1000001)
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTransactor.On("WithinTx", ctx).Return(nil)
+		mockBudgetRepo.On("ListByCostCenter", ctx, &costCenterID).Return([]*domain.CostCenterBudget{budget}, nil)
+		mockBudgetRepo.On("Lock", ctx, budget.ID).Run(func(mock.Arguments) {
+			mockTransactor.AssertNumberOfCalls(t, "WithinTx", 1)
+		}).Return(nil)
+		mockTripRepo.On("List", ctx, mock.Anything).Return([]*domain.Trip{}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, uuid.New(), domain.StatusApproved)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrBudgetExceeded)
+		mockBudgetRepo.AssertExpectations(t)
+		mockTransactor.AssertExpectations(t)
+	})
+
+	manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+	chained := func(severity domain.RuleSeverity, trip *domain.Trip, steps []*domain.ApprovalStep) (*service.TripService, *mocks.MockTripRepository, *mocks.MockCostCenterBudgetRepository, *mocks.MockApprovalStepRepository, *mocks.MockUserRepository) {
+		_, mockTripRepo := setup(severity, trip)
+		mockBudgetRepo := new(mocks.MockCostCenterBudgetRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockStepRepo := new(mocks.MockApprovalStepRepository)
+		mockNotifier := new(mocks.MockNotificationService)
+		mockNotifier.On("Send", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return()
+		mockStepRepo.On("FindByTripID", ctx, trip.ID).Return(steps, nil)
+		mockUserRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockUserRepo.On("FindByID", ctx, trip.RequesterID).Return(nil, nil)
+		mockBudgetRepo.On("Lock", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil)
+		tripService := service.NewTripService(mockTripRepo, mockUserRepo, mockNotifier,
+			service.WithApprovalChains(new(mocks.MockApprovalPolicyRepository), mockStepRepo),
+			service.WithCostCenterBudgets(mockBudgetRepo, new(mocks.MockExpenseReportRepository)))
+		return tripService, mockTripRepo, mockBudgetRepo, mockStepRepo, mockUserRepo
+	}
+
+	for _, severity := range []domain.RuleSeverity{domain.SeverityBlock, domain.SeverityWarn} {
+		t.Run("Not checked for non-approvers over a "+string(severity)+" budget", func(t *testing.T) {
+			// Arrange
+			trip := newTrip(5000000)
+			steps := []*domain.ApprovalStep{
+				{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+			}
+			tripService, mockTripRepo, mockBudgetRepo, mockStepRepo, mockUserRepo := chained(severity, trip, steps)
+			employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+			mockUserRepo.On("FindByID", ctx, employee.ID).Return(employee, nil)
+
+			// Act
+			err := tripService.UpdateTripStatus(ctx, trip.ID, employee.ID, domain.StatusApproved)
+
+			// Assert
+			assert.Equal(t, service.ErrNotApprover, err)
+			assert.Empty(t, trip.PolicyWarnings)
+			mockBudgetRepo.AssertNotCalled(t, "ListByCostCenter", mock.Anything, mock.Anything)
+			mockTripRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockStepRepo.AssertNotCalled(t, "UpdateDecision", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("Only the last step is checked", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(5000000)
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+			{ID: uuid.New(), TripID: trip.ID, Position: 2, Role: domain.RoleFinance, Status: domain.StepPending},
+		}
+		tripService, mockTripRepo, mockBudgetRepo, mockStepRepo, _ := chained(domain.SeverityBlock, trip, steps)
+		mockStepRepo.On("UpdateDecision", ctx, steps[0]).Return(nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, manager.ID, domain.StatusApproved)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StepApproved, steps[0].Status)
+		mockBudgetRepo.AssertNotCalled(t, "ListByCostCenter", mock.Anything, mock.Anything)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Blocked last step keeps its decision pending", func(t *testing.T) {
+		// Arrange
+		trip := newTrip(300001)
+		steps := []*domain.ApprovalStep{
+			{ID: uuid.New(), TripID: trip.ID, Position: 1, Role: domain.RoleManager, ApproverID: &manager.ID, Status: domain.StepPending},
+		}
+		tripService, mockTripRepo, mockBudgetRepo, mockStepRepo, _ := chained(domain.SeverityBlock, trip, steps)
+		mockBudgetRepo.On("ListByCostCenter", ctx, &costCenterID).Return([]*domain.CostCenterBudget{{
+			ID:           uuid.New(),
+			CostCenterID: costCenterID,
+			PeriodStart:  time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:    time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+			Amount:       1000000,
+			Currency:     "BRL",
+			Severity:     domain.SeverityBlock,
+		}}, nil)
+
+		// Act
+		err := tripService.UpdateTripStatus(ctx, trip.ID, manager.ID, domain.StatusApproved)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrBudgetExceeded)
+		assert.Equal(t, domain.StepPending, steps[0].Status)
+		mockStepRepo.AssertNotCalled(t, "UpdateDecision", mock.Anything, mock.Anything)
+		mockTripRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_PerDiem(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), uuid.New())
+	costCenterID := uuid.New()
+	startDate := time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC)
+	lisbon := &domain.Destination{Code: "LIS", Kind: domain.DestinationCity, Name: "Lisboa", CountryCode: "PT", Aliases: []string{"Lisbon"}}
+	rates := []*domain.PerDiemRate{{CountryCode: "PT", Currency: "EUR", DailyAmount: 10000, PartialDayPercent: 50, BreakfastPercent: 20}}
+
+	setup := func() (*service.TripService, *mocks.MockTripRepository, *mocks.MockDestinationRepository) {
+		mockTripRepo := new(mocks.MockTripRepository)
+		mockDestinationRepo := new(mocks.MockDestinationRepository)
+		mockRateRepo := new(mocks.MockPerDiemRateRepository)
+		mockRateRepo.On("List", ctx).Return(rates, nil)
+		mockDestinationRepo.On("FindByCode", ctx, "LIS").Return(lisbon, nil)
+		mockTripRepo.On("FindOverlapping", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Trip{}, nil)
+		tripService := service.NewTripService(mockTripRepo, new(mocks.MockUserRepository), new(mocks.MockNotificationService),
+			service.WithDestinationCatalog(mockDestinationRepo), service.WithPerDiem(mockRateRepo))
+		return tripService, mockTripRepo, mockDestinationRepo
+	}
+
+	t.Run("Computed when the trip is requested", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, _ := setup()
+
+		// Mock behavior
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "LIS",
+			TimeZone:        "UTC",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 2),
+			CostCenterID:    &costCenterID,
+			ProvidedMeals:   []domain.ProvidedMeals{{Date: "2030-05-11", Breakfast: true}},
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		if assert.NotNil(t, trip.PerDiem) {
+			assert.Len(t, trip.PerDiem.Days, 3)
+			assert.Equal(t, []domain.Money{{Amount: 18000, Currency: "EUR"}}, trip.PerDiem.Totals)
+		}
+	})
+
+	t.Run("Recalculated on edits", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockDestinationRepo := setup()
+		requesterID := uuid.New()
+		trip := &domain.Trip{ID: uuid.New(), RequesterID: requesterID, CostCenterID: costCenterID, Type: domain.TripInternational,
+			Status: domain.StatusRequested, Destination: "Lisboa", StartDate: startDate, EndDate: startDate.AddDate(0, 0, 2),
+			PerDiem: &domain.TripPerDiem{Totals: []domain.Money{{Amount: 20000, Currency: "EUR"}}}}
+
+		// Mock behavior
+		mockTripRepo.On("FindByID", ctx, trip.ID).Return(trip, nil)
+		mockTripRepo.On("Update", ctx, trip).Return(nil)
+		mockDestinationRepo.On("FindByCode", ctx, "LISBON").Return(nil, nil)
+		mockDestinationRepo.On("Search", ctx, "Lisbon", 10).Return([]*domain.Destination{lisbon}, nil)
+
+		// Act
+		updated, err := tripService.UpdateTrip(ctx, trip.ID, requesterID, service.TripInput{
+			StartDate: startDate,
+			EndDate:   startDate.AddDate(0, 0, 4),
+			Legs: []domain.ItineraryLeg{
+				{Origin: "São Paulo", Destination: "Lisbon", DepartAt: startDate, ArriveAt: startDate.Add(10 * time.Hour), Mode: domain.TransportFlight},
+				{Origin: "Lisbon", Destination: "São Paulo", DepartAt: startDate.AddDate(0, 0, 4), ArriveAt: startDate.AddDate(0, 0, 4).Add(10 * time.Hour), Mode: domain.TransportFlight},
+			},
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		if assert.NotNil(t, updated.PerDiem) {
+			assert.Len(t, updated.PerDiem.Days, 5)
+			assert.Equal(t, []domain.Money{{Amount: 40000, Currency: "EUR"}}, updated.PerDiem.Totals)
+		}
+	})
+	t.Run("Skipped for a trip without a destination code", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockDestinationRepo := setup()
+
+		// Mock behavior
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			Destination:  "Lisboa",
+			TimeZone:     "UTC",
+			StartDate:    startDate,
+			EndDate:      startDate.AddDate(0, 0, 2),
+			CostCenterID: &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, trip.PerDiem)
+		mockDestinationRepo.AssertNotCalled(t, "FindByCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skipped for a destination without a rate", func(t *testing.T) {
+		// Arrange
+		tripService, mockTripRepo, mockDestinationRepo := setup()
+		berlin := &domain.Destination{Code: "BER", Kind: domain.DestinationCity, Name: "Berlim", CountryCode: "DE"}
+
+		// Mock behavior
+		mockDestinationRepo.On("FindByCode", ctx, "BER").Return(berlin, nil)
+		mockTripRepo.On("Create", ctx, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// Act
+		trip, err := tripService.CreateTrip(ctx, uuid.New(), service.TripInput{
+			DestinationCode: "BER",
+			TimeZone:        "UTC",
+			StartDate:       startDate,
+			EndDate:         startDate.AddDate(0, 0, 2),
+			CostCenterID:    &costCenterID,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, trip.PerDiem)
+		mockTripRepo.AssertExpectations(t)
+	})
+}
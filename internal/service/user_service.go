@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,42 +12,113 @@ import (
 )
 
 var (
-	ErrUserAlreadyExists  = errors.New("user with this email already exists")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserAlreadyExists    = errors.New("user with this email already exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrRegistrationClosed   = errors.New("this organization only accepts registrations from its own email domains")
 )
 
 type UserService struct {
-	repo domain.UserRepository
+	repo     domain.UserRepository
+	orgRepo  domain.OrganizationRepository
+	policy   domain.PasswordPolicy
+	breached *utils.BreachedPasswordList
 }
 
-func NewUserService(repo domain.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// UserServiceOption configures optional UserService behaviour
+type UserServiceOption func(*UserService)
+
+// WithPasswordPolicy replaces the default password policy
+func WithPasswordPolicy(policy domain.PasswordPolicy) UserServiceOption {
+	return func(s *UserService) {
+		s.policy = policy
+	}
+}
+
+// WithBreachedPasswords rejects passwords found in the given offline list
+func WithBreachedPasswords(list *utils.BreachedPasswordList) UserServiceOption {
+	return func(s *UserService) {
+		s.breached = list
+	}
 }
 
-func (s *UserService) Register(ctx context.Context, name, email, password string) (*domain.User, error) {
-	existingUser, err := s.repo.FindByEmail(ctx, email)
+func NewUserService(repo domain.UserRepository, orgRepo domain.OrganizationRepository, opts ...UserServiceOption) *UserService {
+	s := &UserService{
+		repo:    repo,
+		orgRepo: orgRepo,
+		policy:  domain.DefaultPasswordPolicy(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register creates an employee in the organization identified by orgSlug.
+// Only emails of the organization's allowed domains can register this way.
+func (s *UserService) Register(ctx context.Context, orgSlug, name, email, password string) (*domain.User, error) {
+	org, err := s.orgRepo.FindBySlug(ctx, orgSlug)
 	if err != nil {
 		return nil, err
 	}
-	if existingUser != nil {
-		return nil, ErrUserAlreadyExists
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if !org.AllowsSelfRegistration(email) {
+		return nil, ErrRegistrationClosed
 	}
 
-	hashedPassword, err := utils.HashPassword(password)
+	return s.createUser(domain.ContextWithOrgID(ctx, org.ID), name, email, password, domain.RoleEmployee)
+}
+
+// CreateUser adds a user with the given role to the admin's organization,
+// whatever the domain of their email. Only admins can do it.
+func (s *UserService) CreateUser(ctx context.Context, adminID uuid.UUID, name, email, password string, role domain.UserRole) (*domain.User, error) {
+	if err := requireAdmin(ctx, s.repo, adminID); err != nil {
+		return nil, err
+	}
+	if _, ok := domain.OrgIDFromContext(ctx); !ok {
+		return nil, domain.ErrMissingTenant
+	}
+
+	return s.createUser(ctx, name, email, password, role)
+}
+
+// createUser creates a user in the organization carried by ctx
+func (s *UserService) createUser(ctx context.Context, name, email, password string, role domain.UserRole) (*domain.User, error) {
+	orgID, _ := domain.OrgIDFromContext(ctx)
+
+	// Emails are unique across organizations because login is by email only
+	existingUser, err := s.repo.FindByEmail(domain.ContextWithAllTenants(ctx), email)
 	if err != nil {
 		return nil, err
 	}
+	if existingUser != nil {
+		return nil, ErrUserAlreadyExists
+	}
 
 	user := &domain.User{
-		ID:           uuid.New(),
-		Name:         name,
-		Email:        email,
-		PasswordHash: hashedPassword,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:        uuid.New(),
+		OrgID:     orgID,
+		Role:      role,
+		Name:      name,
+		Email:     email,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
+	// Check the password before spending time on hashing it
+	if err := s.checkPassword(ctx, user, password, false); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = hashedPassword
+
 	// Validate user before saving
 	if err := user.Validate(); err != nil {
 		return nil, err
@@ -60,7 +132,8 @@ func (s *UserService) Register(ctx context.Context, name, email, password string
 }
 
 func (s *UserService) Login(ctx context.Context, email, password string) (*domain.User, error) {
-	user, err := s.repo.FindByEmail(ctx, email)
+	// The organization comes from the user record, so the lookup can't be scoped yet
+	user, err := s.repo.FindByEmail(domain.ContextWithAllTenants(ctx), email)
 	if err != nil {
 		return nil, err
 	}
@@ -85,3 +158,112 @@ func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.Us
 	}
 	return user, nil
 }
+
+// UpdateRole changes the role and manager of a user. Only admins can do it.
+func (s *UserService) UpdateRole(ctx context.Context, adminID, userID uuid.UUID, role domain.UserRole, managerID *uuid.UUID) (*domain.User, error) {
+	if err := requireAdmin(ctx, s.repo, adminID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	validationErrors := domain.NewValidationErrors()
+	validationErrors.AddIf(!role.IsValid(), "invalid role")
+	if managerID != nil {
+		if *managerID == userID {
+			validationErrors.Add("a user cannot be their own manager")
+		} else {
+			manager, err := s.repo.FindByID(ctx, *managerID)
+			if err != nil {
+				return nil, err
+			}
+			validationErrors.AddIf(manager == nil, "manager_id must reference a user of the organization")
+		}
+	}
+	if validationErrors.HasErrors() {
+		return nil, validationErrors
+	}
+
+	if err := s.repo.UpdateRole(ctx, userID, role, managerID); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	user.ManagerID = managerID
+	return user, nil
+}
+
+// ChangePassword replaces the password of an authenticated user after checking the current one
+func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !utils.CheckPasswordHash(currentPassword, user.PasswordHash) {
+		return ErrInvalidCredentials
+	}
+
+	return s.setPassword(ctx, user, newPassword)
+}
+
+func (s *UserService) setPassword(ctx context.Context, user *domain.User, password string) error {
+	hashedPassword, err := s.hashNewPassword(ctx, user, password)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdatePassword(ctx, user.ID, hashedPassword)
+}
+
+// hashNewPassword checks a new password of an existing user and hashes it
+func (s *UserService) hashNewPassword(ctx context.Context, user *domain.User, password string) (string, error) {
+	if err := s.checkPassword(ctx, user, password, true); err != nil {
+		return "", err
+	}
+
+	return utils.HashPassword(password)
+}
+
+// checkPassword applies the password policy, the breached password list and,
+// for existing users, the password history.
+func (s *UserService) checkPassword(ctx context.Context, user *domain.User, password string, checkHistory bool) error {
+	if err := s.policy.Validate(password, user); err != nil {
+		return err
+	}
+
+	validationErrors := domain.NewValidationErrors()
+	validationErrors.AddIf(s.breached.Contains(password), "password has appeared in a data breach, choose a different one")
+
+	if checkHistory && s.policy.HistorySize > 0 {
+		history, err := s.repo.FindPasswordHistory(ctx, user.ID, s.policy.HistorySize)
+		if err != nil {
+			return err
+		}
+		for _, hash := range history {
+			if utils.CheckPasswordHash(password, hash) {
+				validationErrors.Add(fmt.Sprintf("password cannot be one of your last %d passwords", s.policy.HistorySize))
+				break
+			}
+		}
+	}
+
+	if validationErrors.HasErrors() {
+		return validationErrors
+	}
+	return nil
+}
+
+// requireAdmin returns ErrPermissionDenied unless the user is an admin of the organization in ctx
+func requireAdmin(ctx context.Context, repo domain.UserRepository, userID uuid.UUID) error {
+	user, err := repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Role != domain.RoleAdmin {
+		return ErrPermissionDenied
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package service_test
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
@@ -14,30 +18,36 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// testOrg is the organization users register into in these tests
+var testOrg = &domain.Organization{ID: uuid.New(), Name: "Acme", Slug: "acme", AllowedEmailDomains: []string{"example.com"}}
+
 func TestUserService_Register(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		name := "Test User"
 		email := "test@example.com"
 		password := "password123"
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(nil, nil)
-		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+		mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(nil, nil)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
 
 		// Act
-		user, err := userService.Register(ctx, name, email, password)
+		user, err := userService.Register(ctx, testOrg.Slug, name, email, password)
 
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, name, user.Name)
 		assert.Equal(t, email, user.Email)
+		assert.Equal(t, testOrg.ID, user.OrgID)
 		assert.NotEmpty(t, user.PasswordHash)
 		mockRepo.AssertExpectations(t)
 	})
@@ -45,7 +55,8 @@ func TestUserService_Register(t *testing.T) {
 	t.Run("User already exists", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		name := "Existing User"
 		email := "existing@example.com"
@@ -56,10 +67,11 @@ func TestUserService_Register(t *testing.T) {
 		}
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(existingUser, nil)
+		mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(existingUser, nil)
 
 		// Act
-		user, err := userService.Register(ctx, name, email, password)
+		user, err := userService.Register(ctx, testOrg.Slug, name, email, password)
 
 		// Assert
 		assert.Error(t, err)
@@ -71,7 +83,8 @@ func TestUserService_Register(t *testing.T) {
 	t.Run("Database error", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		name := "Test User"
 		email := "test@example.com"
@@ -79,10 +92,11 @@ func TestUserService_Register(t *testing.T) {
 		dbError := errors.New("database error")
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(nil, dbError)
+		mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(nil, dbError)
 
 		// Act
-		user, err := userService.Register(ctx, name, email, password)
+		user, err := userService.Register(ctx, testOrg.Slug, name, email, password)
 
 		// Assert
 		assert.Error(t, err)
@@ -92,13 +106,160 @@ func TestUserService_Register(t *testing.T) {
 	})
 }
 
+func TestUserService_Register_UnknownOrganization(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	userService := service.NewUserService(mockRepo, mockOrgRepo)
+
+	// Mock behavior
+	mockOrgRepo.On("FindBySlug", ctx, "unknown").Return(nil, nil)
+
+	// Act
+	user, err := userService.Register(ctx, "unknown", "Test User", "test@example.com", "password123")
+
+	// Assert
+	assert.Equal(t, service.ErrOrganizationNotFound, err)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestUserService_Register_EmailDomain(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(mocks.MockUserRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	userService := service.NewUserService(mockRepo, mockOrgRepo)
+
+	closedOrg := &domain.Organization{ID: uuid.New(), Name: "Closed", Slug: "closed"}
+
+	// Mock behavior
+	mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+	mockOrgRepo.On("FindBySlug", ctx, closedOrg.Slug).Return(closedOrg, nil)
+
+	// Act
+	outsider, outsiderErr := userService.Register(ctx, testOrg.Slug, "Outsider", "outsider@gmail.com", "password123")
+	closed, closedErr := userService.Register(ctx, closedOrg.Slug, "Test User", "test@example.com", "password123")
+
+	// Assert
+	assert.Equal(t, service.ErrRegistrationClosed, outsiderErr)
+	assert.Nil(t, outsider)
+	assert.Equal(t, service.ErrRegistrationClosed, closedErr)
+	assert.Nil(t, closed)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), testOrg.ID)
+
+	t.Run("Admin creates a user outside the allowed domains", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(mockRepo, new(mocks.MockOrganizationRepository))
+
+		admin := &domain.User{ID: uuid.New(), OrgID: testOrg.ID, Role: domain.RoleAdmin}
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), "contractor@gmail.com").Return(nil, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+
+		// Act
+		user, err := userService.CreateUser(ctx, admin.ID, "Contractor", "contractor@gmail.com", "password123", domain.RoleManager)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, testOrg.ID, user.OrgID)
+		assert.Equal(t, domain.RoleManager, user.Role)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only admins create users", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(mockRepo, new(mocks.MockOrganizationRepository))
+
+		manager := &domain.User{ID: uuid.New(), OrgID: testOrg.ID, Role: domain.RoleManager}
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+
+		// Act
+		user, err := userService.CreateUser(ctx, manager.ID, "Contractor", "contractor@gmail.com", "password123", domain.RoleEmployee)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, user)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestUserService_Register_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Weak password", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo, service.WithPasswordPolicy(domain.PasswordPolicy{
+			MinLength:    10,
+			RequireDigit: true,
+		}))
+
+		// Mock behavior
+		mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), "test@example.com").Return(nil, nil)
+
+		// Act
+		user, err := userService.Register(ctx, testOrg.Slug, "Test User", "test@example.com", "password")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must be at least 10 characters")
+		assert.Contains(t, err.Error(), "password must contain a digit")
+		assert.Nil(t, user)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Breached password", func(t *testing.T) {
+		// Arrange
+		breachedPassword := "iloveyou2024"
+		sum := sha1.Sum([]byte(breachedPassword))
+		path := filepath.Join(t.TempDir(), "breached.txt")
+		err := os.WriteFile(path, []byte(hex.EncodeToString(sum[:])+":42\n"), 0o600)
+		assert.NoError(t, err)
+
+		list, err := utils.LoadBreachedPasswordList(path)
+		assert.NoError(t, err)
+
+		mockRepo := new(mocks.MockUserRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo, service.WithBreachedPasswords(list))
+
+		// Mock behavior
+		mockOrgRepo.On("FindBySlug", ctx, testOrg.Slug).Return(testOrg, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), "test@example.com").Return(nil, nil)
+
+		// Act
+		user, err := userService.Register(ctx, testOrg.Slug, "Test User", "test@example.com", breachedPassword)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password has appeared in a data breach")
+		assert.Nil(t, user)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
 func TestUserService_Login(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		email := "test@example.com"
 		password := "password123"
@@ -114,7 +275,7 @@ func TestUserService_Login(t *testing.T) {
 		}
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(user, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(user, nil)
 
 		// Act
 		loggedInUser, err := userService.Login(ctx, email, password)
@@ -128,13 +289,14 @@ func TestUserService_Login(t *testing.T) {
 	t.Run("User not found", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		email := "nonexistent@example.com"
 		password := "password123"
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(nil, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(nil, nil)
 
 		// Act
 		user, err := userService.Login(ctx, email, password)
@@ -149,7 +311,8 @@ func TestUserService_Login(t *testing.T) {
 	t.Run("Invalid password", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		email := "test@example.com"
 		password := "wrongpassword"
@@ -166,7 +329,7 @@ func TestUserService_Login(t *testing.T) {
 		}
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(user, nil)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(user, nil)
 
 		// Act
 		loggedInUser, err := userService.Login(ctx, email, password)
@@ -181,14 +344,15 @@ func TestUserService_Login(t *testing.T) {
 	t.Run("Database error", func(t *testing.T) {
 		// Arrange - create new mock for this test case
 		mockRepo := new(mocks.MockUserRepository)
-		userService := service.NewUserService(mockRepo)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
 
 		email := "test@example.com"
 		password := "password123"
 		dbError := errors.New("database error")
 
 		// Mock behavior
-		mockRepo.On("FindByEmail", ctx, email).Return(nil, dbError)
+		mockRepo.On("FindByEmail", mock.MatchedBy(domain.IsAllTenantsContext), email).Return(nil, dbError)
 
 		// Act
 		user, err := userService.Login(ctx, email, password)
@@ -204,7 +368,8 @@ func TestUserService_Login(t *testing.T) {
 func TestUserService_GetUserByID(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	userService := service.NewUserService(mockRepo)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
+	userService := service.NewUserService(mockRepo, mockOrgRepo)
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
@@ -263,3 +428,149 @@ func TestUserService_GetUserByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_UpdateRole(t *testing.T) {
+	ctx := domain.ContextWithOrgID(context.Background(), testOrg.ID)
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(mockRepo, new(mocks.MockOrganizationRepository))
+
+		admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+		user := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+		mockRepo.On("UpdateRole", ctx, user.ID, domain.RoleManager, &manager.ID).Return(nil)
+
+		// Act
+		updated, err := userService.UpdateRole(ctx, admin.ID, user.ID, domain.RoleManager, &manager.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleManager, updated.Role)
+		assert.Equal(t, manager.ID, *updated.ManagerID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only admins can change roles", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(mockRepo, new(mocks.MockOrganizationRepository))
+
+		manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, manager.ID).Return(manager, nil)
+
+		// Act
+		updated, err := userService.UpdateRole(ctx, manager.ID, uuid.New(), domain.RoleAdmin, nil)
+
+		// Assert
+		assert.Equal(t, service.ErrPermissionDenied, err)
+		assert.Nil(t, updated)
+		mockRepo.AssertNotCalled(t, "UpdateRole")
+	})
+
+	t.Run("Invalid role and self management", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(mockRepo, new(mocks.MockOrganizationRepository))
+
+		admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+		user := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		// Act
+		updated, err := userService.UpdateRole(ctx, admin.ID, user.ID, "owner", &user.ID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid role")
+		assert.Contains(t, err.Error(), "a user cannot be their own manager")
+		assert.Nil(t, updated)
+		mockRepo.AssertNotCalled(t, "UpdateRole")
+	})
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	currentPassword := "current-password"
+	currentHash, err := utils.HashPassword(currentPassword)
+	assert.NoError(t, err)
+
+	newUser := func() *domain.User {
+		return &domain.User{
+			ID:           uuid.New(),
+			Name:         "Test User",
+			Email:        "test@example.com",
+			PasswordHash: currentHash,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
+		user := newUser()
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockRepo.On("FindPasswordHistory", ctx, user.ID, 5).Return([]string{currentHash}, nil)
+		mockRepo.On("UpdatePassword", ctx, user.ID, mock.AnythingOfType("string")).Return(nil)
+
+		// Act
+		err := userService.ChangePassword(ctx, user.ID, currentPassword, "brand-new-password")
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Wrong current password", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
+		user := newUser()
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		// Act
+		err := userService.ChangePassword(ctx, user.ID, "not-the-password", "brand-new-password")
+
+		// Assert
+		assert.Equal(t, service.ErrInvalidCredentials, err)
+		mockRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+
+	t.Run("Password reused from history", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(mocks.MockUserRepository)
+		mockOrgRepo := new(mocks.MockOrganizationRepository)
+		userService := service.NewUserService(mockRepo, mockOrgRepo)
+		user := newUser()
+
+		// Mock behavior
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockRepo.On("FindPasswordHistory", ctx, user.ID, 5).Return([]string{currentHash}, nil)
+
+		// Act
+		err := userService.ChangePassword(ctx, user.ID, currentPassword, currentPassword)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password cannot be one of your last 5 passwords")
+		mockRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// BreachedPasswordList is an offline list of breached password SHA-1 hashes.
+// Hashes are indexed by their first 5 hex characters, the same k-anonymity
+// ranges used by the Have I Been Pwned API, so a lookup only scans one range.
+type BreachedPasswordList struct {
+	ranges map[string][]string
+}
+
+// LoadBreachedPasswordList reads a file in the HIBP "ordered by hash" format:
+// one uppercase or lowercase SHA-1 hash per line, optionally followed by ":count".
+func LoadBreachedPasswordList(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open breached password file: %w", err)
+	}
+	defer file.Close()
+
+	list := &BreachedPasswordList{ranges: make(map[string][]string)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("invalid hash in breached password file: %q", line)
+		}
+		list.ranges[hash[:5]] = append(list.ranges[hash[:5]], hash[5:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read breached password file: %w", err)
+	}
+
+	for prefix := range list.ranges {
+		sort.Strings(list.ranges[prefix])
+	}
+	return list, nil
+}
+
+// Contains reports whether the password appears in the list. A nil list never matches.
+func (l *BreachedPasswordList) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes := l.ranges[hash[:5]]
+	i := sort.SearchStrings(suffixes, hash[5:])
+	return i < len(suffixes) && suffixes[i] == hash[5:]
+}
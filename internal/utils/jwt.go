@@ -8,9 +8,10 @@ import (
 	"github.com/google/uuid"
 )
 
-func GenerateJWT(userID uuid.UUID, secretKey string, expirationHours int) (string, error) {
+func GenerateJWT(userID, orgID uuid.UUID, secretKey string, expirationHours int) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID.String(),
+		"org_id":  orgID.String(),
 		"exp":     time.Now().Add(time.Hour * time.Duration(expirationHours)).Unix(),
 		"iat":     time.Now().Unix(),
 	}
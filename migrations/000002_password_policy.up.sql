@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_history_user_id ON password_history(user_id, created_at DESC);
+
+-- Seed the history with the current password of existing users
+INSERT INTO password_history (user_id, password_hash, created_at)
+SELECT id, password_hash, updated_at FROM users;
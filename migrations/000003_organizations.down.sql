@@ -0,0 +1,12 @@
+DROP POLICY IF EXISTS trips_tenant_isolation ON trips;
+ALTER TABLE trips NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE trips DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_tenant_isolation ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE trips DROP COLUMN IF EXISTS org_id;
+ALTER TABLE users DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organizations;
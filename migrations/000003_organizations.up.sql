@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Existing users and trips are moved to a default organization
+INSERT INTO organizations (id, name, slug)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default');
+
+ALTER TABLE users ADD COLUMN org_id UUID REFERENCES organizations(id);
+UPDATE users SET org_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE users ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE trips ADD COLUMN org_id UUID REFERENCES organizations(id);
+UPDATE trips SET org_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE trips ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX idx_users_org_id ON users(org_id);
+CREATE INDEX idx_trips_org_id ON trips(org_id);
+
+-- Row-level security. The API sets app.current_org_id for every transaction;
+-- app.bypass_tenant is only set for login lookups and background jobs.
+-- FORCE makes the policies apply to the table owner as well, which is the
+-- role the API connects with. Later migrations that touch these tables must
+-- run "SET app.bypass_tenant = 'on'" first.
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY users_tenant_isolation ON users
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE trips ENABLE ROW LEVEL SECURITY;
+ALTER TABLE trips FORCE ROW LEVEL SECURITY;
+CREATE POLICY trips_tenant_isolation ON trips
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
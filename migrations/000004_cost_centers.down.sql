@@ -0,0 +1,9 @@
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trips DROP COLUMN IF EXISTS cost_center_id;
+ALTER TABLE users DROP COLUMN IF EXISTS department_id;
+
+DROP TABLE IF EXISTS departments;
+DROP TABLE IF EXISTS cost_centers;
+
+RESET app.bypass_tenant;
@@ -0,0 +1,57 @@
+-- users and trips have row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+CREATE TABLE IF NOT EXISTS cost_centers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    code VARCHAR(30) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT cost_centers_org_code_unique UNIQUE (org_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS departments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    cost_center_id UUID NOT NULL REFERENCES cost_centers(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT departments_org_name_unique UNIQUE (org_id, name)
+);
+
+ALTER TABLE users ADD COLUMN department_id UUID REFERENCES departments(id) ON DELETE SET NULL;
+
+-- Existing trips are charged to a general cost center in each organization
+INSERT INTO cost_centers (org_id, code, name)
+SELECT id, 'GERAL', 'Geral' FROM organizations;
+
+ALTER TABLE trips ADD COLUMN cost_center_id UUID REFERENCES cost_centers(id);
+UPDATE trips t SET cost_center_id = cc.id
+FROM cost_centers cc
+WHERE cc.org_id = t.org_id AND cc.code = 'GERAL';
+ALTER TABLE trips ALTER COLUMN cost_center_id SET NOT NULL;
+
+CREATE INDEX idx_cost_centers_org_id ON cost_centers(org_id);
+CREATE INDEX idx_departments_org_id ON departments(org_id);
+CREATE INDEX idx_trips_cost_center_id ON trips(cost_center_id);
+
+ALTER TABLE cost_centers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cost_centers FORCE ROW LEVEL SECURITY;
+CREATE POLICY cost_centers_tenant_isolation ON cost_centers
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE departments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE departments FORCE ROW LEVEL SECURITY;
+CREATE POLICY departments_tenant_isolation ON departments
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;
@@ -0,0 +1,9 @@
+SET app.bypass_tenant = 'on';
+
+DROP TABLE IF EXISTS trip_approval_steps;
+DROP TABLE IF EXISTS approval_policies;
+
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+RESET app.bypass_tenant;
@@ -0,0 +1,61 @@
+-- users and trips have row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'employee'
+    CHECK (role IN ('employee', 'manager', 'director', 'finance', 'admin'));
+ALTER TABLE users ADD COLUMN manager_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- The oldest user of each organization becomes its admin so roles can be handed out
+UPDATE users SET role = 'admin'
+WHERE id IN (SELECT DISTINCT ON (org_id) id FROM users ORDER BY org_id, created_at);
+
+CREATE TABLE IF NOT EXISTS approval_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    destinations TEXT[] NOT NULL DEFAULT '{}',
+    min_duration_days INT NOT NULL DEFAULT 0,
+    steps TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS trip_approval_steps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    approver_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT trip_approval_steps_position_unique UNIQUE (trip_id, position)
+);
+
+-- Trips waiting for approval keep needing a single manager decision
+INSERT INTO trip_approval_steps (org_id, trip_id, position, role)
+SELECT org_id, id, 1, 'manager' FROM trips WHERE status = 'solicitado';
+
+CREATE INDEX idx_approval_policies_org_id ON approval_policies(org_id);
+CREATE INDEX idx_trip_approval_steps_pending ON trip_approval_steps(org_id, status);
+
+ALTER TABLE approval_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE approval_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY approval_policies_tenant_isolation ON approval_policies
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE trip_approval_steps ENABLE ROW LEVEL SECURITY;
+ALTER TABLE trip_approval_steps FORCE ROW LEVEL SECURITY;
+CREATE POLICY trip_approval_steps_tenant_isolation ON trip_approval_steps
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;
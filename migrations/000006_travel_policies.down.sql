@@ -0,0 +1,6 @@
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trips DROP COLUMN IF EXISTS policy_warnings;
+DROP TABLE IF EXISTS travel_policies;
+
+RESET app.bypass_tenant;
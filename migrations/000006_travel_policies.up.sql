@@ -0,0 +1,20 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+CREATE TABLE IF NOT EXISTS travel_policies (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    rules JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE trips ADD COLUMN policy_warnings TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE travel_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE travel_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY travel_policies_tenant_isolation ON travel_policies
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;
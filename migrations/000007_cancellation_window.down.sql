@@ -0,0 +1,7 @@
+SET app.bypass_tenant = 'on';
+
+DROP TABLE IF EXISTS trip_events;
+ALTER TABLE travel_policies DROP COLUMN IF EXISTS cancellation;
+ALTER TABLE trips DROP COLUMN IF EXISTS trip_type;
+
+RESET app.bypass_tenant;
@@ -0,0 +1,29 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trips ADD COLUMN trip_type VARCHAR(20) NOT NULL DEFAULT 'domestic'
+    CHECK (trip_type IN ('domestic', 'international'));
+
+ALTER TABLE travel_policies ADD COLUMN cancellation JSONB;
+
+CREATE TABLE IF NOT EXISTS trip_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    justification TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_trip_events_trip_id ON trip_events(trip_id);
+
+ALTER TABLE trip_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE trip_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY trip_events_tenant_isolation ON trip_events
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;
@@ -0,0 +1,8 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Postgres can't drop an enum value, so withdrawn trips fall back to canceled
+-- and the value stays in the type
+UPDATE trips SET status = 'cancelado' WHERE status = 'retirado';
+
+RESET app.bypass_tenant;
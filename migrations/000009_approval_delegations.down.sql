@@ -0,0 +1,6 @@
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trip_events DROP COLUMN IF EXISTS on_behalf_of_id;
+DROP TABLE IF EXISTS approval_delegations;
+
+RESET app.bypass_tenant;
@@ -0,0 +1,30 @@
+-- trip_events has row-level security, see 000007_cancellation_window
+SET app.bypass_tenant = 'on';
+
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    delegator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegate_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    cost_center_id UUID REFERENCES cost_centers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT approval_delegations_dates_check CHECK (ends_at > starts_at),
+    CONSTRAINT approval_delegations_self_check CHECK (delegator_id <> delegate_id)
+);
+
+CREATE INDEX idx_approval_delegations_delegate_id ON approval_delegations(delegate_id, starts_at, ends_at);
+CREATE INDEX idx_approval_delegations_delegator_id ON approval_delegations(delegator_id);
+
+ALTER TABLE trip_events ADD COLUMN on_behalf_of_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE approval_delegations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE approval_delegations FORCE ROW LEVEL SECURITY;
+CREATE POLICY approval_delegations_tenant_isolation ON approval_delegations
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;
@@ -0,0 +1,6 @@
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trip_approval_steps DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE trip_approval_steps DROP COLUMN IF EXISTS reminded_at;
+
+RESET app.bypass_tenant;
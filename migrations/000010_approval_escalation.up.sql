@@ -0,0 +1,7 @@
+-- trip_approval_steps has row-level security, see 000005_approval_chains
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE trip_approval_steps ADD COLUMN reminded_at TIMESTAMPTZ;
+ALTER TABLE trip_approval_steps ADD COLUMN escalated_at TIMESTAMPTZ;
+
+RESET app.bypass_tenant;
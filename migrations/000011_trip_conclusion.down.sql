@@ -0,0 +1,8 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Postgres can't drop an enum value, so concluded trips go back to approved
+-- and the value stays in the type
+UPDATE trips SET status = 'aprovado' WHERE status = 'concluido';
+
+RESET app.bypass_tenant;
@@ -0,0 +1 @@
+ALTER TYPE trip_status ADD VALUE IF NOT EXISTS 'concluido';
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS scheduled_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    trip_id UUID REFERENCES trips(id) ON DELETE CASCADE,
+    template VARCHAR(50) NOT NULL,
+    dedupe_key VARCHAR(255) NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT scheduled_notifications_dedupe_key_unique UNIQUE (org_id, dedupe_key)
+);
+
+CREATE INDEX idx_scheduled_notifications_due_at ON scheduled_notifications(due_at) WHERE sent_at IS NULL;
+CREATE INDEX idx_scheduled_notifications_trip_id ON scheduled_notifications(trip_id);
+
+ALTER TABLE scheduled_notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE scheduled_notifications FORCE ROW LEVEL SECURITY;
+CREATE POLICY scheduled_notifications_tenant_isolation ON scheduled_notifications
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
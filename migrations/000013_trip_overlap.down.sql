@@ -0,0 +1 @@
+ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_no_overlap;
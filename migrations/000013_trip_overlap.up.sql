@@ -0,0 +1,36 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Lets the exclusion constraint compare requester_id with a GiST index
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Exclusion constraints can't be added NOT VALID, so overlaps already in the
+-- table stop the migration with the list of conflicting trips. Deciding which
+-- trip stays is left to an admin, who withdraws or cancels the others through
+-- the API so that travelers are notified and the history names who did it.
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('trip %s overlaps trip %s (org %s, requester %s)', a.id, b.id, a.org_id, a.requester_id),
+                      E'\n' ORDER BY a.org_id, a.requester_id, a.start_date, a.id, b.id)
+    INTO conflicts
+    FROM trips a
+    JOIN trips b ON b.requester_id = a.requester_id
+                AND b.id > a.id
+                AND tstzrange(a.start_date, a.end_date, '[]') && tstzrange(b.start_date, b.end_date, '[]')
+    WHERE a.status NOT IN ('cancelado', 'retirado')
+      AND b.status NOT IN ('cancelado', 'retirado');
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'trips of the same requester overlap; withdraw or cancel one trip of each pair, then run the migration again'
+            USING DETAIL = conflicts;
+    END IF;
+END $$;
+
+ALTER TABLE trips ADD CONSTRAINT trips_no_overlap EXCLUDE USING gist (
+    requester_id WITH =,
+    tstzrange(start_date, end_date, '[]') WITH &&
+) WHERE (status NOT IN ('cancelado', 'retirado'));
+
+RESET app.bypass_tenant;
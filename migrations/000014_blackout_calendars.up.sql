@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS blackout_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    department_id UUID REFERENCES departments(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    severity VARCHAR(10) NOT NULL,
+    periods JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT blackout_calendars_severity_check CHECK (severity IN ('block', 'warn'))
+);
+
+CREATE INDEX idx_blackout_calendars_org_id ON blackout_calendars(org_id);
+
+ALTER TABLE blackout_calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE blackout_calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY blackout_calendars_tenant_isolation ON blackout_calendars
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS trip_legs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    origin VARCHAR(255) NOT NULL,
+    destination VARCHAR(255) NOT NULL,
+    depart_at TIMESTAMPTZ NOT NULL,
+    arrive_at TIMESTAMPTZ NOT NULL,
+    transport_mode VARCHAR(20) NOT NULL,
+    CONSTRAINT trip_legs_position_unique UNIQUE (trip_id, position),
+    CONSTRAINT trip_legs_dates_check CHECK (arrive_at > depart_at)
+);
+
+ALTER TABLE trip_legs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE trip_legs FORCE ROW LEVEL SECURITY;
+CREATE POLICY trip_legs_tenant_isolation ON trip_legs
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
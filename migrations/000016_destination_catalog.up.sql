@@ -0,0 +1,13 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Lets GET /trips?destination= ignore accents
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- IATA code of the destination city in the catalog embedded in the API.
+-- Trips created before the catalog only have the free text destination.
+ALTER TABLE trips ADD COLUMN destination_code VARCHAR(3);
+
+CREATE INDEX idx_trips_destination_code ON trips(org_id, destination_code);
+
+RESET app.bypass_tenant;
@@ -0,0 +1,9 @@
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- IANA time zone the trip dates are local to. start_date and end_date stay
+-- instants; the zone brings back their local date and time. Empty for trips
+-- created before time zones, whose dates are read in UTC.
+ALTER TABLE trips ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+RESET app.bypass_tenant;
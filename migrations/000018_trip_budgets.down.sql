@@ -0,0 +1,2 @@
+ALTER TABLE approval_policies DROP COLUMN IF EXISTS min_budget, DROP COLUMN IF EXISTS budget_currency;
+ALTER TABLE trips DROP COLUMN IF EXISTS budget;
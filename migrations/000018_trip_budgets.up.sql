@@ -0,0 +1,13 @@
+-- trips and approval_policies have row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Estimated cost of the trip: {"currency": "BRL", "items": [{"category": "airfare", "amount": 150000}]}
+-- Amounts are in minor units of the currency
+ALTER TABLE trips ADD COLUMN budget JSONB;
+
+-- Policies with min_budget only apply to trips whose budget reaches it
+ALTER TABLE approval_policies
+    ADD COLUMN min_budget BIGINT NOT NULL DEFAULT 0 CHECK (min_budget >= 0),
+    ADD COLUMN budget_currency VARCHAR(3) NOT NULL DEFAULT '';
+
+RESET app.bypass_tenant;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE organizations DROP COLUMN IF EXISTS base_currency;
@@ -0,0 +1,22 @@
+-- Currency budgets are reported in; trip budgets in other currencies are converted to it
+ALTER TABLE organizations ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- 1 currency is worth rate base_currency from effective_date on
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT exchange_rates_unique UNIQUE (org_id, currency, base_currency, effective_date)
+);
+
+ALTER TABLE exchange_rates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE exchange_rates FORCE ROW LEVEL SECURITY;
+CREATE POLICY exchange_rates_tenant_isolation ON exchange_rates
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
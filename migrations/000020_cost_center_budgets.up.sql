@@ -0,0 +1,28 @@
+-- What a cost center can spend on travel between period_start and period_end, both days included.
+-- amount is in minor units of currency, the organization's base currency when the budget was created.
+CREATE TABLE IF NOT EXISTS cost_center_budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    cost_center_id UUID NOT NULL REFERENCES cost_centers(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    severity VARCHAR(10) NOT NULL CHECK (severity IN ('block', 'warn')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end >= period_start),
+    -- A trip is charged to a single budget
+    CONSTRAINT cost_center_budgets_no_overlap EXCLUDE USING gist (
+        cost_center_id WITH =,
+        daterange(period_start, period_end, '[]') WITH &&
+    )
+);
+
+ALTER TABLE cost_center_budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cost_center_budgets FORCE ROW LEVEL SECURITY;
+CREATE POLICY cost_center_budgets_tenant_isolation ON cost_center_budgets
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
@@ -0,0 +1,2 @@
+ALTER TABLE trips DROP COLUMN IF EXISTS per_diem;
+DROP TABLE IF EXISTS per_diem_rates;
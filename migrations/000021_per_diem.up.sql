@@ -0,0 +1,33 @@
+-- Daily allowance of travelers in a city, or in the whole country when city_code is empty.
+-- Amounts are in minor units of currency; percents are of daily_amount.
+CREATE TABLE IF NOT EXISTS per_diem_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    country_code VARCHAR(2) NOT NULL,
+    city_code VARCHAR(3) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    daily_amount BIGINT NOT NULL CHECK (daily_amount > 0),
+    partial_day_percent INT NOT NULL DEFAULT 100 CHECK (partial_day_percent BETWEEN 0 AND 100),
+    breakfast_percent INT NOT NULL DEFAULT 0 CHECK (breakfast_percent BETWEEN 0 AND 100),
+    lunch_percent INT NOT NULL DEFAULT 0 CHECK (lunch_percent BETWEEN 0 AND 100),
+    dinner_percent INT NOT NULL DEFAULT 0 CHECK (dinner_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT per_diem_rates_unique UNIQUE (org_id, country_code, city_code)
+);
+
+ALTER TABLE per_diem_rates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE per_diem_rates FORCE ROW LEVEL SECURITY;
+CREATE POLICY per_diem_rates_tenant_isolation ON per_diem_rates
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+-- trips has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Computed allowance of the trip: {"provided_meals": [...], "days": [...], "totals": [{"amount": 45000, "currency": "EUR"}]}
+ALTER TABLE trips ADD COLUMN per_diem JSONB;
+
+RESET app.bypass_tenant;
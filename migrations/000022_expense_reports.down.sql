@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS expense_items;
+DROP TABLE IF EXISTS expense_reports;
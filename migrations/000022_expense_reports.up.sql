@@ -0,0 +1,54 @@
+-- Expenses of a trip, submitted for reimbursement apart from the trip approval.
+-- currency is the organization's base currency, the one the report is totaled in.
+CREATE TABLE IF NOT EXISTS expense_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL UNIQUE REFERENCES trips(id) ON DELETE CASCADE,
+    traveler_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'reimbursed')),
+    currency VARCHAR(3) NOT NULL,
+    rejection_reason TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMPTZ,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    reimbursed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reimbursed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_reports_status ON expense_reports(org_id, status);
+
+-- amount is in minor units of currency; converted keeps the amount in the report currency
+-- and the rate it was taken with, for items in another currency
+CREATE TABLE IF NOT EXISTS expense_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    report_id UUID NOT NULL REFERENCES expense_reports(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    expense_date DATE NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    receipt_ref VARCHAR(255) NOT NULL DEFAULT '',
+    converted JSONB,
+    CONSTRAINT expense_items_position UNIQUE (report_id, position)
+);
+
+ALTER TABLE expense_reports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_reports FORCE ROW LEVEL SECURITY;
+CREATE POLICY expense_reports_tenant_isolation ON expense_reports
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE expense_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_items FORCE ROW LEVEL SECURITY;
+CREATE POLICY expense_items_tenant_isolation ON expense_items
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
@@ -0,0 +1,35 @@
+-- Money paid to travelers before a trip, settled against the trip's expense report.
+-- amount is in minor units of currency; converted keeps the amount in the organization's
+-- base currency and the rate it was taken with, for advances in another currency.
+CREATE TABLE IF NOT EXISTS cash_advances (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    traveler_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'approved', 'rejected', 'paid', 'settled')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    purpose VARCHAR(255) NOT NULL,
+    converted JSONB,
+    rejection_reason TEXT NOT NULL DEFAULT '',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    paid_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    paid_at TIMESTAMPTZ,
+    settled_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    settled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cash_advances_trip_id ON cash_advances(trip_id);
+CREATE INDEX IF NOT EXISTS idx_cash_advances_status ON cash_advances(org_id, status);
+
+ALTER TABLE cash_advances ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cash_advances FORCE ROW LEVEL SECURITY;
+CREATE POLICY cash_advances_tenant_isolation ON cash_advances
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS accounting_export_lines;
+DROP TABLE IF EXISTS accounting_export_batches;
+DROP TABLE IF EXISTS gl_accounts;
@@ -0,0 +1,64 @@
+-- General ledger account of the ERP each expense category is booked to
+CREATE TABLE IF NOT EXISTS gl_accounts (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL,
+    account VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, category)
+);
+
+-- Expenses exported to the ERP. An expense is exported in a single batch.
+CREATE TABLE IF NOT EXISTS accounting_export_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    period VARCHAR(7) NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_accounting_export_batches_org ON accounting_export_batches(org_id, created_at);
+
+-- The lines keep what was exported, so a batch can be downloaded again as it was.
+-- expense_item_id isn't a foreign key: expense items are rewritten whenever their report changes.
+CREATE TABLE IF NOT EXISTS accounting_export_lines (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    batch_id UUID NOT NULL REFERENCES accounting_export_batches(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    expense_item_id UUID NOT NULL,
+    trip_id UUID NOT NULL,
+    traveler_id UUID NOT NULL,
+    destination VARCHAR(255) NOT NULL,
+    cost_center_code VARCHAR(30) NOT NULL,
+    gl_account VARCHAR(20) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    expense_date DATE NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    PRIMARY KEY (batch_id, position),
+    CONSTRAINT accounting_export_lines_item UNIQUE (org_id, expense_item_id)
+);
+
+ALTER TABLE gl_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE gl_accounts FORCE ROW LEVEL SECURITY;
+CREATE POLICY gl_accounts_tenant_isolation ON gl_accounts
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE accounting_export_batches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounting_export_batches FORCE ROW LEVEL SECURITY;
+CREATE POLICY accounting_export_batches_tenant_isolation ON accounting_export_batches
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+ALTER TABLE accounting_export_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounting_export_lines FORCE ROW LEVEL SECURITY;
+CREATE POLICY accounting_export_lines_tenant_isolation ON accounting_export_lines
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
@@ -0,0 +1,39 @@
+-- Purchases on travelers' corporate cards, imported from card statements.
+-- external_id identifies the transaction in the statement, so a statement can be
+-- imported again. A transaction pays for at most one expense item and an item is
+-- paid by at most one transaction; expense_item_id isn't a foreign key because
+-- expense items are rewritten whenever their report changes. Saving a report
+-- sends the transactions of the items it dropped back to unmatched.
+CREATE TABLE IF NOT EXISTS card_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    cardholder_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_id VARCHAR(255) NOT NULL,
+    posted_on DATE NOT NULL,
+    merchant VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    converted JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'unmatched'
+        CHECK (status IN ('unmatched', 'matched', 'ignored')),
+    trip_id UUID REFERENCES trips(id) ON DELETE SET NULL,
+    expense_item_id UUID,
+    note TEXT NOT NULL DEFAULT '',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT card_transactions_external_id UNIQUE (org_id, cardholder_id, external_id),
+    CONSTRAINT card_transactions_expense_item UNIQUE (org_id, expense_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_transactions_status ON card_transactions(org_id, status);
+CREATE INDEX IF NOT EXISTS idx_card_transactions_trip_id ON card_transactions(trip_id);
+
+ALTER TABLE card_transactions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE card_transactions FORCE ROW LEVEL SECURITY;
+CREATE POLICY card_transactions_tenant_isolation ON card_transactions
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
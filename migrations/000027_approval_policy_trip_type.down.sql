@@ -0,0 +1 @@
+ALTER TABLE approval_policies DROP COLUMN IF EXISTS trip_type;
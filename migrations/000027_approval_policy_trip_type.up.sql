@@ -0,0 +1,9 @@
+-- approval_policies has row-level security, see 000003_organizations
+SET app.bypass_tenant = 'on';
+
+-- Policies with a trip_type only apply to domestic or to international trips
+ALTER TABLE approval_policies
+    ADD COLUMN trip_type VARCHAR(20) NOT NULL DEFAULT ''
+        CHECK (trip_type IN ('', 'domestic', 'international'));
+
+RESET app.bypass_tenant;
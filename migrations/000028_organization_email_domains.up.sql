@@ -0,0 +1,3 @@
+-- Email domains allowed to register themselves in the organization, e.g. 'acme.com'.
+-- Organizations without any only get users created by their admins.
+ALTER TABLE organizations ADD COLUMN allowed_email_domains TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP POLICY IF EXISTS password_history_tenant_isolation ON password_history;
+ALTER TABLE password_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE password_history DISABLE ROW LEVEL SECURITY;
+ALTER TABLE password_history DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,17 @@
+-- password_history was created before organizations, so it was the one tenant
+-- table without org_id and row-level security
+SET app.bypass_tenant = 'on';
+
+ALTER TABLE password_history ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE password_history h SET org_id = u.org_id FROM users u WHERE u.id = h.user_id;
+ALTER TABLE password_history ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE password_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE password_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY password_history_tenant_isolation ON password_history
+    USING (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid)
+    WITH CHECK (current_setting('app.bypass_tenant', true) = 'on'
+           OR org_id = NULLIF(current_setting('app.current_org_id', true), '')::uuid);
+
+RESET app.bypass_tenant;